@@ -0,0 +1,96 @@
+// Package main implements the dvoting command, which gathers the tools that
+// don't need a running node.
+//
+// Unix example:
+//
+//	# Export the bulletin board of an election from a node.
+//	memcoin --config /tmp/node1 e-voting exportBulletin\
+//	  --electionID <hex> --out /tmp/bulletin.ndjson
+//
+//	# Verify it offline.
+//	dvoting verify --bulletin /tmp/bulletin.ndjson
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dedis/d-voting/contracts/evoting"
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"go.dedis.ch/dela/cli"
+	"go.dedis.ch/dela/cli/ucli"
+	"golang.org/x/xerrors"
+)
+
+func main() {
+	err := run(os.Args, os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%+v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	builder := ucli.NewBuilder("dvoting", nil)
+
+	cmd := builder.SetCommand("verify")
+	cmd.SetDescription("verify an exported bulletin board")
+	cmd.SetFlags(cli.StringFlag{
+		Name:     "bulletin",
+		Usage:    "path of the exported bulletin board",
+		Required: true,
+	})
+	cmd.SetAction(func(flags cli.Flags) error {
+		return verify(flags.String("bulletin"), out)
+	})
+
+	return builder.Build().Run(args)
+}
+
+// verify checks the bulletin board and prints a report with one line per step.
+// It returns an error if a step fails or is skipped, unless the step can never
+// be verified from a bulletin board.
+func verify(path string, out io.Writer) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return xerrors.Errorf("failed to open bulletin board: %v", err)
+	}
+
+	defer file.Close()
+
+	bulletin, err := types.ReadBulletin(file)
+	if err != nil {
+		return xerrors.Errorf("failed to read bulletin board: %v", err)
+	}
+
+	fmt.Fprintf(out, "election %s (bulletin board version %d)\n",
+		bulletin.Header.ElectionID, bulletin.Header.Version)
+
+	failed := false
+	skipped := false
+
+	for _, step := range evoting.VerifyBulletin(bulletin) {
+		switch {
+		case step.Skipped:
+			skipped = skipped || !step.Unverifiable
+			fmt.Fprintf(out, "SKIP  %s: %v\n", step.Name, step.Err)
+		case step.Err != nil:
+			failed = true
+			fmt.Fprintf(out, "FAIL  %s: %v\n", step.Name, step.Err)
+		default:
+			fmt.Fprintf(out, "PASS  %s\n", step.Name)
+		}
+	}
+
+	switch {
+	case failed:
+		return xerrors.New("verification failed")
+	case skipped:
+		return xerrors.New("verification incomplete")
+	}
+
+	fmt.Fprintln(out, "verification passed")
+
+	return nil
+}
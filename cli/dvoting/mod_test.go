@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/suites"
+)
+
+func TestRun_Usage(t *testing.T) {
+	out := new(bytes.Buffer)
+
+	err := run([]string{"dvoting", "verify"}, out)
+	require.EqualError(t, err, `Required flag "bulletin" not set`)
+
+	err = run([]string{"dvoting", "verify", "--bulletin", "/does/not/exist"}, out)
+	require.Regexp(t, "^failed to open bulletin board: ", err)
+}
+
+func TestRun_Verify(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bulletin.ndjson")
+
+	file, err := os.Create(path)
+	require.NoError(t, err)
+
+	suite := suites.MustFind("Ed25519")
+
+	election := types.Election{
+		ElectionID: "abcd",
+		Pubkey:     suite.Point().Pick(suite.RandomStream()),
+		BallotSize: 29,
+	}
+
+	err = types.WriteBulletin(file, election)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	out := new(bytes.Buffer)
+
+	err = run([]string{"dvoting", "verify", "--bulletin", path}, out)
+	require.EqualError(t, err, "verification incomplete")
+
+	require.Equal(t, "election abcd (bulletin board version 1)\n"+
+		"PASS  public key\n"+
+		"SKIP  ballot proofs: the proofs are bound to the voter IDs, which are not published\n"+
+		"SKIP  shuffles: no shuffle has been submitted\n", out.String())
+}
@@ -10,14 +10,15 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"os"
 	"strconv"
-	"strings"
 	"time"
 
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/sign/schnorr"
 	"go.dedis.ch/kyber/v3/suites"
 
+	"github.com/dedis/d-voting/contracts/evoting"
 	"github.com/dedis/d-voting/contracts/evoting/types"
 	"github.com/dedis/d-voting/internal/testing/fake"
 	eproxy "github.com/dedis/d-voting/proxy"
@@ -166,18 +167,93 @@ func (a *RegisterAction) Execute(ctx node.Context) error {
 	router.HandleFunc("/evoting/elections/{electionID}", eproxy.AllowCORS).Methods("OPTIONS")
 	router.HandleFunc("/evoting/elections/{electionID}", ep.DeleteElection).Methods("DELETE")
 	router.HandleFunc("/evoting/elections/{electionID}/vote", ep.NewElectionVote).Methods("POST")
+	router.HandleFunc("/evoting/elections/{electionID}/voters", ep.EditVoters).Methods("POST", "PUT", "DELETE")
+	router.HandleFunc("/evoting/elections/{electionID}/voters", eproxy.AllowCORS).Methods("OPTIONS")
+	router.HandleFunc("/evoting/elections/{electionID}/results", ep.Results).Methods("GET")
+	router.HandleFunc("/evoting/elections/{electionID}/ballots/{receipt}", ep.Ballot).Methods("GET")
+	router.HandleFunc("/evoting/elections/{electionID}/bulletin", ep.Bulletin).Methods("GET")
+	router.HandleFunc("/evoting/elections/{electionID}/actions", ep.Actions).Methods("GET")
+	router.HandleFunc("/evoting/elections/{electionID}/actions", ep.NewAction).Methods("POST")
+	router.HandleFunc("/evoting/elections/{electionID}/actions", eproxy.AllowCORS).Methods("OPTIONS")
+	router.HandleFunc("/evoting/elections/{electionID}/actions/{actionID}", ep.ApproveAction).Methods("PUT")
+	router.HandleFunc("/evoting/elections/{electionID}/actions/{actionID}", eproxy.AllowCORS).Methods("OPTIONS")
+	router.HandleFunc("/evoting/transactions/{txID}", ep.Transaction).Methods("GET")
+	router.HandleFunc("/evoting/events", ep.Events).Methods("GET")
 
 	router.NotFoundHandler = http.HandlerFunc(eproxy.NotFoundHandler)
 	router.MethodNotAllowedHandler = http.HandlerFunc(eproxy.NotAllowedHandler)
 
 	proxy.RegisterHandler("/evoting/elections", router.ServeHTTP)
 	proxy.RegisterHandler("/evoting/elections/", router.ServeHTTP)
+	proxy.RegisterHandler("/evoting/transactions/", router.ServeHTTP)
+	proxy.RegisterHandler("/evoting/events", router.ServeHTTP)
+
+	// the proxy is stopped with the node
+	ctx.Injector.Inject(ep)
 
 	dela.Logger.Info().Msg("d-voting proxy handlers registered")
 
 	return nil
 }
 
+// exportBulletinAction is an action to export the bulletin board of an
+// election
+//
+// - implements node.ActionTemplate
+type exportBulletinAction struct{}
+
+// Execute implements node.ActionTemplate. It writes the bulletin board of the
+// election to the file given by the "out" flag, or to the output of the command
+// if the flag is not set.
+func (a *exportBulletinAction) Execute(ctx node.Context) error {
+	var orderingSvc ordering.Service
+	err := ctx.Injector.Resolve(&orderingSvc)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve ordering.Service: %v", err)
+	}
+
+	var rosterFac authority.Factory
+	err = ctx.Injector.Resolve(&rosterFac)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve authority factory: %v", err)
+	}
+
+	electionFac := types.NewElectionFactory(types.CiphervoteFactory{}, rosterFac)
+
+	election, err := getElection(sjson.NewContext(), electionFac,
+		ctx.Flags.String("electionID"), orderingSvc)
+	if err != nil {
+		return xerrors.Errorf(getElectionErr, err)
+	}
+
+	path := ctx.Flags.String("out")
+	if path == "" {
+		err = types.WriteBulletin(ctx.Out, election)
+		if err != nil {
+			return xerrors.Errorf("failed to write bulletin board: %v", err)
+		}
+
+		return nil
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return xerrors.Errorf("failed to create %q: %v", path, err)
+	}
+
+	defer file.Close()
+
+	err = types.WriteBulletin(file, election)
+	if err != nil {
+		return xerrors.Errorf("failed to write bulletin board: %v", err)
+	}
+
+	fmt.Fprintf(ctx.Out, "bulletin board of election %s written to %s\n",
+		election.ElectionID, path)
+
+	return nil
+}
+
 // getSigner creates a signer from a file.
 func getSigner(filePath string) (crypto.Signer, error) {
 	l := loader.NewFileLoader(filePath)
@@ -246,9 +322,15 @@ func (a *scenarioTestAction) Execute(ctx node.Context) error {
 	// Define the configuration
 	configuration := fake.BasicConfiguration
 
+	// The proxy's secret key is also used as the key of the election's admin
+	adminID, err := suite.Point().Mul(secret, nil).MarshalBinary()
+	if err != nil {
+		return xerrors.Errorf("failed to marshal admin public key: %v", err)
+	}
+
 	createSimpleElectionRequest := ptypes.CreateElectionRequest{
 		Configuration: configuration,
-		AdminID:       "adminId",
+		AdminID:       hex.EncodeToString(adminID),
 	}
 
 	signed, err := createSignedRequest(secret, createSimpleElectionRequest)
@@ -266,7 +348,7 @@ func (a *scenarioTestAction) Execute(ctx node.Context) error {
 		return xerrors.Errorf("failed retrieve the decryption from the server: %v", err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode != http.StatusAccepted {
 		buf, _ := ioutil.ReadAll(resp.Body)
 		return xerrors.Errorf("unexpected status: %s - %s", resp.Status, buf)
 	}
@@ -289,6 +371,11 @@ func (a *scenarioTestAction) Execute(ctx node.Context) error {
 
 	electionID := electionResponse.ElectionID
 
+	err = waitForTransaction(proxyAddr1, electionResponse.TransactionID)
+	if err != nil {
+		return xerrors.Errorf("failed to create election: %v", err)
+	}
+
 	electionIDBuf, err := hex.DecodeString(electionID)
 	if err != nil {
 		return xerrors.Errorf("failed to decode electionID '%s': %v", electionID, err)
@@ -345,7 +432,8 @@ func (a *scenarioTestAction) Execute(ctx node.Context) error {
 
 	fmt.Fprintf(ctx.Out, "Open election")
 
-	_, err = updateElection(secret, proxyAddr1, electionID, "open")
+	_, err = updateElection(secret, proxyAddr1, electionID, "open",
+		election.AdminNonce)
 	if err != nil {
 		return xerrors.Errorf("failed to open election: %v", err)
 	}
@@ -368,9 +456,11 @@ func (a *scenarioTestAction) Execute(ctx node.Context) error {
 
 	fmt.Fprintln(ctx.Out, "Close election")
 
-	status, err := updateElection(secret, proxyAddr1, electionID, "close")
-	if status != http.StatusInternalServerError {
-		return xerrors.Errorf("unexpected error: %d: %v", status, err)
+	// the transaction is rejected as the election has no ballot
+	_, err = updateElection(secret, proxyAddr1, electionID, "close",
+		election.AdminNonce)
+	if err == nil {
+		return xerrors.Errorf("the election should not be closed without ballots")
 	}
 
 	// ##################################### CAST BALLOTS ######################
@@ -399,7 +489,8 @@ func (a *scenarioTestAction) Execute(ctx node.Context) error {
 	}
 
 	// Ballot 1
-	ballot1, err := marshallBallot(b1, dkgActor, election.ChunksPerBallot())
+	ballot1, proof1, err := marshallBallot(b1, dkgActor, electionID, "user1",
+		election.ChunksPerBallot())
 	if err != nil {
 		return xerrors.Errorf("failed to marshall ballot : %v", err)
 	}
@@ -407,6 +498,7 @@ func (a *scenarioTestAction) Execute(ctx node.Context) error {
 	castVoteRequest := ptypes.CastVoteRequest{
 		UserID: "user1",
 		Ballot: ballot1,
+		Proof:  proof1,
 	}
 
 	signed, err = createSignedRequest(secret, castVoteRequest)
@@ -424,7 +516,8 @@ func (a *scenarioTestAction) Execute(ctx node.Context) error {
 	dela.Logger.Info().Msg("Response body: " + respBody)
 
 	// Ballot 2
-	ballot2, err := marshallBallot(b2, dkgActor, election.ChunksPerBallot())
+	ballot2, proof2, err := marshallBallot(b2, dkgActor, electionID, "user2",
+		election.ChunksPerBallot())
 	if err != nil {
 		return xerrors.Errorf("failed to marshall ballot : %v", err)
 	}
@@ -432,6 +525,7 @@ func (a *scenarioTestAction) Execute(ctx node.Context) error {
 	castVoteRequest = ptypes.CastVoteRequest{
 		UserID: "user2",
 		Ballot: ballot2,
+		Proof:  proof2,
 	}
 
 	signed, err = createSignedRequest(secret, castVoteRequest)
@@ -449,7 +543,8 @@ func (a *scenarioTestAction) Execute(ctx node.Context) error {
 	dela.Logger.Info().Msg("Response body: " + respBody)
 
 	// Ballot 3
-	ballot3, err := marshallBallot(b3, dkgActor, election.ChunksPerBallot())
+	ballot3, proof3, err := marshallBallot(b3, dkgActor, electionID, "user3",
+		election.ChunksPerBallot())
 	if err != nil {
 		return xerrors.Errorf("failed to marshall ballot: %v", err)
 	}
@@ -457,6 +552,7 @@ func (a *scenarioTestAction) Execute(ctx node.Context) error {
 	castVoteRequest = ptypes.CastVoteRequest{
 		UserID: "user3",
 		Ballot: ballot3,
+		Proof:  proof3,
 	}
 
 	signed, err = createSignedRequest(secret, castVoteRequest)
@@ -478,17 +574,15 @@ func (a *scenarioTestAction) Execute(ctx node.Context) error {
 		return xerrors.Errorf(getElectionErr, err)
 	}
 
-	encryptedBallots := election.Suffragia.Ciphervotes
-	dela.Logger.Info().Msg("Length encrypted ballots: " + strconv.Itoa(len(encryptedBallots)))
-	dela.Logger.Info().Msgf("Ballot of user1: %s", encryptedBallots[0])
-	dela.Logger.Info().Msgf("Ballot of user2: %s", encryptedBallots[1])
-	dela.Logger.Info().Msgf("Ballot of user3: %s", encryptedBallots[2])
+	// the ballots are stored under their own keys until the election closes
+	dela.Logger.Info().Msg("Number of ballots cast: " + strconv.Itoa(election.Suffragia.Count))
 
 	// ############################# CLOSE ELECTION FOR REAL ###################
 
 	fmt.Fprintln(ctx.Out, "Close election (for real)")
 
-	_, err = updateElection(secret, proxyAddr1, electionID, "close")
+	_, err = updateElection(secret, proxyAddr1, electionID, "close",
+		election.AdminNonce)
 	if err != nil {
 		return xerrors.Errorf("failed to close election: %v", err)
 	}
@@ -569,7 +663,8 @@ func (a *scenarioTestAction) Execute(ctx node.Context) error {
 
 	fmt.Fprintln(ctx.Out, "decrypt ballots")
 
-	_, err = updateElection(secret, proxyAddr1, electionID, "combineShares")
+	_, err = updateElection(secret, proxyAddr1, electionID, "combineShares",
+		election.AdminNonce)
 	if err != nil {
 		return xerrors.Errorf("failed to combine shares: %v", err)
 	}
@@ -637,36 +732,37 @@ func encodeID(ID string) types.ID {
 	return types.ID(base64.StdEncoding.EncodeToString([]byte(ID)))
 }
 
-func marshallBallot(voteStr string, actor dkg.Actor, chunks int) (ptypes.CiphervoteJSON, error) {
-
-	var ballot = make(ptypes.CiphervoteJSON, chunks)
-	vote := strings.NewReader(voteStr)
-
-	buf := make([]byte, 29)
+// marshallBallot encrypts the ballot with the public key of the DKG and proves
+// it for the user.
+func marshallBallot(voteStr string, actor dkg.Actor, electionID, userID string,
+	chunks int) (ptypes.CiphervoteJSON, []byte, error) {
 
-	for i := 0; i < chunks; i++ {
-		var K, C kyber.Point
-		var err error
+	pubkey, err := actor.GetPublicKey()
+	if err != nil {
+		return nil, nil, xerrors.Errorf("failed to get the public key: %v", err)
+	}
 
-		n, err := vote.Read(buf)
-		if err != nil {
-			return nil, xerrors.Errorf("failed to read: %v", err)
-		}
+	ciphervote, ks, err := types.EncryptBallot(pubkey, []byte(voteStr), chunks)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("failed to encrypt the plaintext: %v", err)
+	}
 
-		K, C, _, err = actor.Encrypt(buf[:n])
+	proof, err := types.ProveBallot(electionID, userID, ciphervote, ks)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("failed to prove the ballot: %v", err)
+	}
 
-		if err != nil {
-			return ptypes.CiphervoteJSON{}, xerrors.Errorf("failed to encrypt the plaintext: %v", err)
-		}
+	var ballot = make(ptypes.CiphervoteJSON, chunks)
 
-		kbuff, err := K.MarshalBinary()
+	for i, egpair := range ciphervote {
+		kbuff, err := egpair.K.MarshalBinary()
 		if err != nil {
-			return ptypes.CiphervoteJSON{}, xerrors.Errorf("failed to marshal K: %v", err)
+			return nil, nil, xerrors.Errorf("failed to marshal K: %v", err)
 		}
 
-		cbuff, err := C.MarshalBinary()
+		cbuff, err := egpair.C.MarshalBinary()
 		if err != nil {
-			return ptypes.CiphervoteJSON{}, xerrors.Errorf("failed to marshal C: %v", err)
+			return nil, nil, xerrors.Errorf("failed to marshal C: %v", err)
 		}
 
 		ballot[i] = ptypes.EGPairJSON{
@@ -675,17 +771,18 @@ func marshallBallot(voteStr string, actor dkg.Actor, chunks int) (ptypes.Cipherv
 		}
 	}
 
-	return ballot, nil
+	return ballot, proof, nil
 }
 
-// electionID is hex-encoded
+// castVote casts the vote and waits for its transaction to be accepted.
+// electionID is hex-encoded.
 func castVote(electionID string, signed []byte, proxyAddr string) (string, error) {
 	resp, err := http.Post(proxyAddr+"/evoting/elections/"+electionID+"/vote", contentType, bytes.NewBuffer(signed))
 	if err != nil {
 		return "", xerrors.Errorf("failed retrieve the decryption from the server: %v", err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode != http.StatusAccepted {
 		buf, _ := ioutil.ReadAll(resp.Body)
 		return "", xerrors.Errorf("unexpected status: %s - %s", resp.Status, buf)
 	}
@@ -697,12 +794,40 @@ func castVote(electionID string, signed []byte, proxyAddr string) (string, error
 
 	resp.Body.Close()
 
+	var response ptypes.CastVoteResponse
+
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return "", xerrors.Errorf("failed to decode response: %v", err)
+	}
+
+	err = waitForTransaction(proxyAddr, response.TransactionID)
+	if err != nil {
+		return "", xerrors.Errorf("failed to wait for transaction: %v", err)
+	}
+
 	return string(body), nil
 }
 
-func updateElection(secret kyber.Scalar, proxyAddr, electionIDHex, action string) (int, error) {
+func updateElection(secret kyber.Scalar, proxyAddr, electionIDHex, action string,
+	nonce uint64) (int, error) {
+
+	commands := map[string]evoting.Command{
+		"open":          evoting.CmdOpenElection,
+		"close":         evoting.CmdCloseElection,
+		"combineShares": evoting.CmdCombineShares,
+		"cancel":        evoting.CmdCancelElection,
+	}
+
+	adminSig, err := schnorr.Sign(suite, secret,
+		types.AdminMessage(string(commands[action]), electionIDHex, nil, nonce))
+	if err != nil {
+		return 0, xerrors.Errorf("failed to sign admin message: %v", err)
+	}
+
 	msg := ptypes.UpdateElectionRequest{
-		Action: action,
+		Action:         action,
+		AdminSignature: adminSig,
 	}
 
 	signed, err := createSignedRequest(secret, msg)
@@ -720,14 +845,59 @@ func updateElection(secret kyber.Scalar, proxyAddr, electionIDHex, action string
 		return 0, xerrors.Errorf("failed retrieve the decryption from the server: %v", err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode != http.StatusAccepted {
 		buf, _ := ioutil.ReadAll(resp.Body)
 		return resp.StatusCode, xerrors.Errorf("unexpected status: %s - %s", resp.Status, buf)
 	}
 
+	var txResponse ptypes.TransactionResponse
+
+	err = json.NewDecoder(resp.Body).Decode(&txResponse)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to decode response: %v", err)
+	}
+
+	resp.Body.Close()
+
+	err = waitForTransaction(proxyAddr, txResponse.TransactionID)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to wait for transaction: %v", err)
+	}
+
 	return 0, nil
 }
 
+// waitForTransaction polls the status of the transaction until it is accepted
+// or rejected.
+func waitForTransaction(proxyAddr, txID string) error {
+	for i := 0; i < 50; i++ {
+		resp, err := http.Get(proxyAddr + "/evoting/transactions/" + txID)
+		if err != nil {
+			return xerrors.Errorf("failed to get transaction: %v", err)
+		}
+
+		var status ptypes.GetTransactionResponse
+
+		err = json.NewDecoder(resp.Body).Decode(&status)
+		resp.Body.Close()
+
+		if err != nil {
+			return xerrors.Errorf("failed to decode transaction status: %v", err)
+		}
+
+		switch status.Status {
+		case ptypes.TransactionAccepted:
+			return nil
+		case ptypes.TransactionRejected:
+			return xerrors.Errorf("transaction rejected: %s", status.Message)
+		}
+
+		time.Sleep(200 * time.Millisecond)
+	}
+
+	return xerrors.Errorf("transaction %s is still pending", txID)
+}
+
 func initDKG(secret kyber.Scalar, proxyAddr, electionIDHex string) error {
 	setupDKG := ptypes.NewDKGRequest{
 		ElectionID: electionIDHex,
@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dedis/d-voting/internal/testing/fake"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/cli/node"
+)
+
+func TestExportBulletinAction_Execute(t *testing.T) {
+	action := exportBulletinAction{}
+
+	ctx := node.Context{
+		Injector: node.NewInjector(),
+		Flags:    node.FlagSet{"electionID": "abcd"},
+		Out:      new(bytes.Buffer),
+	}
+
+	err := action.Execute(ctx)
+	require.EqualError(t, err, "failed to resolve ordering.Service: couldn't "+
+		"find dependency for 'ordering.Service'")
+
+	ctx.Injector.Inject(&fake.Service{})
+
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "failed to resolve authority factory: couldn't "+
+		"find dependency for 'authority.Factory'")
+}
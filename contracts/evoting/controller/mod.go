@@ -1,13 +1,27 @@
 package controller
 
 import (
+	"path/filepath"
+
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	eproxy "github.com/dedis/d-voting/proxy"
+	"github.com/dedis/d-voting/services/scheduler"
 	"go.dedis.ch/dela/cli"
 	"go.dedis.ch/dela/cli/node"
 	"go.dedis.ch/dela/core/access"
 	"go.dedis.ch/dela/core/ordering"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/txn/pool"
 	"go.dedis.ch/dela/core/validation"
+	"go.dedis.ch/dela/mino"
+	sjson "go.dedis.ch/dela/serde/json"
+	"golang.org/x/xerrors"
 )
 
+// privateKeyFile is the name of the file containing the node's private key in
+// the config folder.
+const privateKeyFile = "private.key"
+
 // NewController returns a new controller initializer
 func NewController() node.Initializer {
 	return controller{}
@@ -64,15 +78,97 @@ func (m controller) SetCommands(builder node.Builder) {
 		},
 	)
 	sub.SetAction(builder.MakeAction(&scenarioTestAction{}))
+
+	// memcoin --config /tmp/node1 e-voting exportBulletin \
+	//   --electionID <hex> --out /tmp/bulletin.ndjson
+	sub = cmd.SetSubCommand("exportBulletin")
+	sub.SetDescription("export the bulletin board of an election")
+	sub.SetFlags(
+		cli.StringFlag{
+			Name:     "electionID",
+			Usage:    "the hex-encoded ID of the election",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  "out",
+			Usage: "path of the file to write, the output of the command if empty",
+		},
+	)
+	sub.SetAction(builder.MakeAction(&exportBulletinAction{}))
 }
 
-// OnStart implements node.Initializer. It creates and registers a pedersen DKG.
+// OnStart implements node.Initializer. It starts the scheduler that opens and
+// closes the elections according to their schedule, using the node's identity.
 func (m controller) OnStart(ctx cli.Flags, inj node.Injector) error {
+	var p pool.Pool
+	err := inj.Resolve(&p)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve pool.Pool: %v", err)
+	}
+
+	var no mino.Mino
+	err = inj.Resolve(&no)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve mino.Mino: %v", err)
+	}
+
+	var orderingSvc ordering.Service
+	err = inj.Resolve(&orderingSvc)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve ordering.Service: %v", err)
+	}
+
+	var validation validation.Service
+	err = inj.Resolve(&validation)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve validation: %v", err)
+	}
+
+	var rosterFac authority.Factory
+	err = inj.Resolve(&rosterFac)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve authority factory: %v", err)
+	}
+
+	signer, err := getSigner(filepath.Join(ctx.Path("config"), privateKeyFile))
+	if err != nil {
+		return xerrors.Errorf("failed to get the signer: %v", err)
+	}
+
+	client := client{
+		srvc: orderingSvc,
+		mgr:  validation,
+	}
+
+	electionFac := types.NewElectionFactory(types.CiphervoteFactory{}, rosterFac)
+
+	sched := scheduler.NewScheduler(orderingSvc, p, getManager(signer, client), signer,
+		no.GetAddress(), sjson.NewContext(), electionFac, scheduler.DefaultInterval)
+
+	sched.Start()
+
+	inj.Inject(sched)
+
 	return nil
 }
 
-// OnStop implements node.Initializer.
-func (controller) OnStop(node.Injector) error {
+// OnStop implements node.Initializer. It stops the scheduler, and the election
+// proxy if its handlers have been registered.
+func (controller) OnStop(inj node.Injector) error {
+	var sched *scheduler.Scheduler
+	err := inj.Resolve(&sched)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve scheduler: %v", err)
+	}
+
+	sched.Stop()
+
+	var ep eproxy.Election
+	err = inj.Resolve(&ep)
+	if err == nil {
+		ep.Stop()
+	}
+
 	return nil
 }
 
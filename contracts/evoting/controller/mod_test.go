@@ -8,11 +8,13 @@ import (
 )
 
 func TestController_OnStart(t *testing.T) {
-	err := NewController().OnStart(node.FlagSet{}, nil)
-	require.Nil(t, err)
+	err := NewController().OnStart(node.FlagSet{}, node.NewInjector())
+	require.EqualError(t, err, "failed to resolve pool.Pool: couldn't find "+
+		"dependency for 'pool.Pool'")
 }
 
 func TestController_OnStop(t *testing.T) {
-	err := NewController().OnStop(nil)
-	require.Nil(t, err)
+	err := NewController().OnStop(node.NewInjector())
+	require.EqualError(t, err, "failed to resolve scheduler: couldn't find "+
+		"dependency for '*scheduler.Scheduler'")
 }
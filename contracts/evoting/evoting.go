@@ -4,15 +4,17 @@ package evoting
 
 import (
 	"bytes"
+	"crypto/cipher"
 	"crypto/sha256"
 	"encoding/binary"
 	"encoding/hex"
-	"encoding/json"
 	"math/rand"
 	"strings"
+	"time"
 
 	"go.dedis.ch/dela"
 
+	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/share"
 
 	"github.com/dedis/d-voting/contracts/evoting/types"
@@ -22,10 +24,13 @@ import (
 	"go.dedis.ch/dela/core/store"
 	"go.dedis.ch/dela/core/txn"
 	"go.dedis.ch/dela/cosi/threshold"
+	"go.dedis.ch/dela/crypto"
 	"go.dedis.ch/dela/crypto/bls"
+	"go.dedis.ch/dela/mino"
 	"go.dedis.ch/dela/serde"
 	"go.dedis.ch/kyber/v3/proof"
 	"go.dedis.ch/kyber/v3/shuffle"
+	"go.dedis.ch/kyber/v3/sign/schnorr"
 	"golang.org/x/xerrors"
 )
 
@@ -79,14 +84,47 @@ func (e evotingCommand) createElection(snap store.Snapshot, step execution.Step)
 		return xerrors.Errorf("configuration of election is incoherent or has duplicated IDs")
 	}
 
+	_, err = types.ParseAdminID(tx.AdminID)
+	if err != nil {
+		return xerrors.Errorf("invalid admin ID: %v", err)
+	}
+
+	admins := types.AdminSet{
+		Keys:      []string{tx.AdminID},
+		Threshold: tx.AdminThreshold,
+	}
+
+	for _, key := range tx.Admins {
+		if key != tx.AdminID {
+			admins.Keys = append(admins.Keys, key)
+		}
+	}
+
+	if admins.Threshold == 0 {
+		admins.Threshold = 1
+	}
+
+	err = admins.Verify()
+	if err != nil {
+		return xerrors.Errorf("invalid admins: %v", err)
+	}
+
+	shuffleThreshold, decryptionThreshold, err := electionThresholds(tx, roster.Len())
+	if err != nil {
+		return xerrors.Errorf("invalid thresholds: %v", err)
+	}
+
 	units := types.PubsharesUnits{
 		Pubshares: make([]types.PubsharesUnit, 0),
 		PubKeys:   make([][]byte, 0),
 		Indexes:   make([]int, 0),
+		Proofs:    make([]types.PubshareProofsUnit, 0),
 	}
 
 	election := types.Election{
 		ElectionID:    hex.EncodeToString(electionIDBuf),
+		AdminID:       tx.AdminID,
+		Admins:        admins,
 		Configuration: tx.Configuration,
 		Status:        types.Initial,
 		// Pubkey is set by the opening command
@@ -95,55 +133,65 @@ func (e evotingCommand) createElection(snap store.Snapshot, step execution.Step)
 		PubsharesUnits:   units,
 		ShuffleInstances: []types.ShuffleInstance{},
 		DecryptedBallots: []types.Ballot{},
-		// We set the participant in the e-voting once for all. If it happens
-		// that 1/3 of the participants go away, the election will never end.
-		Roster:           roster,
-		ShuffleThreshold: threshold.ByzantineThreshold(roster.Len()),
+		// The participants of the e-voting are replaced only after the DKG
+		// has been reshared to them, see UPDATE_ROSTER.
+		Roster:              roster,
+		ShuffleThreshold:    shuffleThreshold,
+		DecryptionThreshold: decryptionThreshold,
+
+		RandomVectorVersion: types.CurrentRandomVectorVersion,
 	}
 
 	PromElectionStatus.WithLabelValues(election.ElectionID).Set(float64(election.Status))
 
-	electionBuf, err := election.Serialize(e.context)
+	election.CatalogIndex, err = e.addToCatalog(snap, election)
 	if err != nil {
-		return xerrors.Errorf("failed to marshal Election : %v", err)
+		return xerrors.Errorf("failed to add election to the catalog: %v", err)
 	}
 
-	err = snap.Set(electionIDBuf, electionBuf)
+	err = e.saveElection(snap, election, electionIDBuf)
 	if err != nil {
-		return xerrors.Errorf("failed to set value: %v", err)
+		return xerrors.Errorf("failed to save election: %v", err)
 	}
 
-	// Update the election metadata store
+	return nil
+}
 
-	electionsMetadataBuf, err := snap.Get([]byte(ElectionsMetadataKey))
-	if err != nil {
-		return xerrors.Errorf("failed to get key '%s': %v", electionsMetadataBuf, err)
-	}
+// electionThresholds returns the shuffle and decryption thresholds of a new
+// election on a roster of n nodes. They default to the Byzantine threshold. A
+// configured threshold must tolerate the faulty nodes, both ways: they can't
+// reach it on their own, and they can't prevent the others from reaching it.
+func electionThresholds(tx types.CreateElection, n int) (int, int, error) {
+	shuffleThreshold := threshold.ByzantineThreshold(n)
+	decryptionThreshold := threshold.ByzantineThreshold(n)
 
-	electionsMetadata := &types.ElectionsMetadata{
-		ElectionsIDs: types.ElectionIDs{},
-	}
+	max := types.MaxThreshold(n)
 
-	if len(electionsMetadataBuf) != 0 {
-		err := json.Unmarshal(electionsMetadataBuf, electionsMetadata)
-		if err != nil {
-			return xerrors.Errorf("failed to unmarshal ElectionsMetadata: %v", err)
+	if tx.ShuffleThreshold != 0 {
+		if tx.ShuffleThreshold < types.MinThreshold(n) || tx.ShuffleThreshold > max {
+			return 0, 0, xerrors.Errorf("shuffle threshold %d is not in [%d, %d]",
+				tx.ShuffleThreshold, types.MinThreshold(n), max)
 		}
+
+		shuffleThreshold = tx.ShuffleThreshold
 	}
 
-	electionsMetadata.ElectionsIDs.Add(election.ElectionID)
+	if tx.DecryptionThreshold != 0 {
+		// the DKG needs at least 2 shares
+		min := types.MinThreshold(n)
+		if min < 2 {
+			min = 2
+		}
 
-	electionMetadataJSON, err := json.Marshal(electionsMetadata)
-	if err != nil {
-		return xerrors.Errorf("failed to marshal ElectionsMetadata: %v", err)
-	}
+		if tx.DecryptionThreshold < min || tx.DecryptionThreshold > max {
+			return 0, 0, xerrors.Errorf("decryption threshold %d is not in [%d, %d]",
+				tx.DecryptionThreshold, min, max)
+		}
 
-	err = snap.Set([]byte(ElectionsMetadataKey), electionMetadataJSON)
-	if err != nil {
-		return xerrors.Errorf("failed to set value: %v", err)
+		decryptionThreshold = tx.DecryptionThreshold
 	}
 
-	return nil
+	return shuffleThreshold, decryptionThreshold, nil
 }
 
 // openElection set the public key on the election. The public key is fetched
@@ -169,6 +217,32 @@ func (e evotingCommand) openElection(snap store.Snapshot, step execution.Step) e
 		return xerrors.Errorf("the election was opened before, current status: %d", election.Status)
 	}
 
+	// A scheduled election is opened by the nodes, without the signature of
+	// the admin.
+	scheduled := len(tx.AdminSignature) == 0 && election.Configuration.Schedule.Start != 0
+
+	if !scheduled {
+		err = checkAdmin(&election, CmdOpenElection, nil, tx.AdminSignature)
+		if err != nil {
+			return xerrors.Errorf("failed to check admin: %v", err)
+		}
+	}
+
+	now, err := e.chainTime(snap, election)
+	if err != nil {
+		return xerrors.Errorf("failed to get time: %v", err)
+	}
+
+	if !election.Configuration.Schedule.HasStarted(now) {
+		return xerrors.Errorf("the election cannot be opened before %d",
+			election.Configuration.Schedule.Start)
+	}
+
+	// The turnout is computed against the registered voters
+	if election.Configuration.Quorum.MinTurnout != 0 && !election.Electorate.Restricted {
+		return xerrors.Errorf("a turnout quorum requires a registered electorate")
+	}
+
 	election.Status = types.Open
 	PromElectionStatus.WithLabelValues(election.ElectionID).Set(float64(election.Status))
 
@@ -176,40 +250,55 @@ func (e evotingCommand) openElection(snap store.Snapshot, step execution.Step) e
 		return xerrors.Errorf("pubkey is already set: %s", election.Pubkey)
 	}
 
-	dkgActor, exists := e.pedersen.GetActor(electionID)
-	if !exists {
-		return xerrors.Errorf("failed to get actor for election %q", election.ElectionID)
+	result, agreed := types.AgreedDKG(election.DKGResults, election.Roster.Len())
+	if !agreed {
+		return xerrors.Errorf("the participants of the dkg didn't agree on its result yet")
 	}
 
-	pubkey, err := dkgActor.GetPublicKey()
+	pubkey, verificationKeys, err := result.Keys()
 	if err != nil {
-		return xerrors.Errorf("failed to get pubkey: %v", err)
+		return xerrors.Errorf("failed to get the keys of the dkg: %v", err)
 	}
 
-	election.Pubkey = pubkey
-
-	electionBuf, err := election.Serialize(e.context)
+	// the nodes left out of the DKG by a retry have no share, so that the
+	// election runs on the participants only, in the order of their index
+	election.Roster, err = dkgRoster(election.Roster, result.Participants)
 	if err != nil {
-		return xerrors.Errorf("failed to marshal Election : %v", err)
+		return xerrors.Errorf("failed to get the roster of the dkg: %v", err)
 	}
 
-	err = snap.Set(electionID, electionBuf)
+	n := len(result.Participants)
+
+	if election.ShuffleThreshold < types.MinThreshold(n) ||
+		election.ShuffleThreshold > types.MaxThreshold(n) {
+
+		return xerrors.Errorf("shuffle threshold %d is not in [%d, %d]",
+			election.ShuffleThreshold, types.MinThreshold(n), types.MaxThreshold(n))
+	}
+
+	election.Pubkey = pubkey
+	election.VerificationKeys = verificationKeys
+	election.DecryptionThreshold = len(result.Commits)
+	election.DKGResults = nil
+
+	err = e.saveElection(snap, election, electionID)
 	if err != nil {
-		return xerrors.Errorf("failed to set value: %v", err)
+		return xerrors.Errorf("failed to save election: %v", err)
 	}
 
 	return nil
 }
 
-// castVote implements commands. It performs the CAST_VOTE command
-func (e evotingCommand) castVote(snap store.Snapshot, step execution.Step) error {
+// updateConfiguration implements commands. It performs the
+// UPDATE_CONFIGURATION command
+func (e evotingCommand) updateConfiguration(snap store.Snapshot, step execution.Step) error {
 
 	msg, err := e.getTransaction(step.Current)
 	if err != nil {
 		return xerrors.Errorf(errGetTransaction, err)
 	}
 
-	tx, ok := msg.(types.CastVote)
+	tx, ok := msg.(types.UpdateConfiguration)
 	if !ok {
 		return xerrors.Errorf(errWrongTx, msg)
 	}
@@ -219,240 +308,309 @@ func (e evotingCommand) castVote(snap store.Snapshot, step execution.Step) error
 		return xerrors.Errorf(errGetElection, err)
 	}
 
-	if election.Status != types.Open {
-		return xerrors.Errorf("the election is not open, current status: %d", election.Status)
+	if election.Status != types.Initial {
+		return xerrors.Errorf("the configuration can only be updated before "+
+			"the election is open, current status: %d", election.Status)
 	}
 
-	if len(tx.Ballot) != election.ChunksPerBallot() {
-		return xerrors.Errorf("the ballot has unexpected length: %d != %d",
-			len(tx.Ballot), election.ChunksPerBallot())
+	err = checkAdmin(&election, CmdUpdateConfiguration, tx.AdminPayload(), tx.AdminSignature)
+	if err != nil {
+		return xerrors.Errorf("failed to check admin: %v", err)
 	}
 
-	election.Suffragia.CastVote(tx.UserID, tx.Ballot)
-
-	electionBuf, err := election.Serialize(e.context)
+	configuration, err := tx.GetConfiguration()
 	if err != nil {
-		return xerrors.Errorf("failed to marshal Election : %v", err)
+		return xerrors.Errorf("failed to get configuration: %v", err)
 	}
 
-	err = snap.Set(electionID, electionBuf)
-	if err != nil {
-		return xerrors.Errorf("failed to set value: %v", err)
+	if !configuration.IsValid() {
+		return xerrors.Errorf("configuration of election is incoherent or has duplicated IDs")
 	}
 
-	PromElectionBallots.WithLabelValues(election.ElectionID).Set(float64(len(election.Suffragia.Ciphervotes)))
+	election.Configuration = configuration
+	election.BallotSize = configuration.MaxBallotSize()
+
+	err = e.saveElection(snap, election, electionID)
+	if err != nil {
+		return xerrors.Errorf("failed to save election: %v", err)
+	}
 
 	return nil
 }
 
-// shuffleBallots implements commands. It performs the SHUFFLE_BALLOTS command
-func (e evotingCommand) shuffleBallots(snap store.Snapshot, step execution.Step) error {
+// updateRoster implements commands. It performs the UPDATE_ROSTER command. The
+// roster of the election becomes the roster of the chain, to which the shares
+// of the DKG must have been reshared. Like at the opening, the verification
+// keys are the ones of the result of the resharing registered by enough of its
+// participants, see REGISTER_DKG.
+func (e evotingCommand) updateRoster(snap store.Snapshot, step execution.Step) error {
 
 	msg, err := e.getTransaction(step.Current)
 	if err != nil {
 		return xerrors.Errorf(errGetTransaction, err)
 	}
 
-	tx, ok := msg.(types.ShuffleBallots)
+	tx, ok := msg.(types.UpdateRoster)
 	if !ok {
 		return xerrors.Errorf(errWrongTx, msg)
 	}
 
-	err = e.checkPreviousTransactions(step, tx.Round)
-	if err != nil {
-		return xerrors.Errorf("check previous transactions failed: %v", err)
-	}
-
 	election, electionID, err := e.getElection(tx.ElectionID, snap)
 	if err != nil {
 		return xerrors.Errorf(errGetElection, err)
 	}
 
-	if election.Status != types.Closed {
-		return xerrors.Errorf("the election is not closed")
+	switch election.Status {
+	case types.Open, types.Closed, types.ShuffledBallots:
+	default:
+		return xerrors.Errorf("the roster can only be updated between the "+
+			"opening and the decryption, current status: %d", election.Status)
 	}
 
-	// Round starts at 0
-	expectedRound := len(election.ShuffleInstances)
+	// the pubshares are verified against the verification keys of the DKG
+	// they were computed with
+	if len(election.PubsharesUnits.Pubshares) != 0 {
+		return xerrors.Errorf("the roster can't be updated once pubshares are submitted")
+	}
 
-	if tx.Round != expectedRound {
-		return xerrors.Errorf("wrong shuffle round: expected round '%d', "+
-			"transaction is for round '%d'", expectedRound, tx.Round)
+	err = checkAdmin(&election, CmdUpdateRoster, nil, tx.AdminSignature)
+	if err != nil {
+		return xerrors.Errorf("failed to check admin: %v", err)
 	}
 
-	shufflerPublicKey := tx.PublicKey
+	rosterBuf, err := snap.Get(e.rosterKey)
+	if err != nil {
+		return xerrors.Errorf("failed to get roster")
+	}
 
-	err = isMemberOf(election.Roster, shufflerPublicKey)
+	roster, err := e.rosterFac.AuthorityOf(e.context, rosterBuf)
 	if err != nil {
-		return xerrors.Errorf("could not verify identity of shuffler : %v", err)
+		return xerrors.Errorf("failed to get roster: %v", err)
 	}
 
-	// Check the node who submitted the shuffle did not already submit an
-	// accepted shuffle
-	for i, shuffleInstance := range election.ShuffleInstances {
-		if bytes.Equal(shufflerPublicKey, shuffleInstance.ShufflerPublicKey) {
-			return xerrors.Errorf("a node already submitted a shuffle that "+
-				"has been accepted in round %d", i)
-		}
+	n := roster.Len()
+
+	if election.ShuffleThreshold < types.MinThreshold(n) ||
+		election.ShuffleThreshold > types.MaxThreshold(n) {
+
+		return xerrors.Errorf("shuffle threshold %d is not in [%d, %d]",
+			election.ShuffleThreshold, types.MinThreshold(n), types.MaxThreshold(n))
 	}
 
-	// Check the shuffler indeed signed the transaction:
-	signerPubKey, err := bls.NewPublicKey(tx.PublicKey)
-	if err != nil {
-		return xerrors.Errorf("could not decode public key of signer : %v ", err)
+	result, agreed := types.AgreedDKG(election.DKGResults, n)
+	if !agreed {
+		return xerrors.Errorf("the participants of the resharing didn't agree " +
+			"on its result yet")
 	}
 
-	txSignature := tx.Signature
+	// the result was checked when it was registered, but the roster of the
+	// chain may have changed since
+	if len(result.Participants) != n {
+		return xerrors.Errorf("the dkg doesn't run on the roster: %d "+
+			"participants != %d nodes", len(result.Participants), n)
+	}
 
-	signature, err := bls.NewSignatureFactory().SignatureOf(e.context, txSignature)
+	participants, err := dkgRoster(roster, result.Participants)
 	if err != nil {
-		return xerrors.Errorf("could node deserialize shuffle signature : %v", err)
+		return xerrors.Errorf("failed to get the roster of the dkg: %v", err)
 	}
 
-	h := sha256.New()
-
-	err = tx.Fingerprint(h)
+	_, verificationKeys, err := result.Keys()
 	if err != nil {
-		return xerrors.Errorf("failed to get fingerprint: %v", err)
+		return xerrors.Errorf("failed to get the keys of the dkg: %v", err)
 	}
 
-	hash := h.Sum(nil)
+	election.Roster = participants
+	election.VerificationKeys = verificationKeys
+	election.DKGResults = nil
 
-	// Check the signature matches the shuffle using the shuffler's public key
-	err = signerPubKey.Verify(hash, signature)
+	err = e.saveElection(snap, election, electionID)
 	if err != nil {
-		return xerrors.Errorf("signature does not match the Shuffle : %v", err)
+		return xerrors.Errorf("failed to save election: %v", err)
 	}
 
-	// Retrieve the random vector (ie the Scalar vector)
-	randomVector, err := tx.RandomVector.Unmarshal()
+	return nil
+}
+
+// registerDKG implements commands. It performs the REGISTER_DKG command. A
+// participant of the DKG registers the result it certified, so that the
+// election is opened, or its roster updated after a resharing, with the result
+// agreed by the participants rather than with the state of a single node, see
+// types.AgreedDKG.
+func (e evotingCommand) registerDKG(snap store.Snapshot, step execution.Step) error {
+	msg, err := e.getTransaction(step.Current)
 	if err != nil {
-		return xerrors.Errorf("failed to unmarshal random vector: %v", err)
+		return xerrors.Errorf(errGetTransaction, err)
 	}
 
-	// Check that the random vector is correct
-	semiRandomStream, err := NewSemiRandomStream(hash)
-	if err != nil {
-		return xerrors.Errorf("could not create semi-random stream: %v", err)
+	tx, ok := msg.(types.RegisterDKG)
+	if !ok {
+		return xerrors.Errorf(errWrongTx, msg)
 	}
 
-	if election.ChunksPerBallot() != len(randomVector) {
-		return xerrors.Errorf("randomVector has unexpected length : %v != %v",
-			len(randomVector), election.ChunksPerBallot())
+	election, electionID, err := e.getElection(tx.ElectionID, snap)
+	if err != nil {
+		return xerrors.Errorf(errGetElection, err)
 	}
 
-	for i := 0; i < election.ChunksPerBallot(); i++ {
-		v := suite.Scalar().Pick(semiRandomStream)
-		if !randomVector[i].Equal(v) {
-			return xerrors.Errorf("random vector from shuffle transaction is " +
-				"different than expected random vector")
+	var roster authority.Authority
+
+	switch election.Status {
+	case types.Initial:
+		// the DKG runs on the roster of the election, or on part of it after
+		// a retry
+		roster = election.Roster
+	case types.Open, types.Closed, types.ShuffledBallots:
+		// the DKG is reshared to the roster of the chain
+		if len(election.PubsharesUnits.Pubshares) != 0 {
+			return xerrors.Errorf("the dkg can't be reshared once pubshares are submitted")
+		}
+
+		rosterBuf, err := snap.Get(e.rosterKey)
+		if err != nil {
+			return xerrors.Errorf("failed to get roster")
+		}
+
+		roster, err = e.rosterFac.AuthorityOf(e.context, rosterBuf)
+		if err != nil {
+			return xerrors.Errorf("failed to get roster: %v", err)
 		}
+	default:
+		return xerrors.Errorf("the dkg can't be registered, current status: %d",
+			election.Status)
 	}
 
-	if len(tx.ShuffledBallots) == 0 {
-		return xerrors.Errorf("there are no shuffled ballots")
+	addr, err := addressOf(roster, tx.PublicKey)
+	if err != nil {
+		return xerrors.Errorf("could not verify identity of node: %v", err)
+	}
+
+	signerPubKey, err := bls.NewPublicKey(tx.PublicKey)
+	if err != nil {
+		return xerrors.Errorf("could not recover public key from tx: %v", err)
 	}
 
-	XX, YY := types.CiphervotesToPairs(tx.ShuffledBallots)
+	signature, err := bls.NewSignatureFactory().SignatureOf(e.context, tx.Signature)
+	if err != nil {
+		return xerrors.Errorf("could not deserialize signature: %v", err)
+	}
 
-	var ciphervotes []types.Ciphervote
+	h := sha256.New()
 
-	if tx.Round == 0 {
-		ciphervotes = election.Suffragia.Ciphervotes
-	} else {
-		// get the election's last shuffled ballots
-		lastIndex := len(election.ShuffleInstances) - 1
-		ciphervotes = election.ShuffleInstances[lastIndex].ShuffledBallots
+	err = tx.Fingerprint(h)
+	if err != nil {
+		return xerrors.Errorf("failed to get fingerprint: %v", err)
 	}
 
-	if len(ciphervotes) < 2 {
-		return xerrors.Errorf("not enough votes: %d < 2", len(ciphervotes))
+	err = signerPubKey.Verify(h.Sum(nil), signature)
+	if err != nil {
+		return xerrors.Errorf("signature does not match the dkg: %v", err)
 	}
 
-	X, Y := types.CiphervotesToPairs(ciphervotes)
+	result := types.DKGResult{
+		PublicKey:    tx.PublicKey,
+		Commits:      tx.Commits,
+		Participants: tx.Participants,
+	}
 
-	XXUp, YYUp, XXDown, YYDown := shuffle.GetSequenceVerifiable(suite, X, Y, XX,
-		YY, randomVector)
+	err = checkDKGResult(election, roster, addr, result)
+	if err != nil {
+		return xerrors.Errorf("invalid dkg: %v", err)
+	}
 
-	verifier := shuffle.Verifier(suite, nil, election.Pubkey, XXUp, YYUp, XXDown, YYDown)
+	// a node registers a new result when the DKG is retried or reshared
+	registered := false
 
-	err = e.prover(suite, shufflingProtocolName, verifier, tx.Proof)
-	if err != nil {
-		return xerrors.Errorf("proof verification failed: %v", err)
+	for i, other := range election.DKGResults {
+		if bytes.Equal(other.PublicKey, tx.PublicKey) {
+			election.DKGResults[i] = result
+			registered = true
+		}
 	}
 
-	// append the new shuffled ballots and the proof to the lists
-	currentShuffleInstance := types.ShuffleInstance{
-		ShuffledBallots:   tx.ShuffledBallots,
-		ShuffleProofs:     tx.Proof,
-		ShufflerPublicKey: shufflerPublicKey,
+	if !registered {
+		election.DKGResults = append(election.DKGResults, result)
 	}
 
-	election.ShuffleInstances = append(election.ShuffleInstances, currentShuffleInstance)
+	err = e.saveElection(snap, election, electionID)
+	if err != nil {
+		return xerrors.Errorf("failed to save election: %v", err)
+	}
 
-	PromElectionShufflingInstances.WithLabelValues(election.ElectionID).Set(float64(len(election.ShuffleInstances)))
+	return nil
+}
 
-	// in case we have enough shuffled ballots, we update the status
-	if len(election.ShuffleInstances) >= election.ShuffleThreshold {
-		election.Status = types.ShuffledBallots
-		PromElectionStatus.WithLabelValues(election.ElectionID).Set(float64(election.Status))
+// checkDKGResult checks that the result registered by the node at the address
+// can be used by the election: the node participates in the DKG, which runs on
+// members of the roster with the threshold of the election. A resharing runs
+// on the whole roster and keeps the public key of the election.
+func checkDKGResult(election types.Election, roster authority.Authority,
+	addr mino.Address, result types.DKGResult) error {
+
+	pubkey, _, err := result.Keys()
+	if err != nil {
+		return xerrors.Errorf("failed to get the keys: %v", err)
 	}
 
-	electionBuf, err := election.Serialize(e.context)
+	_, err = dkgRoster(roster, result.Participants)
 	if err != nil {
-		return xerrors.Errorf("failed to marshal Election : %v", err)
+		return xerrors.Errorf("failed to get the roster: %v", err)
 	}
 
-	err = snap.Set(electionID, electionBuf)
+	text, err := addr.MarshalText()
 	if err != nil {
-		return xerrors.Errorf("failed to set value: %v", err)
+		return xerrors.Errorf("failed to marshal address: %v", err)
 	}
 
-	return nil
-}
+	if !result.HasParticipant(text) {
+		return xerrors.Errorf("%s is not a participant", addr)
+	}
 
-// checkPreviousTransactions checks if a ShuffleBallotsTransaction has already
-// been accepted and executed for a specific round.
-func (e evotingCommand) checkPreviousTransactions(step execution.Step, round int) error {
-	for _, tx := range step.Previous {
-		// skip tx not concerning the evoting contract
-		if string(tx.GetArg(native.ContractArg)) != ContractName {
-			continue
-		}
+	threshold := len(result.Commits)
 
-		// skip tx that does not contain the election argument
-		if string(tx.GetArg(CmdArg)) != ElectionArg {
-			continue
-		}
+	if len(result.Participants) < threshold {
+		return xerrors.Errorf("the threshold is above the number of "+
+			"participants: %d > %d", threshold, len(result.Participants))
+	}
 
-		msg, err := e.getTransaction(step.Current)
-		if err != nil {
-			return xerrors.Errorf(errGetTransaction, err)
+	// the elections created before the decryption threshold existed take the
+	// one of the DKG
+	if election.Status == types.Initial {
+		if election.DecryptionThreshold != 0 && threshold != election.DecryptionThreshold {
+			return xerrors.Errorf("the threshold is not the one of the "+
+				"election: %d != %d", threshold, election.DecryptionThreshold)
 		}
 
-		// skip if not a shuffling ballot transaction
-		shuffleBallots, ok := msg.(types.ShuffleBallots)
-		if !ok {
-			continue
-		}
+		return nil
+	}
 
-		if shuffleBallots.Round == round {
-			return xerrors.Errorf("shuffle is already happening in this round")
-		}
+	if !pubkey.Equal(election.Pubkey) {
+		return xerrors.Errorf("the public key is not the one of the "+
+			"election: %s != %s", pubkey, election.Pubkey)
+	}
+
+	if threshold != election.DecryptionThreshold {
+		return xerrors.Errorf("the threshold is not the one of the "+
+			"election: %d != %d", threshold, election.DecryptionThreshold)
+	}
+
+	if len(result.Participants) != roster.Len() {
+		return xerrors.Errorf("the dkg doesn't run on the roster: %d "+
+			"participants != %d nodes", len(result.Participants), roster.Len())
 	}
 
 	return nil
 }
 
-// closeElection implements commands. It performs the CLOSE_ELECTION command
-func (e evotingCommand) closeElection(snap store.Snapshot, step execution.Step) error {
+// castVote implements commands. It performs the CAST_VOTE command
+func (e evotingCommand) castVote(snap store.Snapshot, step execution.Step) error {
 
 	msg, err := e.getTransaction(step.Current)
 	if err != nil {
 		return xerrors.Errorf(errGetTransaction, err)
 	}
 
-	tx, ok := msg.(types.CloseElection)
+	tx, ok := msg.(types.CastVote)
 	if !ok {
 		return xerrors.Errorf(errWrongTx, msg)
 	}
@@ -462,68 +620,236 @@ func (e evotingCommand) closeElection(snap store.Snapshot, step execution.Step)
 		return xerrors.Errorf(errGetElection, err)
 	}
 
-	if election.Status != types.Open {
-		return xerrors.Errorf("the election is not open, current status: %d", election.Status)
+	now, err := e.chainTime(snap, election)
+	if err != nil {
+		return xerrors.Errorf("failed to get time: %v", err)
 	}
 
-	if len(election.Suffragia.Ciphervotes) <= 1 {
-		return xerrors.Errorf("at least two ballots are required")
+	err = election.CheckVote(tx, now)
+	if err != nil {
+		return err
 	}
 
-	election.Status = types.Closed
-	PromElectionStatus.WithLabelValues(election.ElectionID).Set(float64(election.Status))
-
-	electionBuf, err := election.Serialize(e.context)
+	eligible, err := e.isEligible(snap, election, electionID, tx.UserID)
 	if err != nil {
-		return xerrors.Errorf("failed to marshal Election : %v", err)
+		return xerrors.Errorf("failed to check voter: %v", err)
 	}
 
-	err = snap.Set(electionID, electionBuf)
+	if !eligible {
+		return xerrors.Errorf("user %q is not allowed to vote", tx.UserID)
+	}
+
+	err = e.storeBallot(snap, &election, electionID, tx.UserID, tx.Ballot)
 	if err != nil {
-		return xerrors.Errorf("failed to set value: %v", err)
+		return xerrors.Errorf("failed to store ballot: %v", err)
+	}
+
+	err = e.saveElection(snap, election, electionID)
+	if err != nil {
+		return xerrors.Errorf("failed to save election: %v", err)
 	}
 
+	PromElectionBallots.WithLabelValues(election.ElectionID).Set(float64(election.Suffragia.Count))
+
 	return nil
 }
 
-// registerPubshares implements commands. It performs the
-// REGISTER_PUB_SHARES command
-func (e evotingCommand) registerPubshares(snap store.Snapshot, step execution.Step) error {
+// castVotes implements commands. It performs the CAST_VOTES command. The votes
+// are applied in order, and a vote that can't be cast is skipped, before
+// anything is written for it, without rejecting the others. The result of
+// each vote is stored under a key derived from the transaction ID, see
+// VoteResultsKey, and each election is saved once all the votes are applied.
+func (e evotingCommand) castVotes(snap store.Snapshot, step execution.Step) error {
+
 	msg, err := e.getTransaction(step.Current)
 	if err != nil {
 		return xerrors.Errorf(errGetTransaction, err)
 	}
 
-	tx, ok := msg.(types.RegisterPubShares)
+	tx, ok := msg.(types.CastVotes)
+	if !ok {
+		return xerrors.Errorf(errWrongTx, msg)
+	}
+
+	if len(tx.Votes) == 0 || len(tx.Votes) > types.MaxBatchedVotes {
+		return xerrors.Errorf("the number of votes must be between 1 and %d: %d",
+			types.MaxBatchedVotes, len(tx.Votes))
+	}
+
+	clock, err := e.getClock(snap)
+	if err != nil {
+		return xerrors.Errorf("failed to get clock: %v", err)
+	}
+
+	// elections contains the loaded elections, nil if the election can't be
+	// read for the reason in loadErrs, and updated lists the elections with
+	// at least one new ballot, in order so that they are saved
+	// deterministically.
+	elections := make(map[string]*types.Election)
+	electionIDs := make(map[string][]byte)
+	loadErrs := make(map[string]error)
+	isUpdated := make(map[string]bool)
+	updated := []string{}
+
+	results := make([]types.VoteResult, len(tx.Votes))
+
+	for i, vote := range tx.Votes {
+		results[i] = types.VoteResult{ElectionID: vote.ElectionID, UserID: vote.UserID}
+
+		election, found := elections[vote.ElectionID]
+		if !found {
+			loaded, electionID, err := e.getElection(vote.ElectionID, snap)
+			if err == nil {
+				election = &loaded
+				electionIDs[vote.ElectionID] = electionID
+			} else {
+				loadErrs[vote.ElectionID] = xerrors.Errorf(errGetElection, err)
+			}
+
+			elections[vote.ElectionID] = election
+		}
+
+		if election == nil {
+			results[i].Error = loadErrs[vote.ElectionID].Error()
+			continue
+		}
+
+		writes, err := e.prepareVote(snap, clock, election,
+			electionIDs[vote.ElectionID], vote)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+
+		err = writes.apply(snap, election)
+		if err != nil {
+			return xerrors.Errorf("failed to store ballot: %v", err)
+		}
+
+		if !isUpdated[vote.ElectionID] {
+			isUpdated[vote.ElectionID] = true
+			updated = append(updated, vote.ElectionID)
+		}
+	}
+
+	for _, id := range updated {
+		election := elections[id]
+
+		err = e.saveElection(snap, *election, electionIDs[id])
+		if err != nil {
+			return xerrors.Errorf("failed to save election: %v", err)
+		}
+
+		PromElectionBallots.WithLabelValues(election.ElectionID).Set(float64(election.Suffragia.Count))
+	}
+
+	buf, err := types.EncodeVoteResults(results)
+	if err != nil {
+		return xerrors.Errorf("failed to encode the results: %v", err)
+	}
+
+	err = snap.Set(types.VoteResultsKey(step.Current.GetID()), buf)
+	if err != nil {
+		return xerrors.Errorf("failed to set the results: %v", err)
+	}
+
+	return nil
+}
+
+// prepareVote checks a vote of a CAST_VOTES transaction like CAST_VOTE does,
+// and returns the writes that store its ballot, or why it must be skipped.
+func (e evotingCommand) prepareVote(snap store.Snapshot, clock types.Clock,
+	election *types.Election, electionID []byte, vote types.CastVote) (ballotWrites, error) {
+
+	now, err := clock.Time(election.Roster)
+	if err != nil {
+		return ballotWrites{}, xerrors.Errorf("failed to get time: %v", err)
+	}
+
+	err = election.CheckVote(vote, now)
+	if err != nil {
+		return ballotWrites{}, err
+	}
+
+	eligible, err := e.isEligible(snap, *election, electionID, vote.UserID)
+	if err != nil {
+		return ballotWrites{}, xerrors.Errorf("failed to check voter: %v", err)
+	}
+
+	if !eligible {
+		return ballotWrites{}, xerrors.Errorf("user %q is not allowed to vote", vote.UserID)
+	}
+
+	writes, err := e.prepareBallot(snap, election, electionID, vote.UserID, vote.Ballot)
+	if err != nil {
+		return ballotWrites{}, xerrors.Errorf("invalid ballot: %v", err)
+	}
+
+	return writes, nil
+}
+
+// shuffleBallots implements commands. It performs the SHUFFLE_BALLOTS command
+func (e evotingCommand) shuffleBallots(snap store.Snapshot, step execution.Step) error {
+
+	msg, err := e.getTransaction(step.Current)
+	if err != nil {
+		return xerrors.Errorf(errGetTransaction, err)
+	}
+
+	tx, ok := msg.(types.ShuffleBallots)
 	if !ok {
 		return xerrors.Errorf(errWrongTx, msg)
 	}
 
+	err = e.checkPreviousTransactions(step, tx.Round)
+	if err != nil {
+		return xerrors.Errorf("check previous transactions failed: %v", err)
+	}
+
 	election, electionID, err := e.getElection(tx.ElectionID, snap)
 	if err != nil {
 		return xerrors.Errorf(errGetElection, err)
 	}
 
-	if election.Status != types.ShuffledBallots {
-		return xerrors.Errorf("the ballots have not been shuffled")
+	if election.Status != types.Closed {
+		return xerrors.Errorf("the election is not closed")
 	}
 
-	err = isMemberOf(election.Roster, tx.PublicKey)
+	// Round starts at 0
+	expectedRound := len(election.ShuffleInstances)
+
+	if tx.Round != expectedRound {
+		return xerrors.Errorf("wrong shuffle round: expected round '%d', "+
+			"transaction is for round '%d'", expectedRound, tx.Round)
+	}
+
+	shufflerPublicKey := tx.PublicKey
+
+	err = isMemberOf(election.Roster, shufflerPublicKey)
 	if err != nil {
-		return xerrors.Errorf("could not verify identity of node : %v", err)
+		return xerrors.Errorf("could not verify identity of shuffler : %v", err)
+	}
+
+	// Check the node who submitted the shuffle did not already submit an
+	// accepted shuffle
+	for i, shuffleInstance := range election.ShuffleInstances {
+		if bytes.Equal(shufflerPublicKey, shuffleInstance.ShufflerPublicKey) {
+			return xerrors.Errorf("a node already submitted a shuffle that "+
+				"has been accepted in round %d", i)
+		}
 	}
 
+	// Check the shuffler indeed signed the transaction:
 	signerPubKey, err := bls.NewPublicKey(tx.PublicKey)
 	if err != nil {
-		return xerrors.Errorf("could not recover public key from tx: %v", err)
+		return xerrors.Errorf("could not decode public key of signer : %v ", err)
 	}
 
-	// Check the node indeed signed the transaction:
 	txSignature := tx.Signature
 
 	signature, err := bls.NewSignatureFactory().SignatureOf(e.context, txSignature)
 	if err != nil {
-		return xerrors.Errorf("could node deserialize pubShare signature: %v", err)
+		return xerrors.Errorf("could node deserialize shuffle signature : %v", err)
 	}
 
 	h := sha256.New()
@@ -535,162 +861,1557 @@ func (e evotingCommand) registerPubshares(snap store.Snapshot, step execution.St
 
 	hash := h.Sum(nil)
 
-	// Check the signature matches the pubshares using the node's public key
+	// Check the signature matches the shuffle using the shuffler's public key
 	err = signerPubKey.Verify(hash, signature)
 	if err != nil {
-		return xerrors.Errorf("signature does not match the PubsharesUnit: %v ", err)
+		return xerrors.Errorf("signature does not match the Shuffle : %v", err)
 	}
 
-	// coherence check on the length of the shares submitted
-	shuffledBallots := election.ShuffleInstances[len(election.ShuffleInstances)-1].
-		ShuffledBallots
-	if len(tx.Pubshares) != len(shuffledBallots) {
-		return xerrors.Errorf("unexpected size of pubshares submission: %d != %d",
-			len(tx.Pubshares), len(shuffledBallots))
+	// Retrieve the random vector (ie the Scalar vector)
+	randomVector, err := tx.RandomVector.Unmarshal()
+	if err != nil {
+		return xerrors.Errorf("failed to unmarshal random vector: %v", err)
 	}
 
-	for i, ballot := range shuffledBallots {
-		if len(ballot) != len(tx.Pubshares[i]) {
-			return xerrors.Errorf("unexpected size of pubshares submission: %d != %d",
-				len(tx.Pubshares[i]), len(ballot))
-		}
+	if tx.RandomVectorVersion != election.RandomVectorVersion {
+		return xerrors.Errorf("unexpected random vector version: %d != %d",
+			tx.RandomVectorVersion, election.RandomVectorVersion)
 	}
 
-	units := &election.PubsharesUnits
+	// Check that the random vector is correct
+	expectedVector, err := ShuffleRandomVector(election.RandomVectorVersion,
+		election.ElectionID, tx.Round, hash, election.ChunksPerBallot())
+	if err != nil {
+		return xerrors.Errorf("failed to derive random vector: %v", err)
+	}
 
-	// Check the node hasn't made any other submissions
-	for _, key := range units.PubKeys {
-		if bytes.Equal(key, tx.PublicKey) {
-			return xerrors.Errorf("'%x' already made a submission", key)
-		}
+	if election.ChunksPerBallot() != len(randomVector) {
+		return xerrors.Errorf("randomVector has unexpected length : %v != %v",
+			len(randomVector), election.ChunksPerBallot())
 	}
 
-	for _, index := range units.Indexes {
-		if index == tx.Index {
-			return xerrors.Errorf("a submission has already been made for index %d", index)
+	for i, v := range expectedVector {
+		if !randomVector[i].Equal(v) {
+			return xerrors.Errorf("random vector from shuffle transaction is " +
+				"different than expected random vector")
 		}
 	}
 
-	// Add the pubshares to the election
-	units.Pubshares = append(units.Pubshares, tx.Pubshares)
-	units.PubKeys = append(units.PubKeys, tx.PublicKey)
-	units.Indexes = append(units.Indexes, tx.Index)
+	if len(tx.ShuffledBallots) == 0 {
+		return xerrors.Errorf("there are no shuffled ballots")
+	}
 
-	nbrSubmissions := len(units.Pubshares)
+	var ciphervotes []types.Ciphervote
 
-	PromElectionPubShares.WithLabelValues(election.ElectionID).Set(float64(nbrSubmissions))
+	if tx.Round == 0 {
+		ciphervotes = election.Suffragia.Ciphervotes
+	} else {
+		// get the election's last shuffled ballots
+		lastIndex := len(election.ShuffleInstances) - 1
+		ciphervotes = election.ShuffleInstances[lastIndex].ShuffledBallots
+	}
 
-	if nbrSubmissions >= election.ShuffleThreshold {
-		election.Status = types.PubSharesSubmitted
+	err = verifyShuffle(e.prover, election.Pubkey, ciphervotes, tx.ShuffledBallots,
+		randomVector, tx.Proof)
+	if err != nil {
+		return err
+	}
+
+	// append the new shuffled ballots and the proof to the lists
+	currentShuffleInstance := types.ShuffleInstance{
+		ShuffledBallots:   tx.ShuffledBallots,
+		ShuffleProofs:     tx.Proof,
+		ShufflerPublicKey: shufflerPublicKey,
+		Signature:         tx.Signature,
+	}
+
+	election.ShuffleInstances = append(election.ShuffleInstances, currentShuffleInstance)
+
+	PromElectionShufflingInstances.WithLabelValues(election.ElectionID).Set(float64(len(election.ShuffleInstances)))
+
+	// in case we have enough shuffled ballots, we update the status
+	if len(election.ShuffleInstances) >= election.ShuffleThreshold {
+		election.Status = types.ShuffledBallots
 		PromElectionStatus.WithLabelValues(election.ElectionID).Set(float64(election.Status))
 	}
 
-	electionBuf, err := election.Serialize(e.context)
+	err = e.saveElection(snap, election, electionID)
 	if err != nil {
-		return xerrors.Errorf("failed to marshal Election: %v", err)
+		return xerrors.Errorf("failed to save election: %v", err)
 	}
 
-	err = snap.Set(electionID, electionBuf)
+	return nil
+}
+
+// shuffleRandomVectorDomain separates the seed of the random vectors of the
+// shuffles from the other hashes of the protocol.
+const shuffleRandomVectorDomain = "d-voting/shuffle-random-vector/v1"
+
+// ShuffleRandomVector derives the random vector of a shuffle, one scalar per
+// chunk, from the hash of the fingerprint of the shuffle. The shufflers and
+// the verifiers must use the same derivation, which is given by the version of
+// the election.
+func ShuffleRandomVector(version types.RandomVectorVersion, electionID string,
+	round int, hash []byte, chunks int) ([]kyber.Scalar, error) {
+
+	var stream cipher.Stream
+
+	switch version {
+	case types.RandomVectorLegacy:
+		semiRandomStream, err := NewSemiRandomStream(hash)
+		if err != nil {
+			return nil, xerrors.Errorf("could not create semi-random stream: %v", err)
+		}
+
+		stream = semiRandomStream
+	case types.RandomVectorXOF:
+		seed := new(bytes.Buffer)
+		seed.WriteString(shuffleRandomVectorDomain)
+
+		length := make([]byte, 4)
+		binary.BigEndian.PutUint32(length, uint32(len(electionID)))
+		seed.Write(length)
+		seed.WriteString(electionID)
+
+		roundBuf := make([]byte, 8)
+		binary.BigEndian.PutUint64(roundBuf, uint64(round))
+		seed.Write(roundBuf)
+
+		seed.Write(hash)
+
+		stream = suite.XOF(seed.Bytes())
+	default:
+		return nil, xerrors.Errorf("unsupported random vector version: %d", version)
+	}
+
+	randomVector := make([]kyber.Scalar, chunks)
+
+	for i := range randomVector {
+		randomVector[i] = suite.Scalar().Pick(stream)
+	}
+
+	return randomVector, nil
+}
+
+// verifyShuffle checks the proof that shuffled is a shuffle of ciphervotes.
+// shuffled must not be empty.
+func verifyShuffle(p prover, pubkey kyber.Point, ciphervotes,
+	shuffled []types.Ciphervote, randomVector []kyber.Scalar, shuffleProof []byte) error {
+
+	if len(ciphervotes) < 2 {
+		return xerrors.Errorf("not enough votes: %d < 2", len(ciphervotes))
+	}
+
+	X, Y := types.CiphervotesToPairs(ciphervotes)
+	XX, YY := types.CiphervotesToPairs(shuffled)
+
+	XXUp, YYUp, XXDown, YYDown := shuffle.GetSequenceVerifiable(suite, X, Y, XX,
+		YY, randomVector)
+
+	verifier := shuffle.Verifier(suite, nil, pubkey, XXUp, YYUp, XXDown, YYDown)
+
+	err := p(suite, shufflingProtocolName, verifier, shuffleProof)
 	if err != nil {
-		return xerrors.Errorf("failed to set value: %v", err)
+		return xerrors.Errorf("proof verification failed: %v", err)
+	}
+
+	return nil
+}
+
+// checkPreviousTransactions checks if a ShuffleBallotsTransaction has already
+// been accepted and executed for a specific round.
+func (e evotingCommand) checkPreviousTransactions(step execution.Step, round int) error {
+	for _, tx := range step.Previous {
+		// skip tx not concerning the evoting contract
+		if string(tx.GetArg(native.ContractArg)) != ContractName {
+			continue
+		}
+
+		// skip tx that does not contain the election argument
+		if string(tx.GetArg(CmdArg)) != ElectionArg {
+			continue
+		}
+
+		msg, err := e.getTransaction(step.Current)
+		if err != nil {
+			return xerrors.Errorf(errGetTransaction, err)
+		}
+
+		// skip if not a shuffling ballot transaction
+		shuffleBallots, ok := msg.(types.ShuffleBallots)
+		if !ok {
+			continue
+		}
+
+		if shuffleBallots.Round == round {
+			return xerrors.Errorf("shuffle is already happening in this round")
+		}
+	}
+
+	return nil
+}
+
+// closeElection implements commands. It performs the CLOSE_ELECTION command
+func (e evotingCommand) closeElection(snap store.Snapshot, step execution.Step) error {
+
+	msg, err := e.getTransaction(step.Current)
+	if err != nil {
+		return xerrors.Errorf(errGetTransaction, err)
+	}
+
+	tx, ok := msg.(types.CloseElection)
+	if !ok {
+		return xerrors.Errorf(errWrongTx, msg)
+	}
+
+	election, electionID, err := e.getElection(tx.ElectionID, snap)
+	if err != nil {
+		return xerrors.Errorf(errGetElection, err)
+	}
+
+	if election.Status != types.Open {
+		return xerrors.Errorf("the election is not open, current status: %d", election.Status)
+	}
+
+	// A scheduled election is closed by the nodes, without the signature of
+	// the admin, once its end is reached.
+	scheduled := false
+
+	if len(tx.AdminSignature) == 0 && election.Configuration.Schedule.End != 0 {
+		now, err := e.chainTime(snap, election)
+		if err != nil {
+			return xerrors.Errorf("failed to get time: %v", err)
+		}
+
+		scheduled = election.Configuration.Schedule.HasEnded(now)
+	}
+
+	if !scheduled {
+		err = checkSoleAdmin(&election, CmdCloseElection, nil, tx.AdminSignature)
+		if err != nil {
+			return xerrors.Errorf("failed to check admin: %v", err)
+		}
+	}
+
+	return e.applyClose(snap, election, electionID)
+}
+
+// applyClose closes the election and stores it.
+func (e evotingCommand) applyClose(snap store.Snapshot, election types.Election,
+	electionID []byte) error {
+
+	userIDs, ciphervotes, err := e.readBallots(snap, election, electionID)
+	if err != nil {
+		return xerrors.Errorf("failed to read ballots: %v", err)
+	}
+
+	// everything is checked before the ballots are moved, so that a rejected
+	// transaction leaves them in place
+	quorumErr := election.Configuration.Quorum.CheckCast(len(ciphervotes),
+		election.Electorate)
+
+	if quorumErr == nil && len(ciphervotes) <= 1 {
+		return xerrors.Errorf("at least two ballots are required")
+	}
+
+	err = e.moveBallots(snap, &election, electionID, userIDs, ciphervotes)
+	if err != nil {
+		return xerrors.Errorf("failed to move ballots: %v", err)
+	}
+
+	if quorumErr != nil {
+		return e.endWithoutQuorum(snap, election, electionID, quorumErr)
+	}
+
+	election.Status = types.Closed
+	PromElectionStatus.WithLabelValues(election.ElectionID).Set(float64(election.Status))
+
+	err = e.saveElection(snap, election, electionID)
+	if err != nil {
+		return xerrors.Errorf("failed to save election: %v", err)
+	}
+
+	return nil
+}
+
+// endWithoutQuorum ends the election without results because the participation
+// required by its quorum is not reached.
+func (e evotingCommand) endWithoutQuorum(snap store.Snapshot, election types.Election,
+	electionID []byte, reason error) error {
+
+	election.Status = types.QuorumNotReached
+	election.StatusReason = reason.Error()
+	PromElectionStatus.WithLabelValues(election.ElectionID).Set(float64(election.Status))
+
+	err := e.saveElection(snap, election, electionID)
+	if err != nil {
+		return xerrors.Errorf("failed to save election: %v", err)
+	}
+
+	return nil
+}
+
+// registerPubshares implements commands. It performs the
+// REGISTER_PUB_SHARES command
+func (e evotingCommand) registerPubshares(snap store.Snapshot, step execution.Step) error {
+	msg, err := e.getTransaction(step.Current)
+	if err != nil {
+		return xerrors.Errorf(errGetTransaction, err)
+	}
+
+	tx, ok := msg.(types.RegisterPubShares)
+	if !ok {
+		return xerrors.Errorf(errWrongTx, msg)
+	}
+
+	election, electionID, err := e.getElection(tx.ElectionID, snap)
+	if err != nil {
+		return xerrors.Errorf(errGetElection, err)
+	}
+
+	if election.Status != types.ShuffledBallots {
+		return xerrors.Errorf("the ballots have not been shuffled")
+	}
+
+	err = isMemberOf(election.Roster, tx.PublicKey)
+	if err != nil {
+		return xerrors.Errorf("could not verify identity of node : %v", err)
+	}
+
+	signerPubKey, err := bls.NewPublicKey(tx.PublicKey)
+	if err != nil {
+		return xerrors.Errorf("could not recover public key from tx: %v", err)
+	}
+
+	// Check the node indeed signed the transaction:
+	txSignature := tx.Signature
+
+	signature, err := bls.NewSignatureFactory().SignatureOf(e.context, txSignature)
+	if err != nil {
+		return xerrors.Errorf("could node deserialize pubShare signature: %v", err)
+	}
+
+	h := sha256.New()
+
+	err = tx.Fingerprint(h)
+	if err != nil {
+		return xerrors.Errorf("failed to get fingerprint: %v", err)
+	}
+
+	hash := h.Sum(nil)
+
+	// Check the signature matches the pubshares using the node's public key
+	err = signerPubKey.Verify(hash, signature)
+	if err != nil {
+		return xerrors.Errorf("signature does not match the PubsharesUnit: %v ", err)
+	}
+
+	// coherence check on the length of the shares submitted
+	shuffledBallots := election.ShuffleInstances[len(election.ShuffleInstances)-1].
+		ShuffledBallots
+	if len(tx.Pubshares) != len(shuffledBallots) {
+		return xerrors.Errorf("unexpected size of pubshares submission: %d != %d",
+			len(tx.Pubshares), len(shuffledBallots))
+	}
+
+	for i, ballot := range shuffledBallots {
+		if len(ballot) != len(tx.Pubshares[i]) {
+			return xerrors.Errorf("unexpected size of pubshares submission: %d != %d",
+				len(tx.Pubshares[i]), len(ballot))
+		}
+	}
+
+	units := &election.PubsharesUnits
+
+	// Check the node hasn't made any other submissions
+	for _, key := range units.PubKeys {
+		if bytes.Equal(key, tx.PublicKey) {
+			return xerrors.Errorf("'%x' already made a submission", key)
+		}
+	}
+
+	for _, index := range units.Indexes {
+		if index == tx.Index {
+			return xerrors.Errorf("a submission has already been made for index %d", index)
+		}
+	}
+
+	// The shares are checked last as it is the most expensive check
+	if tx.Index < 0 || tx.Index >= len(election.VerificationKeys) {
+		return xerrors.Errorf("no verification key for index %d", tx.Index)
+	}
+
+	err = verifyPubshares(election.VerificationKeys[tx.Index], shuffledBallots,
+		tx.Pubshares, tx.Proofs)
+	if err != nil {
+		return xerrors.Errorf("failed to verify pubshares: %v", err)
+	}
+
+	// the submissions made before the proofs were stored have none
+	for len(units.Proofs) < len(units.Pubshares) {
+		units.Proofs = append(units.Proofs, nil)
+	}
+
+	// Add the pubshares to the election
+	units.Pubshares = append(units.Pubshares, tx.Pubshares)
+	units.PubKeys = append(units.PubKeys, tx.PublicKey)
+	units.Indexes = append(units.Indexes, tx.Index)
+	units.Proofs = append(units.Proofs, tx.Proofs)
+
+	nbrSubmissions := len(units.Pubshares)
+
+	PromElectionPubShares.WithLabelValues(election.ElectionID).Set(float64(nbrSubmissions))
+
+	if nbrSubmissions >= election.PubsharesThreshold() {
+		election.Status = types.PubSharesSubmitted
+		PromElectionStatus.WithLabelValues(election.ElectionID).Set(float64(election.Status))
+	}
+
+	err = e.saveElection(snap, election, electionID)
+	if err != nil {
+		return xerrors.Errorf("failed to save election: %v", err)
+	}
+
+	return nil
+}
+
+// verifyPubshares checks the proof of each pubshare against the verification
+// key of the node that submitted them. The pubshares must have the size of the
+// ballots.
+func verifyPubshares(verificationKey kyber.Point, ballots []types.Ciphervote,
+	pubshares types.PubsharesUnit, proofs types.PubshareProofsUnit) error {
+
+	if len(proofs) != len(pubshares) {
+		return xerrors.Errorf("unexpected number of proofs: %d != %d",
+			len(proofs), len(pubshares))
+	}
+
+	for i, ballot := range ballots {
+		if len(proofs[i]) != len(ballot) {
+			return xerrors.Errorf("unexpected number of proofs for ballot %d: %d != %d",
+				i, len(proofs[i]), len(ballot))
+		}
+
+		for j, egpair := range ballot {
+			err := types.VerifyPubshare(verificationKey, egpair, pubshares[i][j], proofs[i][j])
+			if err != nil {
+				return xerrors.Errorf("invalid pubshare %d of ballot %d: %v", j, i, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// combineShares implements commands. It performs the COMBINE_SHARES command
+func (e evotingCommand) combineShares(snap store.Snapshot, step execution.Step) error {
+
+	msg, err := e.getTransaction(step.Current)
+	if err != nil {
+		return xerrors.Errorf(errGetTransaction, err)
+	}
+
+	tx, ok := msg.(types.CombineShares)
+	if !ok {
+		return xerrors.Errorf(errWrongTx, msg)
+	}
+
+	election, electionID, err := e.getElection(tx.ElectionID, snap)
+	if err != nil {
+		return xerrors.Errorf(errGetElection, err)
+	}
+
+	if election.Status != types.PubSharesSubmitted {
+		return xerrors.Errorf("the public shares have not been submitted,"+
+			" current status: %d", election.Status)
+	}
+
+	err = checkAdmin(&election, CmdCombineShares, nil, tx.AdminSignature)
+	if err != nil {
+		return xerrors.Errorf("failed to check admin: %v", err)
+	}
+
+	decryptedBallots, tally, err := decryptBallots(election)
+	if err != nil {
+		return err
+	}
+
+	err = election.Configuration.Quorum.CheckValid(tally)
+	if err != nil {
+		return e.endWithoutQuorum(snap, election, electionID, err)
+	}
+
+	election.DecryptedBallots = decryptedBallots
+	election.Tally = tally
+
+	election.Status = types.ResultAvailable
+	PromElectionStatus.WithLabelValues(election.ElectionID).Set(float64(election.Status))
+
+	err = e.saveElection(snap, election, electionID)
+	if err != nil {
+		return xerrors.Errorf("failed to save election: %v", err)
+	}
+
+	return nil
+}
+
+// cancelElection implements commands. It performs the CANCEL_ELECTION command
+func (e evotingCommand) cancelElection(snap store.Snapshot, step execution.Step) error {
+
+	msg, err := e.getTransaction(step.Current)
+	if err != nil {
+		return xerrors.Errorf(errGetTransaction, err)
+	}
+
+	tx, ok := msg.(types.CancelElection)
+	if !ok {
+		return xerrors.Errorf(errWrongTx, msg)
+	}
+
+	election, electionID, err := e.getElection(tx.ElectionID, snap)
+	if err != nil {
+		return xerrors.Errorf(errGetElection, err)
+	}
+
+	err = checkSoleAdmin(&election, CmdCancelElection, nil, tx.AdminSignature)
+	if err != nil {
+		return xerrors.Errorf("failed to check admin: %v", err)
+	}
+
+	return e.applyCancel(snap, election, electionID)
+}
+
+// applyCancel cancels the election and stores it.
+func (e evotingCommand) applyCancel(snap store.Snapshot, election types.Election,
+	electionID []byte) error {
+
+	election.Status = types.Canceled
+	PromElectionStatus.WithLabelValues(election.ElectionID).Set(float64(election.Status))
+
+	err := e.saveElection(snap, election, electionID)
+	if err != nil {
+		return xerrors.Errorf("failed to save election: %v", err)
+	}
+
+	return nil
+}
+
+// deleteElection implements commands. It performs the DELETE_ELECTION command
+func (e evotingCommand) deleteElection(snap store.Snapshot, step execution.Step) error {
+
+	msg, err := e.getTransaction(step.Current)
+	if err != nil {
+		return xerrors.Errorf(errGetTransaction, err)
+	}
+
+	tx, ok := msg.(types.DeleteElection)
+	if !ok {
+		return xerrors.Errorf(errWrongTx, msg)
+	}
+
+	election, electionID, err := e.getElection(tx.ElectionID, snap)
+	if err != nil {
+		return xerrors.Errorf(errGetElection, err)
+	}
+
+	err = checkSoleAdmin(&election, CmdDeleteElection, nil, tx.AdminSignature)
+	if err != nil {
+		return xerrors.Errorf("failed to check admin: %v", err)
+	}
+
+	return e.applyDelete(snap, election, electionID)
+}
+
+// applyDelete removes the election from the store and marks its catalog entry
+// as deleted.
+func (e evotingCommand) applyDelete(snap store.Snapshot, election types.Election,
+	electionID []byte) error {
+
+	// the ballots are still stored under their own keys if the election has
+	// not been closed
+	if len(election.Suffragia.UserIDs) == 0 {
+		err := e.deleteBallots(snap, election, electionID)
+		if err != nil {
+			return xerrors.Errorf("failed to delete ballots: %v", err)
+		}
+	}
+
+	for i, ciphervote := range election.Suffragia.Ciphervotes {
+		err := e.deleteReceipt(snap, electionID, ciphervote)
+		if err != nil {
+			return xerrors.Errorf("failed to delete receipt %d: %v", i, err)
+		}
+	}
+
+	err := snap.Delete(electionID)
+	if err != nil {
+		return xerrors.Errorf("failed to delete election: %v", err)
+	}
+
+	entry, err := e.getCatalogEntry(snap, election.CatalogIndex)
+	if err != nil {
+		return xerrors.Errorf("failed to get catalog entry: %v", err)
+	}
+
+	// the entry is kept so that the cursors used to list the catalog remain
+	// valid
+	entry.Deleted = true
+
+	err = e.setCatalogEntry(snap, election.CatalogIndex, entry)
+	if err != nil {
+		return xerrors.Errorf("failed to update catalog entry: %v", err)
+	}
+
+	return nil
+}
+
+// proposeAction implements commands. It performs the PROPOSE_ACTION command
+func (e evotingCommand) proposeAction(snap store.Snapshot, step execution.Step) error {
+
+	msg, err := e.getTransaction(step.Current)
+	if err != nil {
+		return xerrors.Errorf(errGetTransaction, err)
+	}
+
+	tx, ok := msg.(types.ProposeAction)
+	if !ok {
+		return xerrors.Errorf(errWrongTx, msg)
+	}
+
+	election, electionID, err := e.getElection(tx.ElectionID, snap)
+	if err != nil {
+		return xerrors.Errorf(errGetElection, err)
+	}
+
+	switch Command(tx.Command) {
+	case CmdCloseElection, CmdCancelElection, CmdDeleteElection:
+	default:
+		return xerrors.Errorf("command %q can't be proposed", tx.Command)
+	}
+
+	err = checkAdminKey(&election, tx.AdminID, Command(tx.Command), nil, tx.AdminSignature)
+	if err != nil {
+		return xerrors.Errorf("failed to check admin: %v", err)
+	}
+
+	action := types.PendingAction{
+		ID:        hex.EncodeToString(step.Current.GetID()),
+		Command:   tx.Command,
+		Hash:      hex.EncodeToString(types.ActionHash(tx.Command, nil)),
+		Approvals: []string{tx.AdminID},
+	}
+
+	if isObsolete(action, election.Status) {
+		return xerrors.Errorf("command %q can't be executed, current status: %d",
+			tx.Command, election.Status)
+	}
+
+	return e.updateAction(snap, election, electionID, action)
+}
+
+// approveAction implements commands. It performs the APPROVE_ACTION command
+func (e evotingCommand) approveAction(snap store.Snapshot, step execution.Step) error {
+
+	msg, err := e.getTransaction(step.Current)
+	if err != nil {
+		return xerrors.Errorf(errGetTransaction, err)
+	}
+
+	tx, ok := msg.(types.ApproveAction)
+	if !ok {
+		return xerrors.Errorf(errWrongTx, msg)
+	}
+
+	election, electionID, err := e.getElection(tx.ElectionID, snap)
+	if err != nil {
+		return xerrors.Errorf(errGetElection, err)
+	}
+
+	index := election.GetPendingAction(tx.ActionID)
+	if index < 0 {
+		return xerrors.Errorf("action %q not found", tx.ActionID)
+	}
+
+	action := election.PendingActions[index]
+
+	err = checkAdminKey(&election, tx.AdminID, CmdApproveAction, action.ApprovalPayload(),
+		tx.AdminSignature)
+	if err != nil {
+		return xerrors.Errorf("failed to check admin: %v", err)
+	}
+
+	if action.HasApproved(tx.AdminID) {
+		return xerrors.Errorf("admin %q already approved the action", tx.AdminID)
+	}
+
+	action.Approvals = append(action.Approvals, tx.AdminID)
+
+	election.PendingActions = append(election.PendingActions[:index],
+		election.PendingActions[index+1:]...)
+
+	return e.updateAction(snap, election, electionID, action)
+}
+
+// updateAction either executes the action if it has enough approvals, or
+// stores it in the pending actions of the election.
+func (e evotingCommand) updateAction(snap store.Snapshot, election types.Election,
+	electionID []byte, action types.PendingAction) error {
+
+	if len(action.Approvals) < election.Admins.Threshold {
+		election.PendingActions = append(election.PendingActions, action)
+
+		err := e.saveElection(snap, election, electionID)
+		if err != nil {
+			return xerrors.Errorf("failed to save election: %v", err)
+		}
+
+		return nil
+	}
+
+	// the other pending actions are dropped by saveElection if they no longer
+	// apply once the action is executed
+	switch Command(action.Command) {
+	case CmdCloseElection:
+		if election.Status != types.Open {
+			return xerrors.Errorf("the election is not open, current status: %d",
+				election.Status)
+		}
+
+		return e.applyClose(snap, election, electionID)
+	case CmdCancelElection:
+		return e.applyCancel(snap, election, electionID)
+	case CmdDeleteElection:
+		return e.applyDelete(snap, election, electionID)
+	default:
+		return xerrors.Errorf("unknown action: %s", action.Command)
+	}
+}
+
+// registerVoters implements commands. It performs the REGISTER_VOTERS command
+func (e evotingCommand) registerVoters(snap store.Snapshot, step execution.Step) error {
+
+	msg, err := e.getTransaction(step.Current)
+	if err != nil {
+		return xerrors.Errorf(errGetTransaction, err)
+	}
+
+	tx, ok := msg.(types.RegisterVoters)
+	if !ok {
+		return xerrors.Errorf(errWrongTx, msg)
+	}
+
+	return e.updateElectorate(snap, tx.ElectionID, CmdRegisterVoters, tx.AdminPayload(),
+		tx.AdminSignature,
+		func(electorate *types.Electorate, electionID []byte) error {
+			return e.addVoterKeys(snap, electorate, electionID, tx.Voters)
+		})
+}
+
+// removeVoters implements commands. It performs the REMOVE_VOTERS command
+func (e evotingCommand) removeVoters(snap store.Snapshot, step execution.Step) error {
+
+	msg, err := e.getTransaction(step.Current)
+	if err != nil {
+		return xerrors.Errorf(errGetTransaction, err)
+	}
+
+	tx, ok := msg.(types.RemoveVoters)
+	if !ok {
+		return xerrors.Errorf(errWrongTx, msg)
+	}
+
+	return e.updateElectorate(snap, tx.ElectionID, CmdRemoveVoters, tx.AdminPayload(),
+		tx.AdminSignature,
+		func(electorate *types.Electorate, electionID []byte) error {
+			return e.removeVoterKeys(snap, electorate, electionID, tx.Voters)
+		})
+}
+
+// replaceVoters implements commands. It performs the REPLACE_VOTERS command
+func (e evotingCommand) replaceVoters(snap store.Snapshot, step execution.Step) error {
+
+	msg, err := e.getTransaction(step.Current)
+	if err != nil {
+		return xerrors.Errorf(errGetTransaction, err)
+	}
+
+	tx, ok := msg.(types.ReplaceVoters)
+	if !ok {
+		return xerrors.Errorf(errWrongTx, msg)
+	}
+
+	return e.updateElectorate(snap, tx.ElectionID, CmdReplaceVoters, tx.AdminPayload(),
+		tx.AdminSignature,
+		func(electorate *types.Electorate, electionID []byte) error {
+			// the keys of the current voters are left behind, a new epoch
+			// makes them unreachable
+			*electorate = types.Electorate{Epoch: electorate.Epoch + 1}
+
+			return e.addVoterKeys(snap, electorate, electionID, tx.Voters)
+		})
+}
+
+// updateElectorate applies the update on the electorate of the election and
+// stores the result. The command must be signed by an admin of the election,
+// and the electorate can only be changed until the election is closed.
+func (e evotingCommand) updateElectorate(snap store.Snapshot, electionIDHex string,
+	cmd Command, payload, adminSignature []byte,
+	update func(electorate *types.Electorate, electionID []byte) error) error {
+
+	election, electionID, err := e.getElection(electionIDHex, snap)
+	if err != nil {
+		return xerrors.Errorf(errGetElection, err)
+	}
+
+	err = checkAdmin(&election, cmd, payload, adminSignature)
+	if err != nil {
+		return xerrors.Errorf("failed to check admin: %v", err)
+	}
+
+	if election.Status != types.Initial && election.Status != types.Open {
+		return xerrors.Errorf("the electorate can't be updated, current status: %d",
+			election.Status)
+	}
+
+	err = update(&election.Electorate, electionID)
+	if err != nil {
+		return xerrors.Errorf("failed to update electorate: %v", err)
+	}
+
+	err = e.saveElection(snap, election, electionID)
+	if err != nil {
+		return xerrors.Errorf("failed to save election: %v", err)
+	}
+
+	return nil
+}
+
+// addVoterKeys registers the hashed user IDs in the electorate. Hashes already
+// registered are ignored. The hashes are all checked before anything is
+// written.
+func (e evotingCommand) addVoterKeys(snap store.Snapshot, electorate *types.Electorate,
+	electionID []byte, hashes []string) error {
+
+	err := types.CheckVoters(hashes)
+	if err != nil {
+		return xerrors.Errorf("failed to add voters: %v", err)
+	}
+
+	for _, hash := range hashes {
+		key := electorate.VoterKey(electionID, hash)
+
+		value, err := snap.Get(key)
+		if err != nil {
+			return xerrors.Errorf("failed to get voter: %v", err)
+		}
+
+		if len(value) != 0 {
+			continue
+		}
+
+		err = snap.Set(key, []byte{1})
+		if err != nil {
+			return xerrors.Errorf("failed to set voter: %v", err)
+		}
+
+		electorate.Count++
+	}
+
+	electorate.Restricted = true
+
+	return nil
+}
+
+// removeVoterKeys removes the hashed user IDs from the electorate. Unknown
+// hashes are ignored.
+func (e evotingCommand) removeVoterKeys(snap store.Snapshot, electorate *types.Electorate,
+	electionID []byte, hashes []string) error {
+
+	for _, hash := range hashes {
+		key := electorate.VoterKey(electionID, hash)
+
+		value, err := snap.Get(key)
+		if err != nil {
+			return xerrors.Errorf("failed to get voter: %v", err)
+		}
+
+		if len(value) == 0 {
+			continue
+		}
+
+		err = snap.Delete(key)
+		if err != nil {
+			return xerrors.Errorf("failed to delete voter: %v", err)
+		}
+
+		electorate.Count--
+	}
+
+	return nil
+}
+
+// isEligible returns true if the user is allowed to vote in the election.
+func (e evotingCommand) isEligible(snap store.Snapshot, election types.Election,
+	electionID []byte, userID string) (bool, error) {
+
+	if !election.Electorate.Restricted {
+		return true, nil
+	}
+
+	hash := types.HashUserID(election.ElectionID, userID)
+
+	value, err := snap.Get(election.Electorate.VoterKey(electionID, hash))
+	if err != nil {
+		return false, xerrors.Errorf("failed to get voter: %v", err)
+	}
+
+	return len(value) != 0, nil
+}
+
+// reportTime implements commands. It performs the REPORT_TIME command. The
+// time of a node of the chain roster is updated, which is the only way for the
+// time of the chain to move forward.
+func (e evotingCommand) reportTime(snap store.Snapshot, step execution.Step) error {
+	msg, err := e.getTransaction(step.Current)
+	if err != nil {
+		return xerrors.Errorf(errGetTransaction, err)
+	}
+
+	tx, ok := msg.(types.ReportTime)
+	if !ok {
+		return xerrors.Errorf(errWrongTx, msg)
+	}
+
+	rosterBuf, err := snap.Get(e.rosterKey)
+	if err != nil {
+		return xerrors.Errorf("failed to get roster")
+	}
+
+	roster, err := e.rosterFac.AuthorityOf(e.context, rosterBuf)
+	if err != nil {
+		return xerrors.Errorf("failed to get roster: %v", err)
+	}
+
+	err = isMemberOf(roster, tx.PublicKey)
+	if err != nil {
+		return xerrors.Errorf("could not verify identity of node: %v", err)
+	}
+
+	signerPubKey, err := bls.NewPublicKey(tx.PublicKey)
+	if err != nil {
+		return xerrors.Errorf("could not recover public key from tx: %v", err)
+	}
+
+	signature, err := bls.NewSignatureFactory().SignatureOf(e.context, tx.Signature)
+	if err != nil {
+		return xerrors.Errorf("could not deserialize signature: %v", err)
+	}
+
+	h := sha256.New()
+
+	err = tx.Fingerprint(h)
+	if err != nil {
+		return xerrors.Errorf("failed to get fingerprint: %v", err)
+	}
+
+	err = signerPubKey.Verify(h.Sum(nil), signature)
+	if err != nil {
+		return xerrors.Errorf("signature does not match the time: %v", err)
+	}
+
+	clock, err := e.getClock(snap)
+	if err != nil {
+		return xerrors.Errorf("failed to get clock: %v", err)
+	}
+
+	err = clock.Report(tx.PublicKey, tx.Timestamp)
+	if err != nil {
+		return xerrors.Errorf("failed to report time: %v", err)
+	}
+
+	clockBuf, err := types.EncodeClock(clock)
+	if err != nil {
+		return xerrors.Errorf("failed to encode clock: %v", err)
+	}
+
+	err = snap.Set([]byte(types.ClockKey), clockBuf)
+	if err != nil {
+		return xerrors.Errorf("failed to set clock: %v", err)
+	}
+
+	return nil
+}
+
+// chainTime returns the time against which the schedule of the election is
+// checked. It is the time agreed by the roster of the election on the chain,
+// see types.Clock, so that every node checks the schedule at the same time,
+// whatever its clock.
+func (e evotingCommand) chainTime(snap store.Snapshot, election types.Election) (time.Time, error) {
+	clock, err := e.getClock(snap)
+	if err != nil {
+		return time.Time{}, xerrors.Errorf("failed to get clock: %v", err)
+	}
+
+	now, err := clock.Time(election.Roster)
+	if err != nil {
+		return time.Time{}, xerrors.Errorf("failed to get the time of the roster: %v", err)
+	}
+
+	return now, nil
+}
+
+// getClock returns the time reported by the nodes.
+func (e evotingCommand) getClock(snap store.Snapshot) (types.Clock, error) {
+	clockBuf, err := snap.Get([]byte(types.ClockKey))
+	if err != nil {
+		return types.Clock{}, xerrors.Errorf("failed to get clock: %v", err)
+	}
+
+	clock, err := types.DecodeClock(clockBuf)
+	if err != nil {
+		return types.Clock{}, xerrors.Errorf("failed to decode clock: %v", err)
+	}
+
+	return clock, nil
+}
+
+// checkAdmin verifies that the signature has been produced by one of the
+// admins of the election to authorize the command with the payload, and
+// increments the nonce of the election so that the signature can't be used
+// again.
+func checkAdmin(election *types.Election, cmd Command, payload, signature []byte) error {
+	for _, key := range election.Admins.Keys {
+		err := verifyAdmin(*election, key, cmd, payload, signature)
+		if err == nil {
+			election.AdminNonce++
+			return nil
+		}
+	}
+
+	return xerrors.Errorf("invalid admin signature")
+}
+
+// checkSoleAdmin verifies that the signature has been produced by one of the
+// admins and that the approval of a single admin is enough to perform the
+// sensitive command.
+func checkSoleAdmin(election *types.Election, cmd Command, payload, signature []byte) error {
+	if election.Admins.Threshold > 1 {
+		return xerrors.Errorf("the command requires the approval of %d admins",
+			election.Admins.Threshold)
+	}
+
+	return checkAdmin(election, cmd, payload, signature)
+}
+
+// checkAdminKey verifies that the key belongs to an admin of the election and
+// that the signature has been produced with it to authorize the command. Like
+// checkAdmin, it increments the nonce of the election.
+func checkAdminKey(election *types.Election, key string, cmd Command,
+	payload, signature []byte) error {
+
+	err := verifyAdmin(*election, key, cmd, payload, signature)
+	if err != nil {
+		return err
+	}
+
+	election.AdminNonce++
+
+	return nil
+}
+
+// verifyAdmin verifies that the key belongs to an admin of the election and
+// that the signature is valid on the admin message of the command for the
+// current nonce of the election.
+func verifyAdmin(election types.Election, key string, cmd Command,
+	payload, signature []byte) error {
+
+	if !election.Admins.Contains(key) {
+		return xerrors.Errorf("%q is not an admin", key)
+	}
+
+	pubkey, err := types.ParseAdminID(key)
+	if err != nil {
+		return xerrors.Errorf("failed to parse admin ID: %v", err)
+	}
+
+	msg := types.AdminMessage(string(cmd), election.ElectionID, payload, election.AdminNonce)
+
+	err = schnorr.Verify(suite, pubkey, msg, signature)
+	if err != nil {
+		return xerrors.Errorf("invalid admin signature: %v", err)
+	}
+
+	return nil
+}
+
+// pruneActions drops the pending actions that can no longer be executed in the
+// current status of the election, so that they don't accumulate.
+func pruneActions(election *types.Election) {
+	var actions []types.PendingAction
+
+	for _, action := range election.PendingActions {
+		if !isObsolete(action, election.Status) {
+			actions = append(actions, action)
+		}
+	}
+
+	election.PendingActions = actions
+}
+
+// isObsolete returns true if the action can't be executed in the given status.
+func isObsolete(action types.PendingAction, status types.Status) bool {
+	switch Command(action.Command) {
+	case CmdCloseElection:
+		return status != types.Open
+	case CmdCancelElection:
+		return status == types.Canceled
+	default:
+		return false
+	}
+}
+
+// isMemberOf is a utility function to verify if a public key is associated to a
+// member of the roster or not. Returns nil if it's the case.
+func isMemberOf(roster authority.Authority, publicKey []byte) error {
+	pubKeyIterator := roster.PublicKeyIterator()
+	isAMember := false
+
+	for pubKeyIterator.HasNext() {
+		key, err := pubKeyIterator.GetNext().MarshalBinary()
+		if err != nil {
+			return xerrors.Errorf("failed to serialize a public key from the roster : %v ", err)
+		}
+
+		if bytes.Equal(publicKey, key) {
+			isAMember = true
+		}
+	}
+
+	if !isAMember {
+		return xerrors.Errorf("public key not associated to a member of the roster: %x", publicKey)
+	}
+
+	return nil
+}
+
+// addressOf returns the address of the member of the roster with the public
+// key.
+func addressOf(roster authority.Authority, publicKey []byte) (mino.Address, error) {
+	addrIter := roster.AddressIterator()
+	pubKeyIter := roster.PublicKeyIterator()
+
+	for addrIter.HasNext() && pubKeyIter.HasNext() {
+		addr := addrIter.GetNext()
+
+		key, err := pubKeyIter.GetNext().MarshalBinary()
+		if err != nil {
+			return nil, xerrors.Errorf("failed to serialize a public key from the roster : %v ", err)
+		}
+
+		if bytes.Equal(publicKey, key) {
+			return addr, nil
+		}
+	}
+
+	return nil, xerrors.Errorf("public key not associated to a member of the roster: %x", publicKey)
+}
+
+// dkgRoster returns the members of the roster that participate in the DKG, in
+// the order of their index in the DKG. The participants are given by their
+// address as text.
+func dkgRoster(roster authority.Authority, participants [][]byte) (authority.Authority, error) {
+	addrs := make([]mino.Address, len(participants))
+	pubkeys := make([]crypto.PublicKey, len(participants))
+
+	for i, participant := range participants {
+		for _, other := range participants[:i] {
+			if bytes.Equal(other, participant) {
+				return nil, xerrors.Errorf("participant %q appears twice", participant)
+			}
+		}
+
+		addrIter := roster.AddressIterator()
+		pubKeyIter := roster.PublicKeyIterator()
+
+		for addrIter.HasNext() && pubKeyIter.HasNext() {
+			addr := addrIter.GetNext()
+			pubkey := pubKeyIter.GetNext()
+
+			text, err := addr.MarshalText()
+			if err != nil {
+				return nil, xerrors.Errorf("failed to marshal address: %v", err)
+			}
+
+			if bytes.Equal(text, participant) {
+				addrs[i] = addr
+				pubkeys[i] = pubkey
+				break
+			}
+		}
+
+		if addrs[i] == nil {
+			return nil, xerrors.Errorf("participant %q is not a member of the roster", participant)
+		}
+	}
+
+	return authority.New(addrs, pubkeys), nil
+}
+
+// SemiRandomStream implements cipher.Stream
+type SemiRandomStream struct {
+	// Seed is the seed on which should be based our random number generation
+	seed []byte
+
+	stream *rand.Rand
+}
+
+// NewSemiRandomStream returns a new initialized semi-random struct based on
+// math.Rand. This random stream is not cryptographically safe: it is only used
+// to derive the random vectors of types.RandomVectorLegacy.
+//
+// - implements cipher.Stream
+func NewSemiRandomStream(seed []byte) (SemiRandomStream, error) {
+	if len(seed) > 8 {
+		seed = seed[0:8]
+	}
+
+	s, n := binary.Varint(seed)
+	if n <= 0 {
+		return SemiRandomStream{}, xerrors.Errorf("the seed has a wrong size (too small)")
+	}
+
+	source := rand.NewSource(s)
+	stream := rand.New(source)
+
+	return SemiRandomStream{stream: stream, seed: seed}, nil
+}
+
+// XORKeyStream implements cipher.Stream
+func (s SemiRandomStream) XORKeyStream(dst, src []byte) {
+	key := make([]byte, len(src))
+
+	_, err := s.stream.Read(key)
+	if err != nil {
+		panic("error reading into semi random stream :" + err.Error())
+	}
+
+	xof := suite.XOF(key)
+	xof.XORKeyStream(dst, src)
+}
+
+// ballotWrites are the writes that store a ballot. They are computed before
+// anything is written so that an invalid ballot leaves the snapshot untouched.
+type ballotWrites struct {
+	key        []byte
+	ballot     []byte
+	receiptKey []byte
+	userID     []byte
+
+	// indexKey is set for the first ballot of the voter, previousReceiptKey
+	// for a ballot that replaces another one.
+	indexKey           []byte
+	previousReceiptKey []byte
+}
+
+// storeBallot stores the ballot under its own key. A new voter is added to the
+// index so that the cost doesn't depend on the number of ballots already cast.
+func (e evotingCommand) storeBallot(snap store.Snapshot, election *types.Election,
+	electionID []byte, userID string, ballot types.Ciphervote) error {
+
+	writes, err := e.prepareBallot(snap, election, electionID, userID, ballot)
+	if err != nil {
+		return xerrors.Errorf("invalid ballot: %v", err)
+	}
+
+	return writes.apply(snap, election)
+}
+
+// prepareBallot computes the writes that store the ballot, without writing
+// anything.
+func (e evotingCommand) prepareBallot(snap store.Snapshot, election *types.Election,
+	electionID []byte, userID string, ballot types.Ciphervote) (ballotWrites, error) {
+
+	writes := ballotWrites{
+		key:    types.BallotKey(electionID, userID),
+		userID: []byte(userID),
+	}
+
+	previous, err := snap.Get(writes.key)
+	if err != nil {
+		return writes, xerrors.Errorf("failed to get ballot: %v", err)
+	}
+
+	if len(previous) == 0 {
+		writes.indexKey = types.BallotIndexKey(electionID, election.Suffragia.Count)
+	} else {
+		// the receipt of the replaced ballot is no longer valid
+		previousBallot, err := e.decodeCiphervote(previous)
+		if err != nil {
+			return writes, xerrors.Errorf("failed to decode previous ballot: %v", err)
+		}
+
+		previousReceipt, err := previousBallot.Receipt()
+		if err != nil {
+			return writes, xerrors.Errorf("failed to get previous receipt: %v", err)
+		}
+
+		writes.previousReceiptKey = types.ReceiptKey(electionID, previousReceipt)
+	}
+
+	writes.ballot, err = ballot.Serialize(e.context)
+	if err != nil {
+		return writes, xerrors.Errorf("failed to marshal ballot: %v", err)
+	}
+
+	receipt, err := ballot.Receipt()
+	if err != nil {
+		return writes, xerrors.Errorf("failed to get receipt: %v", err)
+	}
+
+	writes.receiptKey = types.ReceiptKey(electionID, receipt)
+
+	return writes, nil
+}
+
+// apply performs the writes. It only fails if the store does.
+func (w ballotWrites) apply(snap store.Snapshot, election *types.Election) error {
+	if w.indexKey != nil {
+		err := snap.Set(w.indexKey, w.userID)
+		if err != nil {
+			return xerrors.Errorf("failed to set index: %v", err)
+		}
+
+		election.Suffragia.Count++
+	}
+
+	if w.previousReceiptKey != nil {
+		err := snap.Delete(w.previousReceiptKey)
+		if err != nil {
+			return xerrors.Errorf("failed to delete previous receipt: %v", err)
+		}
+	}
+
+	err := snap.Set(w.key, w.ballot)
+	if err != nil {
+		return xerrors.Errorf("failed to set ballot: %v", err)
+	}
+
+	err = snap.Set(w.receiptKey, w.userID)
+	if err != nil {
+		return xerrors.Errorf("failed to set receipt: %v", err)
+	}
+
+	return nil
+}
+
+// deleteReceipt removes the key of the receipt of the ballot.
+func (e evotingCommand) deleteReceipt(snap store.Snapshot, electionID []byte,
+	ballot types.Ciphervote) error {
+
+	receipt, err := ballot.Receipt()
+	if err != nil {
+		return xerrors.Errorf("failed to get receipt: %v", err)
+	}
+
+	err = snap.Delete(types.ReceiptKey(electionID, receipt))
+	if err != nil {
+		return xerrors.Errorf("failed to delete receipt: %v", err)
+	}
+
+	return nil
+}
+
+// decodeCiphervote deserializes a ballot stored under its own key.
+func (e evotingCommand) decodeCiphervote(ballotBuf []byte) (types.Ciphervote, error) {
+	message, err := e.ciphervoteFac.Deserialize(e.context, ballotBuf)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to deserialize: %v", err)
+	}
+
+	ciphervote, ok := message.(types.Ciphervote)
+	if !ok {
+		return nil, xerrors.Errorf("wrong message type: %T", message)
+	}
+
+	return ciphervote, nil
+}
+
+// readBallots returns the ballots stored under their own keys, in the order of
+// the index, or the suffragia if they are already gathered. Nothing is written.
+func (e evotingCommand) readBallots(snap store.Snapshot, election types.Election,
+	electionID []byte) ([]string, []types.Ciphervote, error) {
+
+	// already gathered
+	if len(election.Suffragia.UserIDs) != 0 {
+		return election.Suffragia.UserIDs, election.Suffragia.Ciphervotes, nil
+	}
+
+	userIDs := make([]string, election.Suffragia.Count)
+	ciphervotes := make([]types.Ciphervote, election.Suffragia.Count)
+
+	for i := 0; i < election.Suffragia.Count; i++ {
+		userID, err := snap.Get(types.BallotIndexKey(electionID, i))
+		if err != nil {
+			return nil, nil, xerrors.Errorf("failed to get index %d: %v", i, err)
+		}
+
+		ballotBuf, err := snap.Get(types.BallotKey(electionID, string(userID)))
+		if err != nil {
+			return nil, nil, xerrors.Errorf("failed to get ballot %d: %v", i, err)
+		}
+
+		ciphervote, err := e.decodeCiphervote(ballotBuf)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("failed to decode ballot %d: %v", i, err)
+		}
+
+		userIDs[i] = string(userID)
+		ciphervotes[i] = ciphervote
 	}
 
-	return nil
+	return userIDs, ciphervotes, nil
 }
 
-// combineShares implements commands. It performs the COMBINE_SHARES command
-func (e evotingCommand) combineShares(snap store.Snapshot, step execution.Step) error {
+// moveBallots sets the ballots read by readBallots as the suffragia of the
+// election and removes their own keys and the index.
+func (e evotingCommand) moveBallots(snap store.Snapshot, election *types.Election,
+	electionID []byte, userIDs []string, ciphervotes []types.Ciphervote) error {
 
-	msg, err := e.getTransaction(step.Current)
-	if err != nil {
-		return xerrors.Errorf(errGetTransaction, err)
+	// already gathered
+	if len(election.Suffragia.UserIDs) != 0 {
+		return nil
 	}
 
-	tx, ok := msg.(types.CombineShares)
-	if !ok {
-		return xerrors.Errorf(errWrongTx, msg)
-	}
+	for i, userID := range userIDs {
+		err := snap.Delete(types.BallotKey(electionID, userID))
+		if err != nil {
+			return xerrors.Errorf("failed to delete ballot %d: %v", i, err)
+		}
 
-	election, electionID, err := e.getElection(tx.ElectionID, snap)
-	if err != nil {
-		return xerrors.Errorf(errGetElection, err)
+		err = snap.Delete(types.BallotIndexKey(electionID, i))
+		if err != nil {
+			return xerrors.Errorf("failed to delete index %d: %v", i, err)
+		}
 	}
 
-	if election.Status != types.PubSharesSubmitted {
-		return xerrors.Errorf("the public shares have not been submitted,"+
-			" current status: %d", election.Status)
-	}
+	election.Suffragia.UserIDs = userIDs
+	election.Suffragia.Ciphervotes = ciphervotes
 
-	allPubShares := election.PubsharesUnits.Pubshares
+	return nil
+}
 
-	shufflesSize := len(election.ShuffleInstances)
+// deleteBallots removes the keys of the ballots, of their receipts, and of the
+// index.
+func (e evotingCommand) deleteBallots(snap store.Snapshot, election types.Election,
+	electionID []byte) error {
 
-	shuffledBallotsSize := len(election.ShuffleInstances[shufflesSize-1].ShuffledBallots)
-	ballotSize := len(election.ShuffleInstances[shufflesSize-1].ShuffledBallots[0])
+	for i := 0; i < election.Suffragia.Count; i++ {
+		indexKey := types.BallotIndexKey(electionID, i)
 
-	decryptedBallots := make([]types.Ballot, shuffledBallotsSize)
+		userID, err := snap.Get(indexKey)
+		if err != nil {
+			return xerrors.Errorf("failed to get index %d: %v", i, err)
+		}
 
-	for i := 0; i < shuffledBallotsSize; i++ {
-		// decryption of one ballot:
-		marshalledBallot := strings.Builder{}
+		key := types.BallotKey(electionID, string(userID))
 
-		for j := 0; j < ballotSize; j++ {
-			chunk, err := decrypt(i, j, allPubShares, election.PubsharesUnits.Indexes)
-			if err != nil {
-				return xerrors.Errorf("failed to decrypt (K, C): %v", err)
-			}
+		ballotBuf, err := snap.Get(key)
+		if err != nil {
+			return xerrors.Errorf("failed to get ballot %d: %v", i, err)
+		}
 
-			marshalledBallot.Write(chunk)
+		ciphervote, err := e.decodeCiphervote(ballotBuf)
+		if err != nil {
+			return xerrors.Errorf("failed to decode ballot %d: %v", i, err)
 		}
 
-		var ballot types.Ballot
-		err = ballot.Unmarshal(marshalledBallot.String(), election)
+		err = e.deleteReceipt(snap, electionID, ciphervote)
+		if err != nil {
+			return xerrors.Errorf("failed to delete receipt %d: %v", i, err)
+		}
 
+		err = snap.Delete(key)
 		if err != nil {
-			dela.Logger.Warn().Msgf("Failed to unmarshal a ballot: %v", err)
+			return xerrors.Errorf("failed to delete ballot %d: %v", i, err)
 		}
 
-		decryptedBallots[i] = ballot
+		err = snap.Delete(indexKey)
+		if err != nil {
+			return xerrors.Errorf("failed to delete index %d: %v", i, err)
+		}
 	}
 
-	election.DecryptedBallots = decryptedBallots
+	return nil
+}
 
-	election.Status = types.ResultAvailable
-	PromElectionStatus.WithLabelValues(election.ElectionID).Set(float64(election.Status))
+// getElection gets the election from the snap. Returns the election ID NOT hex
+// encoded.
+func (e evotingCommand) getElection(electionIDHex string,
+	snap store.Snapshot) (types.Election, []byte, error) {
 
-	electionBuf, err := election.Serialize(e.context)
+	election, electionID, err := e.loadElection(electionIDHex, snap)
 	if err != nil {
-		return xerrors.Errorf("failed to marshal Election : %v", err)
+		return election, nil, err
 	}
 
-	err = snap.Set(electionID, electionBuf)
+	migrated, err := e.migrateCatalog(snap)
 	if err != nil {
-		return xerrors.Errorf("failed to set value: %v", err)
+		return election, nil, xerrors.Errorf("failed to migrate catalog: %v", err)
 	}
 
-	return nil
+	// the catalog index of a legacy election is only known once migrated
+	if migrated {
+		return e.loadElection(electionIDHex, snap)
+	}
+
+	return election, electionID, nil
 }
 
-// cancelElection implements commands. It performs the CANCEL_ELECTION command
-func (e evotingCommand) cancelElection(snap store.Snapshot, step execution.Step) error {
+// loadElection reads the election from the snap. Returns the election ID NOT
+// hex encoded.
+func (e evotingCommand) loadElection(electionIDHex string,
+	snap store.Snapshot) (types.Election, []byte, error) {
 
-	msg, err := e.getTransaction(step.Current)
+	var election types.Election
+
+	electionID, err := hex.DecodeString(electionIDHex)
 	if err != nil {
-		return xerrors.Errorf(errGetTransaction, err)
+		return election, nil, xerrors.Errorf("failed to decode electionIDHex: %v", err)
 	}
 
-	tx, ok := msg.(types.CancelElection)
+	electionBuff, err := snap.Get(electionID)
+	if err != nil {
+		return election, nil, xerrors.Errorf("failed to get key %q: %v", electionID, err)
+	}
+
+	message, err := e.electionFac.Deserialize(e.context, electionBuff)
+	if err != nil {
+		return election, nil, xerrors.Errorf("failed to deserialize Election: %v", err)
+	}
+
+	election, ok := message.(types.Election)
 	if !ok {
-		return xerrors.Errorf(errWrongTx, msg)
+		return election, nil, xerrors.Errorf("wrong message type: %T", message)
 	}
 
-	election, electionID, err := e.getElection(tx.ElectionID, snap)
+	if electionIDHex != election.ElectionID {
+		return election, nil, xerrors.Errorf("electionID do not match: %q != %q",
+			electionIDHex, election.ElectionID)
+	}
+
+	electionIDBuff, err := hex.DecodeString(electionIDHex)
 	if err != nil {
-		return xerrors.Errorf(errGetElection, err)
+		return election, nil, xerrors.Errorf("failed to get election id buff: %v", err)
 	}
 
-	election.Status = types.Canceled
-	PromElectionStatus.WithLabelValues(election.ElectionID).Set(float64(election.Status))
+	return election, electionIDBuff, nil
+}
+
+// saveElection stores the election and keeps its catalog entry up to date.
+func (e evotingCommand) saveElection(snap store.Snapshot, election types.Election,
+	electionID []byte) error {
+
+	pruneActions(&election)
 
 	electionBuf, err := election.Serialize(e.context)
 	if err != nil {
@@ -702,168 +2423,168 @@ func (e evotingCommand) cancelElection(snap store.Snapshot, step execution.Step)
 		return xerrors.Errorf("failed to set value: %v", err)
 	}
 
-	return nil
-}
-
-// deleteElection implements commands. It performs the DELETE_ELECTION command
-func (e evotingCommand) deleteElection(snap store.Snapshot, step execution.Step) error {
+	entry, err := types.NewCatalogEntry(election)
+	if err != nil {
+		return xerrors.Errorf("failed to create catalog entry: %v", err)
+	}
 
-	msg, err := e.getTransaction(step.Current)
+	current, err := e.getCatalogEntry(snap, election.CatalogIndex)
 	if err != nil {
-		return xerrors.Errorf(errGetTransaction, err)
+		return xerrors.Errorf("failed to get catalog entry: %v", err)
 	}
 
-	tx, ok := msg.(types.DeleteElection)
-	if !ok {
-		return xerrors.Errorf(errWrongTx, msg)
+	// most updates, like casting a ballot, don't change the entry
+	if current == entry {
+		return nil
 	}
 
-	election, electionID, err := e.getElection(tx.ElectionID, snap)
+	err = e.setCatalogEntry(snap, election.CatalogIndex, entry)
 	if err != nil {
-		return xerrors.Errorf(errGetElection, err)
+		return xerrors.Errorf("failed to update catalog entry: %v", err)
 	}
 
-	err = snap.Delete(electionID)
+	return nil
+}
+
+// addToCatalog appends the entry of the election at the end of the catalog and
+// returns its index.
+func (e evotingCommand) addToCatalog(snap store.Snapshot,
+	election types.Election) (uint64, error) {
+
+	_, err := e.migrateCatalog(snap)
 	if err != nil {
-		return xerrors.Errorf("failed to delete election: %v", err)
+		return 0, xerrors.Errorf("failed to migrate catalog: %v", err)
 	}
 
-	// Update the election metadata store
+	countBuf, err := snap.Get([]byte(types.CatalogCountKey))
+	if err != nil {
+		return 0, xerrors.Errorf("failed to get catalog count: %v", err)
+	}
 
-	electionsMetadataBuf, err := snap.Get([]byte(ElectionsMetadataKey))
+	index, err := types.DecodeCatalogCount(countBuf)
 	if err != nil {
-		return xerrors.Errorf("failed to get key '%s': %v", electionsMetadataBuf, err)
+		return 0, xerrors.Errorf("failed to decode catalog count: %v", err)
 	}
 
-	if len(electionsMetadataBuf) == 0 {
-		return nil
+	entry, err := types.NewCatalogEntry(election)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to create catalog entry: %v", err)
 	}
 
-	var electionsMetadata types.ElectionsMetadata
+	err = e.setCatalogEntry(snap, index, entry)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to set catalog entry: %v", err)
+	}
 
-	err = json.Unmarshal(electionsMetadataBuf, &electionsMetadata)
+	err = snap.Set([]byte(types.CatalogCountKey), types.EncodeCatalogCount(index+1))
 	if err != nil {
-		return xerrors.Errorf("failed to unmarshal ElectionsMetadata: %v", err)
+		return 0, xerrors.Errorf("failed to set catalog count: %v", err)
 	}
 
-	electionsMetadata.ElectionsIDs.Remove(election.ElectionID)
+	return index, nil
+}
 
-	electionMetadataJSON, err := json.Marshal(electionsMetadata)
+// migrateCatalog moves the elections listed at the legacy key to the catalog,
+// in order, and removes the key. Each election is stored again with its catalog
+// index. It does nothing once the key is removed, and returns true if the
+// elections have been migrated.
+func (e evotingCommand) migrateCatalog(snap store.Snapshot) (bool, error) {
+	legacyBuf, err := snap.Get([]byte(types.LegacyElectionsKey))
 	if err != nil {
-		return xerrors.Errorf("failed to marshal ElectionsMetadata: %v", err)
+		return false, xerrors.Errorf("failed to get legacy elections: %v", err)
 	}
 
-	err = snap.Set([]byte(ElectionsMetadataKey), electionMetadataJSON)
-	if err != nil {
-		return xerrors.Errorf("failed to set value: %v", err)
+	if len(legacyBuf) == 0 {
+		return false, nil
 	}
 
-	return nil
-}
+	electionIDs, err := types.DecodeLegacyElectionIDs(legacyBuf)
+	if err != nil {
+		return false, xerrors.Errorf("failed to decode legacy elections: %v", err)
+	}
 
-// isMemberOf is a utility function to verify if a public key is associated to a
-// member of the roster or not. Returns nil if it's the case.
-func isMemberOf(roster authority.Authority, publicKey []byte) error {
-	pubKeyIterator := roster.PublicKeyIterator()
-	isAMember := false
+	// all the elections are read before anything is written
+	elections := make([]types.Election, 0, len(electionIDs))
+	ids := make([][]byte, 0, len(electionIDs))
 
-	for pubKeyIterator.HasNext() {
-		key, err := pubKeyIterator.GetNext().MarshalBinary()
+	for _, electionIDHex := range electionIDs {
+		electionID, err := hex.DecodeString(electionIDHex)
 		if err != nil {
-			return xerrors.Errorf("failed to serialize a public key from the roster : %v ", err)
+			return false, xerrors.Errorf("failed to decode electionIDHex: %v", err)
 		}
 
-		if bytes.Equal(publicKey, key) {
-			isAMember = true
+		electionBuf, err := snap.Get(electionID)
+		if err != nil {
+			return false, xerrors.Errorf("failed to get election %q: %v", electionIDHex, err)
 		}
-	}
-
-	if !isAMember {
-		return xerrors.Errorf("public key not associated to a member of the roster: %x", publicKey)
-	}
-
-	return nil
-}
 
-// SemiRandomStream implements cipher.Stream
-type SemiRandomStream struct {
-	// Seed is the seed on which should be based our random number generation
-	seed []byte
+		// the election doesn't exist anymore
+		if len(electionBuf) == 0 {
+			continue
+		}
 
-	stream *rand.Rand
-}
+		election, _, err := e.loadElection(electionIDHex, snap)
+		if err != nil {
+			return false, xerrors.Errorf("failed to get election %q: %v", electionIDHex, err)
+		}
 
-// NewSemiRandomStream returns a new initialized semi-random struct based on
-// math.Rand. This random stream is not cryptographically safe.
-//
-// - implements cipher.Stream
-func NewSemiRandomStream(seed []byte) (SemiRandomStream, error) {
-	if len(seed) > 8 {
-		seed = seed[0:8]
+		elections = append(elections, election)
+		ids = append(ids, electionID)
 	}
 
-	s, n := binary.Varint(seed)
-	if n <= 0 {
-		return SemiRandomStream{}, xerrors.Errorf("the seed has a wrong size (too small)")
+	// removed first so that adding to the catalog doesn't migrate again
+	err = snap.Delete([]byte(types.LegacyElectionsKey))
+	if err != nil {
+		return false, xerrors.Errorf("failed to delete legacy elections: %v", err)
 	}
 
-	source := rand.NewSource(s)
-	stream := rand.New(source)
-
-	return SemiRandomStream{stream: stream, seed: seed}, nil
-}
-
-// XORKeyStream implements cipher.Stream
-func (s SemiRandomStream) XORKeyStream(dst, src []byte) {
-	key := make([]byte, len(src))
+	for i, election := range elections {
+		election.CatalogIndex, err = e.addToCatalog(snap, election)
+		if err != nil {
+			return false, xerrors.Errorf("failed to add election to the catalog: %v", err)
+		}
 
-	_, err := s.stream.Read(key)
-	if err != nil {
-		panic("error reading into semi random stream :" + err.Error())
+		err = e.saveElection(snap, election, ids[i])
+		if err != nil {
+			return false, xerrors.Errorf("failed to save election: %v", err)
+		}
 	}
 
-	xof := suite.XOF(key)
-	xof.XORKeyStream(dst, src)
+	return true, nil
 }
 
-// getElection gets the election from the snap. Returns the election ID NOT hex
-// encoded.
-func (e evotingCommand) getElection(electionIDHex string,
-	snap store.Snapshot) (types.Election, []byte, error) {
-
-	var election types.Election
+// getCatalogEntry reads the index-th entry of the catalog. It returns an empty
+// entry if it doesn't exist.
+func (e evotingCommand) getCatalogEntry(snap store.Snapshot,
+	index uint64) (types.CatalogEntry, error) {
 
-	electionID, err := hex.DecodeString(electionIDHex)
+	entryBuf, err := snap.Get(types.CatalogKey(index))
 	if err != nil {
-		return election, nil, xerrors.Errorf("failed to decode electionIDHex: %v", err)
+		return types.CatalogEntry{}, xerrors.Errorf("failed to get entry %d: %v", index, err)
 	}
 
-	electionBuff, err := snap.Get(electionID)
-	if err != nil {
-		return election, nil, xerrors.Errorf("failed to get key %q: %v", electionID, err)
+	if len(entryBuf) == 0 {
+		return types.CatalogEntry{}, nil
 	}
 
-	message, err := e.electionFac.Deserialize(e.context, electionBuff)
-	if err != nil {
-		return election, nil, xerrors.Errorf("failed to deserialize Election: %v", err)
-	}
+	return types.DecodeCatalogEntry(entryBuf)
+}
 
-	election, ok := message.(types.Election)
-	if !ok {
-		return election, nil, xerrors.Errorf("wrong message type: %T", message)
-	}
+// setCatalogEntry stores the index-th entry of the catalog.
+func (e evotingCommand) setCatalogEntry(snap store.Snapshot, index uint64,
+	entry types.CatalogEntry) error {
 
-	if electionIDHex != election.ElectionID {
-		return election, nil, xerrors.Errorf("electionID do not match: %q != %q",
-			electionIDHex, election.ElectionID)
+	entryBuf, err := types.EncodeCatalogEntry(entry)
+	if err != nil {
+		return xerrors.Errorf("failed to encode entry: %v", err)
 	}
 
-	electionIDBuff, err := hex.DecodeString(electionIDHex)
+	err = snap.Set(types.CatalogKey(index), entryBuf)
 	if err != nil {
-		return election, nil, xerrors.Errorf("failed to get election id buff: %v", err)
+		return xerrors.Errorf("failed to set entry %d: %v", index, err)
 	}
 
-	return election, electionIDBuff, nil
+	return nil
 }
 
 // getTransaction extracts the argument from the transaction.
@@ -881,10 +2602,57 @@ func (e evotingCommand) getTransaction(tx txn.Transaction) (serde.Message, error
 	return message, nil
 }
 
-// decrypt combines the public shares to reconstruct the secret
-// (i.e. encrypted ballots).
-func decrypt(ballot int, pair int, allPubShares []types.PubsharesUnit, indexes []int) (
-	[]byte, error) {
+// decryptBallots decrypts the ballots of the last shuffle with the pubshares
+// of the election, and computes the tally. A ballot that can't be parsed is
+// counted as invalid.
+func decryptBallots(election types.Election) ([]types.Ballot, types.Tally, error) {
+	allPubShares := election.PubsharesUnits.Pubshares
+
+	shufflesSize := len(election.ShuffleInstances)
+
+	shuffledBallotsSize := len(election.ShuffleInstances[shufflesSize-1].ShuffledBallots)
+	ballotSize := len(election.ShuffleInstances[shufflesSize-1].ShuffledBallots[0])
+
+	decryptedBallots := make([]types.Ballot, shuffledBallotsSize)
+	tally := types.NewTally(election.Configuration)
+
+	for i := 0; i < shuffledBallotsSize; i++ {
+		// decryption of one ballot:
+		marshalledBallot := strings.Builder{}
+
+		for j := 0; j < ballotSize; j++ {
+			chunk, err := decrypt(i, j, allPubShares, election.PubsharesUnits.Indexes,
+				election.DecryptionThreshold)
+			if err != nil {
+				return nil, tally, xerrors.Errorf("failed to decrypt (K, C): %v", err)
+			}
+
+			marshalledBallot.Write(chunk)
+		}
+
+		var ballot types.Ballot
+		err := ballot.Unmarshal(marshalledBallot.String(), election)
+
+		if err != nil {
+			dela.Logger.Warn().Msgf("Failed to unmarshal a ballot: %v", err)
+			tally.AddInvalid()
+		} else {
+			tally.Add(ballot)
+		}
+
+		decryptedBallots[i] = ballot
+	}
+
+	tally.Finalize()
+
+	return decryptedBallots, tally, nil
+}
+
+// decrypt combines t public shares to reconstruct the secret (i.e. encrypted
+// ballots). If t is 0, which is the case of the elections created before the
+// decryption threshold existed, all the shares are combined.
+func decrypt(ballot int, pair int, allPubShares []types.PubsharesUnit, indexes []int,
+	t int) ([]byte, error) {
 	pubShares := make([]*share.PubShare, 0)
 
 	for i := 0; i < len(allPubShares); i++ {
@@ -898,7 +2666,11 @@ func decrypt(ballot int, pair int, allPubShares []types.PubsharesUnit, indexes [
 		}
 	}
 
-	res, err := share.RecoverCommit(suite, pubShares, len(pubShares), len(pubShares))
+	if t == 0 {
+		t = len(pubShares)
+	}
+
+	res, err := share.RecoverCommit(suite, pubShares, t, len(pubShares))
 	if err != nil {
 		return nil, xerrors.Errorf("failed to recover commit: %v", err)
 	}
@@ -35,6 +35,15 @@ func (electionFormat) Encode(ctx serde.Context, message serde.Message) ([]byte,
 			}
 		}
 
+		verificationKeys := make([][]byte, len(m.VerificationKeys))
+
+		for i, key := range m.VerificationKeys {
+			verificationKeys[i], err = key.MarshalBinary()
+			if err != nil {
+				return nil, xerrors.Errorf("failed to marshall verification key: %v", err)
+			}
+		}
+
 		suffragia, err := encodeSuffragia(ctx, m.Suffragia)
 		if err != nil {
 			return nil, xerrors.Errorf("failed to encode suffragia: %v", err)
@@ -57,17 +66,29 @@ func (electionFormat) Encode(ctx serde.Context, message serde.Message) ([]byte,
 		}
 
 		electionJSON := ElectionJSON{
-			Configuration:    m.Configuration,
-			ElectionID:       m.ElectionID,
-			Status:           uint16(m.Status),
-			Pubkey:           pubkey,
-			BallotSize:       m.BallotSize,
-			Suffragia:        suffragia,
-			ShuffleInstances: shuffleInstances,
-			ShuffleThreshold: m.ShuffleThreshold,
-			PubsharesUnits:   pubsharesUnits,
-			DecryptedBallots: m.DecryptedBallots,
-			RosterBuf:        rosterBuf,
+			Configuration:       m.Configuration,
+			ElectionID:          m.ElectionID,
+			CatalogIndex:        m.CatalogIndex,
+			AdminID:             m.AdminID,
+			Admins:              m.Admins,
+			PendingActions:      m.PendingActions,
+			AdminNonce:          m.AdminNonce,
+			Status:              uint16(m.Status),
+			StatusReason:        m.StatusReason,
+			Pubkey:              pubkey,
+			VerificationKeys:    verificationKeys,
+			DKGResults:          m.DKGResults,
+			BallotSize:          m.BallotSize,
+			Suffragia:           suffragia,
+			Electorate:          m.Electorate,
+			ShuffleInstances:    shuffleInstances,
+			ShuffleThreshold:    m.ShuffleThreshold,
+			DecryptionThreshold: m.DecryptionThreshold,
+			RandomVectorVersion: uint16(m.RandomVectorVersion),
+			PubsharesUnits:      pubsharesUnits,
+			DecryptedBallots:    m.DecryptedBallots,
+			Tally:               m.Tally,
+			RosterBuf:           rosterBuf,
 		}
 
 		buff, err := ctx.Marshal(&electionJSON)
@@ -100,6 +121,19 @@ func (electionFormat) Decode(ctx serde.Context, data []byte) (serde.Message, err
 		}
 	}
 
+	var verificationKeys []kyber.Point
+
+	for _, buf := range electionJSON.VerificationKeys {
+		key := suite.Point()
+
+		err = key.UnmarshalBinary(buf)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to unmarshal verification key: %v", err)
+		}
+
+		verificationKeys = append(verificationKeys, key)
+	}
+
 	suffragia, err := decodeSuffragia(ctx, electionJSON.Suffragia)
 	if err != nil {
 		return nil, xerrors.Errorf("failed to decode suffragia: %v", err)
@@ -127,17 +161,29 @@ func (electionFormat) Decode(ctx serde.Context, data []byte) (serde.Message, err
 	}
 
 	return types.Election{
-		Configuration:    electionJSON.Configuration,
-		ElectionID:       electionJSON.ElectionID,
-		Status:           types.Status(electionJSON.Status),
-		Pubkey:           pubKey,
-		BallotSize:       electionJSON.BallotSize,
-		Suffragia:        suffragia,
-		ShuffleInstances: shuffleInstances,
-		ShuffleThreshold: electionJSON.ShuffleThreshold,
-		PubsharesUnits:   pubSharesSubmissions,
-		DecryptedBallots: electionJSON.DecryptedBallots,
-		Roster:           roster,
+		Configuration:       electionJSON.Configuration,
+		ElectionID:          electionJSON.ElectionID,
+		CatalogIndex:        electionJSON.CatalogIndex,
+		AdminID:             electionJSON.AdminID,
+		Admins:              electionJSON.Admins,
+		PendingActions:      electionJSON.PendingActions,
+		AdminNonce:          electionJSON.AdminNonce,
+		Status:              types.Status(electionJSON.Status),
+		StatusReason:        electionJSON.StatusReason,
+		Pubkey:              pubKey,
+		VerificationKeys:    verificationKeys,
+		DKGResults:          electionJSON.DKGResults,
+		BallotSize:          electionJSON.BallotSize,
+		Suffragia:           suffragia,
+		Electorate:          electionJSON.Electorate,
+		ShuffleInstances:    shuffleInstances,
+		ShuffleThreshold:    electionJSON.ShuffleThreshold,
+		DecryptionThreshold: electionJSON.DecryptionThreshold,
+		RandomVectorVersion: types.RandomVectorVersion(electionJSON.RandomVectorVersion),
+		PubsharesUnits:      pubSharesSubmissions,
+		DecryptedBallots:    electionJSON.DecryptedBallots,
+		Tally:               electionJSON.Tally,
+		Roster:              roster,
 	}, nil
 }
 
@@ -149,9 +195,20 @@ type ElectionJSON struct {
 	// the election
 	ElectionID string
 
-	AdminID string
-	Status  uint16
-	Pubkey  []byte `json:"Pubkey,omitempty"`
+	// CatalogIndex is the position of the election in the catalog
+	CatalogIndex uint64
+
+	AdminID        string
+	Admins         types.AdminSet
+	PendingActions []types.PendingAction
+	AdminNonce     uint64 `json:",omitempty"`
+	Status         uint16
+	StatusReason   string `json:",omitempty"`
+	Pubkey         []byte `json:"Pubkey,omitempty"`
+
+	VerificationKeys [][]byte `json:",omitempty"`
+
+	DKGResults []types.DKGResult `json:",omitempty"`
 
 	// BallotSize represents the total size in bytes of one ballot. It is used
 	// to pad smaller ballots such that all  ballots cast have the same size
@@ -159,6 +216,8 @@ type ElectionJSON struct {
 
 	Suffragia SuffragiaJSON
 
+	Electorate types.Electorate
+
 	// ShuffleInstances is all the shuffles, along with their proof and identity
 	// of shuffler.
 	ShuffleInstances []ShuffleInstanceJSON
@@ -167,10 +226,18 @@ type ElectionJSON struct {
 	// to compute it based on the roster each time we need it.
 	ShuffleThreshold int
 
+	DecryptionThreshold int `json:",omitempty"`
+
+	// RandomVectorVersion is omitted for the legacy version, so that the
+	// elections stored before it existed decode to it.
+	RandomVectorVersion uint16 `json:",omitempty"`
+
 	PubsharesUnits PubsharesUnitsJSON
 
 	DecryptedBallots []types.Ballot
 
+	Tally types.Tally
+
 	// roster is set when the election is created based on the current
 	// roster of the node stored in the global state. The roster will not change
 	// during an election and will be used for DKG and Neff. Its type is
@@ -181,6 +248,7 @@ type ElectionJSON struct {
 
 // SuffragiaJSON defines the JSON representation of a suffragia.
 type SuffragiaJSON struct {
+	Count       int
 	UserIDs     []string
 	Ciphervotes []json.RawMessage
 }
@@ -197,6 +265,7 @@ func encodeSuffragia(ctx serde.Context, suffragia types.Suffragia) (SuffragiaJSO
 		ciphervotes[i] = buff
 	}
 	return SuffragiaJSON{
+		Count:       suffragia.Count,
 		UserIDs:     suffragia.UserIDs,
 		Ciphervotes: ciphervotes,
 	}, nil
@@ -228,6 +297,7 @@ func decodeSuffragia(ctx serde.Context, suffragiaJSON SuffragiaJSON) (types.Suff
 	}
 
 	res = types.Suffragia{
+		Count:       suffragiaJSON.Count,
 		UserIDs:     suffragiaJSON.UserIDs,
 		Ciphervotes: ciphervotes,
 	}
@@ -245,6 +315,9 @@ type ShuffleInstanceJSON struct {
 
 	// ShufflerPublicKey is the key of the node who made the given shuffle.
 	ShufflerPublicKey []byte
+
+	// Signature is the signature of the shuffle by the shuffler
+	Signature []byte `json:",omitempty"`
 }
 
 func encodeShuffleInstances(ctx serde.Context,
@@ -283,6 +356,7 @@ func encodeShuffleInstance(ctx serde.Context,
 		ShuffledBallots:   shuffledBallots,
 		ShuffleProofs:     shuffleInstance.ShuffleProofs,
 		ShufflerPublicKey: shuffleInstance.ShufflerPublicKey,
+		Signature:         shuffleInstance.Signature,
 	}
 
 	return res, nil
@@ -336,6 +410,7 @@ func decodeShuffleInstance(ctx serde.Context,
 		ShuffledBallots:   shuffledBallots,
 		ShuffleProofs:     shuffleInstanceJSON.ShuffleProofs,
 		ShufflerPublicKey: shuffleInstanceJSON.ShufflerPublicKey,
+		Signature:         shuffleInstanceJSON.Signature,
 	}
 
 	return res, nil
@@ -352,6 +427,7 @@ type PubsharesUnitsJSON struct {
 	PubsharesJSON []PubsharesUnitJSON
 	PubKeys       [][]byte
 	Indexes       []int
+	Proofs        []types.PubshareProofsUnit `json:",omitempty"`
 }
 
 func encodePubsharesUnits(units types.PubsharesUnits) (
@@ -379,6 +455,7 @@ func encodePubsharesUnits(units types.PubsharesUnits) (
 
 	unitsJSON.Indexes = units.Indexes
 	unitsJSON.PubKeys = units.PubKeys
+	unitsJSON.Proofs = units.Proofs
 	unitsJSON.PubsharesJSON = submissionsJSON
 
 	return unitsJSON, nil
@@ -409,6 +486,7 @@ func decodePubSharesUnits(unitsJSON PubsharesUnitsJSON) (types.PubsharesUnits, e
 
 	units.Indexes = unitsJSON.Indexes
 	units.PubKeys = unitsJSON.PubKeys
+	units.Proofs = unitsJSON.Proofs
 	units.Pubshares = submissions
 
 	return units, nil
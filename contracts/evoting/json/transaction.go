@@ -20,34 +20,62 @@ func (transactionFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, e
 	switch t := msg.(type) {
 	case types.CreateElection:
 		ce := CreateElectionJSON{
-			Configuration: t.Configuration,
-			AdminID:       t.AdminID,
+			Configuration:       t.Configuration,
+			AdminID:             t.AdminID,
+			Admins:              t.Admins,
+			AdminThreshold:      t.AdminThreshold,
+			ShuffleThreshold:    t.ShuffleThreshold,
+			DecryptionThreshold: t.DecryptionThreshold,
 		}
 
 		m = TransactionJSON{CreateElection: &ce}
 	case types.OpenElection:
 		oe := OpenElectionJSON{
-			ElectionID: t.ElectionID,
+			ElectionID:     t.ElectionID,
+			AdminSignature: t.AdminSignature,
 		}
 
 		m = TransactionJSON{OpenElection: &oe}
+	case types.UpdateConfiguration:
+		uc := UpdateConfigurationJSON{
+			ElectionID:     t.ElectionID,
+			Configuration:  t.Configuration,
+			AdminSignature: t.AdminSignature,
+		}
+
+		m = TransactionJSON{UpdateConfiguration: &uc}
+	case types.UpdateRoster:
+		ur := UpdateRosterJSON{
+			ElectionID:     t.ElectionID,
+			AdminSignature: t.AdminSignature,
+		}
+
+		m = TransactionJSON{UpdateRoster: &ur}
 	case types.CastVote:
-		ballot, err := t.Ballot.Serialize(ctx)
+		cv, err := encodeCastVote(ctx, t)
 		if err != nil {
-			return nil, xerrors.Errorf("failed to serialize ballot: %v", err)
+			return nil, xerrors.Errorf("failed to encode cast vote: %v", err)
 		}
 
-		cv := CastVoteJSON{
-			ElectionID: t.ElectionID,
-			UserID:     t.UserID,
-			Ciphervote: ballot,
+		m = TransactionJSON{CastVote: &cv}
+	case types.CastVotes:
+		votes := make([]CastVoteJSON, len(t.Votes))
+
+		for i, vote := range t.Votes {
+			cv, err := encodeCastVote(ctx, vote)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to encode vote %d: %v", i, err)
+			}
+
+			votes[i] = cv
 		}
 
-		m = TransactionJSON{CastVote: &cv}
+		m = TransactionJSON{CastVotes: &CastVotesJSON{Votes: votes}}
 	case types.CloseElection:
 		ce := CloseElectionJSON{
-			ElectionID: t.ElectionID,
-			UserID:     t.UserID,
+			ElectionID:     t.ElectionID,
+			UserID:         t.UserID,
+			AdminSignature: t.AdminSignature,
 		}
 
 		m = TransactionJSON{CloseElection: &ce}
@@ -64,13 +92,14 @@ func (transactionFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, e
 		}
 
 		sb := ShuffleBallotsJSON{
-			ElectionID:   t.ElectionID,
-			Round:        t.Round,
-			Ciphervotes:  ciphervotes,
-			RandomVector: t.RandomVector,
-			Proof:        t.Proof,
-			Signature:    t.Signature,
-			PublicKey:    t.PublicKey,
+			ElectionID:          t.ElectionID,
+			Round:               t.Round,
+			Ciphervotes:         ciphervotes,
+			RandomVector:        t.RandomVector,
+			RandomVectorVersion: uint16(t.RandomVectorVersion),
+			Proof:               t.Proof,
+			Signature:           t.Signature,
+			PublicKey:           t.PublicKey,
 		}
 
 		m = TransactionJSON{ShuffleBallots: &sb}
@@ -93,6 +122,7 @@ func (transactionFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, e
 			ElectionID: t.ElectionID,
 			Index:      t.Index,
 			PubShares:  pubShares,
+			Proofs:     t.Proofs,
 			Signature:  t.Signature,
 			PublicKey:  t.PublicKey,
 		}
@@ -100,24 +130,87 @@ func (transactionFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, e
 		m = TransactionJSON{RegisterPubShares: &rp}
 	case types.CombineShares:
 		db := CombineSharesJSON{
-			ElectionID: t.ElectionID,
-			UserID:     t.UserID,
+			ElectionID:     t.ElectionID,
+			UserID:         t.UserID,
+			AdminSignature: t.AdminSignature,
 		}
 
 		m = TransactionJSON{CombineShares: &db}
 	case types.CancelElection:
 		ce := CancelElectionJSON{
-			ElectionID: t.ElectionID,
-			UserID:     t.UserID,
+			ElectionID:     t.ElectionID,
+			UserID:         t.UserID,
+			AdminSignature: t.AdminSignature,
 		}
 
 		m = TransactionJSON{CancelElection: &ce}
 	case types.DeleteElection:
 		de := DeleteElectionJSON{
-			ElectionID: t.ElectionID,
+			ElectionID:     t.ElectionID,
+			AdminSignature: t.AdminSignature,
 		}
 
 		m = TransactionJSON{DeleteElection: &de}
+	case types.RegisterVoters:
+		rv := VotersJSON{
+			ElectionID:     t.ElectionID,
+			Voters:         t.Voters,
+			AdminSignature: t.AdminSignature,
+		}
+
+		m = TransactionJSON{RegisterVoters: &rv}
+	case types.RemoveVoters:
+		rv := VotersJSON{
+			ElectionID:     t.ElectionID,
+			Voters:         t.Voters,
+			AdminSignature: t.AdminSignature,
+		}
+
+		m = TransactionJSON{RemoveVoters: &rv}
+	case types.ReplaceVoters:
+		rv := VotersJSON{
+			ElectionID:     t.ElectionID,
+			Voters:         t.Voters,
+			AdminSignature: t.AdminSignature,
+		}
+
+		m = TransactionJSON{ReplaceVoters: &rv}
+	case types.ProposeAction:
+		pa := ProposeActionJSON{
+			ElectionID:     t.ElectionID,
+			Command:        t.Command,
+			AdminID:        t.AdminID,
+			AdminSignature: t.AdminSignature,
+		}
+
+		m = TransactionJSON{ProposeAction: &pa}
+	case types.ApproveAction:
+		aa := ApproveActionJSON{
+			ElectionID:     t.ElectionID,
+			ActionID:       t.ActionID,
+			AdminID:        t.AdminID,
+			AdminSignature: t.AdminSignature,
+		}
+
+		m = TransactionJSON{ApproveAction: &aa}
+	case types.ReportTime:
+		rt := ReportTimeJSON{
+			Timestamp: t.Timestamp,
+			Signature: t.Signature,
+			PublicKey: t.PublicKey,
+		}
+
+		m = TransactionJSON{ReportTime: &rt}
+	case types.RegisterDKG:
+		rd := RegisterDKGJSON{
+			ElectionID:   t.ElectionID,
+			Commits:      t.Commits,
+			Participants: t.Participants,
+			Signature:    t.Signature,
+			PublicKey:    t.PublicKey,
+		}
+
+		m = TransactionJSON{RegisterDKG: &rd}
 	default:
 		return nil, xerrors.Errorf("unknown type: '%T", msg)
 	}
@@ -142,12 +235,28 @@ func (transactionFormat) Decode(ctx serde.Context, data []byte) (serde.Message,
 	switch {
 	case m.CreateElection != nil:
 		return types.CreateElection{
-			Configuration: m.CreateElection.Configuration,
-			AdminID:       m.CreateElection.AdminID,
+			Configuration:       m.CreateElection.Configuration,
+			AdminID:             m.CreateElection.AdminID,
+			Admins:              m.CreateElection.Admins,
+			AdminThreshold:      m.CreateElection.AdminThreshold,
+			ShuffleThreshold:    m.CreateElection.ShuffleThreshold,
+			DecryptionThreshold: m.CreateElection.DecryptionThreshold,
 		}, nil
 	case m.OpenElection != nil:
 		return types.OpenElection{
-			ElectionID: m.OpenElection.ElectionID,
+			ElectionID:     m.OpenElection.ElectionID,
+			AdminSignature: m.OpenElection.AdminSignature,
+		}, nil
+	case m.UpdateConfiguration != nil:
+		return types.UpdateConfiguration{
+			ElectionID:     m.UpdateConfiguration.ElectionID,
+			Configuration:  m.UpdateConfiguration.Configuration,
+			AdminSignature: m.UpdateConfiguration.AdminSignature,
+		}, nil
+	case m.UpdateRoster != nil:
+		return types.UpdateRoster{
+			ElectionID:     m.UpdateRoster.ElectionID,
+			AdminSignature: m.UpdateRoster.AdminSignature,
 		}, nil
 	case m.CastVote != nil:
 		msg, err := decodeCastVote(ctx, *m.CastVote)
@@ -156,10 +265,24 @@ func (transactionFormat) Decode(ctx serde.Context, data []byte) (serde.Message,
 		}
 
 		return msg, nil
+	case m.CastVotes != nil:
+		votes := make([]types.CastVote, len(m.CastVotes.Votes))
+
+		for i, vote := range m.CastVotes.Votes {
+			msg, err := decodeCastVote(ctx, vote)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to decode vote %d: %v", i, err)
+			}
+
+			votes[i] = msg.(types.CastVote)
+		}
+
+		return types.CastVotes{Votes: votes}, nil
 	case m.CloseElection != nil:
 		return types.CloseElection{
-			ElectionID: m.CloseElection.ElectionID,
-			UserID:     m.CloseElection.UserID,
+			ElectionID:     m.CloseElection.ElectionID,
+			UserID:         m.CloseElection.UserID,
+			AdminSignature: m.CloseElection.AdminSignature,
 		}, nil
 	case m.ShuffleBallots != nil:
 		msg, err := decodeShuffleBallots(ctx, *m.ShuffleBallots)
@@ -177,17 +300,66 @@ func (transactionFormat) Decode(ctx serde.Context, data []byte) (serde.Message,
 		return msg, nil
 	case m.CombineShares != nil:
 		return types.CombineShares{
-			ElectionID: m.CombineShares.ElectionID,
-			UserID:     m.CombineShares.UserID,
+			ElectionID:     m.CombineShares.ElectionID,
+			UserID:         m.CombineShares.UserID,
+			AdminSignature: m.CombineShares.AdminSignature,
 		}, nil
 	case m.CancelElection != nil:
 		return types.CancelElection{
-			ElectionID: m.CancelElection.ElectionID,
-			UserID:     m.CancelElection.UserID,
+			ElectionID:     m.CancelElection.ElectionID,
+			UserID:         m.CancelElection.UserID,
+			AdminSignature: m.CancelElection.AdminSignature,
 		}, nil
 	case m.DeleteElection != nil:
 		return types.DeleteElection{
-			ElectionID: m.DeleteElection.ElectionID,
+			ElectionID:     m.DeleteElection.ElectionID,
+			AdminSignature: m.DeleteElection.AdminSignature,
+		}, nil
+	case m.RegisterVoters != nil:
+		return types.RegisterVoters{
+			ElectionID:     m.RegisterVoters.ElectionID,
+			Voters:         m.RegisterVoters.Voters,
+			AdminSignature: m.RegisterVoters.AdminSignature,
+		}, nil
+	case m.RemoveVoters != nil:
+		return types.RemoveVoters{
+			ElectionID:     m.RemoveVoters.ElectionID,
+			Voters:         m.RemoveVoters.Voters,
+			AdminSignature: m.RemoveVoters.AdminSignature,
+		}, nil
+	case m.ReplaceVoters != nil:
+		return types.ReplaceVoters{
+			ElectionID:     m.ReplaceVoters.ElectionID,
+			Voters:         m.ReplaceVoters.Voters,
+			AdminSignature: m.ReplaceVoters.AdminSignature,
+		}, nil
+	case m.ProposeAction != nil:
+		return types.ProposeAction{
+			ElectionID:     m.ProposeAction.ElectionID,
+			Command:        m.ProposeAction.Command,
+			AdminID:        m.ProposeAction.AdminID,
+			AdminSignature: m.ProposeAction.AdminSignature,
+		}, nil
+	case m.ApproveAction != nil:
+		return types.ApproveAction{
+			ElectionID:     m.ApproveAction.ElectionID,
+			ActionID:       m.ApproveAction.ActionID,
+			AdminID:        m.ApproveAction.AdminID,
+			AdminSignature: m.ApproveAction.AdminSignature,
+		}, nil
+	case m.ReportTime != nil:
+		return types.ReportTime{
+			Timestamp: m.ReportTime.Timestamp,
+			Signature: m.ReportTime.Signature,
+			PublicKey: m.ReportTime.PublicKey,
+		}, nil
+	case m.RegisterDKG != nil:
+		return types.RegisterDKG{
+			ElectionID:   m.RegisterDKG.ElectionID,
+			Commits:      m.RegisterDKG.Commits,
+			Participants: m.RegisterDKG.Participants,
+			Signature:    m.RegisterDKG.Signature,
+			PublicKey:    m.RegisterDKG.PublicKey,
 		}, nil
 	}
 
@@ -197,26 +369,55 @@ func (transactionFormat) Decode(ctx serde.Context, data []byte) (serde.Message,
 // TransactionJSON is the JSON message that wraps the different kinds of
 // transactions.
 type TransactionJSON struct {
-	CreateElection    *CreateElectionJSON    `json:",omitempty"`
-	OpenElection      *OpenElectionJSON      `json:",omitempty"`
-	CastVote          *CastVoteJSON          `json:",omitempty"`
-	CloseElection     *CloseElectionJSON     `json:",omitempty"`
-	ShuffleBallots    *ShuffleBallotsJSON    `json:",omitempty"`
-	RegisterPubShares *RegisterPubSharesJSON `json:",omitempty"`
-	CombineShares     *CombineSharesJSON     `json:",omitempty"`
-	CancelElection    *CancelElectionJSON    `json:",omitempty"`
-	DeleteElection    *DeleteElectionJSON    `json:",omitempty"`
+	CreateElection      *CreateElectionJSON      `json:",omitempty"`
+	OpenElection        *OpenElectionJSON        `json:",omitempty"`
+	CastVote            *CastVoteJSON            `json:",omitempty"`
+	CastVotes           *CastVotesJSON           `json:",omitempty"`
+	CloseElection       *CloseElectionJSON       `json:",omitempty"`
+	ShuffleBallots      *ShuffleBallotsJSON      `json:",omitempty"`
+	RegisterPubShares   *RegisterPubSharesJSON   `json:",omitempty"`
+	CombineShares       *CombineSharesJSON       `json:",omitempty"`
+	CancelElection      *CancelElectionJSON      `json:",omitempty"`
+	DeleteElection      *DeleteElectionJSON      `json:",omitempty"`
+	RegisterVoters      *VotersJSON              `json:",omitempty"`
+	RemoveVoters        *VotersJSON              `json:",omitempty"`
+	ReplaceVoters       *VotersJSON              `json:",omitempty"`
+	ProposeAction       *ProposeActionJSON       `json:",omitempty"`
+	ApproveAction       *ApproveActionJSON       `json:",omitempty"`
+	UpdateConfiguration *UpdateConfigurationJSON `json:",omitempty"`
+	UpdateRoster        *UpdateRosterJSON        `json:",omitempty"`
+	ReportTime          *ReportTimeJSON          `json:",omitempty"`
+	RegisterDKG         *RegisterDKGJSON         `json:",omitempty"`
 }
 
 // CreateElectionJSON is the JSON representation of a CreateElection transaction
 type CreateElectionJSON struct {
-	Configuration types.Configuration
-	AdminID       string
+	Configuration       types.Configuration
+	AdminID             string
+	Admins              []string `json:",omitempty"`
+	AdminThreshold      int      `json:",omitempty"`
+	ShuffleThreshold    int      `json:",omitempty"`
+	DecryptionThreshold int      `json:",omitempty"`
 }
 
 // OpenElectionJSON is the JSON representation of a OpenElection transaction
 type OpenElectionJSON struct {
-	ElectionID string
+	ElectionID     string
+	AdminSignature []byte
+}
+
+// UpdateConfigurationJSON is the JSON representation of a UpdateConfiguration
+// transaction
+type UpdateConfigurationJSON struct {
+	ElectionID     string
+	Configuration  []byte
+	AdminSignature []byte
+}
+
+// UpdateRosterJSON is the JSON representation of a UpdateRoster transaction
+type UpdateRosterJSON struct {
+	ElectionID     string
+	AdminSignature []byte
 }
 
 // CastVoteJSON is the JSON representation of a CastVote transaction
@@ -224,12 +425,19 @@ type CastVoteJSON struct {
 	ElectionID string
 	UserID     string
 	Ciphervote json.RawMessage
+	Proof      []byte
+}
+
+// CastVotesJSON is the JSON representation of a CastVotes transaction
+type CastVotesJSON struct {
+	Votes []CastVoteJSON
 }
 
 // CloseElectionJSON is the JSON representation of a CloseElection transaction
 type CloseElectionJSON struct {
-	ElectionID string
-	UserID     string
+	ElectionID     string
+	UserID         string
+	AdminSignature []byte
 }
 
 // ShuffleBallotsJSON is the JSON representation of a ShuffleBallots transaction
@@ -238,34 +446,94 @@ type ShuffleBallotsJSON struct {
 	Round        int
 	Ciphervotes  []json.RawMessage
 	RandomVector types.RandomVector
-	Proof        []byte
-	Signature    []byte
-	PublicKey    []byte
+	// RandomVectorVersion is omitted for the legacy version
+	RandomVectorVersion uint16 `json:",omitempty"`
+	Proof               []byte
+	Signature           []byte
+	PublicKey           []byte
 }
 
 type RegisterPubSharesJSON struct {
 	ElectionID string
 	Index      int
 	PubShares  PubsharesUnitJSON
+	Proofs     types.PubshareProofsUnit
 	Signature  []byte
 	PublicKey  []byte
 }
 
 // CombineSharesJSON is the JSON representation of a CombineShares transaction
 type CombineSharesJSON struct {
-	ElectionID string
-	UserID     string
+	ElectionID     string
+	UserID         string
+	AdminSignature []byte
 }
 
 // CancelElectionJSON is the JSON representation of a CancelElection transaction
 type CancelElectionJSON struct {
-	ElectionID string
-	UserID     string
+	ElectionID     string
+	UserID         string
+	AdminSignature []byte
 }
 
 // DeleteElectionJSON is the JSON representation of a DeleteElection transaction
 type DeleteElectionJSON struct {
-	ElectionID string
+	ElectionID     string
+	AdminSignature []byte
+}
+
+// VotersJSON is the JSON representation of the RegisterVoters, RemoveVoters,
+// and ReplaceVoters transactions
+type VotersJSON struct {
+	ElectionID     string
+	Voters         []string
+	AdminSignature []byte
+}
+
+// ProposeActionJSON is the JSON representation of a ProposeAction transaction
+type ProposeActionJSON struct {
+	ElectionID     string
+	Command        string
+	AdminID        string
+	AdminSignature []byte
+}
+
+// ApproveActionJSON is the JSON representation of a ApproveAction transaction
+type ApproveActionJSON struct {
+	ElectionID     string
+	ActionID       string
+	AdminID        string
+	AdminSignature []byte
+}
+
+// ReportTimeJSON is the JSON representation of a ReportTime transaction
+type ReportTimeJSON struct {
+	Timestamp int64
+	Signature []byte
+	PublicKey []byte
+}
+
+// RegisterDKGJSON is the JSON representation of a RegisterDKG transaction
+type RegisterDKGJSON struct {
+	ElectionID   string
+	Commits      [][]byte
+	Participants [][]byte
+	Signature    []byte
+	PublicKey    []byte
+}
+
+func encodeCastVote(ctx serde.Context, cv types.CastVote) (CastVoteJSON, error) {
+	ballot, err := cv.Ballot.Serialize(ctx)
+	if err != nil {
+		return CastVoteJSON{}, xerrors.Errorf("failed to serialize ballot: %v", err)
+	}
+
+	return CastVoteJSON{
+		ElectionID: cv.ElectionID,
+		UserID:     cv.UserID,
+		Ciphervote: ballot,
+		Proof:      cv.Proof,
+	}, nil
 }
 
 func decodeCastVote(ctx serde.Context, m CastVoteJSON) (serde.Message, error) {
@@ -288,6 +556,7 @@ func decodeCastVote(ctx serde.Context, m CastVoteJSON) (serde.Message, error) {
 		ElectionID: m.ElectionID,
 		UserID:     m.UserID,
 		Ballot:     ciphervote,
+		Proof:      m.Proof,
 	}, nil
 }
 
@@ -314,13 +583,14 @@ func decodeShuffleBallots(ctx serde.Context, m ShuffleBallotsJSON) (serde.Messag
 	}
 
 	return types.ShuffleBallots{
-		ElectionID:      m.ElectionID,
-		Round:           m.Round,
-		ShuffledBallots: ciphervotes,
-		RandomVector:    m.RandomVector,
-		Proof:           m.Proof,
-		Signature:       m.Signature,
-		PublicKey:       m.PublicKey,
+		ElectionID:          m.ElectionID,
+		Round:               m.Round,
+		ShuffledBallots:     ciphervotes,
+		RandomVector:        m.RandomVector,
+		RandomVectorVersion: types.RandomVectorVersion(m.RandomVectorVersion),
+		Proof:               m.Proof,
+		Signature:           m.Signature,
+		PublicKey:           m.PublicKey,
 	}, nil
 }
 
@@ -345,6 +615,7 @@ func decodeRegisterPubShares(m RegisterPubSharesJSON) (serde.Message, error) {
 		ElectionID: m.ElectionID,
 		Index:      m.Index,
 		Pubshares:  pubShares,
+		Proofs:     m.Proofs,
 		Signature:  m.Signature,
 		PublicKey:  m.PublicKey,
 	}, nil
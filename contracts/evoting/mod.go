@@ -3,7 +3,6 @@ package evoting
 import (
 	dvoting "github.com/dedis/d-voting"
 	"github.com/dedis/d-voting/contracts/evoting/types"
-	"github.com/dedis/d-voting/services/dkg"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.dedis.ch/dela/core/access"
 	"go.dedis.ch/dela/core/execution"
@@ -58,12 +57,6 @@ var (
 	)
 )
 
-const (
-	// ElectionsMetadataKey is the key at which election metadata are saved in
-	// the storage.
-	ElectionsMetadataKey = "ElectionsMetadataKey"
-)
-
 var suite = suites.MustFind("Ed25519")
 
 const (
@@ -89,12 +82,22 @@ type commands interface {
 	createElection(snap store.Snapshot, step execution.Step) error
 	openElection(snap store.Snapshot, step execution.Step) error
 	castVote(snap store.Snapshot, step execution.Step) error
+	castVotes(snap store.Snapshot, step execution.Step) error
 	closeElection(snap store.Snapshot, step execution.Step) error
 	shuffleBallots(snap store.Snapshot, step execution.Step) error
 	registerPubshares(snap store.Snapshot, step execution.Step) error
 	combineShares(snap store.Snapshot, step execution.Step) error
 	cancelElection(snap store.Snapshot, step execution.Step) error
 	deleteElection(snap store.Snapshot, step execution.Step) error
+	registerVoters(snap store.Snapshot, step execution.Step) error
+	removeVoters(snap store.Snapshot, step execution.Step) error
+	replaceVoters(snap store.Snapshot, step execution.Step) error
+	proposeAction(snap store.Snapshot, step execution.Step) error
+	approveAction(snap store.Snapshot, step execution.Step) error
+	updateConfiguration(snap store.Snapshot, step execution.Step) error
+	updateRoster(snap store.Snapshot, step execution.Step) error
+	reportTime(snap store.Snapshot, step execution.Step) error
+	registerDKG(snap store.Snapshot, step execution.Step) error
 }
 
 // Command defines a type of command for the value contract
@@ -107,6 +110,8 @@ const (
 	CmdOpenElection Command = "OPEN_ELECTION"
 	// CmdCastVote is the command to cast a vote
 	CmdCastVote Command = "CAST_VOTE"
+	// CmdCastVotes is the command to cast several votes in one transaction
+	CmdCastVotes Command = "CAST_VOTES"
 	// CmdCloseElection is the command to close an election
 	CmdCloseElection Command = "CLOSE_ELECTION"
 	// CmdShuffleBallots is the command to shuffle ballots
@@ -122,6 +127,35 @@ const (
 
 	// CmdDeleteElection is the command to delete an election
 	CmdDeleteElection Command = "DELETE_ELECTION"
+
+	// CmdRegisterVoters is the command to add voters to the electorate
+	CmdRegisterVoters Command = "REGISTER_VOTERS"
+	// CmdRemoveVoters is the command to remove voters from the electorate
+	CmdRemoveVoters Command = "REMOVE_VOTERS"
+	// CmdReplaceVoters is the command to replace the electorate
+	CmdReplaceVoters Command = "REPLACE_VOTERS"
+
+	// CmdProposeAction is the command to propose a sensitive action that must
+	// be approved by several admins
+	CmdProposeAction Command = "PROPOSE_ACTION"
+	// CmdApproveAction is the command to approve a pending action
+	CmdApproveAction Command = "APPROVE_ACTION"
+
+	// CmdUpdateConfiguration is the command to update the configuration of an
+	// election that is not open yet
+	CmdUpdateConfiguration Command = "UPDATE_CONFIGURATION"
+
+	// CmdUpdateRoster is the command to replace the roster of an election by
+	// the roster of the chain, once the DKG has been reshared
+	CmdUpdateRoster Command = "UPDATE_ROSTER"
+
+	// CmdReportTime is the command used by the nodes to report their time, from
+	// which the schedules are checked
+	CmdReportTime Command = "REPORT_TIME"
+
+	// CmdRegisterDKG is the command used by the participants of the DKG to
+	// register its result
+	CmdRegisterDKG Command = "REGISTER_DKG"
 )
 
 // NewCreds creates new credentials for a evoting contract execution. We might
@@ -148,8 +182,6 @@ type Contract struct {
 
 	cmd commands
 
-	pedersen dkg.DKG
-
 	rosterKey []byte
 
 	context serde.Context
@@ -157,11 +189,12 @@ type Contract struct {
 	electionFac    serde.Factory
 	rosterFac      authority.Factory
 	transactionFac serde.Factory
+	ciphervoteFac  serde.Factory
 }
 
 // NewContract creates a new Value contract
 func NewContract(accessKey, rosterKey []byte, srvc access.Service,
-	pedersen dkg.DKG, rosterFac authority.Factory) Contract {
+	rosterFac authority.Factory) Contract {
 
 	ctx := json.NewContext()
 
@@ -172,7 +205,6 @@ func NewContract(accessKey, rosterKey []byte, srvc access.Service,
 	contract := Contract{
 		access:    srvc,
 		accessKey: accessKey,
-		pedersen:  pedersen,
 
 		rosterKey: rosterKey,
 
@@ -181,6 +213,7 @@ func NewContract(accessKey, rosterKey []byte, srvc access.Service,
 		electionFac:    electionFac,
 		rosterFac:      rosterFac,
 		transactionFac: transactionFac,
+		ciphervoteFac:  ciphervoteFac,
 	}
 
 	contract.cmd = evotingCommand{Contract: &contract, prover: proof.HashVerify}
@@ -219,6 +252,11 @@ func (c Contract) Execute(snap store.Snapshot, step execution.Step) error {
 		if err != nil {
 			return xerrors.Errorf("failed to cast vote: %v", err)
 		}
+	case CmdCastVotes:
+		err := c.cmd.castVotes(snap, step)
+		if err != nil {
+			return xerrors.Errorf("failed to cast votes: %v", err)
+		}
 	case CmdCloseElection:
 		err := c.cmd.closeElection(snap, step)
 		if err != nil {
@@ -249,6 +287,51 @@ func (c Contract) Execute(snap store.Snapshot, step execution.Step) error {
 		if err != nil {
 			return xerrors.Errorf("failed to delete election: %v", err)
 		}
+	case CmdRegisterVoters:
+		err := c.cmd.registerVoters(snap, step)
+		if err != nil {
+			return xerrors.Errorf("failed to register voters: %v", err)
+		}
+	case CmdRemoveVoters:
+		err := c.cmd.removeVoters(snap, step)
+		if err != nil {
+			return xerrors.Errorf("failed to remove voters: %v", err)
+		}
+	case CmdReplaceVoters:
+		err := c.cmd.replaceVoters(snap, step)
+		if err != nil {
+			return xerrors.Errorf("failed to replace voters: %v", err)
+		}
+	case CmdProposeAction:
+		err := c.cmd.proposeAction(snap, step)
+		if err != nil {
+			return xerrors.Errorf("failed to propose action: %v", err)
+		}
+	case CmdApproveAction:
+		err := c.cmd.approveAction(snap, step)
+		if err != nil {
+			return xerrors.Errorf("failed to approve action: %v", err)
+		}
+	case CmdUpdateConfiguration:
+		err := c.cmd.updateConfiguration(snap, step)
+		if err != nil {
+			return xerrors.Errorf("failed to update configuration: %v", err)
+		}
+	case CmdUpdateRoster:
+		err := c.cmd.updateRoster(snap, step)
+		if err != nil {
+			return xerrors.Errorf("failed to update roster: %v", err)
+		}
+	case CmdReportTime:
+		err := c.cmd.reportTime(snap, step)
+		if err != nil {
+			return xerrors.Errorf("failed to report time: %v", err)
+		}
+	case CmdRegisterDKG:
+		err := c.cmd.registerDKG(snap, step)
+		if err != nil {
+			return xerrors.Errorf("failed to register the dkg: %v", err)
+		}
 	default:
 		return xerrors.Errorf("unknown command: %s", cmd)
 	}
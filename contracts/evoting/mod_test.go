@@ -3,29 +3,31 @@ package evoting
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"testing"
 
 	"github.com/dedis/d-voting/contracts/evoting/types"
 	"github.com/dedis/d-voting/internal/testing/fake"
-	"github.com/dedis/d-voting/services/dkg"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/dela/core/access"
 	"go.dedis.ch/dela/core/execution"
 	"go.dedis.ch/dela/core/execution/native"
-	"go.dedis.ch/dela/core/ordering"
 	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
 	"go.dedis.ch/dela/core/store"
 	"go.dedis.ch/dela/core/txn"
 	"go.dedis.ch/dela/core/txn/signed"
 	"go.dedis.ch/dela/crypto"
 	"go.dedis.ch/dela/crypto/bls"
+	"go.dedis.ch/dela/mino"
 	"go.dedis.ch/dela/serde"
 	sjson "go.dedis.ch/dela/serde/json"
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/proof"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/sign/schnorr"
 	"go.dedis.ch/kyber/v3/util/random"
 )
 
@@ -33,6 +35,9 @@ var dummyElectionIDBuff = []byte("dummyID")
 var fakeElectionID = hex.EncodeToString(dummyElectionIDBuff)
 var fakeCommonSigner = bls.NewSigner()
 
+var fakeAdminSecret = suite.Scalar().Pick(random.New())
+var fakeAdminID = encodeAdminID(suite.Point().Mul(fakeAdminSecret, nil))
+
 const getTransactionErr = "failed to get transaction: \"evoting:arg\" not found in tx arg"
 const unmarshalTransactionErr = "failed to get transaction: failed to deserialize " +
 	"transaction: failed to decode: failed to unmarshal transaction json: invalid " +
@@ -59,24 +64,20 @@ func fakeProver(proof.Suite, string, proof.Verifier, []byte) error {
 }
 
 func TestExecute(t *testing.T) {
-	fakeDkg := fakeDKG{
-		actor: fakeDkgActor{},
-		err:   nil,
-	}
 	var evotingAccessKey = [32]byte{3}
 	rosterKey := [32]byte{}
 
 	service := fakeAccess{err: fake.GetError()}
 	rosterFac := fakeAuthorityFactory{}
 
-	contract := NewContract(evotingAccessKey[:], rosterKey[:], service, fakeDkg, rosterFac)
+	contract := NewContract(evotingAccessKey[:], rosterKey[:], service, rosterFac)
 
 	err := contract.Execute(fakeStore{}, makeStep(t))
 	require.EqualError(t, err, "identity not authorized: fake.PublicKey ("+fake.GetError().Error()+")")
 
 	service = fakeAccess{}
 
-	contract = NewContract(evotingAccessKey[:], rosterKey[:], service, fakeDkg, rosterFac)
+	contract = NewContract(evotingAccessKey[:], rosterKey[:], service, rosterFac)
 	err = contract.Execute(fakeStore{}, makeStep(t))
 	require.EqualError(t, err, "\"evoting:command\" not found in tx arg")
 
@@ -88,6 +89,9 @@ func TestExecute(t *testing.T) {
 	err = contract.Execute(fakeStore{}, makeStep(t, CmdArg, string(CmdCastVote)))
 	require.EqualError(t, err, fake.Err("failed to cast vote"))
 
+	err = contract.Execute(fakeStore{}, makeStep(t, CmdArg, string(CmdCastVotes)))
+	require.EqualError(t, err, fake.Err("failed to cast votes"))
+
 	err = contract.Execute(fakeStore{}, makeStep(t, CmdArg, string(CmdCloseElection)))
 	require.EqualError(t, err, fake.Err("failed to close election"))
 
@@ -100,6 +104,33 @@ func TestExecute(t *testing.T) {
 	err = contract.Execute(fakeStore{}, makeStep(t, CmdArg, string(CmdCancelElection)))
 	require.EqualError(t, err, fake.Err("failed to cancel election"))
 
+	err = contract.Execute(fakeStore{}, makeStep(t, CmdArg, string(CmdRegisterVoters)))
+	require.EqualError(t, err, fake.Err("failed to register voters"))
+
+	err = contract.Execute(fakeStore{}, makeStep(t, CmdArg, string(CmdRemoveVoters)))
+	require.EqualError(t, err, fake.Err("failed to remove voters"))
+
+	err = contract.Execute(fakeStore{}, makeStep(t, CmdArg, string(CmdReplaceVoters)))
+	require.EqualError(t, err, fake.Err("failed to replace voters"))
+
+	err = contract.Execute(fakeStore{}, makeStep(t, CmdArg, string(CmdProposeAction)))
+	require.EqualError(t, err, fake.Err("failed to propose action"))
+
+	err = contract.Execute(fakeStore{}, makeStep(t, CmdArg, string(CmdApproveAction)))
+	require.EqualError(t, err, fake.Err("failed to approve action"))
+
+	err = contract.Execute(fakeStore{}, makeStep(t, CmdArg, string(CmdUpdateConfiguration)))
+	require.EqualError(t, err, fake.Err("failed to update configuration"))
+
+	err = contract.Execute(fakeStore{}, makeStep(t, CmdArg, string(CmdUpdateRoster)))
+	require.EqualError(t, err, fake.Err("failed to update roster"))
+
+	err = contract.Execute(fakeStore{}, makeStep(t, CmdArg, string(CmdReportTime)))
+	require.EqualError(t, err, fake.Err("failed to report time"))
+
+	err = contract.Execute(fakeStore{}, makeStep(t, CmdArg, string(CmdRegisterDKG)))
+	require.EqualError(t, err, fake.Err("failed to register the dkg"))
+
 	err = contract.Execute(fakeStore{}, makeStep(t, CmdArg, "fake"))
 	require.EqualError(t, err, "unknown command: fake")
 
@@ -112,16 +143,6 @@ func TestExecute(t *testing.T) {
 func TestCommand_CreateElection(t *testing.T) {
 	initMetrics()
 
-	fakeActor := fakeDkgActor{
-		publicKey: suite.Point(),
-		err:       nil,
-	}
-
-	fakeDkg := fakeDKG{
-		actor: fakeActor,
-		err:   nil,
-	}
-
 	createElection := types.CreateElection{
 		AdminID: "dummyAdminID",
 	}
@@ -135,7 +156,7 @@ func TestCommand_CreateElection(t *testing.T) {
 	service := fakeAccess{err: fake.GetError()}
 	rosterFac := fakeAuthorityFactory{}
 
-	contract := NewContract(evotingAccessKey[:], rosterKey[:], service, fakeDkg, rosterFac)
+	contract := NewContract(evotingAccessKey[:], rosterKey[:], service, rosterFac)
 
 	cmd := evotingCommand{
 		Contract: &contract,
@@ -150,6 +171,14 @@ func TestCommand_CreateElection(t *testing.T) {
 	err = cmd.createElection(fake.NewBadSnapshot(), makeStep(t, ElectionArg, string(data)))
 	require.EqualError(t, err, "failed to get roster")
 
+	err = cmd.createElection(fake.NewSnapshot(), makeStep(t, ElectionArg, string(data)))
+	require.Contains(t, err.Error(), "invalid admin ID: failed to decode admin ID")
+
+	createElection.AdminID = fakeAdminID
+
+	data, err = createElection.Serialize(ctx)
+	require.NoError(t, err)
+
 	snap := fake.NewSnapshot()
 	step := makeStep(t, ElectionArg, string(data))
 	err = cmd.createElection(snap, step)
@@ -170,157 +199,350 @@ func TestCommand_CreateElection(t *testing.T) {
 	require.True(t, ok)
 
 	require.Equal(t, types.Initial, election.Status)
+	require.Equal(t, fakeAdminID, election.AdminID)
+	require.Equal(t, types.CurrentRandomVectorVersion, election.RandomVectorVersion)
 	require.Equal(t, float64(types.Initial), testutil.ToFloat64(PromElectionStatus))
+
+	entry := readCatalogEntry(t, snap, election.CatalogIndex)
+	require.Equal(t, election.ElectionID, entry.ElectionID)
+	require.Equal(t, fakeAdminID, entry.AdminID)
+	require.Equal(t, types.Initial, entry.Status)
+
+	// a second election is appended to the catalog
+	tx, err := signed.NewTransaction(1, fake.PublicKey{}, signed.WithArg(ElectionArg, data))
+	require.NoError(t, err)
+
+	err = cmd.createElection(snap, execution.Step{Current: tx})
+	require.NoError(t, err)
+
+	countBuf, err := snap.Get([]byte(types.CatalogCountKey))
+	require.NoError(t, err)
+
+	count, err := types.DecodeCatalogCount(countBuf)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), count)
 }
 
-func TestCommand_OpenElection(t *testing.T) {
-	// TODO
+func TestElectionThresholds(t *testing.T) {
+	// the thresholds default to the Byzantine threshold
+	shuffle, decryption, err := electionThresholds(types.CreateElection{}, 4)
+	require.NoError(t, err)
+	require.Equal(t, 3, shuffle)
+	require.Equal(t, 3, decryption)
+
+	shuffle, decryption, err = electionThresholds(types.CreateElection{
+		ShuffleThreshold:    2,
+		DecryptionThreshold: 3,
+	}, 4)
+	require.NoError(t, err)
+	require.Equal(t, 2, shuffle)
+	require.Equal(t, 3, decryption)
+
+	_, _, err = electionThresholds(types.CreateElection{ShuffleThreshold: 1}, 4)
+	require.EqualError(t, err, "shuffle threshold 1 is not in [2, 3]")
+
+	// a faulty node could prevent the others from reaching a threshold of n
+	_, _, err = electionThresholds(types.CreateElection{ShuffleThreshold: 4}, 4)
+	require.EqualError(t, err, "shuffle threshold 4 is not in [2, 3]")
+
+	_, _, err = electionThresholds(types.CreateElection{DecryptionThreshold: 4}, 4)
+	require.EqualError(t, err, "decryption threshold 4 is not in [2, 3]")
+
+	// the DKG can't run with a single share
+	_, _, err = electionThresholds(types.CreateElection{DecryptionThreshold: 1}, 3)
+	require.EqualError(t, err, "decryption threshold 1 is not in [2, 3]")
 }
 
-func TestCommand_CastVote(t *testing.T) {
-	initMetrics()
+func TestDkgRoster(t *testing.T) {
+	roster := authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner))
 
-	castVote := types.CastVote{
-		ElectionID: fakeElectionID,
-		UserID:     "dummyUserId",
-		Ballot: types.Ciphervote{types.EGPair{
-			K: suite.Point(),
-			C: suite.Point(),
-		}},
+	addrs := make([]mino.Address, 0, 3)
+	texts := make([][]byte, 0, 3)
+
+	addrIter := roster.AddressIterator()
+	for addrIter.HasNext() {
+		addr := addrIter.GetNext()
+
+		text, err := addr.MarshalText()
+		require.NoError(t, err)
+
+		addrs = append(addrs, addr)
+		texts = append(texts, text)
 	}
 
-	data, err := castVote.Serialize(ctx)
+	// the second node was left out of the DKG
+	participants, err := dkgRoster(roster, [][]byte{texts[2], texts[0]})
 	require.NoError(t, err)
+	require.Equal(t, 2, participants.Len())
 
-	dummyElection, contract := initElectionAndContract()
+	pubkey, index := participants.GetPublicKey(addrs[2])
+	require.Equal(t, 0, index)
 
-	electionBuf, err := dummyElection.Serialize(ctx)
+	expected, _ := roster.GetPublicKey(addrs[2])
+	require.Equal(t, expected, pubkey)
+
+	pubkey, _ = participants.GetPublicKey(addrs[1])
+	require.Nil(t, pubkey)
+
+	unknown, err := fake.NewAddress(10).MarshalText()
 	require.NoError(t, err)
 
+	_, err = dkgRoster(roster, [][]byte{unknown})
+	require.EqualError(t, err, fmt.Sprintf("participant %q is not a member of the roster", unknown))
+
+	_, err = dkgRoster(roster, [][]byte{texts[0], texts[0]})
+	require.EqualError(t, err, fmt.Sprintf("participant %q appears twice", texts[0]))
+}
+
+func TestCommand_ElectionCatalog(t *testing.T) {
+	initMetrics()
+
+	election, contract := initElectionAndContract()
+	election.Configuration.MainTitle = "title"
+	election.CatalogIndex = 3
+
 	cmd := evotingCommand{
 		Contract: &contract,
 	}
 
-	err = cmd.castVote(fake.NewSnapshot(), makeStep(t))
-	require.EqualError(t, err, getTransactionErr)
+	snap := fake.NewSnapshot()
 
-	err = cmd.castVote(fake.NewSnapshot(), makeStep(t, ElectionArg, "dummy"))
-	require.EqualError(t, err, unmarshalTransactionErr)
+	err := cmd.saveElection(snap, election, dummyElectionIDBuff)
+	require.NoError(t, err)
 
-	err = cmd.castVote(fake.NewBadSnapshot(), makeStep(t, ElectionArg, string(data)))
-	require.Contains(t, err.Error(), "failed to get key")
+	entry := readCatalogEntry(t, snap, 3)
+	require.Equal(t, "title", entry.Title)
+	require.Equal(t, types.Initial, entry.Status)
 
-	snap := fake.NewSnapshot()
+	election.Status = types.Open
 
-	err = snap.Set(dummyElectionIDBuff, invalidElection)
+	err = cmd.saveElection(snap, election, dummyElectionIDBuff)
 	require.NoError(t, err)
+	require.Equal(t, types.Open, readCatalogEntry(t, snap, 3).Status)
 
-	err = cmd.castVote(snap, makeStep(t, ElectionArg, string(data)))
-	require.Contains(t, err.Error(), deserializeErr)
+	err = cmd.applyDelete(snap, election, dummyElectionIDBuff)
+	require.NoError(t, err)
 
-	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	entry = readCatalogEntry(t, snap, 3)
+	require.True(t, entry.Deleted)
+	require.Equal(t, election.ElectionID, entry.ElectionID)
+
+	res, err := snap.Get(dummyElectionIDBuff)
 	require.NoError(t, err)
+	require.Empty(t, res)
+}
 
-	err = cmd.castVote(snap, makeStep(t, ElectionArg, string(data)))
-	require.EqualError(t, err, fmt.Sprintf("the election is not open, current status: %d", types.Initial))
+func TestCommand_MigrateCatalog(t *testing.T) {
+	initMetrics()
 
-	dummyElection.Status = types.Open
-	dummyElection.BallotSize = 0
+	election, contract := initElectionAndContract()
+	election.Configuration.MainTitle = "legacy"
 
-	electionBuf, err = dummyElection.Serialize(ctx)
+	cmd := evotingCommand{
+		Contract: &contract,
+	}
+
+	snap := fake.NewSnapshot()
+
+	electionBuf, err := election.Serialize(ctx)
 	require.NoError(t, err)
 
 	err = snap.Set(dummyElectionIDBuff, electionBuf)
 	require.NoError(t, err)
 
-	err = cmd.castVote(snap, makeStep(t, ElectionArg, string(data)))
-	require.EqualError(t, err, "the ballot has unexpected length: 1 != 0")
+	// the first election doesn't exist anymore
+	err = snap.Set([]byte(types.LegacyElectionsKey),
+		[]byte(`{"ElectionsIDs":["deadbeef","`+fakeElectionID+`"]}`))
+	require.NoError(t, err)
 
-	dummyElection.BallotSize = 29
+	loaded, _, err := cmd.getElection(fakeElectionID, snap)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), loaded.CatalogIndex)
 
-	electionBuf, err = dummyElection.Serialize(ctx)
+	entry := readCatalogEntry(t, snap, 0)
+	require.Equal(t, "legacy", entry.Title)
+	require.Equal(t, fakeElectionID, entry.ElectionID)
+
+	res, err := snap.Get([]byte(types.LegacyElectionsKey))
 	require.NoError(t, err)
+	require.Empty(t, res)
 
-	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	// the next elections are appended after the migrated ones
+	index, err := cmd.addToCatalog(snap, election)
 	require.NoError(t, err)
+	require.Equal(t, uint64(1), index)
+}
 
-	// encrypt a real message :
-	RandomStream := suite.RandomStream()
-	h := suite.Scalar().Pick(RandomStream)
-	pubKey := suite.Point().Mul(h, nil)
+func TestCommand_OpenElection(t *testing.T) {
+	initMetrics()
 
-	M := suite.Point().Embed([]byte("fakeVote"), random.New())
+	roster, signers := makeDKGRoster(4)
+	election, cmd := initDKGElection(roster)
 
-	// ElGamal-encrypt the point to produce ciphertext (K,C).
-	k := suite.Scalar().Pick(random.New()) // ephemeral private key
-	K := suite.Point().Mul(k, nil)         // ephemeral DH public key
-	S := suite.Point().Mul(k, pubKey)      // ephemeral DH shared secret
-	C := S.Add(S, M)                       // message blinded with secret
+	snap := fake.NewSnapshot()
+	setDKGElection(t, snap, election)
 
-	castVote.Ballot = types.Ciphervote{
-		types.EGPair{
-			K: K,
-			C: C,
-		},
+	openElection := types.OpenElection{
+		ElectionID:     fakeElectionID,
+		AdminSignature: signAdmin(t, CmdOpenElection, nil, 0),
 	}
 
-	castVote.ElectionID = "X"
+	data, err := openElection.Serialize(ctx)
+	require.NoError(t, err)
 
-	data, err = castVote.Serialize(ctx)
+	err = cmd.openElection(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "the participants of the dkg didn't agree on its result yet")
+
+	// the last node was left out of the DKG by a retry
+	commits := makeCommits(t, 2)
+	participants := addressTexts(t, roster)[:3]
+
+	// a faulty node can't open the election with a DKG of its own
+	err = registerDKG(t, snap, cmd, signers[2], makeCommits(t, 2), participants)
 	require.NoError(t, err)
 
-	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	err = registerDKG(t, snap, cmd, signers[0], commits, participants)
 	require.NoError(t, err)
 
-	err = cmd.castVote(snap, makeStep(t, ElectionArg, string(data)))
-	require.EqualError(t, err, "failed to get election: failed to decode "+
-		"electionIDHex: encoding/hex: invalid byte: U+0058 'X'")
+	err = cmd.openElection(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "the participants of the dkg didn't agree on its result yet")
 
-	dummyElection.ElectionID = fakeElectionID
+	err = registerDKG(t, snap, cmd, signers[1], commits, participants)
+	require.NoError(t, err)
 
-	electionBuf, err = dummyElection.Serialize(ctx)
+	err = cmd.openElection(snap, makeStep(t, ElectionArg, string(data)))
 	require.NoError(t, err)
 
-	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	election, _, err = cmd.getElection(fakeElectionID, snap)
 	require.NoError(t, err)
+	require.Equal(t, types.Open, election.Status)
+	require.Equal(t, 2, election.DecryptionThreshold)
+	require.Equal(t, 3, election.Roster.Len())
+	require.Len(t, election.VerificationKeys, 3)
+	require.Empty(t, election.DKGResults)
 
-	castVote.ElectionID = fakeElectionID
+	pubkey := suite.Point()
+	require.NoError(t, pubkey.UnmarshalBinary(commits[0]))
+	require.True(t, pubkey.Equal(election.Pubkey))
 
-	data, err = castVote.Serialize(ctx)
+	err = cmd.openElection(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, fmt.Sprintf("the election was opened before, "+
+		"current status: %d", types.Open))
+}
+
+func TestCommand_RegisterDKG(t *testing.T) {
+	roster, signers := makeDKGRoster(4)
+	election, cmd := initDKGElection(roster)
+
+	snap := fake.NewSnapshot()
+
+	err := cmd.registerDKG(snap, makeStep(t, ElectionArg, "dummy"))
+	require.EqualError(t, err, unmarshalTransactionErr)
+
+	data, err := types.OpenElection{}.Serialize(ctx)
 	require.NoError(t, err)
 
-	err = cmd.castVote(snap, makeStep(t, ElectionArg, string(data)))
+	err = cmd.registerDKG(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "wrong type of transaction: types.OpenElection")
+
+	setDKGElection(t, snap, election)
+
+	commits := makeCommits(t, 2)
+	participants := addressTexts(t, roster)
+
+	outsider := bls.NewSigner()
+	outsiderKey, err := outsider.GetPublicKey().MarshalBinary()
 	require.NoError(t, err)
 
-	res, err := snap.Get(dummyElectionIDBuff)
+	err = registerDKG(t, snap, cmd, outsider, commits, participants)
+	require.EqualError(t, err, fmt.Sprintf("could not verify identity of node: "+
+		"public key not associated to a member of the roster: %x", outsiderKey))
+
+	unknown, err := fake.NewAddress(10).MarshalText()
 	require.NoError(t, err)
 
-	message, err := electionFac.Deserialize(ctx, res)
+	err = registerDKG(t, snap, cmd, signers[0], commits, [][]byte{participants[0], unknown})
+	require.EqualError(t, err, fmt.Sprintf("invalid dkg: failed to get the roster: "+
+		"participant %q is not a member of the roster", unknown))
+
+	err = registerDKG(t, snap, cmd, signers[3], commits, participants[:3])
+	require.EqualError(t, err, "invalid dkg: fake.Address[3] is not a participant")
+
+	err = registerDKG(t, snap, cmd, signers[0], commits, participants[:1])
+	require.EqualError(t, err, "invalid dkg: the threshold is above the number of "+
+		"participants: 2 > 1")
+
+	err = registerDKG(t, snap, cmd, signers[0], makeCommits(t, 3), participants)
+	require.EqualError(t, err, "invalid dkg: the threshold is not the one of the "+
+		"election: 3 != 2")
+
+	err = registerDKG(t, snap, cmd, signers[0], nil, participants)
+	require.EqualError(t, err, "invalid dkg: failed to get the keys: the result has no commits")
+
+	err = registerDKG(t, snap, cmd, signers[0], makeCommits(t, 2), participants)
 	require.NoError(t, err)
 
-	election, ok := message.(types.Election)
-	require.True(t, ok)
+	// a new result replaces the previous one of the node
+	err = registerDKG(t, snap, cmd, signers[0], commits, participants)
+	require.NoError(t, err)
+
+	election, _, err = cmd.getElection(fakeElectionID, snap)
+	require.NoError(t, err)
+	require.Len(t, election.DKGResults, 1)
+	require.Equal(t, commits, election.DKGResults[0].Commits)
+
+	// a resharing keeps the public key of the election and runs on the roster
+	// of the chain
+	election.Status = types.Open
+	election.Pubkey = suite.Point().Pick(suite.RandomStream())
+	election.DKGResults = nil
+	setDKGElection(t, snap, election)
+
+	err = registerDKG(t, snap, cmd, signers[0], commits, participants)
+	require.Regexp(t, "^invalid dkg: the public key is not the one of the election", err)
+
+	election.Pubkey = suite.Point()
+	require.NoError(t, election.Pubkey.UnmarshalBinary(commits[0]))
+	setDKGElection(t, snap, election)
+
+	err = registerDKG(t, snap, cmd, signers[0], commits, participants[:3])
+	require.EqualError(t, err, "invalid dkg: the dkg doesn't run on the roster: "+
+		"3 participants != 4 nodes")
+
+	err = registerDKG(t, snap, cmd, signers[0], commits, participants)
+	require.NoError(t, err)
+
+	election.PubsharesUnits.Pubshares = []types.PubsharesUnit{{}}
+	setDKGElection(t, snap, election)
 
-	require.Len(t, election.Suffragia.Ciphervotes, 1)
-	require.True(t, castVote.Ballot.Equal(election.Suffragia.Ciphervotes[0]))
+	err = registerDKG(t, snap, cmd, signers[0], commits, participants)
+	require.EqualError(t, err, "the dkg can't be reshared once pubshares are submitted")
 
-	require.Equal(t, castVote.UserID, election.Suffragia.UserIDs[0])
-	require.Equal(t, float64(len(election.Suffragia.Ciphervotes)), testutil.ToFloat64(PromElectionBallots))
+	election.Status = types.ResultAvailable
+	setDKGElection(t, snap, election)
+
+	err = registerDKG(t, snap, cmd, signers[0], commits, participants)
+	require.EqualError(t, err, fmt.Sprintf("the dkg can't be registered, "+
+		"current status: %d", types.ResultAvailable))
 }
 
-func TestCommand_CloseElection(t *testing.T) {
-	initMetrics()
+func TestCommand_UpdateConfiguration(t *testing.T) {
+	encodeConfiguration := func(configuration types.Configuration) []byte {
+		buf, err := json.Marshal(configuration)
+		require.NoError(t, err)
 
-	closeElection := types.CloseElection{
-		ElectionID: fakeElectionID,
-		UserID:     "dummyUserId",
+		return buf
 	}
 
-	data, err := closeElection.Serialize(ctx)
+	updateConfiguration := types.UpdateConfiguration{
+		ElectionID:    fakeElectionID,
+		Configuration: encodeConfiguration(fake.BasicConfiguration),
+	}
+
+	data, err := updateConfiguration.Serialize(ctx)
 	require.NoError(t, err)
 
 	dummyElection, contract := initElectionAndContract()
-	dummyElection.ElectionID = fakeElectionID
 
 	electionBuf, err := dummyElection.Serialize(ctx)
 	require.NoError(t, err)
@@ -329,764 +551,2240 @@ func TestCommand_CloseElection(t *testing.T) {
 		Contract: &contract,
 	}
 
-	err = cmd.closeElection(fake.NewSnapshot(), makeStep(t))
+	err = cmd.updateConfiguration(fake.NewSnapshot(), makeStep(t))
 	require.EqualError(t, err, getTransactionErr)
 
-	err = cmd.closeElection(fake.NewSnapshot(), makeStep(t, ElectionArg, "dummy"))
+	err = cmd.updateConfiguration(fake.NewSnapshot(), makeStep(t, ElectionArg, "dummy"))
 	require.EqualError(t, err, unmarshalTransactionErr)
 
-	err = cmd.closeElection(fake.NewBadSnapshot(), makeStep(t, ElectionArg, string(data)))
+	err = cmd.updateConfiguration(fake.NewBadSnapshot(), makeStep(t, ElectionArg, string(data)))
 	require.Contains(t, err.Error(), "failed to get key")
 
 	snap := fake.NewSnapshot()
 
-	err = snap.Set(dummyElectionIDBuff, invalidElection)
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
 	require.NoError(t, err)
 
-	err = cmd.closeElection(snap, makeStep(t, ElectionArg, string(data)))
-	require.Contains(t, err.Error(), deserializeErr)
+	err = cmd.updateConfiguration(snap, makeStep(t, ElectionArg, string(data)))
+	require.Contains(t, err.Error(), "failed to check admin: invalid admin signature")
 
-	err = snap.Set(dummyElectionIDBuff, electionBuf)
-	require.NoError(t, err)
+	sign := func() {
+		updateConfiguration.AdminSignature = signAdmin(t, CmdUpdateConfiguration,
+			updateConfiguration.AdminPayload(), 0)
 
-	closeElection.UserID = hex.EncodeToString([]byte("dummyAdminID"))
+		data, err = updateConfiguration.Serialize(ctx)
+		require.NoError(t, err)
+	}
 
-	data, err = closeElection.Serialize(ctx)
-	require.NoError(t, err)
+	updateConfiguration.Configuration = []byte("{")
+	sign()
 
-	err = cmd.closeElection(snap, makeStep(t, ElectionArg, string(data)))
-	require.EqualError(t, err, fmt.Sprintf("the election is not open, "+
-		"current status: %d", types.Initial))
-	require.Equal(t, 0, testutil.CollectAndCount(PromElectionStatus))
+	err = cmd.updateConfiguration(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "failed to get configuration: failed to unmarshal "+
+		"configuration: unexpected end of JSON input")
 
-	dummyElection.Status = types.Open
+	invalid := fake.BasicConfiguration
+	invalid.Schedule = types.Schedule{Start: 2, End: 1}
 
-	electionBuf, err = dummyElection.Serialize(ctx)
-	require.NoError(t, err)
+	updateConfiguration.Configuration = encodeConfiguration(invalid)
+	sign()
 
-	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	err = cmd.updateConfiguration(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "configuration of election is incoherent or has duplicated IDs")
+
+	// the signature doesn't authorize other bytes, even if they decode to the
+	// same configuration
+	updateConfiguration.Configuration = encodeConfiguration(fake.BasicConfiguration)
+	updateConfiguration.Configuration = append(updateConfiguration.Configuration, ' ')
+
+	data, err = updateConfiguration.Serialize(ctx)
 	require.NoError(t, err)
 
-	err = cmd.closeElection(snap, makeStep(t, ElectionArg, string(data)))
-	require.EqualError(t, err, "at least two ballots are required")
+	err = cmd.updateConfiguration(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "failed to check admin: invalid admin signature")
 
-	dummyElection.Suffragia.CastVote("dummyUser1", types.Ciphervote{})
-	dummyElection.Suffragia.CastVote("dummyUser2", types.Ciphervote{})
+	updateConfiguration.Configuration = encodeConfiguration(fake.BasicConfiguration)
 
-	electionBuf, err = dummyElection.Serialize(ctx)
+	data, err = updateConfiguration.Serialize(ctx)
 	require.NoError(t, err)
 
-	err = snap.Set(dummyElectionIDBuff, electionBuf)
-	require.NoError(t, err)
+	err = cmd.updateConfiguration(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "failed to check admin: invalid admin signature")
 
-	err = cmd.closeElection(snap, makeStep(t, ElectionArg, string(data)))
-	require.NoError(t, err)
-	require.Equal(t, float64(types.Closed), testutil.ToFloat64(PromElectionStatus))
+	sign()
 
-	res, err := snap.Get(dummyElectionIDBuff)
+	err = cmd.updateConfiguration(snap, makeStep(t, ElectionArg, string(data)))
 	require.NoError(t, err)
 
-	message, err := electionFac.Deserialize(ctx, res)
+	election := readElection(t, snap)
+	require.Equal(t, fake.BasicConfiguration.MainTitle, election.Configuration.MainTitle)
+	require.Equal(t, fake.BasicConfiguration.MaxBallotSize(), election.BallotSize)
+	require.Equal(t, uint64(1), election.AdminNonce)
+
+	// the signature can't be replayed
+	err = cmd.updateConfiguration(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "failed to check admin: invalid admin signature")
+
+	dummyElection.Status = types.Open
+
+	electionBuf, err = dummyElection.Serialize(ctx)
 	require.NoError(t, err)
 
-	election, ok := message.(types.Election)
-	require.True(t, ok)
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
 
-	require.Equal(t, types.Closed, election.Status)
-	require.Equal(t, float64(types.Closed), testutil.ToFloat64(PromElectionStatus))
+	err = cmd.updateConfiguration(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, fmt.Sprintf("the configuration can only be "+
+		"updated before the election is open, current status: %d", types.Open))
 }
 
-func TestCommand_ShuffleBallotsCannotShuffleTwice(t *testing.T) {
-	k := 3
+func TestCommand_UpdateRoster(t *testing.T) {
+	updateRoster := types.UpdateRoster{
+		ElectionID: fakeElectionID,
+	}
 
-	election, shuffleBallots, contract := initGoodShuffleBallot(t, k)
+	data, err := updateRoster.Serialize(ctx)
+	require.NoError(t, err)
+
+	dummyElection, contract := initElectionAndContract()
 
 	cmd := evotingCommand{
 		Contract: &contract,
-		prover:   fakeProver,
 	}
 
-	snap := fake.NewSnapshot()
+	err = cmd.updateRoster(fake.NewSnapshot(), makeStep(t))
+	require.EqualError(t, err, getTransactionErr)
 
-	// Attempts to shuffle twice :
-	shuffleBallots.Round = 1
+	err = cmd.updateRoster(fake.NewSnapshot(), makeStep(t, ElectionArg, "dummy"))
+	require.EqualError(t, err, unmarshalTransactionErr)
 
-	election.ShuffleInstances = make([]types.ShuffleInstance, 1)
-	election.ShuffleInstances[0].ShuffledBallots = make([]types.Ciphervote, 3)
+	err = cmd.updateRoster(fake.NewBadSnapshot(), makeStep(t, ElectionArg, string(data)))
+	require.Contains(t, err.Error(), "failed to get key")
 
-	Ks, Cs, _ := fakeKCPoints(k)
-	for i := 0; i < k; i++ {
-		ballot := types.Ciphervote{types.EGPair{
-			K: Ks[i],
-			C: Cs[i],
-		}}
-		election.ShuffleInstances[0].ShuffledBallots[i] = ballot
+	snap := fake.NewSnapshot()
+
+	setElection := func(election types.Election) {
+		electionBuf, err := election.Serialize(ctx)
+		require.NoError(t, err)
+
+		err = snap.Set(dummyElectionIDBuff, electionBuf)
+		require.NoError(t, err)
 	}
 
-	election.ShuffleInstances[0].ShufflerPublicKey = shuffleBallots.PublicKey
+	setElection(dummyElection)
 
-	electionBuff, err := election.Serialize(ctx)
-	require.NoError(t, err)
+	err = cmd.updateRoster(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, fmt.Sprintf("the roster can only be updated "+
+		"between the opening and the decryption, current status: %d", types.Initial))
 
-	err = snap.Set(dummyElectionIDBuff, electionBuff)
-	require.NoError(t, err)
+	dummyElection.Status = types.ShuffledBallots
+	dummyElection.PubsharesUnits.Pubshares = []types.PubsharesUnit{{}}
+	setElection(dummyElection)
 
-	data, err := shuffleBallots.Serialize(ctx)
+	err = cmd.updateRoster(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "the roster can't be updated once pubshares are submitted")
+
+	dummyElection.Status = types.Open
+	dummyElection.PubsharesUnits.Pubshares = nil
+	setElection(dummyElection)
+
+	err = cmd.updateRoster(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "failed to check admin: invalid admin signature")
+
+	updateRoster.AdminSignature = signAdmin(t, CmdUpdateRoster, nil, 0)
+
+	data, err = updateRoster.Serialize(ctx)
 	require.NoError(t, err)
 
-	err = cmd.shuffleBallots(snap, makeStep(t, ElectionArg, string(data)))
-	require.EqualError(t, err, "a node already submitted a shuffle that has "+
-		"been accepted in round 0")
+	// the fake roster of the chain is empty
+	err = cmd.updateRoster(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "shuffle threshold 0 is not in [1, 0]")
 }
 
-func TestCommand_ShuffleBallotsValidScenarios(t *testing.T) {
-	initMetrics()
+func TestCommand_UpdateRosterDKG(t *testing.T) {
+	roster, signers := makeDKGRoster(4)
+	election, cmd := initDKGElection(roster)
 
-	k := 3
+	commits := makeCommits(t, 2)
+	participants := addressTexts(t, roster)
 
-	// Simple Shuffle from round 0 :
-	election, shuffleBallots, contract := initGoodShuffleBallot(t, k)
+	election.Status = types.Closed
+	election.Pubkey = suite.Point()
+	require.NoError(t, election.Pubkey.UnmarshalBinary(commits[0]))
+
+	snap := fake.NewSnapshot()
+	setDKGElection(t, snap, election)
+
+	updateRoster := types.UpdateRoster{
+		ElectionID:     fakeElectionID,
+		AdminSignature: signAdmin(t, CmdUpdateRoster, nil, 0),
+	}
+
+	data, err := updateRoster.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = registerDKG(t, snap, cmd, signers[0], commits, participants)
+	require.NoError(t, err)
+
+	err = cmd.updateRoster(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "the participants of the resharing didn't agree "+
+		"on its result yet")
+
+	err = registerDKG(t, snap, cmd, signers[3], commits, participants)
+	require.NoError(t, err)
+
+	err = cmd.updateRoster(snap, makeStep(t, ElectionArg, string(data)))
+	require.NoError(t, err)
+
+	election, _, err = cmd.getElection(fakeElectionID, snap)
+	require.NoError(t, err)
+	require.Equal(t, 4, election.Roster.Len())
+	require.Len(t, election.VerificationKeys, 4)
+	require.Empty(t, election.DKGResults)
+	require.Equal(t, uint64(1), election.AdminNonce)
+}
+
+func TestCommand_CastVote(t *testing.T) {
+	initMetrics()
+
+	castVote := types.CastVote{
+		ElectionID: fakeElectionID,
+		UserID:     "dummyUserId",
+		Ballot: types.Ciphervote{types.EGPair{
+			K: suite.Point(),
+			C: suite.Point(),
+		}},
+	}
+
+	data, err := castVote.Serialize(ctx)
+	require.NoError(t, err)
+
+	dummyElection, contract := initElectionAndContract()
+
+	electionBuf, err := dummyElection.Serialize(ctx)
+	require.NoError(t, err)
 
 	cmd := evotingCommand{
 		Contract: &contract,
-		prover:   fakeProver,
 	}
 
+	err = cmd.castVote(fake.NewSnapshot(), makeStep(t))
+	require.EqualError(t, err, getTransactionErr)
+
+	err = cmd.castVote(fake.NewSnapshot(), makeStep(t, ElectionArg, "dummy"))
+	require.EqualError(t, err, unmarshalTransactionErr)
+
+	err = cmd.castVote(fake.NewBadSnapshot(), makeStep(t, ElectionArg, string(data)))
+	require.Contains(t, err.Error(), "failed to get key")
+
 	snap := fake.NewSnapshot()
 
-	electionBuf, err := election.Serialize(ctx)
+	err = snap.Set(dummyElectionIDBuff, invalidElection)
 	require.NoError(t, err)
 
+	err = cmd.castVote(snap, makeStep(t, ElectionArg, string(data)))
+	require.Contains(t, err.Error(), deserializeErr)
+
 	err = snap.Set(dummyElectionIDBuff, electionBuf)
 	require.NoError(t, err)
 
-	data, err := shuffleBallots.Serialize(ctx)
+	err = cmd.castVote(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, fmt.Sprintf("the election is not open, current status: %d", types.Initial))
+
+	dummyElection.Status = types.Open
+	dummyElection.BallotSize = 0
+
+	electionBuf, err = dummyElection.Serialize(ctx)
 	require.NoError(t, err)
 
-	step := makeStep(t, ElectionArg, string(data))
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
 
-	err = cmd.shuffleBallots(snap, step)
+	err = cmd.castVote(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "the ballot has unexpected length: 1 != 0")
+
+	dummyElection.BallotSize = 29
+
+	electionBuf, err = dummyElection.Serialize(ctx)
 	require.NoError(t, err)
-	require.Equal(t, float64(1), testutil.ToFloat64(PromElectionShufflingInstances))
 
-	// Valid Shuffle is over :
-	shuffleBallots.Round = k
-	election.ShuffleInstances = make([]types.ShuffleInstance, k)
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
 
-	data, err = shuffleBallots.Serialize(ctx)
+	// encrypt a real message :
+	castVote = makeCastVote(t, castVote.UserID)
+
+	castVote.ElectionID = "X"
+
+	data, err = castVote.Serialize(ctx)
 	require.NoError(t, err)
 
-	for i := 1; i <= k-1; i++ {
-		election.ShuffleInstances[i].ShuffledBallots = make([]types.Ciphervote, 3)
-	}
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
 
-	Ks, Cs, _ := fakeKCPoints(k)
-	for i := 0; i < k; i++ {
-		ballot := types.Ciphervote{types.EGPair{
-			K: Ks[i],
-			C: Cs[i],
-		}}
-		election.ShuffleInstances[k-1].ShuffledBallots[i] = ballot
-	}
+	err = cmd.castVote(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "failed to get election: failed to decode "+
+		"electionIDHex: encoding/hex: invalid byte: U+0058 'X'")
 
-	electionBuf, err = election.Serialize(ctx)
+	dummyElection.ElectionID = fakeElectionID
+
+	electionBuf, err = dummyElection.Serialize(ctx)
 	require.NoError(t, err)
 
 	err = snap.Set(dummyElectionIDBuff, electionBuf)
 	require.NoError(t, err)
 
-	err = cmd.shuffleBallots(snap, makeStep(t, ElectionArg, string(data)))
+	castVote.ElectionID = fakeElectionID
+
+	// the ballot of another voter is rejected
+	otherVote := castVote
+	otherVote.UserID = "otherUserId"
+
+	data, err = otherVote.Serialize(ctx)
 	require.NoError(t, err)
-	require.Equal(t, float64(k+1), testutil.ToFloat64(PromElectionShufflingInstances))
 
-	// Check the shuffle is over:
-	electionTxIDBuff, err := hex.DecodeString(election.ElectionID)
+	err = cmd.castVote(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "invalid ballot proof: invalid proof for chunk 0")
+
+	data, err = castVote.Serialize(ctx)
 	require.NoError(t, err)
 
-	electionBuf, err = snap.Get(electionTxIDBuff)
+	err = cmd.castVote(snap, makeStep(t, ElectionArg, string(data)))
 	require.NoError(t, err)
 
-	message, err := electionFac.Deserialize(ctx, electionBuf)
+	res, err := snap.Get(dummyElectionIDBuff)
+	require.NoError(t, err)
+
+	message, err := electionFac.Deserialize(ctx, res)
 	require.NoError(t, err)
 
 	election, ok := message.(types.Election)
 	require.True(t, ok)
 
-	require.Equal(t, types.ShuffledBallots, election.Status)
-	require.Equal(t, float64(types.ShuffledBallots), testutil.ToFloat64(PromElectionStatus))
+	// the ballot is stored under its own key
+	require.Equal(t, 1, election.Suffragia.Count)
+	require.Empty(t, election.Suffragia.Ciphervotes)
+	require.True(t, castVote.Ballot.Equal(readBallot(t, snap, castVote.UserID)))
+
+	userID, err := snap.Get(types.BallotIndexKey(dummyElectionIDBuff, 0))
+	require.NoError(t, err)
+	require.Equal(t, castVote.UserID, string(userID))
+
+	require.Equal(t, float64(1), testutil.ToFloat64(PromElectionBallots))
+
+	// a second ballot of the same user replaces the first one
+	castVote = makeCastVote(t, castVote.UserID)
+
+	data, err = castVote.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = cmd.castVote(snap, makeStep(t, ElectionArg, string(data)))
+	require.NoError(t, err)
+
+	election = readElection(t, snap)
+	require.Equal(t, 1, election.Suffragia.Count)
+	require.True(t, castVote.Ballot.Equal(readBallot(t, snap, castVote.UserID)))
 }
 
-func TestCommand_ShuffleBallotsFormatErrors(t *testing.T) {
-	k := 3
+func TestCommand_CastVoteSchedule(t *testing.T) {
+	initMetrics()
 
-	election, shuffleBallots, contract := initBadShuffleBallot(k)
+	castVote := makeCastVote(t, "dummyUserId")
 
-	data, err := shuffleBallots.Serialize(ctx)
+	data, err := castVote.Serialize(ctx)
 	require.NoError(t, err)
 
-	electionBuf, err := election.Serialize(ctx)
+	dummyElection, contract := initElectionAndContract()
+	dummyElection.Status = types.Open
+	dummyElection.BallotSize = 29
+	dummyElection.Configuration.Schedule = types.Schedule{
+		Start: 100,
+		End:   200,
+	}
+
+	electionBuf, err := dummyElection.Serialize(ctx)
+	require.NoError(t, err)
+
+	snap := fake.NewSnapshot()
+
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
 	require.NoError(t, err)
 
 	cmd := evotingCommand{
 		Contract: &contract,
-		prover:   fakeProver,
 	}
 
-	err = cmd.shuffleBallots(fake.NewSnapshot(), makeStep(t))
-	require.EqualError(t, err, getTransactionErr)
+	// no time has been agreed yet
+	err = cmd.castVote(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "the election does not accept ballots at 0, "+
+		"schedule is [100, 200[")
 
-	err = cmd.shuffleBallots(fake.NewSnapshot(), makeStep(t, ElectionArg, "dummy"))
-	require.EqualError(t, err, unmarshalTransactionErr)
+	reportTime(t, snap, cmd, 50)
 
-	err = cmd.shuffleBallots(fake.NewBadSnapshot(), makeStep(t, ElectionArg, string(data)))
-	require.Contains(t, err.Error(), "failed to get key")
+	err = cmd.castVote(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "the election does not accept ballots at 50, "+
+		"schedule is [100, 200[")
 
-	// Wrong election id format
-	snap := fake.NewSnapshot()
+	reportTime(t, snap, cmd, 150)
 
-	err = snap.Set(dummyElectionIDBuff, invalidElection)
+	err = cmd.castVote(snap, makeStep(t, ElectionArg, string(data)))
 	require.NoError(t, err)
 
-	err = cmd.shuffleBallots(snap, makeStep(t, ElectionArg, string(data)))
-	require.Contains(t, err.Error(), deserializeErr)
+	reportTime(t, snap, cmd, 200)
 
-	// Election not closed
-	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	err = cmd.castVote(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "the election does not accept ballots at 200, "+
+		"schedule is [100, 200[")
+}
+
+func TestCommand_CastVoteElectorate(t *testing.T) {
+	initMetrics()
+
+	castVote := makeCastVote(t, "dummyUserId")
+
+	data, err := castVote.Serialize(ctx)
 	require.NoError(t, err)
 
-	err = cmd.shuffleBallots(snap, makeStep(t, ElectionArg, string(data)))
-	require.EqualError(t, err, "the election is not closed")
+	dummyElection, contract := initElectionAndContract()
+	dummyElection.Status = types.Open
+	dummyElection.BallotSize = 29
 
-	// Wrong round :
-	election.Status = types.Closed
+	snap := fake.NewSnapshot()
 
-	electionBuf, err = election.Serialize(ctx)
+	addVoters(t, snap, &dummyElection, "anotherUserId")
+
+	electionBuf, err := dummyElection.Serialize(ctx)
 	require.NoError(t, err)
 
 	err = snap.Set(dummyElectionIDBuff, electionBuf)
 	require.NoError(t, err)
 
-	err = cmd.shuffleBallots(snap, makeStep(t, ElectionArg, string(data)))
-	require.EqualError(t, err, "wrong shuffle round: expected round '0', "+
-		"transaction is for round '2'")
+	cmd := evotingCommand{
+		Contract: &contract,
+	}
 
-	// Missing public key of shuffler:
-	shuffleBallots.Round = 1
-	election.ShuffleInstances = make([]types.ShuffleInstance, 1)
-	shuffleBallots.PublicKey = []byte("wrong Key")
+	err = cmd.castVote(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "user \"dummyUserId\" is not allowed to vote")
 
-	data, err = shuffleBallots.Serialize(ctx)
-	require.NoError(t, err)
+	addVoters(t, snap, &dummyElection, "dummyUserId")
 
-	electionBuf, err = election.Serialize(ctx)
+	electionBuf, err = dummyElection.Serialize(ctx)
 	require.NoError(t, err)
 
 	err = snap.Set(dummyElectionIDBuff, electionBuf)
 	require.NoError(t, err)
 
-	err = cmd.shuffleBallots(snap, makeStep(t, ElectionArg, string(data)))
-	require.EqualError(t, err, "could not verify identity of shuffler : "+
-		"public key not associated to a member of the roster: 77726f6e67204b6579")
+	err = cmd.castVote(snap, makeStep(t, ElectionArg, string(data)))
+	require.NoError(t, err)
+}
 
-	// Right key, wrong signature:
-	shuffleBallots.PublicKey, _ = fakeCommonSigner.GetPublicKey().MarshalBinary()
+func TestCommand_CastVotes(t *testing.T) {
+	initMetrics()
 
-	data, err = shuffleBallots.Serialize(ctx)
+	dummyElection, contract := initElectionAndContract()
+	dummyElection.Status = types.Open
+	dummyElection.BallotSize = 29
+
+	electionBuf, err := dummyElection.Serialize(ctx)
 	require.NoError(t, err)
 
-	err = cmd.shuffleBallots(snap, makeStep(t, ElectionArg, string(data)))
-	require.EqualError(t, err, "could node deserialize shuffle signature : "+
-		"couldn't decode signature: couldn't deserialize data: unexpected end of JSON input")
+	snap := fake.NewSnapshot()
 
-	// Wrong election ID (Hash of shuffle fails)
-	signature, err := fakeCommonSigner.Sign([]byte("fake shuffle"))
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
 	require.NoError(t, err)
 
-	wrongSignature, err := signature.Serialize(contract.context)
-	require.NoError(t, err)
+	cmd := evotingCommand{
+		Contract: &contract,
+	}
 
-	shuffleBallots.Signature = wrongSignature
+	err = cmd.castVotes(snap, makeStep(t, ElectionArg, "dummy"))
+	require.EqualError(t, err, unmarshalTransactionErr)
 
-	data, err = shuffleBallots.Serialize(ctx)
+	data, err := types.CastVotes{}.Serialize(ctx)
 	require.NoError(t, err)
 
-	// Signatures not matching:
-	election.ElectionID = fakeElectionID
+	err = cmd.castVotes(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, fmt.Sprintf("the number of votes must be "+
+		"between 1 and %d: 0", types.MaxBatchedVotes))
 
-	electionBuf, err = election.Serialize(ctx)
-	require.NoError(t, err)
+	vote1 := makeCastVote(t, "user1")
+	vote4 := makeCastVote(t, "user4")
 
-	err = snap.Set(dummyElectionIDBuff, electionBuf)
-	require.NoError(t, err)
+	// a copy of the ballot of user1
+	vote5 := vote1
+	vote5.UserID = "user5"
 
-	err = cmd.shuffleBallots(snap, makeStep(t, ElectionArg, string(data)))
-	require.EqualError(t, err, "signature does not match the Shuffle : "+
-		"bls verify failed: bls: invalid signature")
+	castVotes := types.CastVotes{Votes: []types.CastVote{
+		vote1,
+		// invalid votes are skipped
+		{ElectionID: fakeElectionID, UserID: "user2", Ballot: types.Ciphervote{}},
+		{ElectionID: "X", UserID: "user3", Ballot: vote1.Ballot},
+		vote4,
+		vote5,
+	}}
 
-	// Good format, signature not updated thus not matching, no random vector yet :
-	Ks, Cs, pubKey := fakeKCPoints(k)
+	data, err = castVotes.Serialize(ctx)
+	require.NoError(t, err)
 
-	for i := 0; i < k; i++ {
-		ballot := types.Ciphervote{types.EGPair{
-			K: Ks[i],
-			C: Cs[i],
-		}}
-		shuffleBallots.ShuffledBallots[i] = ballot
-	}
+	step := makeStep(t, ElectionArg, string(data))
 
-	h := sha256.New()
+	err = cmd.castVotes(snap, step)
+	require.NoError(t, err)
 
-	err = shuffleBallots.Fingerprint(h)
+	// the result of each vote is recorded under the ID of the transaction
+	buf, err := snap.Get(types.VoteResultsKey(step.Current.GetID()))
 	require.NoError(t, err)
 
-	hash := h.Sum(nil)
+	results, err := types.DecodeVoteResults(buf)
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+	require.Equal(t, types.VoteResult{ElectionID: fakeElectionID, UserID: "user1"}, results[0])
+	require.Equal(t, "user2", results[1].UserID)
+	require.NotEmpty(t, results[1].Error)
+	require.Regexp(t, "^failed to get election: ", results[2].Error)
+	require.Equal(t, types.VoteResult{ElectionID: fakeElectionID, UserID: "user4"}, results[3])
+	require.Equal(t, "user5", results[4].UserID)
+	require.NotEmpty(t, results[4].Error)
+
+	election := readElection(t, snap)
+	require.Equal(t, 2, election.Suffragia.Count)
+	require.True(t, vote1.Ballot.Equal(readBallot(t, snap, "user1")))
+	require.True(t, vote4.Ballot.Equal(readBallot(t, snap, "user4")))
+
+	res, err := snap.Get(types.BallotKey(dummyElectionIDBuff, "user2"))
+	require.NoError(t, err)
+	require.Empty(t, res)
+
+	userID, err := snap.Get(types.BallotIndexKey(dummyElectionIDBuff, 1))
+	require.NoError(t, err)
+	require.Equal(t, "user4", string(userID))
+
+	require.Equal(t, float64(2), testutil.ToFloat64(PromElectionBallots))
+}
+
+func TestCommand_BallotReceipt(t *testing.T) {
+	initMetrics()
+
+	election, contract := initElectionAndContract()
+	election.Status = types.Open
+
+	snap := fake.NewSnapshot()
+
+	cmd := evotingCommand{
+		Contract: &contract,
+	}
+
+	first := types.Ciphervote{types.EGPair{K: suite.Point().Null(), C: suite.Point().Base()}}
+	second := types.Ciphervote{types.EGPair{K: suite.Point().Base(), C: suite.Point().Base()}}
+
+	firstReceipt, err := first.Receipt()
+	require.NoError(t, err)
+
+	secondReceipt, err := second.Receipt()
+	require.NoError(t, err)
+
+	err = cmd.storeBallot(snap, &election, dummyElectionIDBuff, "user1", first)
+	require.NoError(t, err)
+
+	res, err := snap.Get(types.ReceiptKey(dummyElectionIDBuff, firstReceipt))
+	require.NoError(t, err)
+	require.Equal(t, "user1", string(res))
+
+	// a new ballot replaces the receipt
+	err = cmd.storeBallot(snap, &election, dummyElectionIDBuff, "user1", second)
+	require.NoError(t, err)
+
+	res, err = snap.Get(types.ReceiptKey(dummyElectionIDBuff, firstReceipt))
+	require.NoError(t, err)
+	require.Empty(t, res)
+
+	res, err = snap.Get(types.ReceiptKey(dummyElectionIDBuff, secondReceipt))
+	require.NoError(t, err)
+	require.Equal(t, "user1", string(res))
+
+	// the receipt is kept when the ballots are gathered
+	userIDs, ciphervotes, err := cmd.readBallots(snap, election, dummyElectionIDBuff)
+	require.NoError(t, err)
+
+	err = cmd.moveBallots(snap, &election, dummyElectionIDBuff, userIDs, ciphervotes)
+	require.NoError(t, err)
+
+	res, err = snap.Get(types.ReceiptKey(dummyElectionIDBuff, secondReceipt))
+	require.NoError(t, err)
+	require.Equal(t, "user1", string(res))
+
+	err = cmd.applyDelete(snap, election, dummyElectionIDBuff)
+	require.NoError(t, err)
+
+	res, err = snap.Get(types.ReceiptKey(dummyElectionIDBuff, secondReceipt))
+	require.NoError(t, err)
+	require.Empty(t, res)
+}
+
+func TestCommand_OpenElectionSchedule(t *testing.T) {
+	openElection := types.OpenElection{
+		ElectionID: fakeElectionID,
+	}
+
+	data, err := openElection.Serialize(ctx)
+	require.NoError(t, err)
+
+	dummyElection, contract := initElectionAndContract()
+	dummyElection.Configuration.Schedule = types.Schedule{
+		Start: 100,
+	}
+
+	electionBuf, err := dummyElection.Serialize(ctx)
+	require.NoError(t, err)
+
+	snap := fake.NewSnapshot()
+
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
+
+	cmd := evotingCommand{
+		Contract: &contract,
+	}
+
+	reportTime(t, snap, cmd, 99)
+
+	err = cmd.openElection(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "the election cannot be opened before 100")
+
+	// without a schedule, the signature of the admin is required
+	dummyElection.Configuration.Schedule = types.Schedule{}
+
+	electionBuf, err = dummyElection.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
+
+	err = cmd.openElection(snap, makeStep(t, ElectionArg, string(data)))
+	require.Contains(t, err.Error(), "failed to check admin: invalid admin signature")
+}
+
+func TestCommand_CloseElectionSchedule(t *testing.T) {
+	initMetrics()
+
+	closeElection := types.CloseElection{
+		ElectionID: fakeElectionID,
+	}
+
+	data, err := closeElection.Serialize(ctx)
+	require.NoError(t, err)
+
+	dummyElection, contract := initElectionAndContract()
+	dummyElection.Status = types.Open
+	dummyElection.Configuration.Schedule = types.Schedule{
+		End: 100,
+	}
+
+	dummyElection.Suffragia.CastVote("dummyUser1", types.Ciphervote{})
+	dummyElection.Suffragia.CastVote("dummyUser2", types.Ciphervote{})
+
+	electionBuf, err := dummyElection.Serialize(ctx)
+	require.NoError(t, err)
+
+	snap := fake.NewSnapshot()
+
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
+
+	cmd := evotingCommand{
+		Contract: &contract,
+	}
+
+	// the nodes can't close the election before its end without the admin
+	reportTime(t, snap, cmd, 99)
+
+	err = cmd.closeElection(snap, makeStep(t, ElectionArg, string(data)))
+	require.Contains(t, err.Error(), "failed to check admin: invalid admin signature")
+
+	reportTime(t, snap, cmd, 100)
+
+	err = cmd.closeElection(snap, makeStep(t, ElectionArg, string(data)))
+	require.NoError(t, err)
+}
+
+func TestCommand_ReportTime(t *testing.T) {
+	_, contract := initElectionAndContract()
+
+	cmd := evotingCommand{
+		Contract: &contract,
+	}
+
+	snap := fake.NewSnapshot()
+
+	err := cmd.reportTime(snap, makeStep(t, ElectionArg, "dummy"))
+	require.Contains(t, err.Error(), "failed to get transaction")
+
+	data, err := types.OpenElection{}.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = cmd.reportTime(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "wrong type of transaction: types.OpenElection")
+
+	tx := types.ReportTime{Timestamp: 100, PublicKey: []byte("unknown")}
+
+	data, err = tx.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = cmd.reportTime(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "could not verify identity of node: public key "+
+		"not associated to a member of the roster: 756e6b6e6f776e")
+
+	tx.PublicKey, err = fakeCommonSigner.GetPublicKey().MarshalBinary()
+	require.NoError(t, err)
+
+	signature, err := fakeCommonSigner.Sign([]byte("fake time"))
+	require.NoError(t, err)
+
+	tx.Signature, err = signature.Serialize(ctx)
+	require.NoError(t, err)
+
+	data, err = tx.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = cmd.reportTime(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "signature does not match the time: "+
+		"bls verify failed: bls: invalid signature ")
+
+	reportTime(t, snap, cmd, 100)
+
+	clock, err := cmd.getClock(snap)
+	require.NoError(t, err)
+	require.Equal(t, []types.TimeReport{{PublicKey: tx.PublicKey, Time: 100}}, clock.Reports)
+
+	// the time of a node can't go back
+	h := sha256.New()
+
+	err = tx.Fingerprint(h)
+	require.NoError(t, err)
+
+	signature, err = fakeCommonSigner.Sign(h.Sum(nil))
+	require.NoError(t, err)
+
+	tx.Signature, err = signature.Serialize(ctx)
+	require.NoError(t, err)
+
+	data, err = tx.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = cmd.reportTime(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "failed to report time: the time must be after "+
+		"the last report: 100 <= 100")
+}
+
+// Replaying the blocks, long after the time of the schedule, must give the
+// same result as when they were first executed.
+func TestCommand_ScheduleReplay(t *testing.T) {
+	initMetrics()
+
+	castVote := makeCastVote(t, "dummyUserId")
+
+	data, err := castVote.Serialize(ctx)
+	require.NoError(t, err)
+
+	step := makeStep(t, ElectionArg, string(data))
+
+	dummyElection, contract := initElectionAndContract()
+	dummyElection.Status = types.Open
+	dummyElection.BallotSize = 29
+	dummyElection.Configuration.Schedule = types.Schedule{
+		Start: 100,
+		End:   200,
+	}
+
+	electionBuf, err := dummyElection.Serialize(ctx)
+	require.NoError(t, err)
+
+	cmd := evotingCommand{
+		Contract: &contract,
+	}
+
+	// the reports and the vote are old, compared to the clock of the node
+	for _, timestamp := range []int64{150, 199} {
+		results := make([]error, 2)
+
+		for i := range results {
+			snap := fake.NewSnapshot()
+
+			err = snap.Set(dummyElectionIDBuff, electionBuf)
+			require.NoError(t, err)
+
+			reportTime(t, snap, cmd, timestamp)
+
+			results[i] = cmd.castVote(snap, step)
+		}
+
+		require.NoError(t, results[0])
+		require.Equal(t, results[0], results[1])
+	}
+}
+
+func TestCommand_CloseElectionQuorum(t *testing.T) {
+	initMetrics()
+
+	closeElection := types.CloseElection{
+		ElectionID:     fakeElectionID,
+		AdminSignature: signAdmin(t, CmdCloseElection, nil, 0),
+	}
+
+	data, err := closeElection.Serialize(ctx)
+	require.NoError(t, err)
+
+	dummyElection, contract := initElectionAndContract()
+	dummyElection.Status = types.Open
+	dummyElection.Configuration.Quorum = types.Quorum{MinTurnout: 50}
+
+	snap := fake.NewSnapshot()
+
+	addVoters(t, snap, &dummyElection, "user0", "user1", "user2", "user3", "user4")
+
+	dummyElection.Suffragia.CastVote("user0", types.Ciphervote{})
+	dummyElection.Suffragia.CastVote("user1", types.Ciphervote{})
+
+	electionBuf, err := dummyElection.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
+
+	cmd := evotingCommand{
+		Contract: &contract,
+	}
+
+	err = cmd.closeElection(snap, makeStep(t, ElectionArg, string(data)))
+	require.NoError(t, err)
+
+	election := readElection(t, snap)
+	require.Equal(t, types.QuorumNotReached, election.Status)
+	require.Equal(t, "turnout of 2/5 voters, at least 50% required", election.StatusReason)
+	require.Equal(t, float64(types.QuorumNotReached), testutil.ToFloat64(PromElectionStatus))
+
+	// the quorum is reached with a third ballot
+	dummyElection.Suffragia.CastVote("user2", types.Ciphervote{})
+
+	electionBuf, err = dummyElection.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
+
+	err = cmd.closeElection(snap, makeStep(t, ElectionArg, string(data)))
+	require.NoError(t, err)
+
+	election = readElection(t, snap)
+	require.Equal(t, types.Closed, election.Status)
+	require.Empty(t, election.StatusReason)
+}
+
+func TestCommand_CloseElection(t *testing.T) {
+	initMetrics()
+
+	closeElection := types.CloseElection{
+		ElectionID: fakeElectionID,
+		UserID:     "dummyUserId",
+	}
+
+	data, err := closeElection.Serialize(ctx)
+	require.NoError(t, err)
+
+	dummyElection, contract := initElectionAndContract()
+	dummyElection.ElectionID = fakeElectionID
+
+	electionBuf, err := dummyElection.Serialize(ctx)
+	require.NoError(t, err)
+
+	cmd := evotingCommand{
+		Contract: &contract,
+	}
+
+	err = cmd.closeElection(fake.NewSnapshot(), makeStep(t))
+	require.EqualError(t, err, getTransactionErr)
+
+	err = cmd.closeElection(fake.NewSnapshot(), makeStep(t, ElectionArg, "dummy"))
+	require.EqualError(t, err, unmarshalTransactionErr)
+
+	err = cmd.closeElection(fake.NewBadSnapshot(), makeStep(t, ElectionArg, string(data)))
+	require.Contains(t, err.Error(), "failed to get key")
+
+	snap := fake.NewSnapshot()
+
+	err = snap.Set(dummyElectionIDBuff, invalidElection)
+	require.NoError(t, err)
+
+	err = cmd.closeElection(snap, makeStep(t, ElectionArg, string(data)))
+	require.Contains(t, err.Error(), deserializeErr)
+
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
+
+	closeElection.UserID = hex.EncodeToString([]byte("dummyAdminID"))
+
+	data, err = closeElection.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = cmd.closeElection(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, fmt.Sprintf("the election is not open, "+
+		"current status: %d", types.Initial))
+	require.Equal(t, 0, testutil.CollectAndCount(PromElectionStatus))
+
+	dummyElection.Status = types.Open
+
+	electionBuf, err = dummyElection.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
+
+	err = cmd.closeElection(snap, makeStep(t, ElectionArg, string(data)))
+	require.Contains(t, err.Error(), "failed to check admin: invalid admin signature")
+
+	closeElection.AdminSignature = signAdmin(t, CmdCloseElection, nil, 0)
+
+	data, err = closeElection.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = cmd.closeElection(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "at least two ballots are required")
+
+	castBallot(t, snap, &contract, &dummyElection, "dummyUser2")
+
+	electionBuf, err = dummyElection.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
+
+	err = cmd.closeElection(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "at least two ballots are required")
+
+	// the rejected closing leaves the ballot in place
+	res, err := snap.Get(types.BallotKey(dummyElectionIDBuff, "dummyUser2"))
+	require.NoError(t, err)
+	require.NotEmpty(t, res)
+
+	castBallot(t, snap, &contract, &dummyElection, "dummyUser1")
+
+	electionBuf, err = dummyElection.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
+
+	err = cmd.closeElection(snap, makeStep(t, ElectionArg, string(data)))
+	require.NoError(t, err)
+	require.Equal(t, float64(types.Closed), testutil.ToFloat64(PromElectionStatus))
+
+	res, err = snap.Get(dummyElectionIDBuff)
+	require.NoError(t, err)
+
+	message, err := electionFac.Deserialize(ctx, res)
+	require.NoError(t, err)
+
+	election, ok := message.(types.Election)
+	require.True(t, ok)
+
+	require.Equal(t, types.Closed, election.Status)
+	require.Equal(t, float64(types.Closed), testutil.ToFloat64(PromElectionStatus))
+
+	// the ballots are gathered in the order of the index
+	require.Equal(t, []string{"dummyUser2", "dummyUser1"}, election.Suffragia.UserIDs)
+	require.Len(t, election.Suffragia.Ciphervotes, 2)
+
+	res, err = snap.Get(types.BallotKey(dummyElectionIDBuff, "dummyUser1"))
+	require.NoError(t, err)
+	require.Nil(t, res)
+}
+
+func TestCommand_ShuffleBallotsCannotShuffleTwice(t *testing.T) {
+	k := 3
+
+	election, shuffleBallots, contract := initGoodShuffleBallot(t, k)
+
+	cmd := evotingCommand{
+		Contract: &contract,
+		prover:   fakeProver,
+	}
+
+	snap := fake.NewSnapshot()
+
+	// Attempts to shuffle twice :
+	shuffleBallots.Round = 1
+
+	election.ShuffleInstances = make([]types.ShuffleInstance, 1)
+	election.ShuffleInstances[0].ShuffledBallots = make([]types.Ciphervote, 3)
+
+	Ks, Cs, _ := fakeKCPoints(k)
+	for i := 0; i < k; i++ {
+		ballot := types.Ciphervote{types.EGPair{
+			K: Ks[i],
+			C: Cs[i],
+		}}
+		election.ShuffleInstances[0].ShuffledBallots[i] = ballot
+	}
+
+	election.ShuffleInstances[0].ShufflerPublicKey = shuffleBallots.PublicKey
+
+	electionBuff, err := election.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = snap.Set(dummyElectionIDBuff, electionBuff)
+	require.NoError(t, err)
+
+	data, err := shuffleBallots.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = cmd.shuffleBallots(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "a node already submitted a shuffle that has "+
+		"been accepted in round 0")
+}
+
+func TestCommand_ShuffleBallotsValidScenarios(t *testing.T) {
+	initMetrics()
+
+	k := 3
+
+	// Simple Shuffle from round 0 :
+	election, shuffleBallots, contract := initGoodShuffleBallot(t, k)
+
+	cmd := evotingCommand{
+		Contract: &contract,
+		prover:   fakeProver,
+	}
+
+	snap := fake.NewSnapshot()
+
+	electionBuf, err := election.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
+
+	data, err := shuffleBallots.Serialize(ctx)
+	require.NoError(t, err)
+
+	step := makeStep(t, ElectionArg, string(data))
+
+	err = cmd.shuffleBallots(snap, step)
+	require.NoError(t, err)
+	require.Equal(t, float64(1), testutil.ToFloat64(PromElectionShufflingInstances))
+
+	// Valid Shuffle is over :
+	shuffleBallots.Round = k
+	election.ShuffleInstances = make([]types.ShuffleInstance, k)
+
+	// the random vector depends on the round
+	h := sha256.New()
+
+	err = shuffleBallots.Fingerprint(h)
+	require.NoError(t, err)
+
+	e, err := ShuffleRandomVector(election.RandomVectorVersion, election.ElectionID,
+		k, h.Sum(nil), election.ChunksPerBallot())
+	require.NoError(t, err)
+
+	err = shuffleBallots.RandomVector.LoadFromScalars(e)
+	require.NoError(t, err)
+
+	data, err = shuffleBallots.Serialize(ctx)
+	require.NoError(t, err)
+
+	for i := 1; i <= k-1; i++ {
+		election.ShuffleInstances[i].ShuffledBallots = make([]types.Ciphervote, 3)
+	}
+
+	Ks, Cs, _ := fakeKCPoints(k)
+	for i := 0; i < k; i++ {
+		ballot := types.Ciphervote{types.EGPair{
+			K: Ks[i],
+			C: Cs[i],
+		}}
+		election.ShuffleInstances[k-1].ShuffledBallots[i] = ballot
+	}
+
+	electionBuf, err = election.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
+
+	err = cmd.shuffleBallots(snap, makeStep(t, ElectionArg, string(data)))
+	require.NoError(t, err)
+	require.Equal(t, float64(k+1), testutil.ToFloat64(PromElectionShufflingInstances))
+
+	// Check the shuffle is over:
+	electionTxIDBuff, err := hex.DecodeString(election.ElectionID)
+	require.NoError(t, err)
+
+	electionBuf, err = snap.Get(electionTxIDBuff)
+	require.NoError(t, err)
+
+	message, err := electionFac.Deserialize(ctx, electionBuf)
+	require.NoError(t, err)
+
+	election, ok := message.(types.Election)
+	require.True(t, ok)
+
+	require.Equal(t, types.ShuffledBallots, election.Status)
+	require.Equal(t, float64(types.ShuffledBallots), testutil.ToFloat64(PromElectionStatus))
+}
+
+func TestCommand_ShuffleBallotsFormatErrors(t *testing.T) {
+	k := 3
+
+	election, shuffleBallots, contract := initBadShuffleBallot(k)
+
+	data, err := shuffleBallots.Serialize(ctx)
+	require.NoError(t, err)
+
+	electionBuf, err := election.Serialize(ctx)
+	require.NoError(t, err)
+
+	cmd := evotingCommand{
+		Contract: &contract,
+		prover:   fakeProver,
+	}
+
+	err = cmd.shuffleBallots(fake.NewSnapshot(), makeStep(t))
+	require.EqualError(t, err, getTransactionErr)
+
+	err = cmd.shuffleBallots(fake.NewSnapshot(), makeStep(t, ElectionArg, "dummy"))
+	require.EqualError(t, err, unmarshalTransactionErr)
+
+	err = cmd.shuffleBallots(fake.NewBadSnapshot(), makeStep(t, ElectionArg, string(data)))
+	require.Contains(t, err.Error(), "failed to get key")
+
+	// Wrong election id format
+	snap := fake.NewSnapshot()
+
+	err = snap.Set(dummyElectionIDBuff, invalidElection)
+	require.NoError(t, err)
+
+	err = cmd.shuffleBallots(snap, makeStep(t, ElectionArg, string(data)))
+	require.Contains(t, err.Error(), deserializeErr)
+
+	// Election not closed
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
+
+	err = cmd.shuffleBallots(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "the election is not closed")
+
+	// Wrong round :
+	election.Status = types.Closed
+
+	electionBuf, err = election.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
+
+	err = cmd.shuffleBallots(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "wrong shuffle round: expected round '0', "+
+		"transaction is for round '2'")
+
+	// Missing public key of shuffler:
+	shuffleBallots.Round = 1
+	election.ShuffleInstances = make([]types.ShuffleInstance, 1)
+	shuffleBallots.PublicKey = []byte("wrong Key")
+
+	data, err = shuffleBallots.Serialize(ctx)
+	require.NoError(t, err)
+
+	electionBuf, err = election.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
+
+	err = cmd.shuffleBallots(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "could not verify identity of shuffler : "+
+		"public key not associated to a member of the roster: 77726f6e67204b6579")
+
+	// Right key, wrong signature:
+	shuffleBallots.PublicKey, _ = fakeCommonSigner.GetPublicKey().MarshalBinary()
+
+	data, err = shuffleBallots.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = cmd.shuffleBallots(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "could node deserialize shuffle signature : "+
+		"couldn't decode signature: couldn't deserialize data: unexpected end of JSON input")
+
+	// Wrong election ID (Hash of shuffle fails)
+	signature, err := fakeCommonSigner.Sign([]byte("fake shuffle"))
+	require.NoError(t, err)
+
+	wrongSignature, err := signature.Serialize(contract.context)
+	require.NoError(t, err)
+
+	shuffleBallots.Signature = wrongSignature
+
+	data, err = shuffleBallots.Serialize(ctx)
+	require.NoError(t, err)
+
+	// Signatures not matching:
+	election.ElectionID = fakeElectionID
+
+	electionBuf, err = election.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
+
+	err = cmd.shuffleBallots(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "signature does not match the Shuffle : "+
+		"bls verify failed: bls: invalid signature")
+
+	// Good format, signature not updated thus not matching, no random vector yet :
+	Ks, Cs, pubKey := fakeKCPoints(k)
+
+	for i := 0; i < k; i++ {
+		ballot := types.Ciphervote{types.EGPair{
+			K: Ks[i],
+			C: Cs[i],
+		}}
+		shuffleBallots.ShuffledBallots[i] = ballot
+	}
+
+	h := sha256.New()
+
+	err = shuffleBallots.Fingerprint(h)
+	require.NoError(t, err)
+
+	hash := h.Sum(nil)
 
 	signature, _ = fakeCommonSigner.Sign(hash)
 	wrongSignature, _ = signature.Serialize(contract.context)
 
-	shuffleBallots.Signature = wrongSignature
+	shuffleBallots.Signature = wrongSignature
+
+	election.BallotSize = 1
+
+	electionBuf, err = election.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
+
+	data, err = shuffleBallots.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = cmd.shuffleBallots(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "randomVector has unexpected length : 0 != 1")
+
+	// random vector of another version:
+	shuffleBallots.RandomVectorVersion = types.RandomVectorLegacy
+
+	data, err = shuffleBallots.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = cmd.shuffleBallots(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "unexpected random vector version: 0 != 1")
+
+	shuffleBallots.RandomVectorVersion = types.CurrentRandomVectorVersion
+
+	// random vector with right length, but different value :
+	lenRandomVector := election.ChunksPerBallot()
+	e := make([]kyber.Scalar, lenRandomVector)
+	for i := 0; i < lenRandomVector; i++ {
+		v := suite.Scalar().Pick(suite.RandomStream())
+		e[i] = v
+	}
+
+	err = shuffleBallots.RandomVector.LoadFromScalars(e)
+	require.NoError(t, err)
+
+	data, err = shuffleBallots.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = cmd.shuffleBallots(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "random vector from shuffle transaction is "+
+		"different than expected random vector")
+
+	// generate correct random vector, for the round 0 used below:
+	h = sha256.New()
+
+	err = shuffleBallots.Fingerprint(h)
+	require.NoError(t, err)
+
+	hash = h.Sum(nil)
+
+	e, err = ShuffleRandomVector(election.RandomVectorVersion, election.ElectionID,
+		0, hash, lenRandomVector)
+	require.NoError(t, err)
+
+	err = shuffleBallots.RandomVector.LoadFromScalars(e)
+	require.NoError(t, err)
+
+	// > With no casted ballot the shuffling can't happen
+
+	election.Pubkey = pubKey
+	shuffleBallots.Round = 0
+	election.ShuffleInstances = make([]types.ShuffleInstance, 0)
+	election.Suffragia.Ciphervotes = make([]types.Ciphervote, 0)
+
+	data, err = shuffleBallots.Serialize(ctx)
+	require.NoError(t, err)
+
+	electionBuf, err = election.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
+
+	err = cmd.shuffleBallots(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "not enough votes: 0 < 2")
+
+	// > With only one shuffled ballot the shuffling can't happen
+
+	election.Suffragia.CastVote("user1", types.Ciphervote{
+		types.EGPair{K: suite.Point(), C: suite.Point()},
+	})
+
+	data, err = shuffleBallots.Serialize(ctx)
+	require.NoError(t, err)
+
+	electionBuf, err = election.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
+
+	err = cmd.shuffleBallots(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "not enough votes: 1 < 2")
+}
+
+func TestShuffleRandomVector(t *testing.T) {
+	hash := sha256.Sum256([]byte("shuffle"))
+
+	e, err := ShuffleRandomVector(types.RandomVectorXOF, "abcd", 0, hash[:], 3)
+	require.NoError(t, err)
+	require.Len(t, e, 3)
+
+	// the derivation is deterministic
+	e2, err := ShuffleRandomVector(types.RandomVectorXOF, "abcd", 0, hash[:], 3)
+	require.NoError(t, err)
+
+	for i := range e {
+		require.True(t, e[i].Equal(e2[i]))
+	}
+
+	// the vector depends on the round, the election and the version
+	for _, other := range [][]kyber.Scalar{
+		mustShuffleRandomVector(t, types.RandomVectorXOF, "abcd", 1, hash[:]),
+		mustShuffleRandomVector(t, types.RandomVectorXOF, "abce", 0, hash[:]),
+		mustShuffleRandomVector(t, types.RandomVectorLegacy, "abcd", 0, hash[:]),
+	} {
+		require.False(t, e[0].Equal(other[0]))
+	}
+
+	// the legacy derivation only depends on the hash
+	legacy := mustShuffleRandomVector(t, types.RandomVectorLegacy, "abcd", 0, hash[:])
+	legacy2 := mustShuffleRandomVector(t, types.RandomVectorLegacy, "abce", 1, hash[:])
+	require.True(t, legacy[0].Equal(legacy2[0]))
+
+	_, err = ShuffleRandomVector(2, "abcd", 0, hash[:], 3)
+	require.EqualError(t, err, "unsupported random vector version: 2")
+}
+
+func TestDecrypt_Threshold(t *testing.T) {
+	// 2-of-3 sharing of the secret key
+	poly := share.NewPriPoly(suite, 2, nil, suite.RandomStream())
+	pubkey := suite.Point().Mul(poly.Secret(), nil)
+
+	M := suite.Point().Embed([]byte("hello"), suite.RandomStream())
+	k := suite.Scalar().Pick(suite.RandomStream())
+
+	egpair := types.EGPair{
+		K: suite.Point().Mul(k, nil),
+		C: suite.Point().Add(suite.Point().Mul(k, pubkey), M),
+	}
+
+	var allPubShares []types.PubsharesUnit
+	var indexes []int
+
+	for _, priShare := range poly.Shares(3) {
+		S := suite.Point().Mul(priShare.V, egpair.K)
+		pubShare := suite.Point().Sub(egpair.C, S)
+
+		allPubShares = append(allPubShares, types.PubsharesUnit{{pubShare}})
+		indexes = append(indexes, priShare.I)
+	}
+
+	// exactly t shares are combined
+	chunk, err := decrypt(0, 0, allPubShares, indexes, 2)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), chunk)
+
+	chunk, err = decrypt(0, 0, allPubShares[1:], indexes[1:], 2)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), chunk)
+
+	// without a threshold, all the shares are combined
+	chunk, err = decrypt(0, 0, allPubShares, indexes, 0)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), chunk)
+
+	_, err = decrypt(0, 0, allPubShares[:1], indexes[:1], 2)
+	require.Error(t, err)
+}
+
+func TestCommand_RegisterPubShares(t *testing.T) {
+	registerPubShares := types.RegisterPubShares{
+		ElectionID: fakeElectionID,
+		Index:      0,
+		Pubshares:  make([][]types.Pubshare, 0),
+		Signature:  []byte{},
+		PublicKey:  []byte{},
+	}
+
+	data, err := registerPubShares.Serialize(ctx)
+	require.NoError(t, err)
+
+	election, contract := initElectionAndContract()
+	election.ElectionID = fakeElectionID
+
+	electionBuf, err := election.Serialize(ctx)
+	require.NoError(t, err)
+
+	cmd := evotingCommand{
+		Contract: &contract,
+	}
+
+	err = cmd.registerPubshares(fake.NewSnapshot(), makeStep(t))
+	require.EqualError(t, err, getTransactionErr)
+
+	err = cmd.registerPubshares(fake.NewSnapshot(), makeStep(t, ElectionArg, "dummy"))
+	require.EqualError(t, err, unmarshalTransactionErr)
+
+	err = cmd.registerPubshares(fake.NewBadSnapshot(), makeStep(t, ElectionArg, string(data)))
+	require.Contains(t, err.Error(), "failed to get key")
+
+	snap := fake.NewSnapshot()
+
+	err = snap.Set(dummyElectionIDBuff, invalidElection)
+	require.NoError(t, err)
+
+	err = cmd.registerPubshares(snap, makeStep(t, ElectionArg, string(data)))
+	require.Contains(t, err.Error(), deserializeErr)
+
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
+
+	err = cmd.registerPubshares(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "the ballots have not been shuffled")
+
+	// Requirements:
+	election.Status = types.ShuffledBallots
+	election.PubsharesUnits = types.PubsharesUnits{
+		Pubshares: make([]types.PubsharesUnit, 0),
+		PubKeys:   make([][]byte, 0),
+		Indexes:   make([]int, 0),
+	}
+	election.ShuffleInstances = make([]types.ShuffleInstance, 1)
+	election.ShuffleInstances[0] = types.ShuffleInstance{
+		ShuffledBallots: make([]types.Ciphervote, 1),
+	}
+	egpair := types.EGPair{
+		K: suite.Point().Pick(suite.RandomStream()),
+		C: suite.Point().Pick(suite.RandomStream()),
+	}
+	election.ShuffleInstances[0].ShuffledBallots[0] = types.Ciphervote{egpair}
+
+	privShare := suite.Scalar().Pick(suite.RandomStream())
+	election.VerificationKeys = []kyber.Point{suite.Point().Mul(privShare, nil)}
+
+	electionBuf, err = election.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
+
+	err = cmd.registerPubshares(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "could not verify identity of node : public key not associated to a member of the roster: ")
+
+	registerPubShares.PublicKey, err = fakeCommonSigner.GetPublicKey().MarshalBinary()
+	require.NoError(t, err)
+
+	data, err = registerPubShares.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = cmd.registerPubshares(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "could node deserialize pubShare signature:"+
+		" couldn't decode signature: couldn't deserialize data: unexpected end of JSON input")
+
+	signature, err := fakeCommonSigner.Sign([]byte("fake shares"))
+	require.NoError(t, err)
+
+	wrongSignature, err := signature.Serialize(ctx)
+	require.NoError(t, err)
+
+	registerPubShares.Signature = wrongSignature
+
+	data, err = registerPubShares.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = cmd.registerPubshares(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "signature does not match the PubsharesUnit:"+
+		" bls verify failed: bls: invalid signature ")
+
+	h := sha256.New()
+
+	err = registerPubShares.Fingerprint(h)
+	require.NoError(t, err)
+
+	hash := h.Sum(nil)
+
+	signature, err = fakeCommonSigner.Sign(hash)
+	require.NoError(t, err)
+	correctSignature, err := signature.Serialize(ctx)
+	require.NoError(t, err)
+
+	registerPubShares.Signature = correctSignature
+
+	data, err = registerPubShares.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = cmd.registerPubshares(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "unexpected size of pubshares submission: 0 != 1")
+
+	registerPubShares.Pubshares = make([][]types.Pubshare, 1)
+
+	data, err = registerPubShares.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = cmd.registerPubshares(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "unexpected size of pubshares submission: 0 != 1")
+
+	// signs the submission and returns the serialized transaction
+	signPubShares := func() []byte {
+		h := sha256.New()
+
+		err := registerPubShares.Fingerprint(h)
+		require.NoError(t, err)
+
+		signature, err := fakeCommonSigner.Sign(h.Sum(nil))
+		require.NoError(t, err)
+
+		registerPubShares.Signature, err = signature.Serialize(ctx)
+		require.NoError(t, err)
+
+		data, err := registerPubShares.Serialize(ctx)
+		require.NoError(t, err)
+
+		return data
+	}
+
+	pubshare, proof, err := types.ProvePubshare(privShare, egpair)
+	require.NoError(t, err)
+
+	registerPubShares.Pubshares[0] = []types.Pubshare{suite.Point().Pick(suite.RandomStream())}
+
+	err = cmd.registerPubshares(snap, makeStep(t, ElectionArg, string(signPubShares())))
+	require.EqualError(t, err, "failed to verify pubshares: unexpected number of proofs: 0 != 1")
+
+	registerPubShares.Proofs = types.PubshareProofsUnit{{proof}}
+
+	err = cmd.registerPubshares(snap, makeStep(t, ElectionArg, string(signPubShares())))
+	require.EqualError(t, err, "failed to verify pubshares: invalid pubshare 0 of ballot 0: "+
+		"proof verification failed: invalid proof")
+
+	registerPubShares.Index = 1
+
+	err = cmd.registerPubshares(snap, makeStep(t, ElectionArg, string(signPubShares())))
+	require.EqualError(t, err, "no verification key for index 1")
+
+	registerPubShares.Index = 0
+	registerPubShares.Pubshares[0] = []types.Pubshare{pubshare}
+
+	data = signPubShares()
+
+	err = cmd.registerPubshares(snap, makeStep(t, ElectionArg, string(data)))
+	require.NoError(t, err)
+	require.Equal(t, float64(1), testutil.ToFloat64(PromElectionPubShares))
+
+	// With the public key already used:
+	election.PubsharesUnits.PubKeys = append(election.PubsharesUnits.PubKeys,
+		registerPubShares.PublicKey)
+
+	electionBuf, err = election.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
+
+	err = cmd.registerPubshares(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, fmt.Sprintf("'%x' already made a submission",
+		registerPubShares.PublicKey))
+
+	// With the index already used:
+	election.PubsharesUnits.Indexes = append(election.PubsharesUnits.Indexes,
+		registerPubShares.Index)
+	election.PubsharesUnits.PubKeys = make([][]byte, 0)
+
+	electionBuf, err = election.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
+
+	err = cmd.registerPubshares(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "a submission has already been made for index 0")
+
+	// All good:
+	election.PubsharesUnits.Indexes = make([]int, 0)
+
+	electionBuf, err = election.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
+
+	err = cmd.registerPubshares(snap, makeStep(t, ElectionArg, string(data)))
+	require.NoError(t, err)
+	require.Equal(t, float64(1), testutil.ToFloat64(PromElectionPubShares))
+
+	res, err := snap.Get(dummyElectionIDBuff)
+	require.NoError(t, err)
+
+	message, err := electionFac.Deserialize(ctx, res)
+	require.NoError(t, err)
+
+	resultElection, ok := message.(types.Election)
+	require.True(t, ok)
+
+	require.Equal(t, types.PubSharesSubmitted, resultElection.Status)
+
+	require.Equal(t, resultElection.PubsharesUnits.PubKeys[0], registerPubShares.PublicKey)
+	require.Equal(t, resultElection.PubsharesUnits.Indexes[0], registerPubShares.Index)
+}
+
+func TestCommand_DecryptBallots(t *testing.T) {
+	decryptBallot := types.CombineShares{
+		ElectionID: fakeElectionID,
+		UserID:     hex.EncodeToString([]byte("dummyUserId")),
+	}
+
+	data, err := decryptBallot.Serialize(ctx)
+	require.NoError(t, err)
+
+	dummyElection, contract := initElectionAndContract()
+
+	electionBuf, err := dummyElection.Serialize(ctx)
+	require.NoError(t, err)
+
+	cmd := evotingCommand{
+		Contract: &contract,
+	}
+
+	err = cmd.combineShares(fake.NewSnapshot(), makeStep(t))
+	require.EqualError(t, err, getTransactionErr)
+
+	err = cmd.combineShares(fake.NewSnapshot(), makeStep(t, ElectionArg, "dummy"))
+	require.EqualError(t, err, unmarshalTransactionErr)
+
+	err = cmd.combineShares(fake.NewBadSnapshot(), makeStep(t, ElectionArg, string(data)))
+	require.Contains(t, err.Error(), "failed to get key")
+
+	snap := fake.NewSnapshot()
+
+	err = snap.Set(dummyElectionIDBuff, invalidElection)
+	require.NoError(t, err)
+
+	err = cmd.combineShares(snap, makeStep(t, ElectionArg, string(data)))
+	require.Contains(t, err.Error(), deserializeErr)
+
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
+
+	decryptBallot.UserID = hex.EncodeToString([]byte("dummyAdminID"))
+
+	data, err = decryptBallot.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = cmd.combineShares(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, fmt.Sprintf("the public shares have not"+
+		" been submitted, current status: %d", types.Initial))
+
+	dummyElection.Status = types.PubSharesSubmitted
+
+	// Avoid panic (will always be the case in practice):
+	dummyElection.ShuffleInstances = make([]types.ShuffleInstance, 1)
+	dummyElection.ShuffleInstances[0] = types.ShuffleInstance{
+		ShuffledBallots:   make([]types.Ciphervote, 1),
+		ShuffleProofs:     nil,
+		ShufflerPublicKey: nil,
+	}
+
+	dummyElection.ShuffleInstances[0].ShuffledBallots[0] = types.Ciphervote{}
+
+	electionBuf, err = dummyElection.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
+
+	err = cmd.combineShares(snap, makeStep(t, ElectionArg, string(data)))
+	require.Contains(t, err.Error(), "failed to check admin: invalid admin signature")
+
+	decryptBallot.AdminSignature = signAdmin(t, CmdCombineShares, nil, 0)
+
+	data, err = decryptBallot.Serialize(ctx)
+	require.NoError(t, err)
+
+	// Nothing to decrypt
+	err = cmd.combineShares(snap, makeStep(t, ElectionArg, string(data)))
+	require.NoError(t, err)
+
+	dummyElection.ShuffleInstances[0].ShuffledBallots[0] = make([]types.EGPair, 1)
+	dummyElection.ShuffleInstances[0].ShuffledBallots[0][0] = types.EGPair{
+		K: suite.Point(),
+		C: suite.Point(),
+	}
+
+	electionBuf, err = dummyElection.Serialize(ctx)
+	require.NoError(t, err)
 
-	election.BallotSize = 1
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
 
-	electionBuf, err = election.Serialize(ctx)
+	// Decrypt empty ballot
+	err = cmd.combineShares(snap, makeStep(t, ElectionArg, string(data)))
 	require.NoError(t, err)
 
-	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	res, err := snap.Get(dummyElectionIDBuff)
 	require.NoError(t, err)
 
-	data, err = shuffleBallots.Serialize(ctx)
+	message, err := electionFac.Deserialize(ctx, res)
 	require.NoError(t, err)
 
-	err = cmd.shuffleBallots(snap, makeStep(t, ElectionArg, string(data)))
-	require.EqualError(t, err, "randomVector has unexpected length : 0 != 1")
+	election, ok := message.(types.Election)
+	require.True(t, ok)
 
-	// random vector with right length, but different value :
-	lenRandomVector := election.ChunksPerBallot()
-	e := make([]kyber.Scalar, lenRandomVector)
-	for i := 0; i < lenRandomVector; i++ {
-		v := suite.Scalar().Pick(suite.RandomStream())
-		e[i] = v
+	require.Equal(t, types.Ballot{}, election.DecryptedBallots[0])
+	require.Equal(t, uint(1), election.Tally.NumBallots)
+	require.Equal(t, types.ResultAvailable, election.Status)
+	require.Equal(t, float64(types.ResultAvailable), testutil.ToFloat64(PromElectionStatus))
+}
+
+func TestCommand_CancelElection(t *testing.T) {
+	cancelElection := types.CancelElection{
+		ElectionID: fakeElectionID,
+		UserID:     "dummyUserId",
 	}
 
-	err = shuffleBallots.RandomVector.LoadFromScalars(e)
+	data, err := cancelElection.Serialize(ctx)
 	require.NoError(t, err)
 
-	data, err = shuffleBallots.Serialize(ctx)
+	dummyElection, contract := initElectionAndContract()
+	dummyElection.ElectionID = fakeElectionID
+
+	electionBuf, err := dummyElection.Serialize(ctx)
 	require.NoError(t, err)
 
-	err = cmd.shuffleBallots(snap, makeStep(t, ElectionArg, string(data)))
-	require.EqualError(t, err, "random vector from shuffle transaction is "+
-		"different than expected random vector")
+	cmd := evotingCommand{
+		Contract: &contract,
+	}
 
-	// generate correct random vector:
-	h = sha256.New()
+	err = cmd.cancelElection(fake.NewSnapshot(), makeStep(t))
+	require.EqualError(t, err, getTransactionErr)
 
-	err = shuffleBallots.Fingerprint(h)
-	require.NoError(t, err)
+	err = cmd.cancelElection(fake.NewSnapshot(), makeStep(t, ElectionArg, "dummy"))
+	require.EqualError(t, err, unmarshalTransactionErr)
 
-	hash = h.Sum(nil)
+	err = cmd.cancelElection(fake.NewBadSnapshot(), makeStep(t, ElectionArg, string(data)))
+	require.Contains(t, err.Error(), "failed to get key")
+
+	snap := fake.NewSnapshot()
 
-	semiRandomStream, err := NewSemiRandomStream(hash)
+	err = snap.Set(dummyElectionIDBuff, invalidElection)
 	require.NoError(t, err)
 
-	e = make([]kyber.Scalar, lenRandomVector)
-	for i := 0; i < lenRandomVector; i++ {
-		v := suite.Scalar().Pick(semiRandomStream)
-		e[i] = v
-	}
+	err = cmd.cancelElection(snap, makeStep(t, ElectionArg, string(data)))
+	require.Contains(t, err.Error(), deserializeErr)
 
-	err = shuffleBallots.RandomVector.LoadFromScalars(e)
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
 	require.NoError(t, err)
 
-	// > With no casted ballot the shuffling can't happen
-
-	election.Pubkey = pubKey
-	shuffleBallots.Round = 0
-	election.ShuffleInstances = make([]types.ShuffleInstance, 0)
-	election.Suffragia.Ciphervotes = make([]types.Ciphervote, 0)
+	cancelElection.UserID = hex.EncodeToString([]byte("dummyAdminID"))
 
-	data, err = shuffleBallots.Serialize(ctx)
+	data, err = cancelElection.Serialize(ctx)
 	require.NoError(t, err)
 
-	electionBuf, err = election.Serialize(ctx)
-	require.NoError(t, err)
+	err = cmd.cancelElection(snap, makeStep(t, ElectionArg, string(data)))
+	require.Contains(t, err.Error(), "failed to check admin: invalid admin signature")
 
-	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	cancelElection.AdminSignature = signAdmin(t, CmdDeleteElection, nil, 0)
+
+	data, err = cancelElection.Serialize(ctx)
 	require.NoError(t, err)
 
-	err = cmd.shuffleBallots(snap, makeStep(t, ElectionArg, string(data)))
-	require.EqualError(t, err, "not enough votes: 0 < 2")
+	// a signature for another command must be rejected
+	err = cmd.cancelElection(snap, makeStep(t, ElectionArg, string(data)))
+	require.Contains(t, err.Error(), "failed to check admin: invalid admin signature")
 
-	// > With only one shuffled ballot the shuffling can't happen
+	cancelElection.AdminSignature = signAdmin(t, CmdCancelElection, nil, 0)
 
-	election.Suffragia.CastVote("user1", types.Ciphervote{
-		types.EGPair{K: suite.Point(), C: suite.Point()},
-	})
+	data, err = cancelElection.Serialize(ctx)
+	require.NoError(t, err)
 
-	data, err = shuffleBallots.Serialize(ctx)
+	err = cmd.cancelElection(snap, makeStep(t, ElectionArg, string(data)))
 	require.NoError(t, err)
 
-	electionBuf, err = election.Serialize(ctx)
+	res, err := snap.Get(dummyElectionIDBuff)
 	require.NoError(t, err)
 
-	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	message, err := electionFac.Deserialize(ctx, res)
 	require.NoError(t, err)
 
-	err = cmd.shuffleBallots(snap, makeStep(t, ElectionArg, string(data)))
-	require.EqualError(t, err, "not enough votes: 1 < 2")
+	election, ok := message.(types.Election)
+	require.True(t, ok)
+
+	require.Equal(t, types.Canceled, election.Status)
+	require.Equal(t, float64(types.Canceled), testutil.ToFloat64(PromElectionStatus))
 }
 
-func TestCommand_RegisterPubShares(t *testing.T) {
-	registerPubShares := types.RegisterPubShares{
+func TestCommand_RegisterVoters(t *testing.T) {
+	voter1 := types.HashUserID(fakeElectionID, "user1")
+	voter2 := types.HashUserID(fakeElectionID, "user2")
+
+	registerVoters := types.RegisterVoters{
 		ElectionID: fakeElectionID,
-		Index:      0,
-		Pubshares:  make([][]types.Pubshare, 0),
-		Signature:  []byte{},
-		PublicKey:  []byte{},
+		Voters:     []string{voter1, voter2},
 	}
 
-	data, err := registerPubShares.Serialize(ctx)
+	registerVoters.AdminSignature = signAdmin(t, CmdRegisterVoters,
+		registerVoters.AdminPayload(), 0)
+
+	data, err := registerVoters.Serialize(ctx)
 	require.NoError(t, err)
 
-	election, contract := initElectionAndContract()
-	election.ElectionID = fakeElectionID
+	dummyElection, contract := initElectionAndContract()
 
-	electionBuf, err := election.Serialize(ctx)
+	electionBuf, err := dummyElection.Serialize(ctx)
 	require.NoError(t, err)
 
 	cmd := evotingCommand{
 		Contract: &contract,
 	}
 
-	err = cmd.registerPubshares(fake.NewSnapshot(), makeStep(t))
+	err = cmd.registerVoters(fake.NewSnapshot(), makeStep(t))
 	require.EqualError(t, err, getTransactionErr)
 
-	err = cmd.registerPubshares(fake.NewSnapshot(), makeStep(t, ElectionArg, "dummy"))
+	err = cmd.registerVoters(fake.NewSnapshot(), makeStep(t, ElectionArg, "dummy"))
 	require.EqualError(t, err, unmarshalTransactionErr)
 
-	err = cmd.registerPubshares(fake.NewBadSnapshot(), makeStep(t, ElectionArg, string(data)))
+	err = cmd.registerVoters(fake.NewBadSnapshot(), makeStep(t, ElectionArg, string(data)))
 	require.Contains(t, err.Error(), "failed to get key")
 
 	snap := fake.NewSnapshot()
 
-	err = snap.Set(dummyElectionIDBuff, invalidElection)
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
 	require.NoError(t, err)
 
-	err = cmd.registerPubshares(snap, makeStep(t, ElectionArg, string(data)))
-	require.Contains(t, err.Error(), deserializeErr)
+	unsigned := registerVoters
+	unsigned.AdminSignature = nil
 
-	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	badData, err := unsigned.Serialize(ctx)
 	require.NoError(t, err)
 
-	err = cmd.registerPubshares(snap, makeStep(t, ElectionArg, string(data)))
-	require.EqualError(t, err, "the ballots have not been shuffled")
+	err = cmd.registerVoters(snap, makeStep(t, ElectionArg, string(badData)))
+	require.EqualError(t, err, "failed to check admin: invalid admin signature")
 
-	// Requirements:
-	election.Status = types.ShuffledBallots
-	election.PubsharesUnits = types.PubsharesUnits{
-		Pubshares: make([]types.PubsharesUnit, 0),
-		PubKeys:   make([][]byte, 0),
-		Indexes:   make([]int, 0),
-	}
-	election.ShuffleInstances = make([]types.ShuffleInstance, 1)
-	election.ShuffleInstances[0] = types.ShuffleInstance{
-		ShuffledBallots: make([]types.Ciphervote, 1),
-	}
-	election.ShuffleInstances[0].ShuffledBallots[0] = types.Ciphervote{types.EGPair{
-		K: suite.Point(),
-		C: suite.Point(),
-	}}
+	wrongSigned := registerVoters
+	wrongSigned.AdminSignature = signAdminWith(t, suite.Scalar().Pick(random.New()),
+		CmdRegisterVoters, registerVoters.AdminPayload(), 0)
 
-	electionBuf, err = election.Serialize(ctx)
+	badData, err = wrongSigned.Serialize(ctx)
 	require.NoError(t, err)
 
-	err = snap.Set(dummyElectionIDBuff, electionBuf)
-	require.NoError(t, err)
+	err = cmd.registerVoters(snap, makeStep(t, ElectionArg, string(badData)))
+	require.EqualError(t, err, "failed to check admin: invalid admin signature")
 
-	err = cmd.registerPubshares(snap, makeStep(t, ElectionArg, string(data)))
-	require.EqualError(t, err, "could not verify identity of node : public key not associated to a member of the roster: ")
+	// a signature for another command is rejected
+	wrongSigned.AdminSignature = signAdmin(t, CmdRemoveVoters, registerVoters.AdminPayload(), 0)
 
-	registerPubShares.PublicKey, err = fakeCommonSigner.GetPublicKey().MarshalBinary()
+	badData, err = wrongSigned.Serialize(ctx)
 	require.NoError(t, err)
 
-	data, err = registerPubShares.Serialize(ctx)
-	require.NoError(t, err)
+	err = cmd.registerVoters(snap, makeStep(t, ElectionArg, string(badData)))
+	require.EqualError(t, err, "failed to check admin: invalid admin signature")
 
-	err = cmd.registerPubshares(snap, makeStep(t, ElectionArg, string(data)))
-	require.EqualError(t, err, "could node deserialize pubShare signature:"+
-		" couldn't decode signature: couldn't deserialize data: unexpected end of JSON input")
+	election := readElection(t, snap)
+	require.False(t, election.Electorate.Restricted)
 
-	signature, err := fakeCommonSigner.Sign([]byte("fake shares"))
+	err = cmd.registerVoters(snap, makeStep(t, ElectionArg, string(data)))
 	require.NoError(t, err)
 
-	wrongSignature, err := signature.Serialize(ctx)
-	require.NoError(t, err)
+	election = readElection(t, snap)
+	require.True(t, election.Electorate.Restricted)
+	require.Equal(t, 2, election.Electorate.Len())
+	require.True(t, isVoter(t, snap, election, voter1))
+	require.True(t, isVoter(t, snap, election, voter2))
 
-	registerPubShares.Signature = wrongSignature
+	// the signature can't be replayed
+	err = cmd.registerVoters(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "failed to check admin: invalid admin signature")
 
-	data, err = registerPubShares.Serialize(ctx)
+	removeVoters := types.RemoveVoters{
+		ElectionID: fakeElectionID,
+		Voters:     []string{voter1},
+	}
+
+	data, err = removeVoters.Serialize(ctx)
 	require.NoError(t, err)
 
-	err = cmd.registerPubshares(snap, makeStep(t, ElectionArg, string(data)))
-	require.EqualError(t, err, "signature does not match the PubsharesUnit:"+
-		" bls verify failed: bls: invalid signature ")
+	err = cmd.removeVoters(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "failed to check admin: invalid admin signature")
 
-	h := sha256.New()
+	removeVoters.AdminSignature = signAdmin(t, CmdRemoveVoters, removeVoters.AdminPayload(), 1)
 
-	err = registerPubShares.Fingerprint(h)
+	data, err = removeVoters.Serialize(ctx)
 	require.NoError(t, err)
 
-	hash := h.Sum(nil)
-
-	signature, err = fakeCommonSigner.Sign(hash)
-	require.NoError(t, err)
-	correctSignature, err := signature.Serialize(ctx)
+	err = cmd.removeVoters(snap, makeStep(t, ElectionArg, string(data)))
 	require.NoError(t, err)
 
-	registerPubShares.Signature = correctSignature
+	election = readElection(t, snap)
+	require.Equal(t, 1, election.Electorate.Len())
+	require.False(t, isVoter(t, snap, election, voter1))
+	require.True(t, isVoter(t, snap, election, voter2))
 
-	data, err = registerPubShares.Serialize(ctx)
+	replaceVoters := types.ReplaceVoters{
+		ElectionID: fakeElectionID,
+		Voters:     []string{"bad"},
+	}
+
+	data, err = replaceVoters.Serialize(ctx)
 	require.NoError(t, err)
 
-	err = cmd.registerPubshares(snap, makeStep(t, ElectionArg, string(data)))
-	require.EqualError(t, err, "unexpected size of pubshares submission: 0 != 1")
+	err = cmd.replaceVoters(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "failed to check admin: invalid admin signature")
 
-	registerPubShares.Pubshares = make([][]types.Pubshare, 1)
+	replaceVoters.AdminSignature = signAdmin(t, CmdReplaceVoters, replaceVoters.AdminPayload(), 2)
 
-	data, err = registerPubShares.Serialize(ctx)
+	data, err = replaceVoters.Serialize(ctx)
 	require.NoError(t, err)
 
-	err = cmd.registerPubshares(snap, makeStep(t, ElectionArg, string(data)))
-	require.EqualError(t, err, "unexpected size of pubshares submission: 0 != 1")
-
-	registerPubShares.Pubshares[0] = make([]types.Pubshare, 1)
-	registerPubShares.Pubshares[0][0] = suite.Point()
+	err = cmd.replaceVoters(snap, makeStep(t, ElectionArg, string(data)))
+	require.Contains(t, err.Error(), "failed to update electorate: failed to add voters: invalid voter")
 
-	// update signature:
+	// the rejected transaction didn't consume the nonce
+	replaceVoters.Voters = []string{voter1}
+	replaceVoters.AdminSignature = signAdmin(t, CmdReplaceVoters, replaceVoters.AdminPayload(), 2)
 
-	h = sha256.New()
+	data, err = replaceVoters.Serialize(ctx)
+	require.NoError(t, err)
 
-	err = registerPubShares.Fingerprint(h)
+	err = cmd.replaceVoters(snap, makeStep(t, ElectionArg, string(data)))
 	require.NoError(t, err)
 
-	hash = h.Sum(nil)
+	// the previous voters are discarded
+	election = readElection(t, snap)
+	require.Equal(t, 1, election.Electorate.Len())
+	require.True(t, isVoter(t, snap, election, voter1))
+	require.False(t, isVoter(t, snap, election, voter2))
 
-	signature, err = fakeCommonSigner.Sign(hash)
+	dummyElection.Status = types.Closed
+	dummyElection.AdminNonce = 2
+
+	electionBuf, err = dummyElection.Serialize(ctx)
 	require.NoError(t, err)
-	correctSignature, err = signature.Serialize(ctx)
+
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
 	require.NoError(t, err)
 
-	registerPubShares.Signature = correctSignature
+	err = cmd.replaceVoters(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, fmt.Sprintf("the electorate can't be updated, "+
+		"current status: %d", types.Closed))
+}
 
-	data, err = registerPubShares.Serialize(ctx)
-	require.NoError(t, err)
+func TestCommand_ProposeApproveAction(t *testing.T) {
+	initMetrics()
 
-	err = cmd.registerPubshares(snap, makeStep(t, ElectionArg, string(data)))
-	require.NoError(t, err)
-	require.Equal(t, float64(1), testutil.ToFloat64(PromElectionPubShares))
+	secret2 := suite.Scalar().Pick(random.New())
+	admin2 := encodeAdminID(suite.Point().Mul(secret2, nil))
 
-	// With the public key already used:
-	election.PubsharesUnits.PubKeys = append(election.PubsharesUnits.PubKeys,
-		registerPubShares.PublicKey)
+	dummyElection, contract := initElectionAndContract()
+	dummyElection.Admins = types.AdminSet{
+		Keys:      []string{fakeAdminID, admin2},
+		Threshold: 2,
+	}
+	dummyElection.Status = types.Open
 
-	electionBuf, err = election.Serialize(ctx)
+	electionBuf, err := dummyElection.Serialize(ctx)
 	require.NoError(t, err)
 
+	snap := fake.NewSnapshot()
+
 	err = snap.Set(dummyElectionIDBuff, electionBuf)
 	require.NoError(t, err)
 
-	err = cmd.registerPubshares(snap, makeStep(t, ElectionArg, string(data)))
-	require.EqualError(t, err, fmt.Sprintf("'%x' already made a submission",
-		registerPubShares.PublicKey))
+	cmd := evotingCommand{
+		Contract: &contract,
+	}
 
-	// With the index already used:
-	election.PubsharesUnits.Indexes = append(election.PubsharesUnits.Indexes,
-		registerPubShares.Index)
-	election.PubsharesUnits.PubKeys = make([][]byte, 0)
+	// a single admin can't cancel the election
+	cancelElection := types.CancelElection{
+		ElectionID:     fakeElectionID,
+		AdminSignature: signAdmin(t, CmdCancelElection, nil, 0),
+	}
 
-	electionBuf, err = election.Serialize(ctx)
+	data, err := cancelElection.Serialize(ctx)
 	require.NoError(t, err)
 
-	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	err = cmd.cancelElection(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "failed to check admin: the command requires "+
+		"the approval of 2 admins")
+
+	proposeAction := types.ProposeAction{
+		ElectionID:     fakeElectionID,
+		Command:        string(CmdOpenElection),
+		AdminID:        fakeAdminID,
+		AdminSignature: signAdmin(t, CmdOpenElection, nil, 0),
+	}
+
+	data, err = proposeAction.Serialize(ctx)
 	require.NoError(t, err)
 
-	err = cmd.registerPubshares(snap, makeStep(t, ElectionArg, string(data)))
-	require.EqualError(t, err, "a submission has already been made for index 0")
+	err = cmd.proposeAction(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "command \"OPEN_ELECTION\" can't be proposed")
 
-	// All good:
-	election.PubsharesUnits.Indexes = make([]int, 0)
+	proposeAction.Command = string(CmdCancelElection)
 
-	electionBuf, err = election.Serialize(ctx)
+	data, err = proposeAction.Serialize(ctx)
 	require.NoError(t, err)
 
-	err = snap.Set(dummyElectionIDBuff, electionBuf)
-	require.NoError(t, err)
+	err = cmd.proposeAction(snap, makeStep(t, ElectionArg, string(data)))
+	require.Contains(t, err.Error(), "failed to check admin: invalid admin signature")
 
-	err = cmd.registerPubshares(snap, makeStep(t, ElectionArg, string(data)))
-	require.NoError(t, err)
-	require.Equal(t, float64(1), testutil.ToFloat64(PromElectionPubShares))
+	proposeAction.AdminSignature = signAdmin(t, CmdCancelElection, nil, 0)
 
-	res, err := snap.Get(dummyElectionIDBuff)
+	data, err = proposeAction.Serialize(ctx)
 	require.NoError(t, err)
 
-	message, err := electionFac.Deserialize(ctx, res)
-	require.NoError(t, err)
+	step := makeStep(t, ElectionArg, string(data))
 
-	resultElection, ok := message.(types.Election)
-	require.True(t, ok)
+	err = cmd.proposeAction(snap, step)
+	require.NoError(t, err)
 
-	require.Equal(t, types.PubSharesSubmitted, resultElection.Status)
+	election := readElection(t, snap)
+	require.Len(t, election.PendingActions, 1)
+	require.Equal(t, types.Open, election.Status)
+	require.Equal(t, uint64(1), election.AdminNonce)
 
-	require.Equal(t, resultElection.PubsharesUnits.PubKeys[0], registerPubShares.PublicKey)
-	require.Equal(t, resultElection.PubsharesUnits.Indexes[0], registerPubShares.Index)
-}
+	action := election.PendingActions[0]
+	require.Equal(t, hex.EncodeToString(step.Current.GetID()), action.ID)
+	require.Equal(t, hex.EncodeToString(types.ActionHash(string(CmdCancelElection), nil)),
+		action.Hash)
 
-func TestCommand_DecryptBallots(t *testing.T) {
-	decryptBallot := types.CombineShares{
-		ElectionID: fakeElectionID,
-		UserID:     hex.EncodeToString([]byte("dummyUserId")),
+	// a second action is pending until the first one is executed
+	proposeClose := types.ProposeAction{
+		ElectionID:     fakeElectionID,
+		Command:        string(CmdCloseElection),
+		AdminID:        admin2,
+		AdminSignature: signAdminWith(t, secret2, CmdCloseElection, nil, 1),
 	}
 
-	data, err := decryptBallot.Serialize(ctx)
+	data, err = proposeClose.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = cmd.proposeAction(snap, makeStep(t, ElectionArg, string(data)))
 	require.NoError(t, err)
 
-	dummyElection, contract := initElectionAndContract()
+	election = readElection(t, snap)
+	require.Len(t, election.PendingActions, 2)
 
-	electionBuf, err := dummyElection.Serialize(ctx)
+	approveAction := types.ApproveAction{
+		ElectionID:     fakeElectionID,
+		ActionID:       action.ID,
+		AdminID:        fakeAdminID,
+		AdminSignature: signAdmin(t, CmdApproveAction, action.ApprovalPayload(), 2),
+	}
+
+	data, err = approveAction.Serialize(ctx)
 	require.NoError(t, err)
 
-	cmd := evotingCommand{
-		Contract: &contract,
-	}
+	err = cmd.approveAction(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, fmt.Sprintf("admin %q already approved the action", fakeAdminID))
 
-	err = cmd.combineShares(fake.NewSnapshot(), makeStep(t))
-	require.EqualError(t, err, getTransactionErr)
+	approveAction.ActionID = "unknown"
 
-	err = cmd.combineShares(fake.NewSnapshot(), makeStep(t, ElectionArg, "dummy"))
-	require.EqualError(t, err, unmarshalTransactionErr)
+	data, err = approveAction.Serialize(ctx)
+	require.NoError(t, err)
 
-	err = cmd.combineShares(fake.NewBadSnapshot(), makeStep(t, ElectionArg, string(data)))
-	require.Contains(t, err.Error(), "failed to get key")
+	err = cmd.approveAction(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, "action \"unknown\" not found")
 
-	snap := fake.NewSnapshot()
+	// the approval must sign the action ID and its hash
+	approveAction.ActionID = action.ID
+	approveAction.AdminID = admin2
+	approveAction.AdminSignature = signAdminWith(t, secret2, CmdCancelElection, nil, 2)
 
-	err = snap.Set(dummyElectionIDBuff, invalidElection)
+	data, err = approveAction.Serialize(ctx)
 	require.NoError(t, err)
 
-	err = cmd.combineShares(snap, makeStep(t, ElectionArg, string(data)))
-	require.Contains(t, err.Error(), deserializeErr)
+	err = cmd.approveAction(snap, makeStep(t, ElectionArg, string(data)))
+	require.Contains(t, err.Error(), "failed to check admin: invalid admin signature")
 
-	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	approveAction.AdminSignature = signAdminWith(t, secret2, CmdApproveAction,
+		action.ApprovalPayload(), 2)
+
+	data, err = approveAction.Serialize(ctx)
 	require.NoError(t, err)
 
-	decryptBallot.UserID = hex.EncodeToString([]byte("dummyAdminID"))
+	err = cmd.approveAction(snap, makeStep(t, ElectionArg, string(data)))
+	require.NoError(t, err)
 
-	data, err = decryptBallot.Serialize(ctx)
+	// the pending closing is dropped as the election is canceled
+	election = readElection(t, snap)
+	require.Empty(t, election.PendingActions)
+	require.Equal(t, types.Canceled, election.Status)
+
+	// an obsolete action can't be proposed
+	proposeClose.AdminSignature = signAdminWith(t, secret2, CmdCloseElection, nil, 3)
+
+	data, err = proposeClose.Serialize(ctx)
 	require.NoError(t, err)
 
-	err = cmd.combineShares(snap, makeStep(t, ElectionArg, string(data)))
-	require.EqualError(t, err, fmt.Sprintf("the public shares have not"+
-		" been submitted, current status: %d", types.Initial))
+	err = cmd.proposeAction(snap, makeStep(t, ElectionArg, string(data)))
+	require.EqualError(t, err, fmt.Sprintf("command \"CLOSE_ELECTION\" can't be "+
+		"executed, current status: %d", types.Canceled))
+}
 
-	dummyElection.Status = types.PubSharesSubmitted
+func TestRegisterContract(t *testing.T) {
+	RegisterContract(native.NewExecution(), Contract{})
+}
 
-	// Avoid panic (will always be the case in practice):
-	dummyElection.ShuffleInstances = make([]types.ShuffleInstance, 1)
-	dummyElection.ShuffleInstances[0] = types.ShuffleInstance{
-		ShuffledBallots:   make([]types.Ciphervote, 1),
-		ShuffleProofs:     nil,
-		ShufflerPublicKey: nil,
-	}
+// -----------------------------------------------------------------------------
+// Utility functions
 
-	dummyElection.ShuffleInstances[0].ShuffledBallots[0] = types.Ciphervote{}
+// reportTime reports the time of the node of fakeCommonSigner, which is the
+// time of the chain for the rosters of the tests.
+func reportTime(t *testing.T, snap store.Snapshot, cmd evotingCommand, timestamp int64) {
+	tx := types.ReportTime{Timestamp: timestamp}
 
-	electionBuf, err = dummyElection.Serialize(ctx)
+	var err error
+
+	tx.PublicKey, err = fakeCommonSigner.GetPublicKey().MarshalBinary()
 	require.NoError(t, err)
 
-	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	h := sha256.New()
+
+	err = tx.Fingerprint(h)
 	require.NoError(t, err)
 
-	// Nothing to decrypt
-	err = cmd.combineShares(snap, makeStep(t, ElectionArg, string(data)))
+	signature, err := fakeCommonSigner.Sign(h.Sum(nil))
 	require.NoError(t, err)
 
-	dummyElection.ShuffleInstances[0].ShuffledBallots[0] = make([]types.EGPair, 1)
-	dummyElection.ShuffleInstances[0].ShuffledBallots[0][0] = types.EGPair{
-		K: suite.Point(),
-		C: suite.Point(),
-	}
+	tx.Signature, err = signature.Serialize(ctx)
+	require.NoError(t, err)
 
-	electionBuf, err = dummyElection.Serialize(ctx)
+	data, err := tx.Serialize(ctx)
 	require.NoError(t, err)
 
-	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	err = cmd.reportTime(snap, makeStep(t, ElectionArg, string(data)))
 	require.NoError(t, err)
+}
 
-	// Decrypt empty ballot
-	err = cmd.combineShares(snap, makeStep(t, ElectionArg, string(data)))
+func initMetrics() {
+	PromElectionStatus.Reset()
+	PromElectionBallots.Reset()
+	PromElectionShufflingInstances.Reset()
+	PromElectionPubShares.Reset()
+}
+
+func encodeAdminID(pubkey kyber.Point) string {
+	buf, err := pubkey.MarshalBinary()
+	if err != nil {
+		panic("failed to marshal admin public key: " + err.Error())
+	}
+
+	return hex.EncodeToString(buf)
+}
+
+func signAdmin(t *testing.T, cmd Command, payload []byte, nonce uint64) []byte {
+	return signAdminWith(t, fakeAdminSecret, cmd, payload, nonce)
+}
+
+func signAdminWith(t *testing.T, secret kyber.Scalar, cmd Command, payload []byte,
+	nonce uint64) []byte {
+
+	msg := types.AdminMessage(string(cmd), fakeElectionID, payload, nonce)
+
+	signature, err := schnorr.Sign(suite, secret, msg)
 	require.NoError(t, err)
 
-	res, err := snap.Get(dummyElectionIDBuff)
+	return signature
+}
+
+func readBallot(t *testing.T, snap store.Snapshot, userID string) types.Ciphervote {
+	res, err := snap.Get(types.BallotKey(dummyElectionIDBuff, userID))
 	require.NoError(t, err)
 
-	message, err := electionFac.Deserialize(ctx, res)
+	message, err := types.CiphervoteFactory{}.Deserialize(ctx, res)
 	require.NoError(t, err)
 
-	election, ok := message.(types.Election)
+	ciphervote, ok := message.(types.Ciphervote)
 	require.True(t, ok)
 
-	require.Equal(t, types.Ballot{}, election.DecryptedBallots[0])
-	require.Equal(t, types.ResultAvailable, election.Status)
-	require.Equal(t, float64(types.ResultAvailable), testutil.ToFloat64(PromElectionStatus))
+	return ciphervote
 }
 
-func TestCommand_CancelElection(t *testing.T) {
-	cancelElection := types.CancelElection{
-		ElectionID: fakeElectionID,
-		UserID:     "dummyUserId",
-	}
-
-	data, err := cancelElection.Serialize(ctx)
+// readCatalogEntry returns the index-th entry of the catalog.
+func readCatalogEntry(t *testing.T, snap store.Snapshot, index uint64) types.CatalogEntry {
+	res, err := snap.Get(types.CatalogKey(index))
 	require.NoError(t, err)
 
-	dummyElection, contract := initElectionAndContract()
-	dummyElection.ElectionID = fakeElectionID
-
-	electionBuf, err := dummyElection.Serialize(ctx)
+	entry, err := types.DecodeCatalogEntry(res)
 	require.NoError(t, err)
 
-	cmd := evotingCommand{
-		Contract: &contract,
-	}
-
-	err = cmd.cancelElection(fake.NewSnapshot(), makeStep(t))
-	require.EqualError(t, err, getTransactionErr)
+	return entry
+}
 
-	err = cmd.cancelElection(fake.NewSnapshot(), makeStep(t, ElectionArg, "dummy"))
-	require.EqualError(t, err, unmarshalTransactionErr)
+// addVoters registers the users in the electorate of the election the same way
+// as the REGISTER_VOTERS command.
+func addVoters(t *testing.T, snap store.Snapshot, election *types.Election,
+	userIDs ...string) {
 
-	err = cmd.cancelElection(fake.NewBadSnapshot(), makeStep(t, ElectionArg, string(data)))
-	require.Contains(t, err.Error(), "failed to get key")
+	for _, userID := range userIDs {
+		hash := types.HashUserID(election.ElectionID, userID)
 
-	snap := fake.NewSnapshot()
+		err := snap.Set(election.Electorate.VoterKey(dummyElectionIDBuff, hash), []byte{1})
+		require.NoError(t, err)
 
-	err = snap.Set(dummyElectionIDBuff, invalidElection)
-	require.NoError(t, err)
+		election.Electorate.Count++
+	}
 
-	err = cmd.cancelElection(snap, makeStep(t, ElectionArg, string(data)))
-	require.Contains(t, err.Error(), deserializeErr)
+	election.Electorate.Restricted = true
+}
 
-	err = snap.Set(dummyElectionIDBuff, electionBuf)
+// isVoter returns true if the hash is registered in the electorate of the
+// election.
+func isVoter(t *testing.T, snap store.Snapshot, election types.Election, hash string) bool {
+	res, err := snap.Get(election.Electorate.VoterKey(dummyElectionIDBuff, hash))
 	require.NoError(t, err)
 
-	cancelElection.UserID = hex.EncodeToString([]byte("dummyAdminID"))
+	return len(res) != 0
+}
 
-	data, err = cancelElection.Serialize(ctx)
-	require.NoError(t, err)
+// castBallot stores a ballot the same way as the CAST_VOTE command.
+func castBallot(t *testing.T, snap store.Snapshot, contract *Contract,
+	election *types.Election, userID string) {
 
-	err = cmd.cancelElection(snap, makeStep(t, ElectionArg, string(data)))
+	cmd := evotingCommand{
+		Contract: contract,
+	}
+
+	err := cmd.storeBallot(snap, election, dummyElectionIDBuff, userID, types.Ciphervote{})
 	require.NoError(t, err)
+}
 
+func readElection(t *testing.T, snap store.Snapshot) types.Election {
 	res, err := snap.Get(dummyElectionIDBuff)
 	require.NoError(t, err)
 
@@ -1096,32 +2794,42 @@ func TestCommand_CancelElection(t *testing.T) {
 	election, ok := message.(types.Election)
 	require.True(t, ok)
 
-	require.Equal(t, types.Canceled, election.Status)
-	require.Equal(t, float64(types.Canceled), testutil.ToFloat64(PromElectionStatus))
+	return election
 }
 
-func TestRegisterContract(t *testing.T) {
-	RegisterContract(native.NewExecution(), Contract{})
+// makeCastVote returns the vote of the user with a ballot of one chunk and its
+// proof.
+func makeCastVote(t *testing.T, userID string) types.CastVote {
+	pubkey := suite.Point().Pick(suite.RandomStream())
+
+	ballot, ks, err := types.EncryptBallot(pubkey, []byte("fakeVote"), 1)
+	require.NoError(t, err)
+
+	proof, err := types.ProveBallot(fakeElectionID, userID, ballot, ks)
+	require.NoError(t, err)
+
+	return types.CastVote{
+		ElectionID: fakeElectionID,
+		UserID:     userID,
+		Ballot:     ballot,
+		Proof:      proof,
+	}
 }
 
-// -----------------------------------------------------------------------------
-// Utility functions
+func mustShuffleRandomVector(t *testing.T, version types.RandomVectorVersion,
+	electionID string, round int, hash []byte) []kyber.Scalar {
 
-func initMetrics() {
-	PromElectionStatus.Reset()
-	PromElectionBallots.Reset()
-	PromElectionShufflingInstances.Reset()
-	PromElectionPubShares.Reset()
+	e, err := ShuffleRandomVector(version, electionID, round, hash, 1)
+	require.NoError(t, err)
+
+	return e
 }
 
 func initElectionAndContract() (types.Election, Contract) {
-	fakeDkg := fakeDKG{
-		actor: fakeDkgActor{},
-		err:   nil,
-	}
-
 	dummyElection := types.Election{
 		ElectionID:       fakeElectionID,
+		AdminID:          fakeAdminID,
+		Admins:           types.AdminSet{Keys: []string{fakeAdminID}, Threshold: 1},
 		Status:           0,
 		Pubkey:           nil,
 		Suffragia:        types.Suffragia{},
@@ -1129,6 +2837,8 @@ func initElectionAndContract() (types.Election, Contract) {
 		DecryptedBallots: nil,
 		ShuffleThreshold: 0,
 		Roster:           fake.Authority{},
+
+		RandomVectorVersion: types.CurrentRandomVectorVersion,
 	}
 
 	var evotingAccessKey = [32]byte{3}
@@ -1137,11 +2847,112 @@ func initElectionAndContract() (types.Election, Contract) {
 	service := fakeAccess{err: fake.GetError()}
 	rosterFac := fakeAuthorityFactory{}
 
-	contract := NewContract(evotingAccessKey[:], rosterKey[:], service, fakeDkg, rosterFac)
+	contract := NewContract(evotingAccessKey[:], rosterKey[:], service, rosterFac)
 
 	return dummyElection, contract
 }
 
+// makeDKGRoster returns a roster of n nodes that sign with BLS, along with
+// their signers.
+func makeDKGRoster(n int) (authority.Roster, []crypto.Signer) {
+	addrs := make([]mino.Address, n)
+	pubkeys := make([]crypto.PublicKey, n)
+	signers := make([]crypto.Signer, n)
+
+	for i := range signers {
+		signers[i] = bls.NewSigner()
+		addrs[i] = fake.NewAddress(i)
+		pubkeys[i] = signers[i].GetPublicKey()
+	}
+
+	return authority.New(addrs, pubkeys), signers
+}
+
+// initDKGElection returns an election run by the roster, which is also the
+// roster of the chain, and the command to execute on it.
+func initDKGElection(roster authority.Roster) (types.Election, evotingCommand) {
+	election, _ := initElectionAndContract()
+	election.Roster = roster
+	election.ShuffleThreshold = 2
+	election.DecryptionThreshold = 2
+
+	contract := NewContract([]byte{3}, []byte{}, fakeAccess{}, fake.NewRosterFac(roster))
+
+	return election, evotingCommand{Contract: &contract}
+}
+
+func setDKGElection(t *testing.T, snap store.Snapshot, election types.Election) {
+	electionBuf, err := election.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = snap.Set(dummyElectionIDBuff, electionBuf)
+	require.NoError(t, err)
+}
+
+// makeCommits returns the marshalled commits of a random DKG with the
+// threshold.
+func makeCommits(t *testing.T, threshold int) [][]byte {
+	priPoly := share.NewPriPoly(suite, threshold, nil, suite.RandomStream())
+	_, commits := priPoly.Commit(nil).Info()
+
+	res := make([][]byte, len(commits))
+
+	for i, commit := range commits {
+		buf, err := commit.MarshalBinary()
+		require.NoError(t, err)
+
+		res[i] = buf
+	}
+
+	return res
+}
+
+func addressTexts(t *testing.T, roster authority.Authority) [][]byte {
+	texts := [][]byte{}
+
+	addrIter := roster.AddressIterator()
+	for addrIter.HasNext() {
+		text, err := addrIter.GetNext().MarshalText()
+		require.NoError(t, err)
+
+		texts = append(texts, text)
+	}
+
+	return texts
+}
+
+// registerDKG executes the REGISTER_DKG command signed by the node.
+func registerDKG(t *testing.T, snap store.Snapshot, cmd evotingCommand,
+	signer crypto.Signer, commits, participants [][]byte) error {
+
+	tx := types.RegisterDKG{
+		ElectionID:   fakeElectionID,
+		Commits:      commits,
+		Participants: participants,
+	}
+
+	var err error
+
+	tx.PublicKey, err = signer.GetPublicKey().MarshalBinary()
+	require.NoError(t, err)
+
+	h := sha256.New()
+
+	err = tx.Fingerprint(h)
+	require.NoError(t, err)
+
+	signature, err := signer.Sign(h.Sum(nil))
+	require.NoError(t, err)
+
+	tx.Signature, err = signature.Serialize(ctx)
+	require.NoError(t, err)
+
+	data, err := tx.Serialize(ctx)
+	require.NoError(t, err)
+
+	return cmd.registerDKG(snap, makeStep(t, ElectionArg, string(data)))
+}
+
 func initGoodShuffleBallot(t *testing.T, k int) (types.Election, types.ShuffleBallots, Contract) {
 	election, shuffleBallots, contract := initBadShuffleBallot(3)
 	election.Status = types.Closed
@@ -1187,15 +2998,10 @@ func initGoodShuffleBallot(t *testing.T, k int) (types.Election, types.ShuffleBa
 
 	shuffleBallots.Signature = wrongSignature
 
-	semiRandomStream, err := NewSemiRandomStream(hash)
+	e, err := ShuffleRandomVector(election.RandomVectorVersion, election.ElectionID,
+		shuffleBallots.Round, hash, election.ChunksPerBallot())
 	require.NoError(t, err)
 
-	lenRandomVector := election.ChunksPerBallot()
-	e := make([]kyber.Scalar, lenRandomVector)
-	for i := 0; i < lenRandomVector; i++ {
-		v := suite.Scalar().Pick(semiRandomStream)
-		e[i] = v
-	}
 	shuffleBallots.RandomVector.LoadFromScalars(e)
 
 	return election, shuffleBallots, contract
@@ -1212,6 +3018,8 @@ func initBadShuffleBallot(sizeOfElection int) (types.Election, types.ShuffleBall
 		ShuffledBallots: shuffledBallots,
 		Proof:           nil,
 		PublicKey:       FakePubKeyMarshalled,
+
+		RandomVectorVersion: types.CurrentRandomVectorVersion,
 	}
 
 	election, contract := initElectionAndContract()
@@ -1260,59 +3068,6 @@ func makeTx(t *testing.T, args ...string) txn.Transaction {
 	return tx
 }
 
-type fakeDKG struct {
-	actor fakeDkgActor
-	err   error
-}
-
-func (f fakeDKG) Listen(electionID []byte, txmanager txn.Manager) (dkg.Actor, error) {
-	return f.actor, f.err
-}
-
-func (f fakeDKG) GetActor(electionID []byte) (dkg.Actor, bool) {
-	return f.actor, false
-}
-
-func (f fakeDKG) SetService(service ordering.Service) {
-}
-
-type fakeDkgActor struct {
-	publicKey kyber.Point
-	err       error
-}
-
-func (f fakeDkgActor) Setup() (pubKey kyber.Point, err error) {
-	return nil, f.err
-}
-
-func (f fakeDkgActor) GetPublicKey() (kyber.Point, error) {
-	return f.publicKey, f.err
-}
-
-func (f fakeDkgActor) Encrypt(message []byte) (K, C kyber.Point, remainder []byte, err error) {
-	return nil, nil, nil, f.err
-}
-
-func (f fakeDkgActor) Decrypt(K, C kyber.Point) ([]byte, error) {
-	return nil, f.err
-}
-
-func (f fakeDkgActor) Reshare() error {
-	return f.err
-}
-
-func (f fakeDkgActor) MarshalJSON() ([]byte, error) {
-	return nil, f.err
-}
-
-func (f fakeDkgActor) ComputePubshares() error {
-	return f.err
-}
-
-func (f fakeDkgActor) Status() dkg.Status {
-	return dkg.Status{}
-}
-
 type fakeAccess struct {
 	access.Service
 
@@ -1355,6 +3110,10 @@ func (c fakeCmd) castVote(snap store.Snapshot, step execution.Step) error {
 	return c.err
 }
 
+func (c fakeCmd) castVotes(snap store.Snapshot, step execution.Step) error {
+	return c.err
+}
+
 func (c fakeCmd) closeElection(snap store.Snapshot, step execution.Step) error {
 	return c.err
 }
@@ -1379,6 +3138,42 @@ func (c fakeCmd) registerPubshares(snap store.Snapshot, step execution.Step) err
 	return c.err
 }
 
+func (c fakeCmd) registerVoters(snap store.Snapshot, step execution.Step) error {
+	return c.err
+}
+
+func (c fakeCmd) removeVoters(snap store.Snapshot, step execution.Step) error {
+	return c.err
+}
+
+func (c fakeCmd) replaceVoters(snap store.Snapshot, step execution.Step) error {
+	return c.err
+}
+
+func (c fakeCmd) proposeAction(snap store.Snapshot, step execution.Step) error {
+	return c.err
+}
+
+func (c fakeCmd) approveAction(snap store.Snapshot, step execution.Step) error {
+	return c.err
+}
+
+func (c fakeCmd) updateConfiguration(snap store.Snapshot, step execution.Step) error {
+	return c.err
+}
+
+func (c fakeCmd) updateRoster(snap store.Snapshot, step execution.Step) error {
+	return c.err
+}
+
+func (c fakeCmd) reportTime(snap store.Snapshot, step execution.Step) error {
+	return c.err
+}
+
+func (c fakeCmd) registerDKG(snap store.Snapshot, step execution.Step) error {
+	return c.err
+}
+
 type fakeAuthorityFactory struct {
 	serde.Factory
 }
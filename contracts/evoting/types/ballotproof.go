@@ -0,0 +1,187 @@
+package types
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/binary"
+	"io"
+
+	"go.dedis.ch/kyber/v3"
+	"golang.org/x/xerrors"
+)
+
+// ballotProofDomain separates the challenges of the ballot proofs from the
+// other hashes of the protocol.
+const ballotProofDomain = "d-voting/ballot-proof/v1"
+
+// BallotProof is a non-interactive Schnorr proof of knowledge of the random
+// scalar k of each ElGamal pair (K = k*G) of a ciphervote. The challenge is
+// bound to the election and the voter, so that a ciphervote can't be copied,
+// or re-randomized, and cast by another voter who doesn't know the k's.
+//
+// It contains, for each ElGamal pair i, the commitment R_i = r_i*G followed by
+// the response s_i = r_i + c*k_i, where c is the challenge shared by all the
+// pairs.
+type BallotProof []byte
+
+// EncryptBallot ElGamal-encrypts the ballot with the public key of the
+// election, in chunks of at most 29 bytes. It returns the random scalar of each
+// chunk, which is needed to prove the ciphervote with ProveBallot.
+func EncryptBallot(pubkey kyber.Point, ballot []byte, chunks int) (Ciphervote,
+	[]kyber.Scalar, error) {
+
+	max := suite.Point().EmbedLen()
+
+	if len(ballot) > max*chunks {
+		return nil, nil, xerrors.Errorf("the ballot is too long: %d > %d",
+			len(ballot), max*chunks)
+	}
+
+	ciphervote := make(Ciphervote, chunks)
+	ks := make([]kyber.Scalar, chunks)
+
+	for i := range ciphervote {
+		chunk := ballot[i*max:]
+		if len(chunk) > max {
+			chunk = chunk[:max]
+		}
+
+		M := suite.Point().Embed(chunk, suite.RandomStream())
+
+		ks[i] = suite.Scalar().Pick(suite.RandomStream())
+
+		S := suite.Point().Mul(ks[i], pubkey)
+
+		ciphervote[i] = EGPair{
+			K: suite.Point().Mul(ks[i], nil),
+			C: suite.Point().Add(S, M),
+		}
+	}
+
+	return ciphervote, ks, nil
+}
+
+// ProveBallot creates the proof of the ciphervote cast by the voter. ks are the
+// random scalars used to encrypt each ElGamal pair.
+func ProveBallot(electionID, userID string, ciphervote Ciphervote,
+	ks []kyber.Scalar) (BallotProof, error) {
+
+	if len(ks) != len(ciphervote) {
+		return nil, xerrors.Errorf("wrong number of scalars: %d != %d",
+			len(ks), len(ciphervote))
+	}
+
+	rs := make([]kyber.Scalar, len(ciphervote))
+	commitments := make([]kyber.Point, len(ciphervote))
+
+	for i := range ciphervote {
+		rs[i] = suite.Scalar().Pick(suite.RandomStream())
+		commitments[i] = suite.Point().Mul(rs[i], nil)
+	}
+
+	c, err := ballotChallenge(electionID, userID, ciphervote, commitments)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to compute challenge: %v", err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	for i := range ciphervote {
+		s := suite.Scalar().Add(rs[i], suite.Scalar().Mul(c, ks[i]))
+
+		_, err = commitments[i].MarshalTo(buf)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to marshal commitment: %v", err)
+		}
+
+		_, err = s.MarshalTo(buf)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to marshal response: %v", err)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Verify checks that the proof was created for the ciphervote cast by the
+// voter in the election.
+func (p BallotProof) Verify(electionID, userID string, ciphervote Ciphervote) error {
+	size := suite.PointLen() + suite.ScalarLen()
+
+	if len(p) != size*len(ciphervote) {
+		return xerrors.Errorf("the proof has unexpected length: %d != %d",
+			len(p), size*len(ciphervote))
+	}
+
+	reader := bytes.NewReader(p)
+
+	commitments := make([]kyber.Point, len(ciphervote))
+	responses := make([]kyber.Scalar, len(ciphervote))
+
+	for i := range ciphervote {
+		commitments[i] = suite.Point()
+
+		_, err := commitments[i].UnmarshalFrom(reader)
+		if err != nil {
+			return xerrors.Errorf("failed to unmarshal commitment: %v", err)
+		}
+
+		responses[i] = suite.Scalar()
+
+		_, err = responses[i].UnmarshalFrom(reader)
+		if err != nil {
+			return xerrors.Errorf("failed to unmarshal response: %v", err)
+		}
+	}
+
+	c, err := ballotChallenge(electionID, userID, ciphervote, commitments)
+	if err != nil {
+		return xerrors.Errorf("failed to compute challenge: %v", err)
+	}
+
+	for i, egpair := range ciphervote {
+		// s*G == R + c*K
+		left := suite.Point().Mul(responses[i], nil)
+		right := suite.Point().Add(commitments[i], suite.Point().Mul(c, egpair.K))
+
+		if !left.Equal(right) {
+			return xerrors.Errorf("invalid proof for chunk %d", i)
+		}
+	}
+
+	return nil
+}
+
+// ballotChallenge hashes the statement and the commitments of a ballot proof
+// to a scalar.
+func ballotChallenge(electionID, userID string, ciphervote Ciphervote,
+	commitments []kyber.Point) (kyber.Scalar, error) {
+
+	h := sha512.New()
+
+	h.Write([]byte(ballotProofDomain))
+
+	writeWithLength(h, []byte(electionID))
+	writeWithLength(h, []byte(userID))
+
+	for i, egpair := range ciphervote {
+		for _, point := range []kyber.Point{egpair.K, egpair.C, commitments[i]} {
+			_, err := point.MarshalTo(h)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to marshal point: %v", err)
+			}
+		}
+	}
+
+	return suite.Scalar().SetBytes(h.Sum(nil)), nil
+}
+
+// writeWithLength writes the length of the data before the data, so that the
+// concatenation of variable-length values is unambiguous.
+func writeWithLength(w io.Writer, data []byte) {
+	length := make([]byte, 4)
+	binary.BigEndian.PutUint32(length, uint32(len(data)))
+
+	w.Write(length)
+	w.Write(data)
+}
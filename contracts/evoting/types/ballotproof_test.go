@@ -0,0 +1,69 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+)
+
+func TestBallotProof_ProveVerify(t *testing.T) {
+	secret := suite.Scalar().Pick(suite.RandomStream())
+	pubkey := suite.Point().Mul(secret, nil)
+
+	ballot := []byte("select:UTE=:1,0\ntext:c3Vi:aGVsbG8gd29ybGQ=\n\n")
+
+	ciphervote, ks, err := EncryptBallot(pubkey, ballot, 2)
+	require.NoError(t, err)
+	require.Len(t, ciphervote, 2)
+
+	// the chunks decrypt to the ballot
+	var decrypted []byte
+
+	for _, egpair := range ciphervote {
+		M := suite.Point().Sub(egpair.C, suite.Point().Mul(secret, egpair.K))
+
+		chunk, err := M.Data()
+		require.NoError(t, err)
+
+		decrypted = append(decrypted, chunk...)
+	}
+
+	require.Equal(t, ballot, decrypted)
+
+	proof, err := ProveBallot("abcd", "user1", ciphervote, ks)
+	require.NoError(t, err)
+
+	err = proof.Verify("abcd", "user1", ciphervote)
+	require.NoError(t, err)
+
+	// the proof is bound to the voter and the election
+	err = proof.Verify("abcd", "user2", ciphervote)
+	require.EqualError(t, err, "invalid proof for chunk 0")
+
+	err = proof.Verify("abce", "user1", ciphervote)
+	require.EqualError(t, err, "invalid proof for chunk 0")
+
+	// a re-randomized ciphervote can't be proved without the original k's
+	r := suite.Scalar().Pick(suite.RandomStream())
+
+	rerandomized := Ciphervote{
+		{
+			K: suite.Point().Add(ciphervote[0].K, suite.Point().Mul(r, nil)),
+			C: suite.Point().Add(ciphervote[0].C, suite.Point().Mul(r, pubkey)),
+		},
+		ciphervote[1],
+	}
+
+	err = proof.Verify("abcd", "user1", rerandomized)
+	require.Error(t, err)
+
+	err = proof.Verify("abcd", "user1", ciphervote[:1])
+	require.EqualError(t, err, "the proof has unexpected length: 128 != 64")
+
+	_, err = ProveBallot("abcd", "user1", ciphervote, []kyber.Scalar{ks[0]})
+	require.EqualError(t, err, "wrong number of scalars: 1 != 2")
+
+	_, _, err = EncryptBallot(pubkey, ballot, 1)
+	require.EqualError(t, err, "the ballot is too long: 44 > 29")
+}
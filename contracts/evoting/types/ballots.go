@@ -32,6 +32,20 @@ type Ballot struct {
 	// used to map a question ID to its index in the TextResult slice
 	TextResultIDs []ID
 	TextResult    [][]string
+
+	// ScoreResult contains the result of each Score question. The result of a
+	// score question is the score given to each choice. A choice that hasn't
+	// been scored will have a value < 0. The ID slice is used to map a question
+	// ID to its index in the ScoreResult slice
+	ScoreResultIDs []ID
+	ScoreResult    [][]int
+
+	// CumulativeResult contains the result of each Cumulative question. The
+	// result of a cumulative question is the number of points given to each
+	// choice. The ID slice is used to map a question ID to its index in the
+	// CumulativeResult slice
+	CumulativeResultIDs []ID
+	CumulativeResult    [][]uint
 }
 
 // Unmarshal decodes the given string according to the format described in
@@ -54,6 +68,16 @@ func (b *Ballot) Unmarshal(marshalledBallot string, election Election) error {
 	b.TextResultIDs = make([]ID, 0)
 	b.TextResult = make([][]string, 0)
 
+	b.ScoreResultIDs = make([]ID, 0)
+	b.ScoreResult = make([][]int, 0)
+
+	b.CumulativeResultIDs = make([]ID, 0)
+	b.CumulativeResult = make([][]uint, 0)
+
+	// number of selected answers per question, the MinN of conditional
+	// questions is checked once all the answers are known
+	answered := make(map[ID]uint)
+
 	//TODO: Loads of code duplication, can be re-thought
 	for _, line := range lines {
 		if line == "" {
@@ -114,10 +138,12 @@ func (b *Ballot) Unmarshal(marshalledBallot string, election Election) error {
 				b.SelectResult[index] = append(b.SelectResult[index], s)
 			}
 
+			answered[ID(questionID)] = selected
+
 			if selected > q.GetMaxN() {
 				b.invalidate()
 				return fmt.Errorf("question %s has too many selected answers", questionID)
-			} else if selected < q.GetMinN() {
+			} else if selected < q.GetMinN() && q.GetCondition() == nil {
 				b.invalidate()
 				return fmt.Errorf("question %s has not enough selected answers", questionID)
 			}
@@ -158,10 +184,12 @@ func (b *Ballot) Unmarshal(marshalledBallot string, election Election) error {
 				}
 			}
 
+			answered[ID(questionID)] = selected
+
 			if selected > q.GetMaxN() {
 				b.invalidate()
 				return fmt.Errorf("question %s has too many selected answers", questionID)
-			} else if selected < q.GetMinN() {
+			} else if selected < q.GetMinN() && q.GetCondition() == nil {
 				b.invalidate()
 				return fmt.Errorf("question %s has not enough selected answers", questionID)
 			}
@@ -194,10 +222,130 @@ func (b *Ballot) Unmarshal(marshalledBallot string, election Election) error {
 				b.TextResult[index] = append(b.TextResult[index], string(t))
 			}
 
+			answered[ID(questionID)] = selected
+
+			if selected > q.GetMaxN() {
+				b.invalidate()
+				return fmt.Errorf("question %s has too many selected answers", questionID)
+			} else if selected < q.GetMinN() && q.GetCondition() == nil {
+				b.invalidate()
+				return fmt.Errorf("question %s has not enough selected answers", questionID)
+			}
+
+		case "score":
+			sq, ok := q.(Score)
+			if !ok {
+				b.invalidate()
+				return fmt.Errorf("question %s is not a score question", questionID)
+			}
+
+			scores := strings.Split(question[2], ",")
+
+			if len(scores) != q.GetChoicesLength() {
+				b.invalidate()
+				return fmt.Errorf("question %s has a wrong number of answers: expected %d got %d"+
+					"", questionID, q.GetChoicesLength(), len(scores))
+			}
+
+			b.ScoreResultIDs = append(b.ScoreResultIDs, ID(questionID))
+			b.ScoreResult = append(b.ScoreResult, make([]int, 0))
+
+			index := len(b.ScoreResult) - 1
+			var selected uint = 0
+
+			for _, score := range scores {
+				if len(score) > 0 {
+					selected++
+
+					s, err := strconv.ParseUint(score, 10, 32)
+					if err != nil {
+						b.invalidate()
+						return fmt.Errorf("could not parse score value for Q.%s : %v",
+							questionID, err)
+					}
+
+					if uint(s) < sq.MinScore || uint(s) > sq.MaxScore {
+						b.invalidate()
+						return fmt.Errorf("invalid score not in range [MinScore, MaxScore]")
+					}
+
+					b.ScoreResult[index] = append(b.ScoreResult[index], int(s))
+				} else {
+					b.ScoreResult[index] = append(b.ScoreResult[index], -1)
+				}
+			}
+
+			answered[ID(questionID)] = selected
+
+			if selected > q.GetMaxN() {
+				b.invalidate()
+				return fmt.Errorf("question %s has too many selected answers", questionID)
+			} else if selected < q.GetMinN() && q.GetCondition() == nil {
+				b.invalidate()
+				return fmt.Errorf("question %s has not enough selected answers", questionID)
+			}
+
+		case "cumulative":
+			cq, ok := q.(Cumulative)
+			if !ok {
+				b.invalidate()
+				return fmt.Errorf("question %s is not a cumulative question", questionID)
+			}
+
+			points := strings.Split(question[2], ",")
+
+			if len(points) != q.GetChoicesLength() {
+				b.invalidate()
+				return fmt.Errorf("question %s has a wrong number of answers: expected %d got %d"+
+					"", questionID, q.GetChoicesLength(), len(points))
+			}
+
+			b.CumulativeResultIDs = append(b.CumulativeResultIDs, ID(questionID))
+			b.CumulativeResult = append(b.CumulativeResult, make([]uint, 0))
+
+			index := len(b.CumulativeResult) - 1
+			var selected uint = 0
+			var total uint = 0
+
+			for _, point := range points {
+				var p uint64
+
+				if len(point) > 0 {
+					p, err = strconv.ParseUint(point, 10, 32)
+					if err != nil {
+						b.invalidate()
+						return fmt.Errorf("could not parse points for Q.%s : %v",
+							questionID, err)
+					}
+
+					if uint(p) > cq.GetMaxPerChoice() {
+						b.invalidate()
+						return fmt.Errorf("question %s has too many points for a choice",
+							questionID)
+					}
+				}
+
+				if p > 0 {
+					selected++
+				}
+
+				total += uint(p)
+
+				b.CumulativeResult[index] = append(b.CumulativeResult[index], uint(p))
+			}
+
+			if total > cq.Budget {
+				b.invalidate()
+				return fmt.Errorf("question %s exceeds the budget: %d > %d",
+					questionID, total, cq.Budget)
+			}
+
+			answered[ID(questionID)] = selected
+
 			if selected > q.GetMaxN() {
 				b.invalidate()
 				return fmt.Errorf("question %s has too many selected answers", questionID)
-			} else if selected < q.GetMinN() {
+			} else if selected < q.GetMinN() && q.GetCondition() == nil {
 				b.invalidate()
 				return fmt.Errorf("question %s has not enough selected answers", questionID)
 			}
@@ -209,6 +357,12 @@ func (b *Ballot) Unmarshal(marshalledBallot string, election Election) error {
 
 	}
 
+	err := b.checkConditions(election.Configuration, answered)
+	if err != nil {
+		b.invalidate()
+		return err
+	}
+
 	return nil
 }
 
@@ -219,6 +373,10 @@ func (b *Ballot) invalidate() {
 	b.TextResult = nil
 	b.SelectResultIDs = nil
 	b.SelectResult = nil
+	b.ScoreResultIDs = nil
+	b.ScoreResult = nil
+	b.CumulativeResultIDs = nil
+	b.CumulativeResult = nil
 }
 
 // Equal performs a loose comparison of a ballot.
@@ -303,11 +461,63 @@ func (b *Ballot) Equal(other Ballot) bool {
 		}
 	}
 
+	if len(b.ScoreResultIDs) != len(other.ScoreResultIDs) {
+		return false
+	}
+
+	for i, id := range b.ScoreResultIDs {
+		if id != other.ScoreResultIDs[i] {
+			return false
+		}
+	}
+
+	if len(b.ScoreResult) != len(other.ScoreResult) {
+		return false
+	}
+
+	for i, sr := range b.ScoreResult {
+		if len(sr) != len(other.ScoreResult[i]) {
+			return false
+		}
+
+		for j, r := range sr {
+			if r != other.ScoreResult[i][j] {
+				return false
+			}
+		}
+	}
+
+	if len(b.CumulativeResultIDs) != len(other.CumulativeResultIDs) {
+		return false
+	}
+
+	for i, id := range b.CumulativeResultIDs {
+		if id != other.CumulativeResultIDs[i] {
+			return false
+		}
+	}
+
+	if len(b.CumulativeResult) != len(other.CumulativeResult) {
+		return false
+	}
+
+	for i, cr := range b.CumulativeResult {
+		if len(cr) != len(other.CumulativeResult[i]) {
+			return false
+		}
+
+		for j, r := range cr {
+			if r != other.CumulativeResult[i][j] {
+				return false
+			}
+		}
+	}
+
 	return true
 }
 
 // Subject is a wrapper around multiple questions that can be of type "select",
-// "rank", or "text".
+// "rank", "text", "score", or "cumulative".
 type Subject struct {
 	ID ID
 
@@ -317,10 +527,12 @@ type Subject struct {
 	// identifier. This is purely for display purpose.
 	Order []ID
 
-	Subjects []Subject
-	Selects  []Select
-	Ranks    []Rank
-	Texts    []Text
+	Subjects    []Subject
+	Selects     []Select
+	Ranks       []Rank
+	Texts       []Text
+	Scores      []Score
+	Cumulatives []Cumulative
 }
 
 // GetQuestion finds the question associated to a given ID and returns it
@@ -351,6 +563,18 @@ func (s *Subject) GetQuestion(ID ID) Question {
 		}
 	}
 
+	for _, score := range s.Scores {
+		if score.ID == ID {
+			return score
+		}
+	}
+
+	for _, cumulative := range s.Cumulatives {
+		if cumulative.ID == ID {
+			return cumulative
+		}
+	}
+
 	return nil
 }
 
@@ -387,6 +611,21 @@ func (s *Subject) MaxEncodedSize() int {
 			int(math.Max(float64(len(text.Choices)-int(text.MaxN)), 0))
 	}
 
+	for _, score := range s.Scores {
+		size += len("score::")
+		size += len(score.ID)
+		// digits of the highest score + ',' per choice
+		size += len(score.Choices) * (len(strconv.FormatUint(uint64(score.MaxScore), 10)) + 1)
+	}
+
+	for _, cumulative := range s.Cumulatives {
+		size += len("cumulative::")
+		size += len(cumulative.ID)
+		// digits of the cap + ',' per choice
+		maxPoints := uint64(cumulative.GetMaxPerChoice())
+		size += len(cumulative.Choices) * (len(strconv.FormatUint(maxPoints, 10)) + 1)
+	}
+
 	// Last line has 2 '\n'
 	if size != 0 {
 		size++
@@ -405,7 +644,7 @@ func (s *Subject) isValid(uniqueIDs map[ID]bool) bool {
 	for _, rank := range s.Ranks {
 		uniqueIDs[rank.ID] = true
 
-		if !isValid(rank) {
+		if !isValid(rank) || !rank.isValidMethod() {
 			return false
 		}
 	}
@@ -426,9 +665,26 @@ func (s *Subject) isValid(uniqueIDs map[ID]bool) bool {
 		}
 	}
 
+	for _, score := range s.Scores {
+		uniqueIDs[score.ID] = true
+
+		if !isValid(score) || !score.isValidRange() {
+			return false
+		}
+	}
+
+	for _, cumulative := range s.Cumulatives {
+		uniqueIDs[cumulative.ID] = true
+
+		if !isValid(cumulative) || !cumulative.isValidBudget() {
+			return false
+		}
+	}
+
 	// If some ID was not unique
 	currentMapSize := len(uniqueIDs)
-	if prevMapSize+len(s.Ranks)+len(s.Texts)+len(s.Selects)+1 > currentMapSize {
+	if prevMapSize+len(s.Ranks)+len(s.Texts)+len(s.Selects)+len(s.Scores)+
+		len(s.Cumulatives)+1 > currentMapSize {
 		return false
 	}
 
@@ -451,9 +707,11 @@ func (s *Subject) isValid(uniqueIDs map[ID]bool) bool {
 // Question is an interface offering the primitives all questions should have to
 // verify the validity of an answer on a decrypted ballot.
 type Question interface {
+	GetID() ID
 	GetMaxN() uint
 	GetMinN() uint
 	GetChoicesLength() int
+	GetCondition() *Condition
 }
 
 func isValid(q Question) bool {
@@ -469,6 +727,10 @@ type Select struct {
 	MaxN    uint
 	MinN    uint
 	Choices []string
+
+	// Condition makes the question apply only to the ballots that answered
+	// some choices of another question. The question always applies if nil.
+	Condition *Condition `json:",omitempty"`
 }
 
 // GetMaxN implements Question
@@ -486,6 +748,16 @@ func (s Select) GetChoicesLength() int {
 	return len(s.Choices)
 }
 
+// GetID implements Question
+func (s Select) GetID() ID {
+	return s.ID
+}
+
+// GetCondition implements Question
+func (s Select) GetCondition() *Condition {
+	return s.Condition
+}
+
 // Rank describes a "rank" question, which requires the user to rank choices.
 // implements Question
 type Rank struct {
@@ -495,6 +767,17 @@ type Rank struct {
 	MaxN    uint
 	MinN    uint
 	Choices []string
+
+	// Method is the counting method used to compute the winners. By default
+	// only the ranks given to each choice are counted.
+	Method RankMethod `json:",omitempty"`
+	// Seats is the number of choices to elect with the STV, Borda and Schulze
+	// methods. Defaults to 1.
+	Seats uint `json:",omitempty"`
+
+	// Condition makes the question apply only to the ballots that answered
+	// some choices of another question. The question always applies if nil.
+	Condition *Condition `json:",omitempty"`
 }
 
 // GetMaxN implements Question
@@ -512,6 +795,16 @@ func (r Rank) GetChoicesLength() int {
 	return len(r.Choices)
 }
 
+// GetID implements Question
+func (r Rank) GetID() ID {
+	return r.ID
+}
+
+// GetCondition implements Question
+func (r Rank) GetCondition() *Condition {
+	return r.Condition
+}
+
 // Text describes a "text" question, which allows the user to enter free text.
 // implements Question
 type Text struct {
@@ -523,6 +816,10 @@ type Text struct {
 	MaxLength uint
 	Regex     string
 	Choices   []string
+
+	// Condition makes the question apply only to the ballots that answered
+	// some choices of another question. The question always applies if nil.
+	Condition *Condition `json:",omitempty"`
 }
 
 // GetMaxN implements Question
@@ -539,3 +836,126 @@ func (t Text) GetMinN() uint {
 func (t Text) GetChoicesLength() int {
 	return len(t.Choices)
 }
+
+// GetID implements Question
+func (t Text) GetID() ID {
+	return t.ID
+}
+
+// GetCondition implements Question
+func (t Text) GetCondition() *Condition {
+	return t.Condition
+}
+
+// Score describes a "score" question, which requires the user to give a score
+// between MinScore and MaxScore to choices. implements Question
+type Score struct {
+	ID ID
+
+	Title    string
+	MaxN     uint
+	MinN     uint
+	MinScore uint
+	MaxScore uint
+	Choices  []string
+
+	// Condition makes the question apply only to the ballots that answered
+	// some choices of another question. The question always applies if nil.
+	Condition *Condition `json:",omitempty"`
+}
+
+// GetMaxN implements Question
+func (s Score) GetMaxN() uint {
+	return s.MaxN
+}
+
+// GetMinN implements Question
+func (s Score) GetMinN() uint {
+	return s.MinN
+}
+
+// GetChoicesLength implements Question
+func (s Score) GetChoicesLength() int {
+	return len(s.Choices)
+}
+
+// GetID implements Question
+func (s Score) GetID() ID {
+	return s.ID
+}
+
+// GetCondition implements Question
+func (s Score) GetCondition() *Condition {
+	return s.Condition
+}
+
+// maxScoreRange is the maximum number of distinct scores of a Score question,
+// which bounds the size of its tally.
+const maxScoreRange = 1000
+
+// isValidRange verifies that the scores can be parsed by Unmarshal and that
+// the range is not too large to be tallied.
+func (s Score) isValidRange() bool {
+	return s.MinScore <= s.MaxScore && s.MaxScore <= math.MaxUint32 &&
+		s.MaxScore-s.MinScore < maxScoreRange
+}
+
+// Cumulative describes a "cumulative" question, which requires the user to
+// spread a budget of points across the choices. implements Question
+type Cumulative struct {
+	ID ID
+
+	Title   string
+	MaxN    uint
+	MinN    uint
+	Budget  uint
+	Choices []string
+
+	// MaxPerChoice is the maximum number of points a choice can receive. The
+	// whole budget can be given to a single choice if it is 0.
+	MaxPerChoice uint `json:",omitempty"`
+
+	// Condition makes the question apply only to the ballots that answered
+	// some choices of another question. The question always applies if nil.
+	Condition *Condition `json:",omitempty"`
+}
+
+// GetMaxN implements Question
+func (c Cumulative) GetMaxN() uint {
+	return c.MaxN
+}
+
+// GetMinN implements Question
+func (c Cumulative) GetMinN() uint {
+	return c.MinN
+}
+
+// GetChoicesLength implements Question
+func (c Cumulative) GetChoicesLength() int {
+	return len(c.Choices)
+}
+
+// GetID implements Question
+func (c Cumulative) GetID() ID {
+	return c.ID
+}
+
+// GetCondition implements Question
+func (c Cumulative) GetCondition() *Condition {
+	return c.Condition
+}
+
+// GetMaxPerChoice returns the maximum number of points a choice can receive.
+func (c Cumulative) GetMaxPerChoice() uint {
+	if c.MaxPerChoice == 0 || c.MaxPerChoice > c.Budget {
+		return c.Budget
+	}
+
+	return c.MaxPerChoice
+}
+
+// isValidBudget verifies that there are points to spread and that they can be
+// parsed by Unmarshal.
+func (c Cumulative) isValidBudget() bool {
+	return c.Budget > 0 && c.Budget <= math.MaxUint32 && c.MaxPerChoice <= c.Budget
+}
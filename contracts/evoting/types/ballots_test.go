@@ -1,9 +1,12 @@
 package types
 
 import (
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -269,6 +272,207 @@ func TestBallot_Unmarshal(t *testing.T) {
 	require.EqualError(t, err, "question type is unknown")
 }
 
+func TestBallot_UnmarshalScore(t *testing.T) {
+	election := Election{
+		Configuration: Configuration{Scaffold: []Subject{{
+			Scores: []Score{{
+				ID:       questionID(1),
+				MaxN:     3,
+				MinN:     2,
+				MinScore: 1,
+				MaxScore: 10,
+				Choices:  make([]string, 3),
+			}},
+		}}},
+	}
+
+	election.BallotSize = election.Configuration.MaxBallotSize()
+	require.Equal(t, len("score:"+questionID(1)+":10,10,10\n\n"), election.BallotSize)
+
+	b := Ballot{}
+
+	err := b.Unmarshal("score:"+questionID(1)+":10,,3\n\n", election)
+	require.NoError(t, err)
+	require.Equal(t, []ID{questionID(1)}, b.ScoreResultIDs)
+	require.Equal(t, [][]int{{10, -1, 3}}, b.ScoreResult)
+
+	err = b.Unmarshal("score:"+questionID(1)+":10,,0\n\n", election)
+	require.EqualError(t, err, "invalid score not in range [MinScore, MaxScore]")
+	require.Nil(t, b.ScoreResult)
+
+	err = b.Unmarshal("score:"+questionID(1)+":10,,\n\n", election)
+	require.EqualError(t, err, "question UTE= has not enough selected answers")
+
+	err = b.Unmarshal("score:"+questionID(1)+":x,1,1\n\n", election)
+	require.EqualError(t, err, "could not parse score value for Q.UTE= : "+
+		"strconv.ParseUint: parsing \"x\": invalid syntax")
+
+	err = b.Unmarshal("score:"+questionID(1)+":1,1\n\n", election)
+	require.EqualError(t, err, "question UTE= has a wrong number of answers: expected 3 got 2")
+
+	election.Configuration.Scaffold[0].Selects = []Select{{
+		ID:      questionID(2),
+		MaxN:    1,
+		Choices: make([]string, 1),
+	}}
+
+	err = b.Unmarshal("score:"+questionID(2)+":1\n\n", election)
+	require.EqualError(t, err, "question UTI= is not a score question")
+}
+
+func TestScore_IsValidRange(t *testing.T) {
+	score := Score{MinScore: 0, MaxScore: 10}
+	require.True(t, score.isValidRange())
+
+	score.MinScore = 11
+	require.False(t, score.isValidRange())
+
+	score.MinScore = 0
+	score.MaxScore = maxScoreRange
+	require.False(t, score.isValidRange())
+}
+
+func TestBallot_UnmarshalCumulative(t *testing.T) {
+	election := Election{
+		Configuration: Configuration{Scaffold: []Subject{{
+			Cumulatives: []Cumulative{{
+				ID:           questionID(1),
+				MaxN:         3,
+				MinN:         1,
+				Budget:       10,
+				MaxPerChoice: 6,
+				Choices:      make([]string, 3),
+			}},
+		}}},
+	}
+
+	require.True(t, election.Configuration.IsValid())
+
+	election.BallotSize = election.Configuration.MaxBallotSize()
+	require.Equal(t, len("cumulative:"+questionID(1)+":6,6,6\n\n"), election.BallotSize)
+
+	b := Ballot{}
+
+	err := b.Unmarshal("cumulative:"+questionID(1)+":6,,4\n\n", election)
+	require.NoError(t, err)
+	require.Equal(t, []ID{questionID(1)}, b.CumulativeResultIDs)
+	require.Equal(t, [][]uint{{6, 0, 4}}, b.CumulativeResult)
+
+	err = b.Unmarshal("cumulative:"+questionID(1)+":6,3,2\n\n", election)
+	require.EqualError(t, err, "question UTE= exceeds the budget: 11 > 10")
+	require.Nil(t, b.CumulativeResult)
+
+	err = b.Unmarshal("cumulative:"+questionID(1)+":7,0,0\n\n", election)
+	require.EqualError(t, err, "question UTE= has too many points for a choice")
+
+	err = b.Unmarshal("cumulative:"+questionID(1)+":0,0,0\n\n", election)
+	require.EqualError(t, err, "question UTE= has not enough selected answers")
+
+	err = b.Unmarshal("cumulative:"+questionID(1)+":-1,0,0\n\n", election)
+	require.EqualError(t, err, "could not parse points for Q.UTE= : "+
+		"strconv.ParseUint: parsing \"-1\": invalid syntax")
+}
+
+func TestCumulative_IsValidBudget(t *testing.T) {
+	cumulative := Cumulative{Budget: 10}
+	require.True(t, cumulative.isValidBudget())
+	require.Equal(t, uint(10), cumulative.GetMaxPerChoice())
+
+	cumulative.MaxPerChoice = 11
+	require.False(t, cumulative.isValidBudget())
+
+	cumulative.Budget = 0
+	cumulative.MaxPerChoice = 0
+	require.False(t, cumulative.isValidBudget())
+}
+
+func TestBallot_UnmarshalConditions(t *testing.T) {
+	election := Election{
+		BallotSize: 100,
+		Configuration: Configuration{Scaffold: []Subject{{
+			Selects: []Select{{
+				ID:      questionID(1),
+				MaxN:    1,
+				MinN:    1,
+				Choices: []string{"yes", "other"},
+			}},
+			Texts: []Text{{
+				ID:        questionID(2),
+				MaxN:      1,
+				MinN:      1,
+				MaxLength: 10,
+				Choices:   []string{"explain"},
+				Condition: &Condition{QuestionID: questionID(1), Choices: []int{1}},
+			}},
+		}}},
+	}
+
+	require.True(t, election.Configuration.IsValid())
+
+	b := Ballot{}
+
+	// the condition is met and the question is answered
+	err := b.Unmarshal("select:"+questionID(1)+":0,1\n"+
+		"text:"+questionID(2)+":YmxhYmxh\n\n", election)
+	require.NoError(t, err)
+	require.True(t, b.IsApplicable(election.Configuration.Scaffold[0].Texts[0]))
+
+	// the condition is met but the question is not answered
+	err = b.Unmarshal("select:"+questionID(1)+":0,1\n\n", election)
+	require.EqualError(t, err, "question UTI= has not enough selected answers")
+
+	// the condition is not met and the question is skipped
+	err = b.Unmarshal("select:"+questionID(1)+":1,0\n"+
+		"text:"+questionID(2)+":\n\n", election)
+	require.NoError(t, err)
+	require.False(t, b.IsApplicable(election.Configuration.Scaffold[0].Texts[0]))
+
+	// the condition is not met but the question is answered
+	err = b.Unmarshal("select:"+questionID(1)+":1,0\n"+
+		"text:"+questionID(2)+":YmxhYmxh\n\n", election)
+	require.EqualError(t, err, "question UTI= must be skipped")
+	require.Nil(t, b.TextResult)
+}
+
+func TestConfiguration_IsValidConditions(t *testing.T) {
+	configuration := Configuration{Scaffold: []Subject{{
+		Selects: []Select{{
+			ID:        questionID(1),
+			MaxN:      1,
+			Choices:   []string{"a", "b"},
+			Condition: &Condition{QuestionID: questionID(2), Choices: []int{0}},
+		}, {
+			ID:      questionID(2),
+			MaxN:    1,
+			Choices: []string{"a"},
+		}},
+	}}}
+
+	require.True(t, configuration.IsValid())
+
+	// unknown choice
+	configuration.Scaffold[0].Selects[0].Condition.Choices = []int{1}
+	require.False(t, configuration.IsValid())
+
+	// unknown question
+	configuration.Scaffold[0].Selects[0].Condition = &Condition{
+		QuestionID: questionID(3),
+		Choices:    []int{0},
+	}
+	require.False(t, configuration.IsValid())
+
+	// cycle
+	configuration.Scaffold[0].Selects[0].Condition = &Condition{
+		QuestionID: questionID(2),
+		Choices:    []int{0},
+	}
+	configuration.Scaffold[0].Selects[1].Condition = &Condition{
+		QuestionID: questionID(1),
+		Choices:    []int{0},
+	}
+	require.False(t, configuration.IsValid())
+}
+
 func TestSubject_MaxEncodedSize(t *testing.T) {
 	subject := Subject{
 		Subjects: []Subject{{
@@ -471,6 +675,74 @@ func TestSubject_IsValid(t *testing.T) {
 	require.False(t, valid)
 }
 
+func TestConfiguration_IsValidSchedule(t *testing.T) {
+	configuration := Configuration{
+		MainTitle: "title",
+		Schedule:  Schedule{Start: 10, End: 20},
+	}
+
+	require.True(t, configuration.IsValid())
+
+	configuration.Schedule = Schedule{Start: 20, End: 10}
+	require.False(t, configuration.IsValid())
+
+	configuration.Schedule = Schedule{Start: -1}
+	require.False(t, configuration.IsValid())
+
+	configuration.Schedule = Schedule{End: 10}
+	require.True(t, configuration.IsValid())
+}
+
+func TestQuorum_Check(t *testing.T) {
+	quorum := Quorum{MinBallots: 2}
+	require.True(t, quorum.IsValid())
+
+	err := quorum.CheckCast(1, Electorate{})
+	require.EqualError(t, err, "1 ballots cast, at least 2 required")
+
+	err = quorum.CheckCast(2, Electorate{})
+	require.NoError(t, err)
+
+	quorum.MinTurnout = 25
+
+	err = quorum.CheckCast(2, Electorate{})
+	require.EqualError(t, err, "a turnout quorum requires a registered electorate")
+
+	electorate := Electorate{Restricted: true, Count: 8}
+
+	err = quorum.CheckCast(2, electorate)
+	require.NoError(t, err)
+
+	quorum.MinTurnout = 26
+
+	err = quorum.CheckCast(2, electorate)
+	require.EqualError(t, err, "turnout of 2/8 voters, at least 26% required")
+
+	quorum.MinTurnout = 101
+	require.False(t, quorum.IsValid())
+
+	quorum = Quorum{MinValidBallots: 2}
+
+	err = quorum.CheckValid(Tally{NumBallots: 3, InvalidBallots: 2})
+	require.EqualError(t, err, "1 valid ballots, at least 2 required")
+}
+
+func TestSchedule_Bounds(t *testing.T) {
+	schedule := Schedule{}
+
+	require.False(t, schedule.IsSet())
+	require.True(t, schedule.HasStarted(time.Unix(0, 0)))
+	require.False(t, schedule.HasEnded(time.Unix(1000, 0)))
+
+	schedule = Schedule{Start: 10, End: 20}
+
+	require.True(t, schedule.IsSet())
+	require.False(t, schedule.HasStarted(time.Unix(9, 0)))
+	require.True(t, schedule.HasStarted(time.Unix(10, 0)))
+	require.False(t, schedule.HasEnded(time.Unix(19, 0)))
+	require.True(t, schedule.HasEnded(time.Unix(20, 0)))
+}
+
 func TestBallot_Equal(t *testing.T) {
 	type check struct {
 		ballot    Ballot
@@ -662,3 +934,50 @@ func TestBallot_Equal(t *testing.T) {
 		e.assertion(t, e.ballot.Equal(e.other))
 	}
 }
+
+func TestElectorate_VoterKey(t *testing.T) {
+	electorate := Electorate{}
+
+	voter1 := HashUserID("election", "user1")
+	voter2 := HashUserID("election", "user2")
+
+	require.NotEqual(t, voter1, HashUserID("other", "user1"))
+
+	key := electorate.VoterKey([]byte("election"), voter1)
+	require.Len(t, key, sha256.Size)
+	require.NotEqual(t, key, electorate.VoterKey([]byte("election"), voter2))
+	require.NotEqual(t, key, electorate.VoterKey([]byte("other"), voter1))
+
+	// a replaced electorate uses other keys
+	electorate.Epoch++
+	require.NotEqual(t, key, electorate.VoterKey([]byte("election"), voter1))
+
+	require.NoError(t, CheckVoters([]string{voter1, voter2}))
+
+	err := CheckVoters([]string{voter1, "bad"})
+	require.Contains(t, err.Error(), "invalid voter")
+
+	err = CheckVoters([]string{"aa"})
+	require.Contains(t, err.Error(), "has a wrong size: 1 != 32")
+}
+
+func TestAdminSet_Verify(t *testing.T) {
+	key, err := suite.Point().Pick(suite.RandomStream()).MarshalBinary()
+	require.NoError(t, err)
+
+	admin := hex.EncodeToString(key)
+
+	admins := AdminSet{Keys: []string{admin}, Threshold: 1}
+	require.NoError(t, admins.Verify())
+	require.True(t, admins.Contains(admin))
+	require.False(t, admins.Contains("other"))
+
+	admins.Threshold = 2
+	require.EqualError(t, admins.Verify(), "threshold must be in [1, 1], got 2")
+
+	admins.Keys = append(admins.Keys, admin)
+	require.EqualError(t, admins.Verify(), "duplicated admin \""+admin+"\"")
+
+	admins.Keys = []string{"zz"}
+	require.Contains(t, admins.Verify().Error(), "invalid admin \"zz\"")
+}
@@ -0,0 +1,438 @@
+package types
+
+import (
+	"encoding/json"
+	"io"
+
+	"go.dedis.ch/kyber/v3"
+	"golang.org/x/xerrors"
+)
+
+// BulletinVersion is the version of the bulletin board format. It is increased
+// whenever a change prevents an older reader from reading the bulletin board.
+// The readers also accept the previous versions.
+const BulletinVersion = 2
+
+// BulletinRecordType is the type of a record of the bulletin board
+type BulletinRecordType string
+
+const (
+	// BulletinHeaderType is the first record. It describes the election.
+	BulletinHeaderType BulletinRecordType = "header"
+	// BulletinBallotType is a ciphervote of the suffragia
+	BulletinBallotType BulletinRecordType = "ballot"
+	// BulletinShuffleType is a shuffle instance
+	BulletinShuffleType BulletinRecordType = "shuffle"
+	// BulletinPubsharesType is the pubshares submitted by a node
+	BulletinPubsharesType BulletinRecordType = "pubshares"
+	// BulletinResultType is the last record. It contains the decrypted ballots
+	// and the tally.
+	BulletinResultType BulletinRecordType = "result"
+)
+
+// BulletinRecord is a line of the bulletin board, which is exported as
+// newline-delimited JSON. Only the field of its type is set.
+type BulletinRecord struct {
+	Type      BulletinRecordType
+	Header    *BulletinHeader    `json:",omitempty"`
+	Ballot    *BulletinBallot    `json:",omitempty"`
+	Shuffle   *BulletinShuffle   `json:",omitempty"`
+	Pubshares *BulletinPubshares `json:",omitempty"`
+	Result    *BulletinResult    `json:",omitempty"`
+}
+
+// BulletinHeader describes the election and the format of the bulletin board
+type BulletinHeader struct {
+	Version          int
+	ElectionID       string
+	Status           Status
+	Configuration    Configuration
+	BallotSize       int
+	ChunksPerBallot  int
+	ShuffleThreshold int
+	// DecryptionThreshold is the number of pubshares combined to decrypt a
+	// ballot. If it is absent, all the pubshares are combined.
+	DecryptionThreshold int `json:",omitempty"`
+	// RandomVectorVersion is the derivation of the random vectors of the
+	// shuffles. It is absent in version 1, which only has legacy elections.
+	RandomVectorVersion RandomVectorVersion `json:",omitempty"`
+	// Pubkey is the public key of the DKG, used to encrypt the ballots
+	Pubkey []byte
+	// VerificationKeys are the public keys of the private shares of the DKG,
+	// which verify the proofs of the pubshares
+	VerificationKeys [][]byte `json:",omitempty"`
+	// Roster contains the public keys of the nodes of the election, which are
+	// the only ones allowed to shuffle
+	Roster [][]byte `json:",omitempty"`
+}
+
+// BulletinEGPair is an ElGamal pair with its points marshalled
+type BulletinEGPair struct {
+	K []byte
+	C []byte
+}
+
+// BulletinBallot is the ciphervote at position Index in the suffragia
+type BulletinBallot struct {
+	Index      int
+	Ciphervote []BulletinEGPair
+}
+
+// BulletinShuffle is the shuffle of a round, with the key of its shuffler and
+// its signature
+type BulletinShuffle struct {
+	Round             int
+	ShufflerPublicKey []byte
+	Signature         []byte `json:",omitempty"`
+	ShuffleProofs     []byte
+	ShuffledBallots   [][]BulletinEGPair
+}
+
+// BulletinPubshares is the pubshares submitted by the node at Index in the
+// DKG. Pubshares[i][j] is the share of the j-th chunk of the i-th ballot and
+// Proofs[i][j] its proof.
+type BulletinPubshares struct {
+	Index     int
+	PubKey    []byte
+	Pubshares [][][]byte
+	Proofs    PubshareProofsUnit `json:",omitempty"`
+}
+
+// BulletinResult contains the decrypted ballots and the tally
+type BulletinResult struct {
+	DecryptedBallots []Ballot
+	Tally            Tally
+}
+
+// Bulletin is the content of a bulletin board, with the points unmarshalled
+type Bulletin struct {
+	Header    BulletinHeader
+	Ballots   []Ciphervote
+	Shuffles  []ShuffleInstance
+	Pubshares PubsharesUnits
+	Result    *BulletinResult
+}
+
+// WriteBulletin writes the bulletin board of the election, one record per
+// line, in this order: the header, the ballots, the shuffles, the pubshares,
+// and the result if it is available.
+func WriteBulletin(w io.Writer, election Election) error {
+	enc := json.NewEncoder(w)
+
+	var pubkey []byte
+
+	if election.Pubkey != nil {
+		var err error
+
+		pubkey, err = election.Pubkey.MarshalBinary()
+		if err != nil {
+			return xerrors.Errorf("failed to marshal pubkey: %v", err)
+		}
+	}
+
+	verificationKeys := make([][]byte, len(election.VerificationKeys))
+
+	for i, key := range election.VerificationKeys {
+		var err error
+
+		verificationKeys[i], err = key.MarshalBinary()
+		if err != nil {
+			return xerrors.Errorf("failed to marshal verification key: %v", err)
+		}
+	}
+
+	var roster [][]byte
+
+	if election.Roster != nil {
+		iter := election.Roster.PublicKeyIterator()
+
+		for iter.HasNext() {
+			key, err := iter.GetNext().MarshalBinary()
+			if err != nil {
+				return xerrors.Errorf("failed to marshal roster key: %v", err)
+			}
+
+			roster = append(roster, key)
+		}
+	}
+
+	err := enc.Encode(BulletinRecord{
+		Type: BulletinHeaderType,
+		Header: &BulletinHeader{
+			Version:             BulletinVersion,
+			ElectionID:          election.ElectionID,
+			Status:              election.Status,
+			Configuration:       election.Configuration,
+			BallotSize:          election.BallotSize,
+			ChunksPerBallot:     election.ChunksPerBallot(),
+			ShuffleThreshold:    election.ShuffleThreshold,
+			DecryptionThreshold: election.DecryptionThreshold,
+			RandomVectorVersion: election.RandomVectorVersion,
+			Pubkey:              pubkey,
+			VerificationKeys:    verificationKeys,
+			Roster:              roster,
+		},
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to write header: %v", err)
+	}
+
+	for i, ciphervote := range election.Suffragia.Ciphervotes {
+		pairs, err := encodeBulletinPairs(ciphervote)
+		if err != nil {
+			return xerrors.Errorf("failed to encode ballot %d: %v", i, err)
+		}
+
+		err = enc.Encode(BulletinRecord{
+			Type:   BulletinBallotType,
+			Ballot: &BulletinBallot{Index: i, Ciphervote: pairs},
+		})
+		if err != nil {
+			return xerrors.Errorf("failed to write ballot %d: %v", i, err)
+		}
+	}
+
+	for round, instance := range election.ShuffleInstances {
+		ballots := make([][]BulletinEGPair, len(instance.ShuffledBallots))
+
+		for i, ciphervote := range instance.ShuffledBallots {
+			ballots[i], err = encodeBulletinPairs(ciphervote)
+			if err != nil {
+				return xerrors.Errorf("failed to encode shuffled ballot %d "+
+					"of round %d: %v", i, round, err)
+			}
+		}
+
+		err = enc.Encode(BulletinRecord{
+			Type: BulletinShuffleType,
+			Shuffle: &BulletinShuffle{
+				Round:             round,
+				ShufflerPublicKey: instance.ShufflerPublicKey,
+				Signature:         instance.Signature,
+				ShuffleProofs:     instance.ShuffleProofs,
+				ShuffledBallots:   ballots,
+			},
+		})
+		if err != nil {
+			return xerrors.Errorf("failed to write shuffle %d: %v", round, err)
+		}
+	}
+
+	units := election.PubsharesUnits
+
+	for i, unit := range units.Pubshares {
+		pubshares := make([][][]byte, len(unit))
+
+		for j, ballotShares := range unit {
+			pubshares[j] = make([][]byte, len(ballotShares))
+
+			for k, pubshare := range ballotShares {
+				pubshares[j][k], err = pubshare.MarshalBinary()
+				if err != nil {
+					return xerrors.Errorf("failed to marshal pubshare: %v", err)
+				}
+			}
+		}
+
+		// the submissions made before the proofs were stored have none
+		var proofs PubshareProofsUnit
+		if i < len(units.Proofs) {
+			proofs = units.Proofs[i]
+		}
+
+		err = enc.Encode(BulletinRecord{
+			Type: BulletinPubsharesType,
+			Pubshares: &BulletinPubshares{
+				Index:     units.Indexes[i],
+				PubKey:    units.PubKeys[i],
+				Pubshares: pubshares,
+				Proofs:    proofs,
+			},
+		})
+		if err != nil {
+			return xerrors.Errorf("failed to write pubshares %d: %v", i, err)
+		}
+	}
+
+	if election.Status != ResultAvailable {
+		return nil
+	}
+
+	err = enc.Encode(BulletinRecord{
+		Type: BulletinResultType,
+		Result: &BulletinResult{
+			DecryptedBallots: election.DecryptedBallots,
+			Tally:            election.Tally,
+		},
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to write result: %v", err)
+	}
+
+	return nil
+}
+
+// ReadBulletin reads a bulletin board written by WriteBulletin. It fails if
+// the version of the bulletin board is not supported.
+func ReadBulletin(r io.Reader) (Bulletin, error) {
+	var bulletin Bulletin
+
+	dec := json.NewDecoder(r)
+
+	var record BulletinRecord
+
+	err := dec.Decode(&record)
+	if err != nil {
+		return bulletin, xerrors.Errorf("failed to read header: %v", err)
+	}
+
+	if record.Type != BulletinHeaderType || record.Header == nil {
+		return bulletin, xerrors.Errorf("the first record must be the header: %s",
+			record.Type)
+	}
+
+	if record.Header.Version < 1 || record.Header.Version > BulletinVersion {
+		return bulletin, xerrors.Errorf("unsupported version: %d",
+			record.Header.Version)
+	}
+
+	bulletin.Header = *record.Header
+
+	for dec.More() {
+		record = BulletinRecord{}
+
+		err = dec.Decode(&record)
+		if err != nil {
+			return bulletin, xerrors.Errorf("failed to read record: %v", err)
+		}
+
+		err = bulletin.add(record)
+		if err != nil {
+			return bulletin, xerrors.Errorf("invalid %s record: %v", record.Type, err)
+		}
+	}
+
+	return bulletin, nil
+}
+
+// add adds a record, other than the header, to the bulletin board.
+func (b *Bulletin) add(record BulletinRecord) error {
+	switch {
+	case record.Type == BulletinBallotType && record.Ballot != nil:
+		if record.Ballot.Index != len(b.Ballots) {
+			return xerrors.Errorf("unexpected index: %d != %d",
+				record.Ballot.Index, len(b.Ballots))
+		}
+
+		ciphervote, err := decodeBulletinPairs(record.Ballot.Ciphervote)
+		if err != nil {
+			return xerrors.Errorf("failed to decode ballot: %v", err)
+		}
+
+		b.Ballots = append(b.Ballots, ciphervote)
+
+	case record.Type == BulletinShuffleType && record.Shuffle != nil:
+		if record.Shuffle.Round != len(b.Shuffles) {
+			return xerrors.Errorf("unexpected round: %d != %d",
+				record.Shuffle.Round, len(b.Shuffles))
+		}
+
+		ballots := make([]Ciphervote, len(record.Shuffle.ShuffledBallots))
+
+		for i, pairs := range record.Shuffle.ShuffledBallots {
+			var err error
+
+			ballots[i], err = decodeBulletinPairs(pairs)
+			if err != nil {
+				return xerrors.Errorf("failed to decode ballot %d: %v", i, err)
+			}
+		}
+
+		b.Shuffles = append(b.Shuffles, ShuffleInstance{
+			ShuffledBallots:   ballots,
+			ShuffleProofs:     record.Shuffle.ShuffleProofs,
+			ShufflerPublicKey: record.Shuffle.ShufflerPublicKey,
+			Signature:         record.Shuffle.Signature,
+		})
+
+	case record.Type == BulletinPubsharesType && record.Pubshares != nil:
+		unit := make(PubsharesUnit, len(record.Pubshares.Pubshares))
+
+		for i, ballotShares := range record.Pubshares.Pubshares {
+			unit[i] = make([]Pubshare, len(ballotShares))
+
+			for j, buf := range ballotShares {
+				point, err := unmarshalPoint(buf)
+				if err != nil {
+					return xerrors.Errorf("failed to decode pubshare: %v", err)
+				}
+
+				unit[i][j] = point
+			}
+		}
+
+		b.Pubshares.Pubshares = append(b.Pubshares.Pubshares, unit)
+		b.Pubshares.PubKeys = append(b.Pubshares.PubKeys, record.Pubshares.PubKey)
+		b.Pubshares.Indexes = append(b.Pubshares.Indexes, record.Pubshares.Index)
+		b.Pubshares.Proofs = append(b.Pubshares.Proofs, record.Pubshares.Proofs)
+
+	case record.Type == BulletinResultType && record.Result != nil:
+		b.Result = record.Result
+
+	default:
+		return xerrors.Errorf("unexpected record")
+	}
+
+	return nil
+}
+
+func encodeBulletinPairs(ciphervote Ciphervote) ([]BulletinEGPair, error) {
+	pairs := make([]BulletinEGPair, len(ciphervote))
+
+	for i, egpair := range ciphervote {
+		k, err := egpair.K.MarshalBinary()
+		if err != nil {
+			return nil, xerrors.Errorf("failed to marshal K: %v", err)
+		}
+
+		c, err := egpair.C.MarshalBinary()
+		if err != nil {
+			return nil, xerrors.Errorf("failed to marshal C: %v", err)
+		}
+
+		pairs[i] = BulletinEGPair{K: k, C: c}
+	}
+
+	return pairs, nil
+}
+
+func decodeBulletinPairs(pairs []BulletinEGPair) (Ciphervote, error) {
+	ciphervote := make(Ciphervote, len(pairs))
+
+	for i, pair := range pairs {
+		k, err := unmarshalPoint(pair.K)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to unmarshal K: %v", err)
+		}
+
+		c, err := unmarshalPoint(pair.C)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to unmarshal C: %v", err)
+		}
+
+		ciphervote[i] = EGPair{K: k, C: c}
+	}
+
+	return ciphervote, nil
+}
+
+func unmarshalPoint(buf []byte) (kyber.Point, error) {
+	point := suite.Point()
+
+	err := point.UnmarshalBinary(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return point, nil
+}
@@ -0,0 +1,90 @@
+package types
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulletin_WriteRead(t *testing.T) {
+	ciphervote := func(k, c int64) Ciphervote {
+		return Ciphervote{EGPair{
+			K: suite.Point().Mul(suite.Scalar().SetInt64(k), nil),
+			C: suite.Point().Mul(suite.Scalar().SetInt64(c), nil),
+		}}
+	}
+
+	election := Election{
+		ElectionID:          "abcd",
+		Configuration:       Configuration{MainTitle: "title"},
+		Status:              ResultAvailable,
+		Pubkey:              suite.Point().Pick(suite.RandomStream()),
+		BallotSize:          29,
+		ShuffleThreshold:    1,
+		RandomVectorVersion: RandomVectorXOF,
+		Suffragia: Suffragia{
+			Count:       2,
+			UserIDs:     []string{"user1", "user2"},
+			Ciphervotes: []Ciphervote{ciphervote(1, 2), ciphervote(3, 4)},
+		},
+		ShuffleInstances: []ShuffleInstance{{
+			ShuffledBallots:   []Ciphervote{ciphervote(5, 6), ciphervote(7, 8)},
+			ShuffleProofs:     []byte("proof"),
+			ShufflerPublicKey: []byte("shuffler"),
+		}},
+		PubsharesUnits: PubsharesUnits{
+			Pubshares: []PubsharesUnit{{{suite.Point().Base()}, {suite.Point().Null()}}},
+			PubKeys:   [][]byte{[]byte("node")},
+			Indexes:   []int{2},
+		},
+		DecryptedBallots: []Ballot{{TextResultIDs: []ID{"aa"}, TextResult: [][]string{{"yes"}}}},
+		Tally:            Tally{NumBallots: 1, InvalidBallots: 1},
+	}
+
+	buf := new(bytes.Buffer)
+
+	err := WriteBulletin(buf, election)
+	require.NoError(t, err)
+
+	// header, 2 ballots, 1 shuffle, 1 pubshares, result
+	require.Equal(t, 6, strings.Count(buf.String(), "\n"))
+
+	bulletin, err := ReadBulletin(buf)
+	require.NoError(t, err)
+
+	require.Equal(t, BulletinVersion, bulletin.Header.Version)
+	require.Equal(t, "abcd", bulletin.Header.ElectionID)
+	require.Equal(t, RandomVectorXOF, bulletin.Header.RandomVectorVersion)
+	require.Equal(t, 1, bulletin.Header.ChunksPerBallot)
+
+	require.Len(t, bulletin.Ballots, 2)
+	require.True(t, bulletin.Ballots[1].Equal(ciphervote(3, 4)))
+
+	require.Len(t, bulletin.Shuffles, 1)
+	require.True(t, bulletin.Shuffles[0].ShuffledBallots[0].Equal(ciphervote(5, 6)))
+	require.Equal(t, []byte("proof"), bulletin.Shuffles[0].ShuffleProofs)
+
+	require.Equal(t, []int{2}, bulletin.Pubshares.Indexes)
+	require.True(t, bulletin.Pubshares.Pubshares[0][0][0].Equal(suite.Point().Base()))
+
+	require.NotNil(t, bulletin.Result)
+	require.Equal(t, election.DecryptedBallots, bulletin.Result.DecryptedBallots)
+	require.Equal(t, uint(1), bulletin.Result.Tally.InvalidBallots)
+}
+
+func TestBulletin_ReadInvalid(t *testing.T) {
+	_, err := ReadBulletin(strings.NewReader(`{"Type":"ballot","Ballot":{}}`))
+	require.EqualError(t, err, "the first record must be the header: ballot")
+
+	_, err = ReadBulletin(strings.NewReader(`{"Type":"header","Header":{"Version":99}}`))
+	require.EqualError(t, err, "unsupported version: 99")
+
+	_, err = ReadBulletin(strings.NewReader(`{"Type":"header","Header":{"Version":0}}`))
+	require.EqualError(t, err, "unsupported version: 0")
+
+	_, err = ReadBulletin(strings.NewReader(`{"Type":"header","Header":{"Version":1}}
+{"Type":"ballot","Ballot":{"Index":3}}`))
+	require.EqualError(t, err, "invalid ballot record: unexpected index: 3 != 0")
+}
@@ -0,0 +1,220 @@
+package types
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// CatalogPrefix prefixes all the keys of the election catalog.
+	CatalogPrefix = "catalog:"
+
+	// CatalogCountKey is the key at which the number of entries of the catalog
+	// is stored.
+	CatalogCountKey = CatalogPrefix + "count"
+
+	// LegacyElectionsKey is the key at which the IDs of the elections were
+	// listed before the catalog. The list is moved to the catalog by the first
+	// transaction that uses it.
+	LegacyElectionsKey = "ElectionsMetadataKey"
+)
+
+// CatalogEntry is the summary of an election stored in the catalog. It allows
+// listing the elections without reading each of them.
+type CatalogEntry struct {
+	// ElectionID is the hex-encoded ID of the election
+	ElectionID string
+	Title      string
+	Status     Status
+	AdminID    string
+
+	// Pubkey is the hex-encoded public key of the election, if already set
+	Pubkey string `json:",omitempty"`
+
+	// Deleted is set when the election has been deleted. The entry is kept so
+	// that the position of the other entries doesn't change.
+	Deleted bool `json:",omitempty"`
+}
+
+// NewCatalogEntry returns the catalog entry of the election.
+func NewCatalogEntry(election Election) (CatalogEntry, error) {
+	entry := CatalogEntry{
+		ElectionID: election.ElectionID,
+		Title:      election.Configuration.MainTitle,
+		Status:     election.Status,
+		AdminID:    election.AdminID,
+	}
+
+	if election.Pubkey != nil {
+		pubkeyBuf, err := election.Pubkey.MarshalBinary()
+		if err != nil {
+			return entry, xerrors.Errorf("failed to marshal pubkey: %v", err)
+		}
+
+		entry.Pubkey = hex.EncodeToString(pubkeyBuf)
+	}
+
+	return entry, nil
+}
+
+// Matches returns true if the entry satisfies the filter. Deleted entries
+// never match.
+func (c CatalogEntry) Matches(filter CatalogFilter) bool {
+	if c.Deleted {
+		return false
+	}
+
+	if filter.Status != nil && c.Status != *filter.Status {
+		return false
+	}
+
+	if filter.AdminID != "" && c.AdminID != filter.AdminID {
+		return false
+	}
+
+	return strings.Contains(strings.ToLower(c.Title), strings.ToLower(filter.Title))
+}
+
+// CatalogFilter selects entries of the catalog. Empty fields match everything.
+type CatalogFilter struct {
+	Status  *Status
+	AdminID string
+	// Title is a case-insensitive substring of the title
+	Title string
+}
+
+// CatalogKey returns the key of the index-th entry of the catalog.
+func CatalogKey(index uint64) []byte {
+	key := make([]byte, len(CatalogPrefix)+8)
+
+	copy(key, CatalogPrefix)
+	binary.BigEndian.PutUint64(key[len(CatalogPrefix):], index)
+
+	return key
+}
+
+// EncodeCatalogCount returns the value stored at CatalogCountKey.
+func EncodeCatalogCount(count uint64) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, count)
+
+	return buf
+}
+
+// DecodeCatalogCount parses the value stored at CatalogCountKey. An empty
+// value means that the catalog is empty.
+func DecodeCatalogCount(buf []byte) (uint64, error) {
+	if len(buf) == 0 {
+		return 0, nil
+	}
+
+	if len(buf) != 8 {
+		return 0, xerrors.Errorf("invalid catalog count length: %d", len(buf))
+	}
+
+	return binary.BigEndian.Uint64(buf), nil
+}
+
+// EncodeCatalogEntry returns the value stored at the key of the entry.
+func EncodeCatalogEntry(entry CatalogEntry) ([]byte, error) {
+	buf, err := json.Marshal(entry)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal catalog entry: %v", err)
+	}
+
+	return buf, nil
+}
+
+// DecodeCatalogEntry parses the value stored at the key of an entry.
+func DecodeCatalogEntry(buf []byte) (CatalogEntry, error) {
+	var entry CatalogEntry
+
+	err := json.Unmarshal(buf, &entry)
+	if err != nil {
+		return entry, xerrors.Errorf("failed to unmarshal catalog entry: %v", err)
+	}
+
+	return entry, nil
+}
+
+// legacyElections is the JSON value stored at LegacyElectionsKey.
+type legacyElections struct {
+	ElectionsIDs []string
+}
+
+// DecodeLegacyElectionIDs parses the value stored at LegacyElectionsKey. An
+// empty value means that there is nothing to migrate.
+func DecodeLegacyElectionIDs(buf []byte) ([]string, error) {
+	if len(buf) == 0 {
+		return nil, nil
+	}
+
+	var legacy legacyElections
+
+	err := json.Unmarshal(buf, &legacy)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to unmarshal legacy elections: %v", err)
+	}
+
+	return legacy.ElectionsIDs, nil
+}
+
+// LegacyCatalogEntries returns the catalog entries of the elections listed at
+// LegacyElectionsKey, for a chain whose catalog is not migrated yet. get
+// returns the value stored at a key. Elections that don't exist anymore are
+// ignored.
+func LegacyCatalogEntries(ctx serde.Context, electionFac serde.Factory,
+	get func(key []byte) ([]byte, error)) ([]CatalogEntry, error) {
+
+	legacyBuf, err := get([]byte(LegacyElectionsKey))
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get legacy elections: %v", err)
+	}
+
+	electionIDs, err := DecodeLegacyElectionIDs(legacyBuf)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode legacy elections: %v", err)
+	}
+
+	entries := make([]CatalogEntry, 0, len(electionIDs))
+
+	for _, electionIDHex := range electionIDs {
+		electionID, err := hex.DecodeString(electionIDHex)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to decode electionID: %v", err)
+		}
+
+		electionBuf, err := get(electionID)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to get election %q: %v", electionIDHex, err)
+		}
+
+		if len(electionBuf) == 0 {
+			continue
+		}
+
+		message, err := electionFac.Deserialize(ctx, electionBuf)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to deserialize election: %v", err)
+		}
+
+		election, ok := message.(Election)
+		if !ok {
+			return nil, xerrors.Errorf("wrong message type: %T", message)
+		}
+
+		entry, err := NewCatalogEntry(election)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to create catalog entry: %v", err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
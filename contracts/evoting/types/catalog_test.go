@@ -0,0 +1,67 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogEntry_Matches(t *testing.T) {
+	entry := CatalogEntry{
+		Title:   "Student Council Election",
+		Status:  Open,
+		AdminID: "aa",
+	}
+
+	open := Open
+	closed := Closed
+
+	require.True(t, entry.Matches(CatalogFilter{}))
+	require.True(t, entry.Matches(CatalogFilter{Status: &open, AdminID: "aa"}))
+	require.True(t, entry.Matches(CatalogFilter{Title: "council"}))
+
+	require.False(t, entry.Matches(CatalogFilter{Status: &closed}))
+	require.False(t, entry.Matches(CatalogFilter{AdminID: "bb"}))
+	require.False(t, entry.Matches(CatalogFilter{Title: "board"}))
+
+	entry.Deleted = true
+	require.False(t, entry.Matches(CatalogFilter{}))
+}
+
+func TestCatalog_Encoding(t *testing.T) {
+	require.Len(t, CatalogKey(1), len(CatalogPrefix)+8)
+	require.NotEqual(t, CatalogKey(1), CatalogKey(256))
+
+	count, err := DecodeCatalogCount(nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), count)
+
+	count, err = DecodeCatalogCount(EncodeCatalogCount(42))
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), count)
+
+	_, err = DecodeCatalogCount([]byte{1})
+	require.EqualError(t, err, "invalid catalog count length: 1")
+
+	entry := CatalogEntry{ElectionID: "abcd", Title: "title", Status: Closed}
+
+	buf, err := EncodeCatalogEntry(entry)
+	require.NoError(t, err)
+
+	decoded, err := DecodeCatalogEntry(buf)
+	require.NoError(t, err)
+	require.Equal(t, entry, decoded)
+}
+
+func TestCatalog_DecodeLegacyElectionIDs(t *testing.T) {
+	electionIDs, err := DecodeLegacyElectionIDs(nil)
+	require.NoError(t, err)
+	require.Empty(t, electionIDs)
+
+	electionIDs, err = DecodeLegacyElectionIDs([]byte(`{"ElectionsIDs":["aa","bb"]}`))
+	require.NoError(t, err)
+	require.Equal(t, []string{"aa", "bb"}, electionIDs)
+
+	_, err = DecodeLegacyElectionIDs([]byte("{"))
+	require.Contains(t, err.Error(), "failed to unmarshal legacy elections")
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"io"
 
@@ -66,6 +67,19 @@ func (c Ciphervote) FingerPrint(writer io.Writer) error {
 	return nil
 }
 
+// Receipt returns the SHA256 hash of the fingerprint of the ciphervote. It is
+// given to the voter to check that the ballot was recorded as cast.
+func (c Ciphervote) Receipt() ([]byte, error) {
+	h := sha256.New()
+
+	err := c.FingerPrint(h)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get fingerprint: %v", err)
+	}
+
+	return h.Sum(nil), nil
+}
+
 // GetElGPairs returns corresponding kyber.Points from the ciphertexts
 func (c Ciphervote) GetElGPairs() (ks []kyber.Point, cs []kyber.Point) {
 	ks = make([]kyber.Point, len(c))
@@ -0,0 +1,124 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"golang.org/x/xerrors"
+)
+
+// ClockKey is the key at which the time reported by the nodes is stored, see
+// REPORT_TIME.
+const ClockKey = "evoting:clock"
+
+// TimeReport is the last time reported by a node.
+type TimeReport struct {
+	// PublicKey is the public key of the node in the roster of the chain
+	PublicKey []byte
+	// Time is a unix timestamp in seconds
+	Time int64
+}
+
+// Clock is the time agreed on the chain. It is built from the time reported by
+// the nodes so that the transactions are executed against the same time on
+// every node, whatever its own clock, and when the blocks are replayed.
+type Clock struct {
+	Reports []TimeReport
+}
+
+// Report updates the time reported by the node. A node can only move its time
+// forward.
+func (c *Clock) Report(publicKey []byte, t int64) error {
+	if t <= 0 {
+		return xerrors.Errorf("invalid time: %d", t)
+	}
+
+	for i, report := range c.Reports {
+		if !bytes.Equal(report.PublicKey, publicKey) {
+			continue
+		}
+
+		if t <= report.Time {
+			return xerrors.Errorf("the time must be after the last report: %d <= %d",
+				t, report.Time)
+		}
+
+		c.Reports[i].Time = t
+
+		return nil
+	}
+
+	c.Reports = append(c.Reports, TimeReport{PublicKey: publicKey, Time: t})
+
+	return nil
+}
+
+// Time returns the time agreed by the roster, or the unix epoch if not enough
+// nodes of the roster reported their time. It is the MinThreshold(n)-th latest
+// time reported by the members of the roster: as at least one honest node
+// reported a time as late, the faulty nodes can't move the time forward, nor
+// prevent it from moving forward by not reporting.
+func (c Clock) Time(roster authority.Authority) (time.Time, error) {
+	if roster == nil {
+		return time.Unix(0, 0), nil
+	}
+
+	times := []int64{}
+
+	iter := roster.PublicKeyIterator()
+
+	for iter.HasNext() {
+		key, err := iter.GetNext().MarshalBinary()
+		if err != nil {
+			return time.Time{}, xerrors.Errorf("failed to marshal public key: %v", err)
+		}
+
+		for _, report := range c.Reports {
+			if bytes.Equal(report.PublicKey, key) {
+				times = append(times, report.Time)
+				break
+			}
+		}
+	}
+
+	k := MinThreshold(roster.Len())
+	if len(times) < k {
+		return time.Unix(0, 0), nil
+	}
+
+	sort.Slice(times, func(i, j int) bool {
+		return times[i] > times[j]
+	})
+
+	return time.Unix(times[k-1], 0), nil
+}
+
+// EncodeClock returns the value stored at ClockKey.
+func EncodeClock(clock Clock) ([]byte, error) {
+	buf, err := json.Marshal(clock)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal clock: %v", err)
+	}
+
+	return buf, nil
+}
+
+// DecodeClock parses the value stored at ClockKey. An empty value means that
+// no time has been reported yet.
+func DecodeClock(buf []byte) (Clock, error) {
+	var clock Clock
+
+	if len(buf) == 0 {
+		return clock, nil
+	}
+
+	err := json.Unmarshal(buf, &clock)
+	if err != nil {
+		return clock, xerrors.Errorf("failed to unmarshal clock: %v", err)
+	}
+
+	return clock, nil
+}
@@ -0,0 +1,122 @@
+package types
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/crypto/bls"
+	"go.dedis.ch/dela/mino"
+)
+
+func TestClock_Report(t *testing.T) {
+	clock := Clock{}
+
+	require.NoError(t, clock.Report([]byte("A"), 100))
+	require.NoError(t, clock.Report([]byte("B"), 50))
+	require.NoError(t, clock.Report([]byte("A"), 101))
+	require.Len(t, clock.Reports, 2)
+
+	err := clock.Report([]byte("A"), 101)
+	require.EqualError(t, err, "the time must be after the last report: 101 <= 101")
+
+	err = clock.Report([]byte("C"), 0)
+	require.EqualError(t, err, "invalid time: 0")
+}
+
+func TestClock_Time(t *testing.T) {
+	roster, keys := makeClockRoster(t, 4)
+
+	clock := Clock{}
+
+	// a single report is not enough for a roster of 4 nodes
+	require.NoError(t, clock.Report(keys[0], 1000))
+
+	now, err := clock.Time(roster)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), now.Unix())
+
+	// a faulty node can't move the time forward on its own
+	require.NoError(t, clock.Report(keys[1], 100))
+
+	now, err = clock.Time(roster)
+	require.NoError(t, err)
+	require.Equal(t, time.Unix(100, 0), now)
+
+	require.NoError(t, clock.Report(keys[2], 150))
+
+	now, err = clock.Time(roster)
+	require.NoError(t, err)
+	require.Equal(t, time.Unix(150, 0), now)
+
+	// the reports of the nodes outside of the roster are ignored
+	require.NoError(t, clock.Report([]byte("outsider"), 2000))
+
+	now, err = clock.Time(roster)
+	require.NoError(t, err)
+	require.Equal(t, time.Unix(150, 0), now)
+
+	now, err = clock.Time(nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), now.Unix())
+}
+
+func TestClock_Encoding(t *testing.T) {
+	clock, err := DecodeClock(nil)
+	require.NoError(t, err)
+	require.Empty(t, clock.Reports)
+
+	require.NoError(t, clock.Report([]byte("A"), 100))
+
+	buf, err := EncodeClock(clock)
+	require.NoError(t, err)
+
+	decoded, err := DecodeClock(buf)
+	require.NoError(t, err)
+	require.Equal(t, clock, decoded)
+
+	_, err = DecodeClock([]byte("{"))
+	require.EqualError(t, err, "failed to unmarshal clock: unexpected end of JSON input")
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+func makeClockRoster(t *testing.T, n int) (authority.Authority, [][]byte) {
+	addrs := make([]mino.Address, n)
+	pubkeys := make([]crypto.PublicKey, n)
+	keys := make([][]byte, n)
+
+	for i := range addrs {
+		signer := bls.NewSigner()
+
+		key, err := signer.GetPublicKey().MarshalBinary()
+		require.NoError(t, err)
+
+		addrs[i] = clockAddress(i)
+		pubkeys[i] = signer.GetPublicKey()
+		keys[i] = key
+	}
+
+	return authority.New(addrs, pubkeys), keys
+}
+
+// clockAddress is the address of a node of the roster.
+//
+// - implements mino.Address
+type clockAddress int
+
+func (a clockAddress) Equal(other mino.Address) bool {
+	return other == a
+}
+
+func (a clockAddress) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+func (a clockAddress) String() string {
+	return fmt.Sprintf("node%d", int(a))
+}
@@ -0,0 +1,184 @@
+package types
+
+import (
+	"fmt"
+)
+
+// Condition makes a question depend on the answer given to another question,
+// for example "if you chose 'Other', explain".
+type Condition struct {
+	// QuestionID is the ID of the question the condition depends on
+	QuestionID ID
+	// Choices contains the indexes of the choices of the question. The
+	// condition is met if at least one of them is answered: selected, ranked,
+	// filled, scored or given points depending on the type of the question.
+	Choices []int
+}
+
+// questions returns all the questions of the subject and its sub-subjects.
+func (s *Subject) questions() []Question {
+	questions := make([]Question, 0)
+
+	for _, selection := range s.Selects {
+		questions = append(questions, selection)
+	}
+
+	for _, rank := range s.Ranks {
+		questions = append(questions, rank)
+	}
+
+	for _, text := range s.Texts {
+		questions = append(questions, text)
+	}
+
+	for _, score := range s.Scores {
+		questions = append(questions, score)
+	}
+
+	for _, cumulative := range s.Cumulatives {
+		questions = append(questions, cumulative)
+	}
+
+	for _, subject := range s.Subjects {
+		questions = append(questions, subject.questions()...)
+	}
+
+	return questions
+}
+
+// questions returns all the questions of the configuration.
+func (c *Configuration) questions() []Question {
+	questions := make([]Question, 0)
+
+	for _, subject := range c.Scaffold {
+		questions = append(questions, subject.questions()...)
+	}
+
+	return questions
+}
+
+// isValidConditions verifies that the conditions refer to existing choices and
+// that no question depends on itself, directly or not.
+func (c *Configuration) isValidConditions() bool {
+	questions := c.questions()
+
+	byID := make(map[ID]Question, len(questions))
+	for _, q := range questions {
+		byID[q.GetID()] = q
+	}
+
+	for _, q := range questions {
+		condition := q.GetCondition()
+		if condition == nil {
+			continue
+		}
+
+		dependency, found := byID[condition.QuestionID]
+		if !found || len(condition.Choices) == 0 {
+			return false
+		}
+
+		for _, choice := range condition.Choices {
+			if choice < 0 || choice >= dependency.GetChoicesLength() {
+				return false
+			}
+		}
+
+		// follow the dependencies: a chain longer than the number of questions
+		// contains a cycle
+		current := condition
+		for steps := 0; current != nil; steps++ {
+			if current.QuestionID == q.GetID() || steps > len(questions) {
+				return false
+			}
+
+			next, found := byID[current.QuestionID]
+			if !found {
+				return false
+			}
+
+			current = next.GetCondition()
+		}
+	}
+
+	return true
+}
+
+// IsApplicable returns true if the question applies to the ballot, that is it
+// has no condition or its condition is met.
+func (b *Ballot) IsApplicable(q Question) bool {
+	return b.meets(q.GetCondition())
+}
+
+// meets returns true if the condition is nil or met by the ballot.
+func (b *Ballot) meets(condition *Condition) bool {
+	if condition == nil {
+		return true
+	}
+
+	for _, choice := range condition.Choices {
+		if b.isAnswered(condition.QuestionID, choice) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isAnswered returns true if the choice of the question has been answered on
+// the ballot.
+func (b *Ballot) isAnswered(id ID, choice int) bool {
+	for i, qid := range b.SelectResultIDs {
+		if qid == id && choice < len(b.SelectResult[i]) {
+			return b.SelectResult[i][choice]
+		}
+	}
+
+	for i, qid := range b.RankResultIDs {
+		if qid == id && choice < len(b.RankResult[i]) {
+			return b.RankResult[i][choice] >= 0
+		}
+	}
+
+	for i, qid := range b.TextResultIDs {
+		if qid == id && choice < len(b.TextResult[i]) {
+			return b.TextResult[i][choice] != ""
+		}
+	}
+
+	for i, qid := range b.ScoreResultIDs {
+		if qid == id && choice < len(b.ScoreResult[i]) {
+			return b.ScoreResult[i][choice] >= 0
+		}
+	}
+
+	for i, qid := range b.CumulativeResultIDs {
+		if qid == id && choice < len(b.CumulativeResult[i]) {
+			return b.CumulativeResult[i][choice] > 0
+		}
+	}
+
+	return false
+}
+
+// checkConditions verifies that the conditional questions that don't apply to
+// the ballot are left empty, and that the ones that apply have enough answers.
+func (b *Ballot) checkConditions(configuration Configuration,
+	answered map[ID]uint) error {
+
+	for _, q := range configuration.questions() {
+		if q.GetCondition() == nil {
+			continue
+		}
+
+		if !b.IsApplicable(q) {
+			if answered[q.GetID()] > 0 {
+				return fmt.Errorf("question %s must be skipped", q.GetID())
+			}
+		} else if answered[q.GetID()] < q.GetMinN() {
+			return fmt.Errorf("question %s has not enough selected answers", q.GetID())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package types
+
+import (
+	"bytes"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	"golang.org/x/xerrors"
+)
+
+// DKGResult is the result of the DKG of an election as registered by one of
+// its participants, see REGISTER_DKG.
+type DKGResult struct {
+	// PublicKey is the public key, in the roster, of the node that registered
+	// the result
+	PublicKey []byte
+
+	// Commits are the marshalled commitments of the public polynomial of the
+	// DKG. The first one is the public key of the DKG and their number is the
+	// threshold of the DKG.
+	Commits [][]byte
+
+	// Participants are the addresses of the participants of the DKG, as text,
+	// in the order of their index in the DKG
+	Participants [][]byte
+}
+
+// Equal returns true if both results describe the same DKG, whoever registered
+// them.
+func (r DKGResult) Equal(other DKGResult) bool {
+	return equalItems(r.Commits, other.Commits) &&
+		equalItems(r.Participants, other.Participants)
+}
+
+// Keys returns the public key of the DKG and the verification keys of the
+// participants, in the order of their index.
+func (r DKGResult) Keys() (kyber.Point, []kyber.Point, error) {
+	if len(r.Commits) == 0 {
+		return nil, nil, xerrors.New("the result has no commits")
+	}
+
+	commits := make([]kyber.Point, len(r.Commits))
+
+	for i, buf := range r.Commits {
+		commits[i] = suite.Point()
+
+		err := commits[i].UnmarshalBinary(buf)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("failed to unmarshal commit %d: %v", i, err)
+		}
+	}
+
+	poly := share.NewPubPoly(suite, nil, commits)
+
+	verificationKeys := make([]kyber.Point, len(r.Participants))
+	for i := range verificationKeys {
+		verificationKeys[i] = poly.Eval(i).V
+	}
+
+	return commits[0], verificationKeys, nil
+}
+
+// HasParticipant returns true if the address is one of the participants.
+func (r DKGResult) HasParticipant(addr []byte) bool {
+	for _, participant := range r.Participants {
+		if bytes.Equal(participant, addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AgreedDKG returns the first result registered by at least MinThreshold(n) of
+// its participants, for a roster of n nodes. As one of them at least is honest
+// and only registers the DKG it certified, the faulty nodes can't make the
+// election run on another DKG. It returns false if no result is agreed yet.
+func AgreedDKG(results []DKGResult, n int) (DKGResult, bool) {
+	quorum := MinThreshold(n)
+
+	for _, result := range results {
+		count := 0
+
+		for _, other := range results {
+			if result.Equal(other) {
+				count++
+			}
+		}
+
+		if count >= quorum {
+			return result, true
+		}
+	}
+
+	return DKGResult{}, false
+}
+
+func equalItems(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
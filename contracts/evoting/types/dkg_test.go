@@ -0,0 +1,71 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/share"
+)
+
+func TestDKGResult_Keys(t *testing.T) {
+	priPoly := share.NewPriPoly(suite, 2, nil, suite.RandomStream())
+	pubPoly := priPoly.Commit(nil)
+	_, commits := pubPoly.Info()
+
+	result := DKGResult{
+		Commits:      make([][]byte, len(commits)),
+		Participants: [][]byte{[]byte("A"), []byte("B"), []byte("C")},
+	}
+
+	for i, commit := range commits {
+		buf, err := commit.MarshalBinary()
+		require.NoError(t, err)
+
+		result.Commits[i] = buf
+	}
+
+	pubkey, keys, err := result.Keys()
+	require.NoError(t, err)
+	require.True(t, pubkey.Equal(pubPoly.Commit()))
+	require.Len(t, keys, 3)
+
+	for i, key := range keys {
+		require.True(t, key.Equal(pubPoly.Eval(i).V))
+	}
+
+	_, _, err = DKGResult{}.Keys()
+	require.EqualError(t, err, "the result has no commits")
+
+	_, _, err = DKGResult{Commits: [][]byte{[]byte("bad")}}.Keys()
+	require.Regexp(t, "^failed to unmarshal commit 0: ", err)
+}
+
+func TestAgreedDKG(t *testing.T) {
+	participants := [][]byte{[]byte("A"), []byte("B"), []byte("C"), []byte("D")}
+
+	good := DKGResult{Commits: [][]byte{[]byte("good")}, Participants: participants}
+	bad := DKGResult{Commits: [][]byte{[]byte("bad")}, Participants: participants}
+
+	registered := func(result DKGResult, key string) DKGResult {
+		result.PublicKey = []byte(key)
+		return result
+	}
+
+	_, agreed := AgreedDKG(nil, 4)
+	require.False(t, agreed)
+
+	// a single node can't make the election run on its DKG
+	results := []DKGResult{registered(bad, "A"), registered(good, "B")}
+
+	_, agreed = AgreedDKG(results, 4)
+	require.False(t, agreed)
+
+	results = append(results, registered(good, "C"))
+
+	result, agreed := AgreedDKG(results, 4)
+	require.True(t, agreed)
+	require.True(t, result.Equal(good))
+	require.False(t, result.Equal(bad))
+	require.True(t, result.HasParticipant([]byte("D")))
+	require.False(t, result.HasParticipant([]byte("E")))
+}
@@ -1,8 +1,12 @@
 package types
 
 import (
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
 	"io"
+	"time"
 
 	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
 	ctypes "go.dedis.ch/dela/core/ordering/cosipbft/types"
@@ -37,6 +41,9 @@ const (
 	ResultAvailable Status = 5
 	// Canceled is when the election has been cancel
 	Canceled Status = 6
+	// QuorumNotReached is when the election ended without results because
+	// the participation required by its quorum was not reached
+	QuorumNotReached Status = 7
 )
 
 // electionFormat contains the supported formats for the election. Right now
@@ -61,9 +68,42 @@ type Election struct {
 	// the election
 	ElectionID string
 
+	// CatalogIndex is the position of the election in the catalog
+	CatalogIndex uint64
+
+	// AdminID is the hex-encoded public key of the admin of the election. The
+	// admin must sign the commands that change the lifecycle of the election.
+	AdminID string
+
+	// Admins contains all the admins of the election, including AdminID, and
+	// the number of approvals required by sensitive actions.
+	Admins AdminSet
+
+	// PendingActions are the sensitive actions waiting for approvals
+	PendingActions []PendingAction
+
+	// AdminNonce is the number of commands authorized by the admins so far.
+	// It is part of the message signed by the admins, see AdminMessage, so
+	// that a signature can only be used once.
+	AdminNonce uint64
+
 	Status Status
 	Pubkey kyber.Point
 
+	// VerificationKeys are the public keys of the private shares of the DKG:
+	// VerificationKeys[i] is x_i*G for the node at index i. They are set with
+	// Pubkey and used to verify the pubshares.
+	VerificationKeys []kyber.Point
+
+	// DKGResults are the results of the DKG registered by its participants,
+	// until the election is opened or its roster updated with the one they
+	// agree on, see AgreedDKG.
+	DKGResults []DKGResult
+
+	// StatusReason explains the status, for example why the quorum is not
+	// reached
+	StatusReason string
+
 	// BallotSize represents the total size in bytes of one ballot. It is used
 	// to pad smaller ballots such that all  ballots cast have the same size
 	BallotSize int
@@ -71,20 +111,39 @@ type Election struct {
 	// Suffragia is a map from User ID to their encrypted ballot
 	Suffragia Suffragia
 
+	// Electorate contains the hashed IDs of the users allowed to vote
+	Electorate Electorate
+
 	// ShuffleInstances is all the shuffles, along with their proof and identity
 	// of shuffler.
 	ShuffleInstances []ShuffleInstance
 
-	// ShuffleThreshold is set based on the roster. We save it so we do not have
-	// to compute it based on the roster each time we need it.
+	// ShuffleThreshold is set based on the roster, unless it is configured
+	// when the election is created. We save it so we do not have to compute it
+	// based on the roster each time we need it.
 	ShuffleThreshold int
 
+	// DecryptionThreshold is the t of the t-of-n DKG: the number of pubshares
+	// required to decrypt the ballots. It is set when the election is created
+	// and the DKG runs with it. It is 0 for the elections created before it
+	// existed, which are decrypted with all the submitted pubshares.
+	DecryptionThreshold int
+
+	// RandomVectorVersion is the derivation of the random vectors of the
+	// shuffles. It is set when the election is created: the elections stored
+	// before it existed use RandomVectorLegacy.
+	RandomVectorVersion RandomVectorVersion
+
 	// PubsharesUnits is an array containing all the submission of pubShares.
 	// Each node submits its share to its personal index from the DKG service.
 	PubsharesUnits PubsharesUnits
 
 	DecryptedBallots []Ballot
 
+	// Tally contains the aggregated results, computed once the ballots are
+	// decrypted.
+	Tally Tally
+
 	// roster is set when the election is created based on the current
 	// roster of the node stored in the global state. The roster will not change
 	// during an election and will be used for DKG and Neff. Its type is
@@ -147,10 +206,81 @@ func (e *Election) ChunksPerBallot() int {
 	return e.BallotSize/29 + 1
 }
 
+// CheckVote verifies that the vote can be cast at the given time: the election
+// is open, and the ballot has the expected length and is proved by the voter.
+// The eligibility of the voter is stored under its own key, see VoterKey, and
+// must be checked by the caller.
+func (e *Election) CheckVote(vote CastVote, now time.Time) error {
+	if e.Status != Open {
+		return xerrors.Errorf("the election is not open, current status: %d", e.Status)
+	}
+
+	schedule := e.Configuration.Schedule
+
+	if !schedule.HasStarted(now) || schedule.HasEnded(now) {
+		return xerrors.Errorf("the election does not accept ballots at %d, "+
+			"schedule is [%d, %d[", now.Unix(), schedule.Start, schedule.End)
+	}
+
+	if len(vote.Ballot) != e.ChunksPerBallot() {
+		return xerrors.Errorf("the ballot has unexpected length: %d != %d",
+			len(vote.Ballot), e.ChunksPerBallot())
+	}
+
+	err := vote.Proof.Verify(e.ElectionID, vote.UserID, vote.Ballot)
+	if err != nil {
+		return xerrors.Errorf("invalid ballot proof: %v", err)
+	}
+
+	return nil
+}
+
+// PubsharesThreshold returns the number of pubshares submissions required
+// before the ballots can be decrypted.
+func (e *Election) PubsharesThreshold() int {
+	if e.DecryptionThreshold == 0 {
+		return e.ShuffleThreshold
+	}
+
+	return e.DecryptionThreshold
+}
+
+// MinThreshold returns the smallest safe threshold for a roster of n nodes.
+// Up to f = (n-1)/3 nodes can be faulty, so that a threshold of f+1 ensures
+// that at least one honest node shuffles the ballots, and that the faulty
+// nodes can't decrypt the ballots on their own.
+func MinThreshold(n int) int {
+	return (n-1)/3 + 1
+}
+
+// MaxThreshold returns the largest threshold that the honest nodes can reach
+// on their own for a roster of n nodes, so that the faulty nodes can't block
+// the shuffle or the decryption.
+func MaxThreshold(n int) int {
+	return n - MinThreshold(n) + 1
+}
+
 // RandomVector is a slice of kyber.Scalar (encoded) which is used to prove
 // and verify the proof of a shuffle
 type RandomVector [][]byte
 
+// RandomVectorVersion defines how the random vector of a shuffle is derived
+type RandomVectorVersion uint16
+
+const (
+	// RandomVectorLegacy derives the random vector with math/rand seeded with
+	// the first 8 bytes of the hash of the shuffle. It is not
+	// cryptographically safe and is only kept to verify the elections
+	// created before RandomVectorXOF.
+	RandomVectorLegacy RandomVectorVersion = 0
+	// RandomVectorXOF derives the random vector with the XOF of the suite,
+	// seeded with the full hash of the shuffle, the election ID and the round.
+	RandomVectorXOF RandomVectorVersion = 1
+
+	// CurrentRandomVectorVersion is the version used by the new elections
+	CurrentRandomVectorVersion = RandomVectorXOF
+)
+
 // Unmarshal returns the native type of a random vector
 func (r RandomVector) Unmarshal() ([]kyber.Scalar, error) {
 	e := make([]kyber.Scalar, len(r))
@@ -194,12 +324,107 @@ type ShuffleInstance struct {
 
 	// ShufflerPublicKey is the key of the node who made the given shuffle.
 	ShufflerPublicKey []byte
+
+	// Signature is the signature of the shuffle by the shuffler
+	Signature []byte
 }
 
 // Configuration contains the configuration of a new poll.
 type Configuration struct {
 	MainTitle string
 	Scaffold  []Subject
+
+	// Schedule optionally defines the time window during which the election
+	// is open.
+	Schedule Schedule
+
+	// Quorum optionally defines the participation required for the results to
+	// be released.
+	Quorum Quorum
+}
+
+// Schedule defines when an election opens and closes. Times are unix
+// timestamps in seconds. A zero value means that the bound is not set and the
+// corresponding transition must be triggered manually.
+type Schedule struct {
+	Start int64
+	End   int64
+}
+
+// IsSet returns true if at least one of the bounds is defined.
+func (s Schedule) IsSet() bool {
+	return s.Start != 0 || s.End != 0
+}
+
+// HasStarted returns true if the start time is reached at t, or if no start
+// time is defined.
+func (s Schedule) HasStarted(t time.Time) bool {
+	return s.Start == 0 || t.Unix() >= s.Start
+}
+
+// HasEnded returns true if an end time is defined and is reached at t.
+func (s Schedule) HasEnded(t time.Time) bool {
+	return s.End != 0 && t.Unix() >= s.End
+}
+
+// IsValid returns true if the bounds are coherent.
+func (s Schedule) IsValid() bool {
+	if s.Start < 0 || s.End < 0 {
+		return false
+	}
+
+	return s.Start == 0 || s.End == 0 || s.Start < s.End
+}
+
+// Quorum defines the participation required for the results of an election to
+// be released. A zero value disables the corresponding rule.
+type Quorum struct {
+	// MinBallots is the minimum number of ballots cast
+	MinBallots uint
+	// MinTurnout is the minimum percentage of the registered voters who cast a
+	// ballot. It requires a restricted electorate.
+	MinTurnout uint
+	// MinValidBallots is the minimum number of valid ballots once decrypted
+	MinValidBallots uint
+}
+
+// IsValid returns true if the turnout is a percentage.
+func (q Quorum) IsValid() bool {
+	return q.MinTurnout <= 100
+}
+
+// CheckCast returns an error if the ballots cast don't reach the quorum.
+func (q Quorum) CheckCast(cast int, electorate Electorate) error {
+	if uint(cast) < q.MinBallots {
+		return xerrors.Errorf("%d ballots cast, at least %d required", cast, q.MinBallots)
+	}
+
+	if q.MinTurnout == 0 {
+		return nil
+	}
+
+	if !electorate.Restricted {
+		return xerrors.Errorf("a turnout quorum requires a registered electorate")
+	}
+
+	if uint(cast)*100 < q.MinTurnout*uint(electorate.Len()) {
+		return xerrors.Errorf("turnout of %d/%d voters, at least %d%% required",
+			cast, electorate.Len(), q.MinTurnout)
+	}
+
+	return nil
+}
+
+// CheckValid returns an error if the valid ballots of the tally don't reach
+// the quorum.
+func (q Quorum) CheckValid(tally Tally) error {
+	valid := tally.NumBallots - tally.InvalidBallots
+
+	if valid < q.MinValidBallots {
+		return xerrors.Errorf("%d valid ballots, at least %d required", valid, q.MinValidBallots)
+	}
+
+	return nil
 }
 
 // MaxBallotSize returns the maximum number of bytes required to store a ballot
@@ -228,6 +453,10 @@ func (c *Configuration) GetQuestion(ID ID) Question {
 // IsValid returns true if and only if the whole configuration is coherent and
 // valid.
 func (c *Configuration) IsValid() bool {
+	if !c.Schedule.IsValid() || !c.Quorum.IsValid() {
+		return false
+	}
+
 	// serves as a set to check each ID is unique
 	uniqueIDs := make(map[ID]bool)
 
@@ -237,6 +466,10 @@ func (c *Configuration) IsValid() bool {
 		}
 	}
 
+	if !c.isValidConditions() {
+		return false
+	}
+
 	// if an id is not encoded in base64
 	for id := range uniqueIDs {
 		_, err := base64.StdEncoding.DecodeString(string(id))
@@ -248,12 +481,220 @@ func (c *Configuration) IsValid() bool {
 	return true
 }
 
+// ParseAdminID returns the public key encoded in the admin ID.
+func ParseAdminID(adminID string) (kyber.Point, error) {
+	buf, err := hex.DecodeString(adminID)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode admin ID: %v", err)
+	}
+
+	pubkey := suite.Point()
+
+	err = pubkey.UnmarshalBinary(buf)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to unmarshal admin public key: %v", err)
+	}
+
+	return pubkey, nil
+}
+
+// AdminSet contains the admins of an election and the number of them that
+// must approve the sensitive actions, i.e. closing, canceling, and deleting
+// the election.
+type AdminSet struct {
+	// Keys are the hex-encoded public keys of the admins
+	Keys []string
+	// Threshold is the number of approvals required by a sensitive action
+	Threshold int
+}
+
+// Contains returns true if the key is one of the admins.
+func (a AdminSet) Contains(key string) bool {
+	for _, k := range a.Keys {
+		if k == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Verify checks that the admin set is coherent. It returns an error if a key
+// is malformed or duplicated, or if the threshold can't be reached.
+func (a AdminSet) Verify() error {
+	keys := make(map[string]struct{}, len(a.Keys))
+
+	for _, key := range a.Keys {
+		_, err := ParseAdminID(key)
+		if err != nil {
+			return xerrors.Errorf("invalid admin %q: %v", key, err)
+		}
+
+		_, found := keys[key]
+		if found {
+			return xerrors.Errorf("duplicated admin %q", key)
+		}
+
+		keys[key] = struct{}{}
+	}
+
+	if a.Threshold < 1 || a.Threshold > len(a.Keys) {
+		return xerrors.Errorf("threshold must be in [1, %d], got %d",
+			len(a.Keys), a.Threshold)
+	}
+
+	return nil
+}
+
+// PendingAction is a sensitive action proposed by an admin that is executed
+// once enough admins approved it.
+type PendingAction struct {
+	// ID is the hex-encoded ID of the transaction that proposed the action
+	ID string
+	// Command is the command executed once the action is approved
+	Command string
+	// Hash is the hex-encoded hash of the command and of its payload, see
+	// ActionHash. The approvals sign it with the ID, see ApprovalPayload.
+	Hash string
+	// Approvals are the keys of the admins that approved the action, including
+	// the proposer.
+	Approvals []string
+}
+
+// ActionHash returns the hash of the command of an action and of its payload,
+// which identifies what is executed once the action is approved.
+func ActionHash(command string, payload []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte(command))
+	h.Write(payload)
+
+	return h.Sum(nil)
+}
+
+// ApprovalPayload returns the payload of the admin message that approves the
+// action: its ID together with its hash, so that an approval can only be used
+// for the action as it was proposed.
+func (p PendingAction) ApprovalPayload() []byte {
+	h := sha256.New()
+	h.Write([]byte(p.ID))
+	h.Write([]byte(p.Hash))
+
+	return h.Sum(nil)
+}
+
+// HasApproved returns true if the admin already approved the action.
+func (p PendingAction) HasApproved(key string) bool {
+	for _, k := range p.Approvals {
+		if k == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetPendingAction returns the index of the pending action with the given ID,
+// or -1 if it doesn't exist.
+func (e Election) GetPendingAction(actionID string) int {
+	for i, action := range e.PendingActions {
+		if action.ID == actionID {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Electorate describes the users allowed to cast a ballot in an election. User
+// IDs are hashed with HashUserID and each registered hash is stored under its
+// own key, returned by VoterKey, so that the size of the election doesn't grow
+// with the number of voters.
+type Electorate struct {
+	// Restricted is set once a list of voters has been registered. An election
+	// that never registered voters accepts ballots from any user.
+	Restricted bool
+
+	// Count is the number of registered voters.
+	Count int
+
+	// Epoch is incremented each time the electorate is replaced, which
+	// discards the voters registered before at once.
+	Epoch uint64
+}
+
+// HashUserID returns the hex-encoded hash of a user ID as stored in the
+// electorate. The election ID is used as a salt so that the same user can not
+// be linked across elections.
+func HashUserID(electionID, userID string) string {
+	h := sha256.New()
+	h.Write([]byte(electionID))
+	h.Write([]byte(userID))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Len returns the number of eligible users.
+func (e Electorate) Len() int {
+	return e.Count
+}
+
+// VoterKey returns the key under which the hashed user ID is registered in the
+// current electorate of the election.
+func (e Electorate) VoterKey(electionID []byte, hash string) []byte {
+	h := sha256.New()
+
+	h.Write([]byte("voter:"))
+	h.Write(electionID)
+
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, e.Epoch)
+	h.Write(buf)
+
+	h.Write([]byte(hash))
+
+	return h.Sum(nil)
+}
+
+// CheckVoters returns an error if one of the hashes is malformed.
+func CheckVoters(hashes []string) error {
+	for _, hash := range hashes {
+		err := checkVoterHash(hash)
+		if err != nil {
+			return xerrors.Errorf("invalid voter: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func checkVoterHash(hash string) error {
+	buf, err := hex.DecodeString(hash)
+	if err != nil {
+		return xerrors.Errorf("failed to decode hash %q: %v", hash, err)
+	}
+
+	if len(buf) != sha256.Size {
+		return xerrors.Errorf("hash %q has a wrong size: %d != %d", hash,
+			len(buf), sha256.Size)
+	}
+
+	return nil
+}
+
+// Suffragia contains the ballots cast in an election. While the election is
+// open, each ballot is stored under its own key, see BallotKey, so that casting
+// a vote doesn't depend on the number of ballots already cast. The ballots are
+// gathered here, in the order of their first cast, when the election closes.
 type Suffragia struct {
+	// Count is the number of distinct users who cast a ballot
+	Count int
+
 	UserIDs     []string
 	Ciphervotes []Ciphervote
 }
 
 // CastVote adds a new vote and its associated user or updates a user's vote.
+// It operates on the gathered ballots only.
 func (s *Suffragia) CastVote(userID string, ciphervote Ciphervote) {
 	for i, u := range s.UserIDs {
 		if u == userID {
@@ -264,6 +705,48 @@ func (s *Suffragia) CastVote(userID string, ciphervote Ciphervote) {
 
 	s.UserIDs = append(s.UserIDs, userID)
 	s.Ciphervotes = append(s.Ciphervotes, ciphervote.Copy())
+	s.Count = len(s.UserIDs)
+}
+
+// BallotKey returns the key under which the ballot of a user is stored while
+// the election is open.
+func BallotKey(electionID []byte, userID string) []byte {
+	h := sha256.New()
+
+	h.Write([]byte("ballot"))
+	h.Write(electionID)
+	h.Write([]byte(userID))
+
+	return h.Sum(nil)
+}
+
+// BallotIndexKey returns the key under which the user ID of the index-th voter
+// is stored while the election is open. It gives a deterministic order to the
+// ballots.
+func BallotIndexKey(electionID []byte, index int) []byte {
+	h := sha256.New()
+
+	h.Write([]byte("ballot-index"))
+	h.Write(electionID)
+
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(index))
+	h.Write(buf)
+
+	return h.Sum(nil)
+}
+
+// ReceiptKey returns the key under which the user ID of the ballot with the
+// given receipt is stored. The key is kept once the ballots are gathered so
+// that a voter can look up the ballot with the receipt only.
+func ReceiptKey(electionID []byte, receipt []byte) []byte {
+	h := sha256.New()
+
+	h.Write([]byte("ballot-receipt"))
+	h.Write(electionID)
+	h.Write(receipt)
+
+	return h.Sum(nil)
 }
 
 // CiphervotesFromPairs transforms two parallel lists of EGPoints to a list of
@@ -354,4 +837,8 @@ type PubsharesUnits struct {
 	// Indexes is the index of the nodes who made each corresponding
 	// PubsharesUnit
 	Indexes []int
+	// Proofs contains the proofs of each corresponding PubsharesUnit, which
+	// prove that the pubshares were computed with the private share of the
+	// node at Indexes
+	Proofs []PubshareProofsUnit
 }
@@ -0,0 +1,87 @@
+package types
+
+import (
+	"bytes"
+	"io"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/proof/dleq"
+	"golang.org/x/xerrors"
+)
+
+// PubshareProof is a DLEQ proof that a pubshare is C - x*K, where x is the
+// private share of the node whose verification key is x*G. It proves that
+// log_G(x*G) == log_K(x*K) without revealing x.
+type PubshareProof []byte
+
+// PubshareProofsUnit holds the proofs of a PubsharesUnit, 1 for each pubshare
+type PubshareProofsUnit [][]PubshareProof
+
+// Fingerprint implements serde.Fingerprinter
+func (p PubshareProofsUnit) Fingerprint(writer io.Writer) error {
+	for _, ballotProofs := range p {
+		for _, proof := range ballotProofs {
+			_, err := writer.Write(proof)
+			if err != nil {
+				return xerrors.Errorf("failed to write proof: %v", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ProvePubshare computes the pubshare of the ElGamal pair with the private
+// share of a node, along with the proof that it is correct.
+func ProvePubshare(privShare kyber.Scalar, egpair EGPair) (Pubshare, PubshareProof, error) {
+	proof, _, S, err := dleq.NewDLEQProof(suite, suite.Point().Base(), egpair.K, privShare)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("failed to create proof: %v", err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	for _, m := range []kyber.Marshaling{proof.VG, proof.VH, proof.C, proof.R} {
+		_, err = m.MarshalTo(buf)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("failed to marshal proof: %v", err)
+		}
+	}
+
+	return suite.Point().Sub(egpair.C, S), buf.Bytes(), nil
+}
+
+// VerifyPubshare checks that the pubshare of the ElGamal pair was computed
+// with the private share of the verification key.
+func VerifyPubshare(verificationKey kyber.Point, egpair EGPair, pubshare Pubshare,
+	proof PubshareProof) error {
+
+	p := dleq.Proof{
+		VG: suite.Point(),
+		VH: suite.Point(),
+		C:  suite.Scalar(),
+		R:  suite.Scalar(),
+	}
+
+	reader := bytes.NewReader(proof)
+
+	for _, m := range []kyber.Marshaling{p.VG, p.VH, p.C, p.R} {
+		_, err := m.UnmarshalFrom(reader)
+		if err != nil {
+			return xerrors.Errorf("failed to unmarshal proof: %v", err)
+		}
+	}
+
+	if reader.Len() != 0 {
+		return xerrors.Errorf("unexpected %d bytes after the proof", reader.Len())
+	}
+
+	S := suite.Point().Sub(egpair.C, pubshare)
+
+	err := p.Verify(suite, suite.Point().Base(), egpair.K, verificationKey, S)
+	if err != nil {
+		return xerrors.Errorf("proof verification failed: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPubshare_ProveVerify(t *testing.T) {
+	privShare := suite.Scalar().Pick(suite.RandomStream())
+	verificationKey := suite.Point().Mul(privShare, nil)
+
+	egpair := EGPair{
+		K: suite.Point().Pick(suite.RandomStream()),
+		C: suite.Point().Pick(suite.RandomStream()),
+	}
+
+	pubshare, proof, err := ProvePubshare(privShare, egpair)
+	require.NoError(t, err)
+
+	S := suite.Point().Mul(privShare, egpair.K)
+	require.True(t, pubshare.Equal(suite.Point().Sub(egpair.C, S)))
+
+	err = VerifyPubshare(verificationKey, egpair, pubshare, proof)
+	require.NoError(t, err)
+
+	// a share computed with another secret is rejected
+	other := suite.Point().Pick(suite.RandomStream())
+
+	err = VerifyPubshare(verificationKey, egpair, other, proof)
+	require.EqualError(t, err, "proof verification failed: invalid proof")
+
+	// a proof for another node is rejected
+	err = VerifyPubshare(other, egpair, pubshare, proof)
+	require.EqualError(t, err, "proof verification failed: invalid proof")
+
+	err = VerifyPubshare(verificationKey, egpair, pubshare, proof[:10])
+	require.Error(t, err)
+
+	err = VerifyPubshare(verificationKey, egpair, pubshare, append(proof, 0))
+	require.EqualError(t, err, "unexpected 1 bytes after the proof")
+}
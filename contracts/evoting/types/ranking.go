@@ -0,0 +1,379 @@
+package types
+
+import (
+	"sort"
+)
+
+// RankMethod defines how the winners of a Rank question are computed.
+type RankMethod string
+
+const (
+	// RankMethodNone only counts the ranks given to each choice
+	RankMethodNone RankMethod = ""
+	// RankMethodIRV is instant-runoff voting: the choice with the fewest first
+	// preferences is eliminated until one has a majority of the ballots.
+	RankMethodIRV RankMethod = "irv"
+	// RankMethodBorda gives n-1 points to the first preference, n-2 to the
+	// second, and so on.
+	RankMethodBorda RankMethod = "borda"
+	// RankMethodSchulze is the Schulze Condorcet method, based on the
+	// strongest paths between each pair of choices.
+	RankMethodSchulze RankMethod = "schulze"
+	// RankMethodSTV is the single transferable vote with the Droop quota and
+	// fractional transfers of surpluses, for multi-seat races.
+	RankMethodSTV RankMethod = "stv"
+)
+
+// STVScale is the number of units representing one vote in the scores of the
+// STV method. Integers are used so that all the nodes compute exactly the
+// same transfers.
+const STVScale = 1000000
+
+// RankOutcome is the result of a counting method applied on a Rank question.
+// Choices are designated by their index in the question.
+type RankOutcome struct {
+	Method RankMethod
+	// Winners are the elected choices, in the order they were elected
+	Winners []int
+	// Scale is the number of units representing one vote in the scores
+	Scale uint64
+	// Rounds contains the detail of each round of counting. Borda and
+	// Schulze have a single round.
+	Rounds []RankRound
+	// Pairwise contains, for Schulze, the number of ballots that prefer
+	// choice i over choice j: Pairwise[i][j].
+	Pairwise [][]uint64 `json:",omitempty"`
+	// Paths contains, for Schulze, the strength of the strongest path from
+	// choice i to choice j: Paths[i][j].
+	Paths [][]uint64 `json:",omitempty"`
+}
+
+// RankRound is a round of counting.
+type RankRound struct {
+	// Scores contains the score of each choice in the round, in Scale units.
+	// Choices already elected or eliminated have a score of 0.
+	Scores []uint64
+	// Elected contains the choices elected at the end of the round
+	Elected []int
+	// Eliminated is the choice eliminated at the end of the round, or -1
+	Eliminated int
+	// Exhausted is the weight of the ballots that don't rank any remaining
+	// choice, in Scale units
+	Exhausted uint64
+}
+
+// isValidMethod verifies that the method is known and that the number of
+// seats is coherent.
+func (r Rank) isValidMethod() bool {
+	switch r.Method {
+	case RankMethodNone, RankMethodIRV, RankMethodBorda, RankMethodSchulze, RankMethodSTV:
+	default:
+		return false
+	}
+
+	return int(r.Seats) <= len(r.Choices)
+}
+
+// getSeats returns the number of choices to elect.
+func (r RankTally) getSeats() int {
+	if r.Method == RankMethodIRV || r.Seats == 0 {
+		return 1
+	}
+
+	return int(r.Seats)
+}
+
+// toOrdering returns the choices of a rank answer ordered by preference.
+// Unranked choices are omitted, and choices with the same rank are ordered by
+// index.
+func toOrdering(ranks []int8) []int {
+	ordering := make([]int, 0, len(ranks))
+
+	for i, rank := range ranks {
+		if rank >= 0 {
+			ordering = append(ordering, i)
+		}
+	}
+
+	sort.SliceStable(ordering, func(i, j int) bool {
+		return ranks[ordering[i]] < ranks[ordering[j]]
+	})
+
+	return ordering
+}
+
+// ComputeRankOutcome applies the method on the orderings of the ballots.
+func ComputeRankOutcome(method RankMethod, seats int, numChoices int,
+	orderings [][]int) *RankOutcome {
+
+	switch method {
+	case RankMethodIRV:
+		return countTransferable(method, 1, numChoices, orderings)
+	case RankMethodSTV:
+		return countTransferable(method, seats, numChoices, orderings)
+	case RankMethodBorda:
+		return countBorda(seats, numChoices, orderings)
+	case RankMethodSchulze:
+		return countSchulze(seats, numChoices, orderings)
+	default:
+		return nil
+	}
+}
+
+// countBorda gives n-1-k points to the choice at position k of each ballot.
+func countBorda(seats int, numChoices int, orderings [][]int) *RankOutcome {
+	scores := make([]uint64, numChoices)
+
+	for _, ordering := range orderings {
+		for k, choice := range ordering {
+			scores[choice] += uint64(numChoices - 1 - k)
+		}
+	}
+
+	return &RankOutcome{
+		Method:  RankMethodBorda,
+		Winners: topChoices(scores, seats),
+		Scale:   1,
+		Rounds: []RankRound{{
+			Scores:     scores,
+			Elected:    topChoices(scores, seats),
+			Eliminated: -1,
+		}},
+	}
+}
+
+// countSchulze computes the strongest paths between each pair of choices. A
+// choice ranks above another one if its path to the other is stronger than
+// the reverse path. Ties are broken by index.
+func countSchulze(seats int, numChoices int, orderings [][]int) *RankOutcome {
+	pairwise := make([][]uint64, numChoices)
+	for i := range pairwise {
+		pairwise[i] = make([]uint64, numChoices)
+	}
+
+	for _, ordering := range orderings {
+		ranked := make([]bool, numChoices)
+
+		for _, preferred := range ordering {
+			ranked[preferred] = true
+
+			// preferred beats all choices that are ranked after it, or that
+			// aren't ranked at all
+			for other := 0; other < numChoices; other++ {
+				if !ranked[other] {
+					pairwise[preferred][other]++
+				}
+			}
+		}
+	}
+
+	paths := make([][]uint64, numChoices)
+	for i := range paths {
+		paths[i] = make([]uint64, numChoices)
+
+		for j := range paths[i] {
+			if i != j && pairwise[i][j] > pairwise[j][i] {
+				paths[i][j] = pairwise[i][j]
+			}
+		}
+	}
+
+	for k := 0; k < numChoices; k++ {
+		for i := 0; i < numChoices; i++ {
+			if i == k {
+				continue
+			}
+
+			for j := 0; j < numChoices; j++ {
+				if j == i || j == k {
+					continue
+				}
+
+				strength := min64(paths[i][k], paths[k][j])
+				if strength > paths[i][j] {
+					paths[i][j] = strength
+				}
+			}
+		}
+	}
+
+	// the score of a choice is the number of choices it beats
+	scores := make([]uint64, numChoices)
+
+	for i := 0; i < numChoices; i++ {
+		for j := 0; j < numChoices; j++ {
+			if i != j && paths[i][j] > paths[j][i] {
+				scores[i]++
+			}
+		}
+	}
+
+	return &RankOutcome{
+		Method:  RankMethodSchulze,
+		Winners: topChoices(scores, seats),
+		Scale:   1,
+		Rounds: []RankRound{{
+			Scores:     scores,
+			Elected:    topChoices(scores, seats),
+			Eliminated: -1,
+		}},
+		Pairwise: pairwise,
+		Paths:    paths,
+	}
+}
+
+// countTransferable runs IRV or STV. Each ballot counts for its first
+// continuing choice. A choice reaching the quota is elected and, for STV, its
+// surplus is transferred to the next preferences at a reduced weight.
+// Otherwise the choice with the lowest score is eliminated. Ties are broken by
+// electing the lowest index and eliminating the highest index.
+func countTransferable(method RankMethod, seats int, numChoices int,
+	orderings [][]int) *RankOutcome {
+
+	outcome := &RankOutcome{
+		Method:  method,
+		Winners: []int{},
+		Scale:   STVScale,
+		Rounds:  []RankRound{},
+	}
+
+	// 0: continuing, 1: elected, 2: eliminated
+	state := make([]int, numChoices)
+
+	weights := make([]uint64, len(orderings))
+	for i := range weights {
+		weights[i] = STVScale
+	}
+
+	// Droop quota, only used by STV
+	quota := uint64(len(orderings))*STVScale/uint64(seats+1) + 1
+
+	for len(outcome.Winners) < seats {
+		continuing := 0
+		for _, s := range state {
+			if s == 0 {
+				continuing++
+			}
+		}
+
+		if continuing == 0 {
+			break
+		}
+
+		round := RankRound{
+			Scores:     make([]uint64, numChoices),
+			Elected:    []int{},
+			Eliminated: -1,
+		}
+
+		// current choice of each ballot, -1 if exhausted
+		current := make([]int, len(orderings))
+
+		for i, ordering := range orderings {
+			current[i] = -1
+
+			for _, choice := range ordering {
+				if state[choice] == 0 {
+					current[i] = choice
+					break
+				}
+			}
+
+			if current[i] < 0 {
+				round.Exhausted += weights[i]
+			} else {
+				round.Scores[current[i]] += weights[i]
+			}
+		}
+
+		if method == RankMethodIRV {
+			var active uint64
+			for _, score := range round.Scores {
+				active += score
+			}
+
+			quota = active/2 + 1
+		}
+
+		// all remaining choices are elected if there are not enough of them
+		if len(outcome.Winners)+continuing <= seats {
+			for _, choice := range topChoices(round.Scores, numChoices) {
+				if state[choice] == 0 {
+					state[choice] = 1
+					round.Elected = append(round.Elected, choice)
+					outcome.Winners = append(outcome.Winners, choice)
+				}
+			}
+
+			outcome.Rounds = append(outcome.Rounds, round)
+
+			break
+		}
+
+		best := -1
+
+		for choice, score := range round.Scores {
+			if state[choice] == 0 && score >= quota && (best < 0 || score > round.Scores[best]) {
+				best = choice
+			}
+		}
+
+		if best >= 0 {
+			state[best] = 1
+			round.Elected = append(round.Elected, best)
+			outcome.Winners = append(outcome.Winners, best)
+
+			// transfer the surplus at a reduced weight
+			score := round.Scores[best]
+			surplus := score - quota
+
+			for i := range orderings {
+				if current[i] == best {
+					weights[i] = weights[i] * surplus / score
+				}
+			}
+		} else {
+			worst := -1
+
+			for choice, score := range round.Scores {
+				if state[choice] == 0 && (worst < 0 || score <= round.Scores[worst]) {
+					worst = choice
+				}
+			}
+
+			state[worst] = 2
+			round.Eliminated = worst
+		}
+
+		outcome.Rounds = append(outcome.Rounds, round)
+	}
+
+	return outcome
+}
+
+// topChoices returns the n choices with the highest scores, the lowest index
+// first in case of a tie.
+func topChoices(scores []uint64, n int) []int {
+	choices := make([]int, len(scores))
+	for i := range choices {
+		choices[i] = i
+	}
+
+	sort.SliceStable(choices, func(i, j int) bool {
+		return scores[choices[i]] > scores[choices[j]]
+	})
+
+	if n > len(choices) {
+		n = len(choices)
+	}
+
+	return choices[:n]
+}
+
+func min64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,122 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRanking_IRV(t *testing.T) {
+	orderings := repeatOrderings(
+		3, []int{0},
+		2, []int{1, 2},
+		4, []int{2, 1},
+	)
+
+	outcome := ComputeRankOutcome(RankMethodIRV, 1, 3, orderings)
+
+	require.Equal(t, []int{2}, outcome.Winners)
+	require.Len(t, outcome.Rounds, 2)
+
+	require.Equal(t, []uint64{3 * STVScale, 2 * STVScale, 4 * STVScale}, outcome.Rounds[0].Scores)
+	require.Equal(t, 1, outcome.Rounds[0].Eliminated)
+	require.Empty(t, outcome.Rounds[0].Elected)
+
+	require.Equal(t, []uint64{3 * STVScale, 0, 6 * STVScale}, outcome.Rounds[1].Scores)
+	require.Equal(t, []int{2}, outcome.Rounds[1].Elected)
+}
+
+func TestRanking_STV(t *testing.T) {
+	orderings := repeatOrderings(
+		6, []int{0, 1},
+		1, []int{2},
+		2, []int{1},
+	)
+
+	outcome := ComputeRankOutcome(RankMethodSTV, 2, 3, orderings)
+
+	require.Equal(t, []int{0, 1}, outcome.Winners)
+	require.Len(t, outcome.Rounds, 2)
+
+	// the surplus of 6-3.000001 votes is transferred from a to b
+	require.Equal(t, []int{0}, outcome.Rounds[0].Elected)
+	require.Equal(t, uint64(2*STVScale+6*499999), outcome.Rounds[1].Scores[1])
+	require.Equal(t, []int{1}, outcome.Rounds[1].Elected)
+}
+
+func TestRanking_Borda(t *testing.T) {
+	orderings := [][]int{{0, 1, 2}, {1, 0, 2}, {1, 2, 0}}
+
+	outcome := ComputeRankOutcome(RankMethodBorda, 1, 3, orderings)
+
+	require.Equal(t, []int{1}, outcome.Winners)
+	require.Equal(t, []uint64{3, 5, 1}, outcome.Rounds[0].Scores)
+}
+
+func TestRanking_Schulze(t *testing.T) {
+	orderings := [][]int{{0, 1, 2}, {1, 0, 2}, {1, 2, 0}, {2}}
+
+	outcome := ComputeRankOutcome(RankMethodSchulze, 2, 3, orderings)
+
+	require.Equal(t, []int{1, 0}, outcome.Winners)
+	require.Equal(t, uint64(2), outcome.Pairwise[1][0])
+	require.Equal(t, uint64(1), outcome.Pairwise[0][1])
+	require.Equal(t, uint64(3), outcome.Pairwise[1][2])
+}
+
+func TestRanking_Tally(t *testing.T) {
+	configuration := Configuration{
+		Scaffold: []Subject{{
+			ID: "subject",
+			Ranks: []Rank{{
+				ID:      questionID(1),
+				MaxN:    3,
+				Choices: []string{"a", "b", "c"},
+				Method:  RankMethodBorda,
+			}},
+		}},
+	}
+
+	tally := NewTally(configuration)
+
+	tally.Add(Ballot{
+		RankResultIDs: []ID{questionID(1)},
+		RankResult:    [][]int8{{2, 0, 1}},
+	})
+
+	tally.Finalize()
+
+	require.NotNil(t, tally.Ranks[0].Outcome)
+	require.Equal(t, []int{1}, tally.Ranks[0].Outcome.Winners)
+	require.Equal(t, []uint64{0, 2, 1}, tally.Ranks[0].Outcome.Rounds[0].Scores)
+}
+
+func TestRanking_IsValidMethod(t *testing.T) {
+	rank := Rank{Choices: []string{"a", "b"}}
+	require.True(t, rank.isValidMethod())
+
+	rank.Method = "unknown"
+	require.False(t, rank.isValidMethod())
+
+	rank.Method = RankMethodSTV
+	rank.Seats = 3
+	require.False(t, rank.isValidMethod())
+}
+
+func TestRanking_ToOrdering(t *testing.T) {
+	require.Equal(t, []int{2, 0}, toOrdering([]int8{1, -1, 0}))
+}
+
+// repeatOrderings takes pairs of (count, ordering) and returns the list of
+// orderings.
+func repeatOrderings(args ...interface{}) [][]int {
+	orderings := [][]int{}
+
+	for i := 0; i < len(args); i += 2 {
+		for j := 0; j < args[i].(int); j++ {
+			orderings = append(orderings, args[i+1].([]int))
+		}
+	}
+
+	return orderings
+}
@@ -0,0 +1,488 @@
+package types
+
+import (
+	"sort"
+)
+
+// Tally contains the aggregated results of an election. It is computed by the
+// smart contract from the decrypted ballots so that every node agrees on the
+// same numbers.
+type Tally struct {
+	// NumBallots is the total number of decrypted ballots
+	NumBallots uint
+	// InvalidBallots is the number of ballots that could not be decoded. They
+	// are not counted in the results of the questions.
+	InvalidBallots uint
+
+	Selects     []SelectTally
+	Ranks       []RankTally
+	Texts       []TextTally
+	Scores      []ScoreTally
+	Cumulatives []CumulativeTally
+
+	// conditions contains the condition of each conditional question
+	conditions map[ID]*Condition
+}
+
+// SelectTally contains the results of a Select question.
+type SelectTally struct {
+	ID ID
+	// Applicable is the number of valid ballots to which the question applies,
+	// which is the denominator of its results. It is lower than the number of
+	// valid ballots for conditional questions.
+	Applicable uint
+	// Answered is the number of ballots that answered the question
+	Answered uint
+	// Counts contains for each choice the number of ballots that selected it
+	Counts []uint
+}
+
+// RankTally contains the results of a Rank question.
+type RankTally struct {
+	ID ID
+	// Applicable is the number of valid ballots to which the question applies,
+	// which is the denominator of its results. It is lower than the number of
+	// valid ballots for conditional questions.
+	Applicable uint
+	// Answered is the number of ballots that answered the question
+	Answered uint
+	// Matrix contains for each choice the number of ballots that gave it each
+	// rank: Matrix[choice][rank].
+	Matrix [][]uint
+	// Method is the counting method of the question, if any
+	Method RankMethod `json:",omitempty"`
+	// Seats is the number of choices to elect
+	Seats uint `json:",omitempty"`
+	// Outcome is the result of the counting method, set by Finalize
+	Outcome *RankOutcome `json:",omitempty"`
+
+	// orderings contains the preferences of each ballot, needed by the
+	// counting methods
+	orderings [][]int
+}
+
+// TextTally contains the results of a Text question.
+type TextTally struct {
+	ID ID
+	// Applicable is the number of valid ballots to which the question applies,
+	// which is the denominator of its results. It is lower than the number of
+	// valid ballots for conditional questions.
+	Applicable uint
+	// Answered is the number of ballots that answered the question
+	Answered uint
+	// Answers contains for each choice the distinct answers with the number of
+	// ballots that gave them, sorted by decreasing count.
+	Answers [][]TextCount
+}
+
+// ScoreTally contains the results of a Score question.
+type ScoreTally struct {
+	ID ID
+	// Applicable is the number of valid ballots to which the question applies,
+	// which is the denominator of its results. It is lower than the number of
+	// valid ballots for conditional questions.
+	Applicable uint
+	// Answered is the number of ballots that answered the question
+	Answered uint
+	// MinScore is the lowest possible score, the first entry of the histograms
+	MinScore uint
+	// Choices contains the statistics of each choice
+	Choices []ScoreChoiceTally
+}
+
+// ScoreChoiceTally contains the scores given to a choice of a Score question.
+type ScoreChoiceTally struct {
+	// Scored is the number of ballots that scored the choice
+	Scored uint
+	// Sum is the sum of the scores
+	Sum uint
+	// Histogram contains the number of ballots that gave each score:
+	// Histogram[score-MinScore].
+	Histogram []uint
+	// Mean is the average score, set by Finalize
+	Mean float64
+	// Median is the median score, set by Finalize. With an even number of
+	// scores it is the average of the two middle ones.
+	Median float64
+	// MajorityGrade is the lower median score used by majority judgment, set
+	// by Finalize. It is -1 if the choice has not been scored.
+	MajorityGrade int
+}
+
+// CumulativeTally contains the results of a Cumulative question.
+type CumulativeTally struct {
+	ID ID
+	// Applicable is the number of valid ballots to which the question applies,
+	// which is the denominator of its results. It is lower than the number of
+	// valid ballots for conditional questions.
+	Applicable uint
+	// Answered is the number of ballots that answered the question
+	Answered uint
+	// Points contains for each choice the sum of the points it received
+	Points []uint
+	// Supporters contains for each choice the number of ballots that gave it
+	// at least one point
+	Supporters []uint
+}
+
+// TextCount is an answer to a Text question with its number of occurrences.
+type TextCount struct {
+	Text  string
+	Count uint
+}
+
+// NewTally returns an empty tally for the questions of the configuration.
+func NewTally(configuration Configuration) Tally {
+	tally := Tally{
+		Selects:     []SelectTally{},
+		Ranks:       []RankTally{},
+		Texts:       []TextTally{},
+		Scores:      []ScoreTally{},
+		Cumulatives: []CumulativeTally{},
+		conditions:  make(map[ID]*Condition),
+	}
+
+	for _, subject := range configuration.Scaffold {
+		tally.addSubject(subject)
+	}
+
+	for _, q := range configuration.questions() {
+		if q.GetCondition() != nil {
+			tally.conditions[q.GetID()] = q.GetCondition()
+		}
+	}
+
+	return tally
+}
+
+func (t *Tally) addSubject(subject Subject) {
+	for _, selection := range subject.Selects {
+		t.Selects = append(t.Selects, SelectTally{
+			ID:     selection.ID,
+			Counts: make([]uint, len(selection.Choices)),
+		})
+	}
+
+	for _, rank := range subject.Ranks {
+		matrix := make([][]uint, len(rank.Choices))
+		for i := range matrix {
+			matrix[i] = make([]uint, rank.MaxN)
+		}
+
+		t.Ranks = append(t.Ranks, RankTally{
+			ID:        rank.ID,
+			Matrix:    matrix,
+			Method:    rank.Method,
+			Seats:     rank.Seats,
+			orderings: [][]int{},
+		})
+	}
+
+	for _, text := range subject.Texts {
+		answers := make([][]TextCount, len(text.Choices))
+		for i := range answers {
+			answers[i] = []TextCount{}
+		}
+
+		t.Texts = append(t.Texts, TextTally{
+			ID:      text.ID,
+			Answers: answers,
+		})
+	}
+
+	for _, score := range subject.Scores {
+		choices := make([]ScoreChoiceTally, len(score.Choices))
+		for i := range choices {
+			choices[i] = ScoreChoiceTally{
+				Histogram:     make([]uint, score.MaxScore-score.MinScore+1),
+				MajorityGrade: -1,
+			}
+		}
+
+		t.Scores = append(t.Scores, ScoreTally{
+			ID:       score.ID,
+			MinScore: score.MinScore,
+			Choices:  choices,
+		})
+	}
+
+	for _, cumulative := range subject.Cumulatives {
+		t.Cumulatives = append(t.Cumulatives, CumulativeTally{
+			ID:         cumulative.ID,
+			Points:     make([]uint, len(cumulative.Choices)),
+			Supporters: make([]uint, len(cumulative.Choices)),
+		})
+	}
+
+	for _, sub := range subject.Subjects {
+		t.addSubject(sub)
+	}
+}
+
+// AddInvalid counts a ballot that could not be decoded.
+func (t *Tally) AddInvalid() {
+	t.NumBallots++
+	t.InvalidBallots++
+}
+
+// Add counts a valid ballot. Answers to unknown questions are ignored as they
+// are rejected when the ballot is decoded.
+func (t *Tally) Add(ballot Ballot) {
+	t.NumBallots++
+
+	t.addApplicable(ballot)
+
+	for i, id := range ballot.SelectResultIDs {
+		st := t.getSelect(id)
+		if st == nil || !ballot.meets(t.conditions[id]) ||
+			len(ballot.SelectResult[i]) != len(st.Counts) {
+			continue
+		}
+
+		st.Answered++
+
+		for j, selected := range ballot.SelectResult[i] {
+			if selected {
+				st.Counts[j]++
+			}
+		}
+	}
+
+	for i, id := range ballot.RankResultIDs {
+		rt := t.getRank(id)
+		if rt == nil || !ballot.meets(t.conditions[id]) ||
+			len(ballot.RankResult[i]) != len(rt.Matrix) {
+			continue
+		}
+
+		rt.Answered++
+
+		for j, rank := range ballot.RankResult[i] {
+			if rank >= 0 && int(rank) < len(rt.Matrix[j]) {
+				rt.Matrix[j][rank]++
+			}
+		}
+
+		if rt.Method != RankMethodNone {
+			rt.orderings = append(rt.orderings, toOrdering(ballot.RankResult[i]))
+		}
+	}
+
+	for i, id := range ballot.TextResultIDs {
+		tt := t.getText(id)
+		if tt == nil || !ballot.meets(t.conditions[id]) ||
+			len(ballot.TextResult[i]) != len(tt.Answers) {
+			continue
+		}
+
+		tt.Answered++
+
+		for j, text := range ballot.TextResult[i] {
+			if text != "" {
+				tt.Answers[j] = addTextCount(tt.Answers[j], text)
+			}
+		}
+	}
+
+	for i, id := range ballot.ScoreResultIDs {
+		sc := t.getScore(id)
+		if sc == nil || !ballot.meets(t.conditions[id]) ||
+			len(ballot.ScoreResult[i]) != len(sc.Choices) {
+			continue
+		}
+
+		sc.Answered++
+
+		for j, score := range ballot.ScoreResult[i] {
+			choice := &sc.Choices[j]
+
+			if score < int(sc.MinScore) || score-int(sc.MinScore) >= len(choice.Histogram) {
+				continue
+			}
+
+			choice.Scored++
+			choice.Sum += uint(score)
+			choice.Histogram[uint(score)-sc.MinScore]++
+		}
+	}
+
+	for i, id := range ballot.CumulativeResultIDs {
+		ct := t.getCumulative(id)
+		if ct == nil || !ballot.meets(t.conditions[id]) ||
+			len(ballot.CumulativeResult[i]) != len(ct.Points) {
+			continue
+		}
+
+		ct.Answered++
+
+		for j, points := range ballot.CumulativeResult[i] {
+			if points > 0 {
+				ct.Points[j] += points
+				ct.Supporters[j]++
+			}
+		}
+	}
+}
+
+// Finalize runs the counting methods of the Rank questions and computes the
+// statistics of the Score questions once all the ballots have been added. The
+// preferences of the ballots are not serialized, therefore it must be called
+// on the tally that counted them.
+func (t *Tally) Finalize() {
+	for i := range t.Ranks {
+		rt := &t.Ranks[i]
+
+		rt.Outcome = ComputeRankOutcome(rt.Method, rt.getSeats(), len(rt.Matrix),
+			rt.orderings)
+	}
+
+	for i := range t.Scores {
+		for j := range t.Scores[i].Choices {
+			t.Scores[i].Choices[j].finalize(t.Scores[i].MinScore)
+		}
+	}
+}
+
+// finalize computes the statistics of the choice from its histogram.
+func (c *ScoreChoiceTally) finalize(minScore uint) {
+	if c.Scored == 0 {
+		return
+	}
+
+	c.Mean = float64(c.Sum) / float64(c.Scored)
+
+	lower := c.nthScore((c.Scored-1)/2, minScore)
+	upper := c.nthScore(c.Scored/2, minScore)
+
+	c.Median = float64(lower+upper) / 2
+	c.MajorityGrade = int(lower)
+}
+
+// nthScore returns the n-th lowest score, starting at 0.
+func (c *ScoreChoiceTally) nthScore(n uint, minScore uint) uint {
+	var seen uint
+
+	for i, count := range c.Histogram {
+		seen += count
+
+		if seen > n {
+			return minScore + uint(i)
+		}
+	}
+
+	return minScore + uint(len(c.Histogram)) - 1
+}
+
+// addApplicable counts the ballot in the denominator of the questions that
+// apply to it.
+func (t *Tally) addApplicable(ballot Ballot) {
+	for i := range t.Selects {
+		if ballot.meets(t.conditions[t.Selects[i].ID]) {
+			t.Selects[i].Applicable++
+		}
+	}
+
+	for i := range t.Ranks {
+		if ballot.meets(t.conditions[t.Ranks[i].ID]) {
+			t.Ranks[i].Applicable++
+		}
+	}
+
+	for i := range t.Texts {
+		if ballot.meets(t.conditions[t.Texts[i].ID]) {
+			t.Texts[i].Applicable++
+		}
+	}
+
+	for i := range t.Scores {
+		if ballot.meets(t.conditions[t.Scores[i].ID]) {
+			t.Scores[i].Applicable++
+		}
+	}
+
+	for i := range t.Cumulatives {
+		if ballot.meets(t.conditions[t.Cumulatives[i].ID]) {
+			t.Cumulatives[i].Applicable++
+		}
+	}
+}
+
+func (t *Tally) getSelect(id ID) *SelectTally {
+	for i := range t.Selects {
+		if t.Selects[i].ID == id {
+			return &t.Selects[i]
+		}
+	}
+
+	return nil
+}
+
+func (t *Tally) getRank(id ID) *RankTally {
+	for i := range t.Ranks {
+		if t.Ranks[i].ID == id {
+			return &t.Ranks[i]
+		}
+	}
+
+	return nil
+}
+
+func (t *Tally) getScore(id ID) *ScoreTally {
+	for i := range t.Scores {
+		if t.Scores[i].ID == id {
+			return &t.Scores[i]
+		}
+	}
+
+	return nil
+}
+
+func (t *Tally) getCumulative(id ID) *CumulativeTally {
+	for i := range t.Cumulatives {
+		if t.Cumulatives[i].ID == id {
+			return &t.Cumulatives[i]
+		}
+	}
+
+	return nil
+}
+
+func (t *Tally) getText(id ID) *TextTally {
+	for i := range t.Texts {
+		if t.Texts[i].ID == id {
+			return &t.Texts[i]
+		}
+	}
+
+	return nil
+}
+
+// addTextCount increments the count of the text and keeps the answers sorted
+// by decreasing count, then alphabetically, so that the order doesn't depend
+// on the order of the ballots.
+func addTextCount(answers []TextCount, text string) []TextCount {
+	found := false
+
+	for i := range answers {
+		if answers[i].Text == text {
+			answers[i].Count++
+			found = true
+
+			break
+		}
+	}
+
+	if !found {
+		answers = append(answers, TextCount{Text: text, Count: 1})
+	}
+
+	sort.SliceStable(answers, func(i, j int) bool {
+		if answers[i].Count != answers[j].Count {
+			return answers[i].Count > answers[j].Count
+		}
+
+		return answers[i].Text < answers[j].Text
+	})
+
+	return answers
+}
@@ -0,0 +1,205 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var tallyConfiguration = Configuration{
+	MainTitle: "tally",
+	Scaffold: []Subject{
+		{
+			ID: "subject",
+			Selects: []Select{{
+				ID:      questionID(1),
+				MaxN:    2,
+				Choices: []string{"a", "b", "c"},
+			}},
+			Subjects: []Subject{{
+				ID: "sub",
+				Ranks: []Rank{{
+					ID:      questionID(2),
+					MaxN:    2,
+					Choices: []string{"a", "b"},
+				}},
+				Texts: []Text{{
+					ID:        questionID(3),
+					MaxN:      1,
+					MaxLength: 10,
+					Choices:   []string{"name"},
+				}},
+			}},
+		},
+	},
+}
+
+func TestTally_Add(t *testing.T) {
+	tally := NewTally(tallyConfiguration)
+
+	require.Len(t, tally.Selects, 1)
+	require.Len(t, tally.Ranks, 1)
+	require.Len(t, tally.Texts, 1)
+
+	tally.Add(Ballot{
+		SelectResultIDs: []ID{questionID(1)},
+		SelectResult:    [][]bool{{true, false, true}},
+		RankResultIDs:   []ID{questionID(2)},
+		RankResult:      [][]int8{{1, 0}},
+		TextResultIDs:   []ID{questionID(3)},
+		TextResult:      [][]string{{"bob"}},
+	})
+
+	tally.Add(Ballot{
+		SelectResultIDs: []ID{questionID(1)},
+		SelectResult:    [][]bool{{true, true, false}},
+		RankResultIDs:   []ID{questionID(2)},
+		RankResult:      [][]int8{{0, -1}},
+		TextResultIDs:   []ID{questionID(3)},
+		TextResult:      [][]string{{"alice"}},
+	})
+
+	tally.Add(Ballot{
+		TextResultIDs: []ID{questionID(3)},
+		TextResult:    [][]string{{"bob"}},
+	})
+
+	tally.AddInvalid()
+
+	require.Equal(t, uint(4), tally.NumBallots)
+	require.Equal(t, uint(1), tally.InvalidBallots)
+
+	require.Equal(t, uint(2), tally.Selects[0].Answered)
+	require.Equal(t, []uint{2, 1, 1}, tally.Selects[0].Counts)
+
+	require.Equal(t, uint(2), tally.Ranks[0].Answered)
+	require.Equal(t, [][]uint{{1, 1}, {1, 0}}, tally.Ranks[0].Matrix)
+
+	require.Equal(t, uint(3), tally.Texts[0].Answered)
+	require.Equal(t, []TextCount{{"bob", 2}, {"alice", 1}}, tally.Texts[0].Answers[0])
+}
+
+func TestTally_AddUnknownQuestion(t *testing.T) {
+	tally := NewTally(tallyConfiguration)
+
+	tally.Add(Ballot{
+		SelectResultIDs: []ID{questionID(9), questionID(1)},
+		SelectResult:    [][]bool{{true}, {true, true}},
+	})
+
+	require.Equal(t, uint(1), tally.NumBallots)
+	require.Equal(t, uint(0), tally.Selects[0].Answered)
+}
+
+func TestTally_Score(t *testing.T) {
+	configuration := Configuration{
+		Scaffold: []Subject{{
+			ID: "subject",
+			Scores: []Score{{
+				ID:       questionID(1),
+				MaxN:     2,
+				MinScore: 1,
+				MaxScore: 5,
+				Choices:  []string{"a", "b"},
+			}},
+		}},
+	}
+
+	tally := NewTally(configuration)
+
+	for _, scores := range [][]int{{1, 5}, {2, -1}, {5, -1}, {4, -1}} {
+		tally.Add(Ballot{
+			ScoreResultIDs: []ID{questionID(1)},
+			ScoreResult:    [][]int{scores},
+		})
+	}
+
+	tally.Finalize()
+
+	require.Equal(t, uint(4), tally.Scores[0].Answered)
+
+	a := tally.Scores[0].Choices[0]
+	require.Equal(t, uint(4), a.Scored)
+	require.Equal(t, uint(12), a.Sum)
+	require.Equal(t, []uint{1, 1, 0, 1, 1}, a.Histogram)
+	require.Equal(t, 3.0, a.Mean)
+	require.Equal(t, 3.0, a.Median)
+	require.Equal(t, 2, a.MajorityGrade)
+
+	b := tally.Scores[0].Choices[1]
+	require.Equal(t, uint(1), b.Scored)
+	require.Equal(t, 5.0, b.Median)
+	require.Equal(t, 5, b.MajorityGrade)
+}
+
+func TestTally_Cumulative(t *testing.T) {
+	configuration := Configuration{
+		Scaffold: []Subject{{
+			ID: "subject",
+			Cumulatives: []Cumulative{{
+				ID:      questionID(1),
+				MaxN:    2,
+				Budget:  5,
+				Choices: []string{"a", "b", "c"},
+			}},
+		}},
+	}
+
+	tally := NewTally(configuration)
+
+	tally.Add(Ballot{
+		CumulativeResultIDs: []ID{questionID(1)},
+		CumulativeResult:    [][]uint{{5, 0, 0}},
+	})
+
+	tally.Add(Ballot{
+		CumulativeResultIDs: []ID{questionID(1)},
+		CumulativeResult:    [][]uint{{2, 0, 3}},
+	})
+
+	require.Equal(t, uint(2), tally.Cumulatives[0].Answered)
+	require.Equal(t, []uint{7, 0, 3}, tally.Cumulatives[0].Points)
+	require.Equal(t, []uint{2, 0, 1}, tally.Cumulatives[0].Supporters)
+}
+
+func TestTally_Conditions(t *testing.T) {
+	configuration := Configuration{
+		Scaffold: []Subject{{
+			ID: "subject",
+			Selects: []Select{{
+				ID:      questionID(1),
+				MaxN:    1,
+				Choices: []string{"yes", "other"},
+			}, {
+				ID:        questionID(2),
+				MaxN:      1,
+				Choices:   []string{"a", "b"},
+				Condition: &Condition{QuestionID: questionID(1), Choices: []int{1}},
+			}},
+		}},
+	}
+
+	tally := NewTally(configuration)
+
+	tally.Add(Ballot{
+		SelectResultIDs: []ID{questionID(1), questionID(2)},
+		SelectResult:    [][]bool{{false, true}, {true, false}},
+	})
+
+	tally.Add(Ballot{
+		SelectResultIDs: []ID{questionID(1), questionID(2)},
+		SelectResult:    [][]bool{{true, false}, {false, false}},
+	})
+
+	tally.Add(Ballot{
+		SelectResultIDs: []ID{questionID(1)},
+		SelectResult:    [][]bool{{true, false}},
+	})
+
+	require.Equal(t, uint(3), tally.Selects[0].Applicable)
+	require.Equal(t, uint(3), tally.Selects[0].Answered)
+
+	require.Equal(t, uint(1), tally.Selects[1].Applicable)
+	require.Equal(t, uint(1), tally.Selects[1].Answered)
+	require.Equal(t, []uint{1, 0}, tally.Selects[1].Counts)
+}
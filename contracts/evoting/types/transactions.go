@@ -2,7 +2,10 @@ package types
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
 	"encoding/hex"
+	"encoding/json"
 	"io"
 	"strconv"
 
@@ -21,44 +24,6 @@ func RegisterTransactionFormat(f serde.Format, e serde.FormatEngine) {
 	transactionFormats.Register(f, e)
 }
 
-// ElectionsMetadata ...
-type ElectionsMetadata struct {
-	ElectionsIDs ElectionIDs
-}
-
-// ElectionIDs is a slice of hex-encoded election IDs
-type ElectionIDs []string
-
-// Contains checks if el is present. Return < 0 if not.
-func (e ElectionIDs) Contains(el string) int {
-	for i, e1 := range e {
-		if e1 == el {
-			return i
-		}
-	}
-
-	return -1
-}
-
-// Add adds an election ID or returns an error if already present
-func (e *ElectionIDs) Add(id string) error {
-	if e.Contains(id) >= 0 {
-		return xerrors.Errorf("id %q already exist", id)
-	}
-
-	*e = append(*e, id)
-
-	return nil
-}
-
-// Remove removes an election ID from the list, if it exists
-func (e *ElectionIDs) Remove(id string) {
-	i := e.Contains(id)
-	if i >= 0 {
-		*e = append((*e)[:i], (*e)[i+1:]...)
-	}
-}
-
 // TransactionFactory provides the mean to deserialize a transaction.
 //
 // - implements serde.Factory
@@ -93,6 +58,19 @@ func (t TransactionFactory) Deserialize(ctx serde.Context, data []byte) (serde.M
 type CreateElection struct {
 	Configuration Configuration
 	AdminID       string
+	// Admins optionally contains the hex-encoded public keys of the
+	// co-administrators. AdminID is always part of the admins.
+	Admins []string
+	// AdminThreshold is the number of admins that must approve a sensitive
+	// action. It defaults to 1.
+	AdminThreshold int
+	// ShuffleThreshold is the number of shuffles required before decrypting
+	// the ballots. It defaults to the Byzantine threshold of the roster.
+	ShuffleThreshold int
+	// DecryptionThreshold is the t of the t-of-n DKG, i.e. the number of
+	// pubshares required to decrypt the ballots. It defaults to the Byzantine
+	// threshold of the roster.
+	DecryptionThreshold int
 }
 
 // Serialize implements serde.Message
@@ -113,6 +91,9 @@ func (ce CreateElection) Serialize(ctx serde.Context) ([]byte, error) {
 type OpenElection struct {
 	// ElectionID is hex-encoded
 	ElectionID string
+	// AdminSignature is the signature of the election's admin on
+	// AdminMessage, which authorizes the command
+	AdminSignature []byte
 }
 
 // Serialize implements serde.Message
@@ -127,6 +108,58 @@ func (oe OpenElection) Serialize(ctx serde.Context) ([]byte, error) {
 	return data, nil
 }
 
+// UpdateConfiguration defines the transaction to replace the configuration of
+// an election that is not open yet.
+//
+// - implements serde.Message
+type UpdateConfiguration struct {
+	// ElectionID is hex-encoded
+	ElectionID string
+	// Configuration is the JSON encoding of the new configuration. The admin
+	// signs these bytes as they are, see AdminPayload, so that the signature
+	// doesn't depend on how the configuration would be encoded again.
+	Configuration []byte
+	// AdminSignature is the signature of the election's admin on
+	// AdminMessage, which authorizes the command
+	AdminSignature []byte
+}
+
+// Serialize implements serde.Message
+func (uc UpdateConfiguration) Serialize(ctx serde.Context) ([]byte, error) {
+	format := transactionFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, uc)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode update configuration: %v", err)
+	}
+
+	return data, nil
+}
+
+// UpdateRoster defines the transaction to replace the roster of an election by
+// the roster of the chain.
+//
+// - implements serde.Message
+type UpdateRoster struct {
+	// ElectionID is hex-encoded
+	ElectionID string
+	// AdminSignature is the signature of the election's admin on
+	// AdminMessage, which authorizes the command
+	AdminSignature []byte
+}
+
+// Serialize implements serde.Message
+func (ur UpdateRoster) Serialize(ctx serde.Context) ([]byte, error) {
+	format := transactionFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, ur)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode update roster: %v", err)
+	}
+
+	return data, nil
+}
+
 // CastVote defines the transaction to cast a vote
 //
 // - implements serde.Message
@@ -135,6 +168,8 @@ type CastVote struct {
 	ElectionID string
 	UserID     string
 	Ballot     Ciphervote
+	// Proof proves that the voter encrypted the ballot, see ProveBallot
+	Proof BallotProof
 }
 
 // Serialize implements serde.Message
@@ -149,6 +184,30 @@ func (cv CastVote) Serialize(ctx serde.Context) ([]byte, error) {
 	return data, nil
 }
 
+// MaxBatchedVotes is the maximum number of votes in a CastVotes transaction.
+const MaxBatchedVotes = 500
+
+// CastVotes defines the transaction to cast several votes at once. The votes
+// are applied independently: an invalid vote is skipped without rejecting the
+// others, and the result of each vote is recorded, see VoteResult.
+//
+// - implements serde.Message
+type CastVotes struct {
+	Votes []CastVote
+}
+
+// Serialize implements serde.Message
+func (cv CastVotes) Serialize(ctx serde.Context) ([]byte, error) {
+	format := transactionFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, cv)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode cast votes: %v", err)
+	}
+
+	return data, nil
+}
+
 // CloseElection defines the transaction to close an election
 //
 // - implements serde.Message
@@ -156,6 +215,9 @@ type CloseElection struct {
 	// ElectionID is hex-encoded
 	ElectionID string
 	UserID     string
+	// AdminSignature is the signature of the election's admin on
+	// AdminMessage, which authorizes the command
+	AdminSignature []byte
 }
 
 // Serialize implements serde.Message
@@ -181,6 +243,9 @@ type ShuffleBallots struct {
 	// RandomVector is the vector to be used to generate the proof of the next
 	// shuffle
 	RandomVector RandomVector
+	// RandomVectorVersion is the derivation used for RandomVector. It must be
+	// the version of the election.
+	RandomVectorVersion RandomVectorVersion
 	// Proof is the proof corresponding to the shuffle of this transaction
 	Proof []byte
 	// Signature is the signature of the result of HashShuffle() with the private
@@ -213,6 +278,9 @@ type RegisterPubShares struct {
 	// Pubshares are the public shares of the node submitting the transaction
 	// so that they can be used for decryption.
 	Pubshares PubsharesUnit
+	// Proofs are the proofs that the pubshares were computed with the private
+	// share of the node at Index, 1 for each pubshare.
+	Proofs PubshareProofsUnit
 	// Signature is the signature of the result of HashPubShares() with the
 	// private key corresponding to PublicKey
 	Signature []byte
@@ -240,6 +308,9 @@ type CombineShares struct {
 	// ElectionID is hex-encoded
 	ElectionID string
 	UserID     string
+	// AdminSignature is the signature of the election's admin on
+	// AdminMessage, which authorizes the command
+	AdminSignature []byte
 }
 
 // Serialize implements serde.Message
@@ -261,6 +332,9 @@ type CancelElection struct {
 	// ElectionID is hex-encoded
 	ElectionID string
 	UserID     string
+	// AdminSignature is the signature of the election's admin on
+	// AdminMessage, which authorizes the command
+	AdminSignature []byte
 }
 
 // Serialize implements serde.Message
@@ -281,6 +355,9 @@ func (ce CancelElection) Serialize(ctx serde.Context) ([]byte, error) {
 type DeleteElection struct {
 	// ElectionID is hex-encoded
 	ElectionID string
+	// AdminSignature is the signature of the election's admin on
+	// AdminMessage, which authorizes the command
+	AdminSignature []byte
 }
 
 // Serialize implements serde.Message
@@ -295,6 +372,270 @@ func (ce DeleteElection) Serialize(ctx serde.Context) ([]byte, error) {
 	return data, nil
 }
 
+// RegisterVoters defines the transaction to add voters to the electorate of an
+// election.
+//
+// - implements serde.Message
+type RegisterVoters struct {
+	// ElectionID is hex-encoded
+	ElectionID string
+	// Voters are the hashes of the user IDs, as computed by HashUserID
+	Voters []string
+	// AdminSignature is the signature of the election's admin on
+	// AdminMessage, which authorizes the command
+	AdminSignature []byte
+}
+
+// Serialize implements serde.Message
+func (rv RegisterVoters) Serialize(ctx serde.Context) ([]byte, error) {
+	format := transactionFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, rv)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode register voters: %v", err)
+	}
+
+	return data, nil
+}
+
+// RemoveVoters defines the transaction to remove voters from the electorate
+// of an election.
+//
+// - implements serde.Message
+type RemoveVoters struct {
+	// ElectionID is hex-encoded
+	ElectionID string
+	// Voters are the hashes of the user IDs, as computed by HashUserID
+	Voters []string
+	// AdminSignature is the signature of the election's admin on
+	// AdminMessage, which authorizes the command
+	AdminSignature []byte
+}
+
+// Serialize implements serde.Message
+func (rv RemoveVoters) Serialize(ctx serde.Context) ([]byte, error) {
+	format := transactionFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, rv)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode remove voters: %v", err)
+	}
+
+	return data, nil
+}
+
+// ReplaceVoters defines the transaction to replace the whole electorate of an
+// election.
+//
+// - implements serde.Message
+type ReplaceVoters struct {
+	// ElectionID is hex-encoded
+	ElectionID string
+	// Voters are the hashes of the user IDs, as computed by HashUserID
+	Voters []string
+	// AdminSignature is the signature of the election's admin on
+	// AdminMessage, which authorizes the command
+	AdminSignature []byte
+}
+
+// Serialize implements serde.Message
+func (rv ReplaceVoters) Serialize(ctx serde.Context) ([]byte, error) {
+	format := transactionFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, rv)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode replace voters: %v", err)
+	}
+
+	return data, nil
+}
+
+// ProposeAction defines the transaction of an admin proposing a sensitive
+// action that must be approved by other admins.
+//
+// - implements serde.Message
+type ProposeAction struct {
+	// ElectionID is hex-encoded
+	ElectionID string
+	// Command is the proposed command, such as "CLOSE_ELECTION"
+	Command string
+	// AdminID is the hex-encoded public key of the proposer
+	AdminID string
+	// AdminSignature is the proposer's signature on AdminMessage
+	AdminSignature []byte
+}
+
+// Serialize implements serde.Message
+func (pa ProposeAction) Serialize(ctx serde.Context) ([]byte, error) {
+	format := transactionFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, pa)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode propose action: %v", err)
+	}
+
+	return data, nil
+}
+
+// ApproveAction defines the transaction of an admin approving a pending
+// action.
+//
+// - implements serde.Message
+type ApproveAction struct {
+	// ElectionID is hex-encoded
+	ElectionID string
+	// ActionID is the ID of the pending action
+	ActionID string
+	// AdminID is the hex-encoded public key of the approver
+	AdminID string
+	// AdminSignature is the approver's signature on AdminMessage for the
+	// APPROVE_ACTION command, with the ApprovalPayload of the pending action
+	AdminSignature []byte
+}
+
+// Serialize implements serde.Message
+func (aa ApproveAction) Serialize(ctx serde.Context) ([]byte, error) {
+	format := transactionFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, aa)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode approve action: %v", err)
+	}
+
+	return data, nil
+}
+
+// ReportTime defines the transaction used by a node to report its time, from
+// which the time of the chain is derived, see Clock.
+//
+// - implements serde.Message
+// - implements serde.Fingerprinter
+type ReportTime struct {
+	// Timestamp is the unix time of the node, in seconds
+	Timestamp int64
+	// Signature is the signature of the fingerprint with the private key
+	// corresponding to PublicKey
+	Signature []byte
+	// PublicKey is the public key of the node in the roster of the chain
+	PublicKey []byte
+}
+
+// Serialize implements serde.Message
+func (rt ReportTime) Serialize(ctx serde.Context) ([]byte, error) {
+	format := transactionFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, rt)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode report time: %v", err)
+	}
+
+	return data, nil
+}
+
+// RegisterDKG defines the transaction used by a participant of the DKG to
+// register its result, from which the election is opened or its roster
+// updated, see AgreedDKG.
+//
+// - implements serde.Message
+// - implements serde.Fingerprinter
+type RegisterDKG struct {
+	ElectionID string
+	// Commits are the marshalled commitments of the public polynomial of the
+	// DKG
+	Commits [][]byte
+	// Participants are the addresses of the participants, as text, in the
+	// order of their index in the DKG
+	Participants [][]byte
+	// Signature is the signature of the fingerprint with the private key
+	// corresponding to PublicKey
+	Signature []byte
+	// PublicKey is the public key of the node in the roster
+	PublicKey []byte
+}
+
+// Serialize implements serde.Message
+func (rd RegisterDKG) Serialize(ctx serde.Context) ([]byte, error) {
+	format := transactionFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, rd)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode register dkg: %v", err)
+	}
+
+	return data, nil
+}
+
+// AdminMessage returns the message that the admin of an election signs to
+// authorize a command, such as "CLOSE_ELECTION", on the election. The payload
+// binds the signature to the parameters of the transaction, see the
+// AdminPayload functions, and is nil for the commands without parameters. The
+// nonce is the AdminNonce of the election: it is incremented each time a
+// command is authorized so that a signature can only be used once. The
+// command, the election ID and the payload are length-prefixed so that their
+// concatenation is unambiguous.
+func AdminMessage(command, electionID string, payload []byte, nonce uint64) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, nonce)
+
+	h := sha256.New()
+	h.Write(lengthPrefixed([]byte(command)))
+	h.Write(lengthPrefixed([]byte(electionID)))
+	h.Write(lengthPrefixed(payload))
+	h.Write(buf)
+
+	return h.Sum(nil)
+}
+
+// GetConfiguration returns the new configuration.
+func (uc UpdateConfiguration) GetConfiguration() (Configuration, error) {
+	var configuration Configuration
+
+	err := json.Unmarshal(uc.Configuration, &configuration)
+	if err != nil {
+		return configuration, xerrors.Errorf("failed to unmarshal configuration: %v", err)
+	}
+
+	return configuration, nil
+}
+
+// AdminPayload returns the hash of the encoded configuration, as sent in the
+// transaction, which is part of the admin message.
+func (uc UpdateConfiguration) AdminPayload() []byte {
+	h := sha256.Sum256(uc.Configuration)
+
+	return h[:]
+}
+
+// AdminPayload returns the hash of the voters, which is part of the admin
+// message.
+func (rv RegisterVoters) AdminPayload() []byte {
+	return VotersPayload(rv.Voters)
+}
+
+// AdminPayload returns the hash of the voters, which is part of the admin
+// message.
+func (rv RemoveVoters) AdminPayload() []byte {
+	return VotersPayload(rv.Voters)
+}
+
+// AdminPayload returns the hash of the voters, which is part of the admin
+// message.
+func (rv ReplaceVoters) AdminPayload() []byte {
+	return VotersPayload(rv.Voters)
+}
+
+// VotersPayload returns the hash of the concatenation of the length-prefixed
+// voters, in order, as signed by the admin to update the electorate.
+func VotersPayload(voters []string) []byte {
+	h := sha256.New()
+
+	for _, voter := range voters {
+		h.Write(lengthPrefixed([]byte(voter)))
+	}
+
+	return h.Sum(nil)
+}
+
 // RandomID returns the hex encoding of a randomly created 32 byte ID.
 func RandomID() (string, error) {
 	buf := make([]byte, 32)
@@ -306,14 +647,27 @@ func RandomID() (string, error) {
 	return hex.EncodeToString(buf), nil
 }
 
-// Fingerprint implements serde.Fingerprinter. If creates a fingerprint only
-// based on the electionID and the shuffled ballots.
+// Fingerprint implements serde.Fingerprinter. If creates a fingerprint based
+// on the electionID, the random vector version, the round and the shuffled
+// ballots. The version and the round are left out with RandomVectorLegacy so
+// that the shuffles of the legacy elections keep their fingerprint.
 func (sb ShuffleBallots) Fingerprint(writer io.Writer) error {
 	_, err := writer.Write([]byte(sb.ElectionID))
 	if err != nil {
 		return xerrors.Errorf("failed to write the election ID: %v", err)
 	}
 
+	if sb.RandomVectorVersion != RandomVectorLegacy {
+		buf := make([]byte, 10)
+		binary.BigEndian.PutUint16(buf, uint16(sb.RandomVectorVersion))
+		binary.BigEndian.PutUint64(buf[2:], uint64(sb.Round))
+
+		_, err = writer.Write(buf)
+		if err != nil {
+			return xerrors.Errorf("failed to write the version and the round: %v", err)
+		}
+	}
+
 	for _, ballot := range sb.ShuffledBallots {
 		err := ballot.FingerPrint(writer)
 		if err != nil {
@@ -341,5 +695,75 @@ func (rp RegisterPubShares) Fingerprint(writer io.Writer) error {
 		return xerrors.Errorf("failed to fingerprint pubShares: %V", err)
 	}
 
+	err = rp.Proofs.Fingerprint(writer)
+	if err != nil {
+		return xerrors.Errorf("failed to fingerprint proofs: %v", err)
+	}
+
+	return nil
+}
+
+// Fingerprint implements serde.Fingerprinter. It writes the clock key, so that
+// the signature can't be mistaken for another one, and the timestamp.
+func (rt ReportTime) Fingerprint(writer io.Writer) error {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(rt.Timestamp))
+
+	_, err := writer.Write(append([]byte(ClockKey), buf...))
+	if err != nil {
+		return xerrors.Errorf("failed to write the timestamp: %v", err)
+	}
+
+	return nil
+}
+
+// Fingerprint implements serde.Fingerprinter. It writes the election ID, the
+// commits and the participants, each prefixed by its length.
+func (rd RegisterDKG) Fingerprint(writer io.Writer) error {
+	_, err := writer.Write([]byte(rd.ElectionID))
+	if err != nil {
+		return xerrors.Errorf("failed to write the election ID: %v", err)
+	}
+
+	err = writeItems(writer, rd.Commits)
+	if err != nil {
+		return xerrors.Errorf("failed to write the commits: %v", err)
+	}
+
+	err = writeItems(writer, rd.Participants)
+	if err != nil {
+		return xerrors.Errorf("failed to write the participants: %v", err)
+	}
+
 	return nil
 }
+
+// writeItems writes the number of items followed by the items, each prefixed
+// by its length, so that the same bytes can't be read as other items.
+func writeItems(writer io.Writer, items [][]byte) error {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint32(buf, uint32(len(items)))
+
+	_, err := writer.Write(buf)
+	if err != nil {
+		return xerrors.Errorf("failed to write the number of items: %v", err)
+	}
+
+	for _, item := range items {
+		_, err = writer.Write(lengthPrefixed(item))
+		if err != nil {
+			return xerrors.Errorf("failed to write item: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// lengthPrefixed returns the item prefixed by its length, as a 32-bit
+// big-endian integer.
+func lengthPrefixed(item []byte) []byte {
+	buf := make([]byte, 4, 4+len(item))
+	binary.BigEndian.PutUint32(buf, uint32(len(item)))
+
+	return append(buf, item...)
+}
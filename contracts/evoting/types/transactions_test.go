@@ -0,0 +1,40 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminMessage(t *testing.T) {
+	msg := AdminMessage("OPEN_ELECTION", "ab", []byte("c"), 0)
+
+	require.Equal(t, msg, AdminMessage("OPEN_ELECTION", "ab", []byte("c"), 0))
+	require.NotEqual(t, msg, AdminMessage("OPEN_ELECTION", "a", []byte("bc"), 0))
+	require.NotEqual(t, msg, AdminMessage("OPEN_ELECTIONa", "b", []byte("c"), 0))
+	require.NotEqual(t, msg, AdminMessage("OPEN_ELECTION", "ab", []byte("c"), 1))
+}
+
+func TestVotersPayload(t *testing.T) {
+	payload := VotersPayload([]string{"ab", "c"})
+
+	require.Equal(t, payload, VotersPayload([]string{"ab", "c"}))
+	require.NotEqual(t, payload, VotersPayload([]string{"a", "bc"}))
+	require.NotEqual(t, payload, VotersPayload([]string{"abc"}))
+	require.NotEqual(t, payload, VotersPayload([]string{"c", "ab"}))
+}
+
+func TestUpdateConfiguration_GetConfiguration(t *testing.T) {
+	update := UpdateConfiguration{Configuration: []byte(`{"MainTitle":"title"}`)}
+
+	configuration, err := update.GetConfiguration()
+	require.NoError(t, err)
+	require.Equal(t, "title", configuration.MainTitle)
+
+	// the payload is bound to the bytes as they are sent
+	other := UpdateConfiguration{Configuration: []byte(`{"MainTitle": "title"}`)}
+	require.NotEqual(t, update.AdminPayload(), other.AdminPayload())
+
+	_, err = UpdateConfiguration{Configuration: []byte("{")}.GetConfiguration()
+	require.EqualError(t, err, "failed to unmarshal configuration: unexpected end of JSON input")
+}
@@ -0,0 +1,57 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+
+	"golang.org/x/xerrors"
+)
+
+// VoteResult is the result of one of the votes of a CAST_VOTES transaction.
+type VoteResult struct {
+	// ElectionID is hex-encoded
+	ElectionID string
+	UserID     string
+	// Error explains why the vote was skipped. It is empty if the ballot is
+	// stored.
+	Error string `json:",omitempty"`
+}
+
+// VoteResultsKey returns the key under which the results of the votes of a
+// CAST_VOTES transaction are stored, in the order of the votes, so that each
+// voter can learn the outcome of its own vote from the transaction ID.
+func VoteResultsKey(txID []byte) []byte {
+	h := sha256.New()
+
+	h.Write([]byte("vote-results"))
+	h.Write(txID)
+
+	return h.Sum(nil)
+}
+
+// EncodeVoteResults returns the value stored at VoteResultsKey.
+func EncodeVoteResults(results []VoteResult) ([]byte, error) {
+	buf, err := json.Marshal(results)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal vote results: %v", err)
+	}
+
+	return buf, nil
+}
+
+// DecodeVoteResults parses the value stored at VoteResultsKey. An empty value
+// means that the transaction is unknown or doesn't cast votes.
+func DecodeVoteResults(buf []byte) ([]VoteResult, error) {
+	if len(buf) == 0 {
+		return nil, nil
+	}
+
+	var results []VoteResult
+
+	err := json.Unmarshal(buf, &results)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to unmarshal vote results: %v", err)
+	}
+
+	return results, nil
+}
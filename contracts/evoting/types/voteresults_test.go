@@ -0,0 +1,35 @@
+package types
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVoteResultsKey(t *testing.T) {
+	key := VoteResultsKey([]byte("tx1"))
+	require.Len(t, key, sha256.Size)
+	require.NotEqual(t, key, VoteResultsKey([]byte("tx2")))
+}
+
+func TestVoteResults_Encoding(t *testing.T) {
+	results, err := DecodeVoteResults(nil)
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	results = []VoteResult{
+		{ElectionID: "aa", UserID: "user1"},
+		{ElectionID: "aa", UserID: "user2", Error: "the election is not open"},
+	}
+
+	buf, err := EncodeVoteResults(results)
+	require.NoError(t, err)
+
+	decoded, err := DecodeVoteResults(buf)
+	require.NoError(t, err)
+	require.Equal(t, results, decoded)
+
+	_, err = DecodeVoteResults([]byte("["))
+	require.EqualError(t, err, "failed to unmarshal vote results: unexpected end of JSON input")
+}
@@ -0,0 +1,395 @@
+package evoting
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"go.dedis.ch/dela/crypto/bls"
+	sjson "go.dedis.ch/dela/serde/json"
+	"go.dedis.ch/kyber/v3/proof"
+	"golang.org/x/xerrors"
+)
+
+// VerifyStep is the outcome of a step of the verification of a bulletin
+// board. The step passes if Err is nil and it is not skipped.
+type VerifyStep struct {
+	Name    string
+	Skipped bool
+	// Unverifiable is set on a skipped step whose data is never published, as
+	// opposed to data that is not available yet.
+	Unverifiable bool
+	Err          error
+}
+
+// VerifyBulletin checks a bulletin board offline, the same way the smart
+// contract does. It verifies the proof of every shuffle round with the random
+// vector derived from the shuffle, that the shuffles are signed by distinct
+// members of the roster and reach the threshold, the proofs of the pubshares,
+// decrypts the ballots of the last round with the pubshares, parses them, and
+// compares the decrypted ballots and the tally with the published result. It
+// returns one step per check; a step that depends on a failed or missing step
+// is skipped.
+func VerifyBulletin(bulletin types.Bulletin) []VerifyStep {
+	var steps []VerifyStep
+
+	fail := func(name string, err error) []VerifyStep {
+		return append(steps, VerifyStep{Name: name, Err: err})
+	}
+
+	skip := func(name string, reason string) []VerifyStep {
+		return append(steps, VerifyStep{Name: name, Skipped: true, Err: xerrors.New(reason)})
+	}
+
+	header := bulletin.Header
+
+	pubkey := suite.Point()
+
+	err := pubkey.UnmarshalBinary(header.Pubkey)
+	if err != nil {
+		return fail("public key", xerrors.Errorf("failed to unmarshal: %v", err))
+	}
+
+	steps = append(steps, VerifyStep{Name: "public key"})
+
+	// the proof of a ballot is bound to the ID of its voter, so it is only
+	// checked by the nodes when the ballot is cast
+	steps = append(steps, VerifyStep{
+		Name:         "ballot proofs",
+		Skipped:      true,
+		Unverifiable: true,
+		Err:          xerrors.New("the proofs are bound to the voter IDs, which are not published"),
+	})
+
+	election := types.Election{
+		ElectionID:          header.ElectionID,
+		Configuration:       header.Configuration,
+		Status:              header.Status,
+		Pubkey:              pubkey,
+		BallotSize:          header.BallotSize,
+		ShuffleThreshold:    header.ShuffleThreshold,
+		DecryptionThreshold: header.DecryptionThreshold,
+		RandomVectorVersion: header.RandomVectorVersion,
+		Suffragia:           types.Suffragia{Ciphervotes: bulletin.Ballots},
+		ShuffleInstances:    bulletin.Shuffles,
+		PubsharesUnits:      bulletin.Pubshares,
+	}
+
+	if len(bulletin.Shuffles) == 0 {
+		return skip("shuffles", "no shuffle has been submitted")
+	}
+
+	input := bulletin.Ballots
+
+	for round, instance := range bulletin.Shuffles {
+		name := fmt.Sprintf("shuffle round %d", round)
+
+		err = verifyShuffleInstance(election, round, input, instance)
+		if err != nil {
+			return fail(name, err)
+		}
+
+		steps = append(steps, VerifyStep{Name: name})
+
+		input = instance.ShuffledBallots
+	}
+
+	switch {
+	case len(header.Roster) == 0:
+		steps = skip("shufflers", "the roster is not published")
+	case !hasSignatures(bulletin.Shuffles):
+		steps = skip("shufflers", "the signatures of the shuffles are not published")
+	default:
+		err = verifyShufflers(election, header.Roster, bulletin.Shuffles)
+		if err != nil {
+			steps = fail("shufflers", err)
+		} else {
+			steps = append(steps, VerifyStep{Name: "shufflers"})
+		}
+	}
+
+	if len(bulletin.Shuffles) < header.ShuffleThreshold {
+		reason := fmt.Sprintf("%d shuffles, at least %d required",
+			len(bulletin.Shuffles), header.ShuffleThreshold)
+
+		// the election might still be shuffled
+		if len(bulletin.Pubshares.Pubshares) == 0 && bulletin.Result == nil {
+			return skip("shuffle threshold", reason)
+		}
+
+		return fail("shuffle threshold", xerrors.New(reason))
+	}
+
+	steps = append(steps, VerifyStep{Name: "shuffle threshold"})
+
+	if len(bulletin.Pubshares.Pubshares) == 0 {
+		return skip("decryption", "no pubshares have been submitted")
+	}
+
+	err = checkPubshares(bulletin.Pubshares, len(input), election.ChunksPerBallot())
+	if err != nil {
+		return fail("decryption", err)
+	}
+
+	if len(header.VerificationKeys) == 0 {
+		steps = skip("pubshare proofs", "the verification keys are not published")
+	} else {
+		err = verifyPubshareProofs(header.VerificationKeys, bulletin.Pubshares, input)
+		if err != nil {
+			steps = fail("pubshare proofs", err)
+		} else {
+			steps = append(steps, VerifyStep{Name: "pubshare proofs"})
+		}
+	}
+
+	ballots, tally, err := decryptBallots(election)
+	if err != nil {
+		return fail("decryption", err)
+	}
+
+	steps = append(steps, VerifyStep{Name: "decryption"})
+
+	if bulletin.Result == nil {
+		return skip("decrypted ballots", "the result is not published")
+	}
+
+	err = compareBallots(ballots, bulletin.Result.DecryptedBallots)
+	if err != nil {
+		steps = fail("decrypted ballots", err)
+	} else {
+		steps = append(steps, VerifyStep{Name: "decrypted ballots"})
+	}
+
+	err = compareJSON(tally, bulletin.Result.Tally)
+	if err != nil {
+		return fail("tally", err)
+	}
+
+	return append(steps, VerifyStep{Name: "tally"})
+}
+
+// verifyShuffleInstance verifies the proof of a shuffle of the input ballots.
+// The random vector is derived from the shuffle as the shuffler did.
+func verifyShuffleInstance(election types.Election, round int,
+	input []types.Ciphervote, instance types.ShuffleInstance) error {
+
+	if len(instance.ShuffledBallots) == 0 {
+		return xerrors.Errorf("there are no shuffled ballots")
+	}
+
+	if len(instance.ShuffledBallots) != len(input) {
+		return xerrors.Errorf("wrong number of shuffled ballots: %d != %d",
+			len(instance.ShuffledBallots), len(input))
+	}
+
+	// the bulletin board is not trusted: the ballots are checked before being
+	// given to the shuffle verifier
+	for i := range input {
+		if len(input[i]) != election.ChunksPerBallot() ||
+			len(instance.ShuffledBallots[i]) != election.ChunksPerBallot() {
+
+			return xerrors.Errorf("ballot %d doesn't have %d chunks", i,
+				election.ChunksPerBallot())
+		}
+	}
+
+	hash, err := shuffleHash(election, round, instance)
+	if err != nil {
+		return xerrors.Errorf("failed to get fingerprint: %v", err)
+	}
+
+	randomVector, err := ShuffleRandomVector(election.RandomVectorVersion,
+		election.ElectionID, round, hash, election.ChunksPerBallot())
+	if err != nil {
+		return xerrors.Errorf("failed to derive random vector: %v", err)
+	}
+
+	return verifyShuffle(proof.HashVerify, election.Pubkey, input,
+		instance.ShuffledBallots, randomVector, instance.ShuffleProofs)
+}
+
+// shuffleHash returns the hash of the shuffle, signed by the shuffler, as the
+// smart contract computes it.
+func shuffleHash(election types.Election, round int,
+	instance types.ShuffleInstance) ([]byte, error) {
+
+	h := sha256.New()
+
+	shuffleBallots := types.ShuffleBallots{
+		ElectionID:          election.ElectionID,
+		Round:               round,
+		ShuffledBallots:     instance.ShuffledBallots,
+		RandomVectorVersion: election.RandomVectorVersion,
+	}
+
+	err := shuffleBallots.Fingerprint(h)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.Sum(nil), nil
+}
+
+// hasSignatures returns true if every shuffle has its signature.
+func hasSignatures(shuffles []types.ShuffleInstance) bool {
+	for _, instance := range shuffles {
+		if len(instance.Signature) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// verifyShufflers checks that each shuffle is signed by a different member of
+// the roster.
+func verifyShufflers(election types.Election, roster [][]byte,
+	shuffles []types.ShuffleInstance) error {
+
+	ctx := sjson.NewContext()
+
+	for round, instance := range shuffles {
+		if !containsKey(roster, instance.ShufflerPublicKey) {
+			return xerrors.Errorf("round %d: the shuffler is not a member of the "+
+				"roster: %x", round, instance.ShufflerPublicKey)
+		}
+
+		for _, previous := range shuffles[:round] {
+			if bytes.Equal(previous.ShufflerPublicKey, instance.ShufflerPublicKey) {
+				return xerrors.Errorf("round %d: the shuffler already made a "+
+					"shuffle: %x", round, instance.ShufflerPublicKey)
+			}
+		}
+
+		pubkey, err := bls.NewPublicKey(instance.ShufflerPublicKey)
+		if err != nil {
+			return xerrors.Errorf("round %d: failed to decode public key: %v", round, err)
+		}
+
+		signature, err := bls.NewSignatureFactory().SignatureOf(ctx, instance.Signature)
+		if err != nil {
+			return xerrors.Errorf("round %d: failed to decode signature: %v", round, err)
+		}
+
+		hash, err := shuffleHash(election, round, instance)
+		if err != nil {
+			return xerrors.Errorf("round %d: failed to get fingerprint: %v", round, err)
+		}
+
+		err = pubkey.Verify(hash, signature)
+		if err != nil {
+			return xerrors.Errorf("round %d: invalid signature: %v", round, err)
+		}
+	}
+
+	return nil
+}
+
+// containsKey returns true if the key is in the list.
+func containsKey(keys [][]byte, key []byte) bool {
+	for _, k := range keys {
+		if bytes.Equal(k, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkPubshares checks that each node submitted one pubshare per chunk of
+// each ballot.
+func checkPubshares(units types.PubsharesUnits, ballots, chunks int) error {
+	if len(units.Indexes) != len(units.Pubshares) {
+		return xerrors.Errorf("wrong number of indexes: %d != %d",
+			len(units.Indexes), len(units.Pubshares))
+	}
+
+	for i, unit := range units.Pubshares {
+		if len(unit) != ballots {
+			return xerrors.Errorf("node %d: wrong number of ballots: %d != %d",
+				units.Indexes[i], len(unit), ballots)
+		}
+
+		for j, ballotShares := range unit {
+			if len(ballotShares) != chunks {
+				return xerrors.Errorf("node %d: ballot %d doesn't have %d pubshares",
+					units.Indexes[i], j, chunks)
+			}
+		}
+	}
+
+	return nil
+}
+
+// verifyPubshareProofs checks the proof of every pubshare against the
+// verification key of the node that submitted it.
+func verifyPubshareProofs(verificationKeys [][]byte, units types.PubsharesUnits,
+	ballots []types.Ciphervote) error {
+
+	for i, unit := range units.Pubshares {
+		index := units.Indexes[i]
+
+		if index < 0 || index >= len(verificationKeys) {
+			return xerrors.Errorf("node %d: no verification key", index)
+		}
+
+		if i >= len(units.Proofs) || units.Proofs[i] == nil {
+			return xerrors.Errorf("node %d: the proofs are not published", index)
+		}
+
+		key := suite.Point()
+
+		err := key.UnmarshalBinary(verificationKeys[index])
+		if err != nil {
+			return xerrors.Errorf("node %d: failed to unmarshal verification key: %v",
+				index, err)
+		}
+
+		err = verifyPubshares(key, ballots, unit, units.Proofs[i])
+		if err != nil {
+			return xerrors.Errorf("node %d: %v", index, err)
+		}
+	}
+
+	return nil
+}
+
+// compareBallots returns an error with the index of the first ballot that
+// differs.
+func compareBallots(decrypted, published []types.Ballot) error {
+	if len(decrypted) != len(published) {
+		return xerrors.Errorf("wrong number of ballots: %d != %d",
+			len(published), len(decrypted))
+	}
+
+	for i := range decrypted {
+		err := compareJSON(decrypted[i], published[i])
+		if err != nil {
+			return xerrors.Errorf("ballot %d: %v", i, err)
+		}
+	}
+
+	return nil
+}
+
+// compareJSON compares the JSON encoding of the values, which is how the
+// published values are read.
+func compareJSON(expected, actual interface{}) error {
+	expectedBuf, err := json.Marshal(expected)
+	if err != nil {
+		return xerrors.Errorf("failed to marshal: %v", err)
+	}
+
+	actualBuf, err := json.Marshal(actual)
+	if err != nil {
+		return xerrors.Errorf("failed to marshal: %v", err)
+	}
+
+	if !bytes.Equal(expectedBuf, actualBuf) {
+		return xerrors.Errorf("published %s, expected %s", actualBuf, expectedBuf)
+	}
+
+	return nil
+}
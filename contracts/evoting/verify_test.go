@@ -0,0 +1,285 @@
+package evoting
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"github.com/stretchr/testify/require"
+	sjson "go.dedis.ch/dela/serde/json"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/proof"
+	"go.dedis.ch/kyber/v3/shuffle"
+)
+
+func TestVerifyBulletin(t *testing.T) {
+	election := makeVerifiableElection(t)
+
+	steps := VerifyBulletin(writeAndReadBulletin(t, election))
+	require.Len(t, steps, 9)
+
+	// only the proofs of the ballots can't be checked
+	require.Equal(t, "ballot proofs", steps[1].Name)
+	require.True(t, steps[1].Unverifiable)
+
+	for _, step := range steps {
+		require.Equal(t, step.Unverifiable, step.Skipped, step.Name)
+
+		if !step.Skipped {
+			require.NoError(t, step.Err, step.Name)
+		}
+	}
+
+	// a tampered tally is detected
+	election.Tally.NumBallots = 3
+
+	steps = VerifyBulletin(writeAndReadBulletin(t, election))
+	require.Equal(t, "tally", steps[8].Name)
+	require.Error(t, steps[8].Err)
+
+	// a pubshare that doesn't match its proof is detected
+	bulletin := writeAndReadBulletin(t, election)
+	proofs := bulletin.Pubshares.Proofs[0]
+	proofs[0], proofs[1] = proofs[1], proofs[0]
+
+	steps = VerifyBulletin(bulletin)
+	require.Equal(t, "pubshare proofs", steps[5].Name)
+	require.Contains(t, steps[5].Err.Error(), "node 0: invalid pubshare 0 of ballot 0")
+
+	// a shuffle signed by a node outside of the roster is detected
+	bulletin = writeAndReadBulletin(t, election)
+	bulletin.Header.Roster = bulletin.Header.Roster[:1]
+
+	steps = VerifyBulletin(bulletin)
+	require.Equal(t, "shufflers", steps[3].Name)
+	require.Contains(t, steps[3].Err.Error(), "round 0: the shuffler is not a member of the roster")
+
+	// a signature that doesn't match the shuffle is detected
+	bulletin = writeAndReadBulletin(t, election)
+	bulletin.Shuffles[0].Signature = election.ShuffleInstances[0].ShuffleProofs
+
+	steps = VerifyBulletin(bulletin)
+	require.Equal(t, "shufflers", steps[3].Name)
+	require.Contains(t, steps[3].Err.Error(), "round 0: failed to decode signature")
+
+	// a result with too few shuffles is detected
+	bulletin = writeAndReadBulletin(t, election)
+	bulletin.Header.ShuffleThreshold = 2
+
+	steps = VerifyBulletin(bulletin)
+	require.Len(t, steps, 5)
+	require.Equal(t, "shuffle threshold", steps[4].Name)
+	require.False(t, steps[4].Skipped)
+	require.EqualError(t, steps[4].Err, "1 shuffles, at least 2 required")
+
+	// the shuffle doesn't verify with the legacy random vector
+	bulletin = writeAndReadBulletin(t, election)
+	bulletin.Header.RandomVectorVersion = types.RandomVectorLegacy
+
+	steps = VerifyBulletin(bulletin)
+	require.Len(t, steps, 3)
+	require.Error(t, steps[2].Err)
+
+	// a tampered shuffle is detected
+	shuffled := election.ShuffleInstances[0].ShuffledBallots
+	shuffled[0], shuffled[1] = shuffled[1], shuffled[0]
+
+	steps = VerifyBulletin(writeAndReadBulletin(t, election))
+	require.Len(t, steps, 3)
+	require.Equal(t, "shuffle round 0", steps[2].Name)
+	require.Error(t, steps[2].Err)
+}
+
+func TestVerifyBulletin_Skipped(t *testing.T) {
+	election := makeVerifiableElection(t)
+	election.ShuffleInstances = nil
+
+	steps := VerifyBulletin(writeAndReadBulletin(t, election))
+	require.Len(t, steps, 3)
+	require.True(t, steps[2].Skipped)
+	require.False(t, steps[2].Unverifiable)
+	require.EqualError(t, steps[2].Err, "no shuffle has been submitted")
+
+	// the election is still being shuffled
+	election = makeVerifiableElection(t)
+	election.ShuffleThreshold = 2
+	election.PubsharesUnits = types.PubsharesUnits{}
+	election.Status = types.Closed
+
+	bulletin := writeAndReadBulletin(t, election)
+	bulletin.Result = nil
+
+	steps = VerifyBulletin(bulletin)
+	require.Len(t, steps, 5)
+	require.True(t, steps[4].Skipped)
+	require.EqualError(t, steps[4].Err, "1 shuffles, at least 2 required")
+
+	// the shufflers can't be checked without the roster
+	election = makeVerifiableElection(t)
+	election.Roster = nil
+
+	steps = VerifyBulletin(writeAndReadBulletin(t, election))
+	require.Len(t, steps, 9)
+	require.True(t, steps[3].Skipped)
+	require.EqualError(t, steps[3].Err, "the roster is not published")
+
+	steps = VerifyBulletin(types.Bulletin{})
+	require.Len(t, steps, 1)
+	require.Error(t, steps[0].Err)
+
+	// the proofs of the pubshares can't be checked without the verification
+	// keys
+	election = makeVerifiableElection(t)
+	election.VerificationKeys = nil
+
+	steps = VerifyBulletin(writeAndReadBulletin(t, election))
+	require.Len(t, steps, 9)
+	require.True(t, steps[5].Skipped)
+	require.EqualError(t, steps[5].Err, "the verification keys are not published")
+}
+
+func TestShuffleHash(t *testing.T) {
+	election := makeVerifiableElection(t)
+	instance := election.ShuffleInstances[0]
+
+	hash0, err := shuffleHash(election, 0, instance)
+	require.NoError(t, err)
+
+	hash1, err := shuffleHash(election, 1, instance)
+	require.NoError(t, err)
+	require.NotEqual(t, hash0, hash1)
+
+	// the legacy hash only covers the election ID and the ballots
+	election.RandomVectorVersion = types.RandomVectorLegacy
+
+	legacy0, err := shuffleHash(election, 0, instance)
+	require.NoError(t, err)
+	require.NotEqual(t, hash0, legacy0)
+
+	legacy1, err := shuffleHash(election, 1, instance)
+	require.NoError(t, err)
+	require.Equal(t, legacy0, legacy1)
+}
+
+// makeVerifiableElection returns an election with two ballots shuffled and
+// decrypted as the nodes do. A single node holds the secret key, and a member
+// of the roster signs the shuffle.
+func makeVerifiableElection(t *testing.T) types.Election {
+	secret := suite.Scalar().Pick(suite.RandomStream())
+	pubkey := suite.Point().Mul(secret, nil)
+
+	election := types.Election{
+		ElectionID: "abcd",
+		Configuration: types.Configuration{Scaffold: []types.Subject{{
+			ID: "c3Vi",
+			Selects: []types.Select{{
+				ID:      "UTE=",
+				MaxN:    1,
+				MinN:    1,
+				Choices: []string{"yes", "no"},
+			}},
+		}}},
+		Status:           types.ResultAvailable,
+		Pubkey:           pubkey,
+		BallotSize:       29,
+		Roster:           fakeAuthority{},
+		ShuffleThreshold: 1,
+		// the single node holds the whole secret
+		VerificationKeys: []kyber.Point{pubkey},
+
+		RandomVectorVersion: types.CurrentRandomVectorVersion,
+	}
+
+	for _, vote := range []string{"select:UTE=:1,0\n", "select:UTE=:0,1\n"} {
+		M := suite.Point().Embed([]byte(vote), suite.RandomStream())
+		k := suite.Scalar().Pick(suite.RandomStream())
+
+		election.Suffragia.Ciphervotes = append(election.Suffragia.Ciphervotes,
+			types.Ciphervote{types.EGPair{
+				K: suite.Point().Mul(k, nil),
+				C: suite.Point().Add(suite.Point().Mul(k, pubkey), M),
+			}})
+	}
+
+	X, Y := types.CiphervotesToPairs(election.Suffragia.Ciphervotes)
+
+	XX, YY, getProver := shuffle.SequencesShuffle(suite, nil, pubkey, X, Y,
+		suite.RandomStream())
+
+	shuffled, err := types.CiphervotesFromPairs(XX, YY)
+	require.NoError(t, err)
+
+	h := sha256.New()
+
+	err = types.ShuffleBallots{
+		ElectionID:          "abcd",
+		ShuffledBallots:     shuffled,
+		RandomVectorVersion: election.RandomVectorVersion,
+	}.Fingerprint(h)
+	require.NoError(t, err)
+
+	hash := h.Sum(nil)
+
+	randomVector, err := ShuffleRandomVector(election.RandomVectorVersion, "abcd", 0,
+		hash, election.ChunksPerBallot())
+	require.NoError(t, err)
+
+	prover, err := getProver(randomVector)
+	require.NoError(t, err)
+
+	shuffleProof, err := proof.HashProve(suite, shufflingProtocolName, prover)
+	require.NoError(t, err)
+
+	shuffler, err := fakeCommonSigner.GetPublicKey().MarshalBinary()
+	require.NoError(t, err)
+
+	signature, err := fakeCommonSigner.Sign(hash)
+	require.NoError(t, err)
+
+	signatureBuf, err := signature.Serialize(sjson.NewContext())
+	require.NoError(t, err)
+
+	election.ShuffleInstances = []types.ShuffleInstance{{
+		ShuffledBallots:   shuffled,
+		ShuffleProofs:     shuffleProof,
+		ShufflerPublicKey: shuffler,
+		Signature:         signatureBuf,
+	}}
+
+	unit := make(types.PubsharesUnit, len(shuffled))
+	proofs := make(types.PubshareProofsUnit, len(shuffled))
+
+	for i, ciphervote := range shuffled {
+		pubshare, pubshareProof, err := types.ProvePubshare(secret, ciphervote[0])
+		require.NoError(t, err)
+
+		unit[i] = []types.Pubshare{pubshare}
+		proofs[i] = []types.PubshareProof{pubshareProof}
+	}
+
+	election.PubsharesUnits = types.PubsharesUnits{
+		Pubshares: []types.PubsharesUnit{unit},
+		PubKeys:   [][]byte{[]byte("node")},
+		Indexes:   []int{0},
+		Proofs:    []types.PubshareProofsUnit{proofs},
+	}
+
+	election.DecryptedBallots, election.Tally, err = decryptBallots(election)
+	require.NoError(t, err)
+	require.Equal(t, uint(0), election.Tally.InvalidBallots)
+
+	return election
+}
+
+func writeAndReadBulletin(t *testing.T, election types.Election) types.Bulletin {
+	buf := new(bytes.Buffer)
+
+	err := types.WriteBulletin(buf, election)
+	require.NoError(t, err)
+
+	bulletin, err := types.ReadBulletin(buf)
+	require.NoError(t, err)
+
+	return bulletin
+}
@@ -247,11 +247,11 @@ func newDVotingNode(t require.TestingT, path string, randSource rand.Source) dVo
 
 	electionFac := etypes.NewElectionFactory(etypes.CiphervoteFactory{}, rosterFac)
 
-	dkg := pedersen.NewPedersen(onet, srvc, pool, electionFac, signer)
+	dkg := pedersen.NewPedersen(onet, srvc, pool, electionFac, rosterFac, signer)
 
 	rosterKey := [32]byte{}
 	evoting.RegisterContract(exec, evoting.NewContract(evotingAccessKey[:], rosterKey[:],
-		accessService, dkg, rosterFac))
+		accessService, rosterFac))
 
 	neffShuffle := neff.NewNeffShuffle(onet, srvc, pool, blocks, electionFac, signer)
 
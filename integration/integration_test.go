@@ -7,12 +7,10 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"math/rand"
 	"os"
 	"strconv"
-	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -34,7 +32,8 @@ import (
 	"go.dedis.ch/dela/crypto"
 	"go.dedis.ch/dela/serde"
 	"go.dedis.ch/dela/serde/json"
-	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/sign/schnorr"
+	"go.dedis.ch/kyber/v3/util/random"
 	"golang.org/x/xerrors"
 )
 
@@ -42,6 +41,9 @@ const addAndWaitErr = "failed to addAndWait: %v"
 
 var serdecontext = json.NewContext()
 
+// adminSecret is the private key of the admin of the elections
+var adminSecret = suite.Scalar().Pick(random.New())
+
 // Check the shuffled votes versus the cast votes on a few nodes
 func TestIntegration(t *testing.T) {
 	t.Run("3 nodes, 3 votes", getIntegrationTest(3, 3))
@@ -52,7 +54,7 @@ func getIntegrationTest(numNodes, numVotes int) func(*testing.T) {
 	return func(t *testing.T) {
 		t.Parallel()
 
-		adminID := "first admin"
+		adminID := getAdminID()
 
 		// ##### SETUP ENV #####
 		// make tests reproducible
@@ -93,7 +95,7 @@ func getIntegrationTest(numNodes, numVotes int) func(*testing.T) {
 		require.NoError(t, err)
 
 		// ##### OPEN ELECTION #####
-		err = openElection(m, electionID)
+		err = openElection(m, electionID, 0)
 		require.NoError(t, err)
 
 		electionFac := types.NewElectionFactory(types.CiphervoteFactory{}, nodes[0].GetRosterFac())
@@ -108,7 +110,7 @@ func getIntegrationTest(numNodes, numVotes int) func(*testing.T) {
 		fmt.Println("casted votes:", castedVotes)
 
 		// ##### CLOSE ELECTION #####
-		err = closeElection(m, electionID, adminID)
+		err = closeElection(m, electionID, adminID, election.AdminNonce)
 		require.NoError(t, err)
 
 		waitForStatus(t, types.Closed, electionFac, electionID, nodes, numNodes,
@@ -309,9 +311,32 @@ func createElection(m txManager, title string, admin string) ([]byte, error) {
 	return electionID, nil
 }
 
-func openElection(m txManager, electionID []byte) error {
+// getAdminID returns the hex-encoded public key of the admin.
+func getAdminID() string {
+	buf, err := suite.Point().Mul(adminSecret, nil).MarshalBinary()
+	if err != nil {
+		panic("failed to marshal admin public key: " + err.Error())
+	}
+
+	return hex.EncodeToString(buf)
+}
+
+// signAdmin returns the admin's signature that authorizes the command on the
+// election. nonce is the AdminNonce of the election.
+func signAdmin(cmd evoting.Command, electionIDHex string, nonce uint64) ([]byte, error) {
+	return schnorr.Sign(suite, adminSecret,
+		types.AdminMessage(string(cmd), electionIDHex, nil, nonce))
+}
+
+func openElection(m txManager, electionID []byte, nonce uint64) error {
+	adminSig, err := signAdmin(evoting.CmdOpenElection, hex.EncodeToString(electionID), nonce)
+	if err != nil {
+		return xerrors.Errorf("failed to sign: %v", err)
+	}
+
 	openElection := &types.OpenElection{
-		ElectionID: hex.EncodeToString(electionID),
+		ElectionID:     hex.EncodeToString(electionID),
+		AdminSignature: adminSig,
 	}
 
 	data, err := openElection.Serialize(serdecontext)
@@ -378,17 +403,19 @@ func castVotesRandomly(m txManager, actor dkg.Actor, election types.Election,
 		randomIndex := rand.Intn(len(possibleBallots))
 		vote := possibleBallots[randomIndex]
 
-		ciphervote, err := marshallBallot(strings.NewReader(vote), actor, election.ChunksPerBallot())
+		userID := "user " + strconv.Itoa(i)
+
+		ciphervote, proof, err := marshallBallot(vote, actor, election.ElectionID, userID,
+			election.ChunksPerBallot())
 		if err != nil {
 			return nil, xerrors.Errorf("failed to marshallBallot: %v", err)
 		}
 
-		userID := "user " + strconv.Itoa(i)
-
 		castVote := types.CastVote{
 			ElectionID: election.ElectionID,
 			UserID:     userID,
 			Ballot:     ciphervote,
+			Proof:      proof,
 		}
 
 		data, err := castVote.Serialize(serdecontext)
@@ -419,39 +446,37 @@ func castVotesRandomly(m txManager, actor dkg.Actor, election types.Election,
 	return votes, nil
 }
 
-func marshallBallot(vote io.Reader, actor dkg.Actor, chunks int) (types.Ciphervote, error) {
-
-	var ballot = make([]types.EGPair, chunks)
+func marshallBallot(vote string, actor dkg.Actor, electionID, userID string,
+	chunks int) (types.Ciphervote, types.BallotProof, error) {
 
-	buf := make([]byte, 29)
-
-	for i := 0; i < chunks; i++ {
-		var K, C kyber.Point
-		var err error
-
-		n, err := vote.Read(buf)
-		if err != nil {
-			return nil, xerrors.Errorf("failed to read: %v", err)
-		}
+	pubkey, err := actor.GetPublicKey()
+	if err != nil {
+		return nil, nil, xerrors.Errorf("failed to get the public key: %v", err)
+	}
 
-		K, C, _, err = actor.Encrypt(buf[:n])
-		if err != nil {
-			return types.Ciphervote{}, xerrors.Errorf("failed to encrypt the plaintext: %v", err)
-		}
+	ciphervote, ks, err := types.EncryptBallot(pubkey, []byte(vote), chunks)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("failed to encrypt the plaintext: %v", err)
+	}
 
-		ballot[i] = types.EGPair{
-			K: K,
-			C: C,
-		}
+	proof, err := types.ProveBallot(electionID, userID, ciphervote, ks)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("failed to prove the ballot: %v", err)
 	}
 
-	return ballot, nil
+	return ciphervote, proof, nil
 }
 
-func closeElection(m txManager, electionID []byte, admin string) error {
+func closeElection(m txManager, electionID []byte, admin string, nonce uint64) error {
+	adminSig, err := signAdmin(evoting.CmdCloseElection, hex.EncodeToString(electionID), nonce)
+	if err != nil {
+		return xerrors.Errorf("failed to sign: %v", err)
+	}
+
 	closeElection := &types.CloseElection{
-		ElectionID: hex.EncodeToString(electionID),
-		UserID:     admin,
+		ElectionID:     hex.EncodeToString(electionID),
+		UserID:         admin,
+		AdminSignature: adminSig,
 	}
 
 	data, err := closeElection.Serialize(serdecontext)
@@ -521,8 +546,15 @@ func decryptBallots(m txManager, actor dkg.Actor, election types.Election) error
 		return xerrors.Errorf("cannot decrypt: not all pubShares submitted")
 	}
 
+	adminSig, err := signAdmin(evoting.CmdCombineShares, election.ElectionID,
+		election.AdminNonce)
+	if err != nil {
+		return xerrors.Errorf("failed to sign: %v", err)
+	}
+
 	decryptBallots := types.CombineShares{
-		ElectionID: election.ElectionID,
+		ElectionID:     election.ElectionID,
+		AdminSignature: adminSig,
 	}
 
 	data, err := decryptBallots.Serialize(serdecontext)
@@ -31,7 +31,7 @@ func BenchmarkIntegration_CustomVotesScenario(b *testing.B) {
 	numVotes := 3
 	numChunksPerBallot := 3
 
-	adminID := "I am an admin"
+	adminID := getAdminID()
 
 	// ##### SETUP ENV #####
 	// make tests reproducible
@@ -72,7 +72,7 @@ func BenchmarkIntegration_CustomVotesScenario(b *testing.B) {
 	require.NoError(b, err)
 
 	// ##### OPEN ELECTION #####
-	err = openElection(m, electionID)
+	err = openElection(m, electionID, 0)
 	require.NoError(b, err)
 
 	electionFac := types.NewElectionFactory(types.CiphervoteFactory{}, nodes[0].GetRosterFac())
@@ -85,7 +85,7 @@ func BenchmarkIntegration_CustomVotesScenario(b *testing.B) {
 	require.NoError(b, err)
 
 	// ##### CLOSE ELECTION #####
-	err = closeElection(m, electionID, adminID)
+	err = closeElection(m, electionID, adminID, election.AdminNonce)
 	require.NoError(b, err)
 
 	time.Sleep(time.Millisecond * 1000)
@@ -221,21 +221,23 @@ func castVotesNChunks(m txManager, actor dkg.Actor, election types.Election,
 
 	vote := ballotBuilder.String()
 
-	ballot, err := marshallBallot(strings.NewReader(vote), actor, election.ChunksPerBallot())
-	if err != nil {
-		return nil, xerrors.Errorf("failed to marshallBallot: %v", err)
-	}
-
 	votes := make([]types.Ballot, numberOfVotes)
 
 	for i := 0; i < numberOfVotes; i++ {
 
 		userID := "user " + strconv.Itoa(i)
 
+		ciphervote, proof, err := marshallBallot(vote, actor, election.ElectionID, userID,
+			election.ChunksPerBallot())
+		if err != nil {
+			return nil, xerrors.Errorf("failed to marshallBallot: %v", err)
+		}
+
 		castVote := types.CastVote{
 			ElectionID: election.ElectionID,
 			UserID:     userID,
-			Ballot:     ballot,
+			Ballot:     ciphervote,
+			Proof:      proof,
 		}
 
 		data, err := castVote.Serialize(serdecontext)
@@ -14,11 +14,11 @@ import (
 	"os/exec"
 	"reflect"
 	"strconv"
-	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/dedis/d-voting/contracts/evoting"
 	"github.com/dedis/d-voting/contracts/evoting/types"
 	"github.com/dedis/d-voting/internal/testing/fake"
 	ptypes "github.com/dedis/d-voting/proxy/types"
@@ -27,7 +27,6 @@ import (
 	"go.dedis.ch/kyber/v3/sign/schnorr"
 	"go.dedis.ch/kyber/v3/suites"
 	"go.dedis.ch/kyber/v3/util/encoding"
-	"go.dedis.ch/kyber/v3/util/random"
 	"golang.org/x/xerrors"
 )
 
@@ -93,9 +92,13 @@ func startElectionProcess(wg *sync.WaitGroup, numNodes int, numVotes int, proxyA
 
 	configuration := fake.BasicConfiguration
 
+	// the proxy's secret key is also used as the key of the election's admin
+	adminID, err := suite.Point().Mul(secret, nil).MarshalBinary()
+	require.NoError(t, err)
+
 	createSimpleElectionRequest := ptypes.CreateElectionRequest{
 		Configuration: configuration,
-		AdminID:       "adminId",
+		AdminID:       hex.EncodeToString(adminID),
 	}
 
 	signed, err := createSignedRequest(secret, createSimpleElectionRequest)
@@ -103,7 +106,7 @@ func startElectionProcess(wg *sync.WaitGroup, numNodes int, numVotes int, proxyA
 
 	resp, err := http.Post(proxyArray[0]+"/evoting/elections", contentType, bytes.NewBuffer(signed))
 	require.NoError(t, err)
-	require.Equal(t, resp.StatusCode, http.StatusOK, "unexpected status: %s", resp.Status)
+	require.Equal(t, resp.StatusCode, http.StatusAccepted, "unexpected status: %s", resp.Status)
 
 	body, err := io.ReadAll(resp.Body)
 	require.NoError(t, err)
@@ -118,6 +121,8 @@ func startElectionProcess(wg *sync.WaitGroup, numNodes int, numVotes int, proxyA
 
 	electionID := createElectionResponse.ElectionID
 
+	waitForTransaction(proxyArray[0], createElectionResponse.TransactionID, t)
+
 	t.Logf("ID of the election : " + electionID)
 
 	// ##################################### SETUP DKG #########################
@@ -216,14 +221,18 @@ func startElectionProcess(wg *sync.WaitGroup, numNodes int, numVotes int, proxyA
 	for i := 0; i < numVotes; i++ {
 		// t.Logf("ballot in str is: %v", ballotList[i])
 
-		ballot, err := marshallBallotManual(ballotList[i], pubKey, Chunksperballot)
+		userID := "user" + strconv.Itoa(i+1)
+
+		ballot, proof, err := marshallBallotManual(ballotList[i], pubKey, electionID, userID,
+			Chunksperballot)
 		require.NoError(t, err)
 
 		// t.Logf("ballot is: %v", ballot)
 
 		castVoteRequest := ptypes.CastVoteRequest{
-			UserID: "user" + strconv.Itoa(i+1),
+			UserID: userID,
 			Ballot: ballot,
+			Proof:  proof,
 		}
 
 		randomproxy = proxyArray[rand.Intn(len(proxyArray))]
@@ -235,13 +244,22 @@ func startElectionProcess(wg *sync.WaitGroup, numNodes int, numVotes int, proxyA
 
 		resp, err = http.Post(randomproxy+"/evoting/elections/"+electionID+"/vote", contentType, bytes.NewBuffer(signed))
 		require.NoError(t, err)
-		require.Equal(t, http.StatusOK, resp.StatusCode, "unexpected status: %s", resp.Status)
+		require.Equal(t, http.StatusAccepted, resp.StatusCode, "unexpected status: %s", resp.Status)
+
+		var castVoteResponse ptypes.CastVoteResponse
 
-		_, err = io.ReadAll(resp.Body)
+		err = json.NewDecoder(resp.Body).Decode(&castVoteResponse)
 		require.NoError(t, err)
 
 		resp.Body.Close()
 
+		status := waitForTransaction(randomproxy, castVoteResponse.TransactionID, t)
+
+		for _, vote := range status.Votes {
+			if vote.UserID == userID {
+				require.Empty(t, vote.Error)
+			}
+		}
 	}
 	time.Sleep(time.Second * 5)
 
@@ -405,37 +423,31 @@ func startElectionProcess(wg *sync.WaitGroup, numNodes int, numVotes int, proxyA
 
 // -----------------------------------------------------------------------------
 // Utility functions
-func marshallBallotManual(voteStr string, pubkey kyber.Point, chunks int) (ptypes.CiphervoteJSON, error) {
+func marshallBallotManual(voteStr string, pubkey kyber.Point, electionID, userID string,
+	chunks int) (ptypes.CiphervoteJSON, []byte, error) {
 
 	ballot := make(ptypes.CiphervoteJSON, chunks)
-	vote := strings.NewReader(voteStr)
 	fmt.Printf("votestr is: %v", voteStr)
 
-	buf := make([]byte, 29)
-
-	for i := 0; i < chunks; i++ {
-		var K, C kyber.Point
-		var err error
-
-		n, err := vote.Read(buf)
-		if err != nil {
-			return nil, xerrors.Errorf("failed to read: %v", err)
-		}
-
-		K, C, _, err = encryptManual(buf[:n], pubkey)
+	ciphervote, ks, err := types.EncryptBallot(pubkey, []byte(voteStr), chunks)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("failed to encrypt the plaintext: %v", err)
+	}
 
-		if err != nil {
-			return ptypes.CiphervoteJSON{}, xerrors.Errorf("failed to encrypt the plaintext: %v", err)
-		}
+	proof, err := types.ProveBallot(electionID, userID, ciphervote, ks)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("failed to prove the ballot: %v", err)
+	}
 
-		kbuff, err := K.MarshalBinary()
+	for i, egpair := range ciphervote {
+		kbuff, err := egpair.K.MarshalBinary()
 		if err != nil {
-			return ptypes.CiphervoteJSON{}, xerrors.Errorf("failed to marshal K: %v", err)
+			return nil, nil, xerrors.Errorf("failed to marshal K: %v", err)
 		}
 
-		cbuff, err := C.MarshalBinary()
+		cbuff, err := egpair.C.MarshalBinary()
 		if err != nil {
-			return ptypes.CiphervoteJSON{}, xerrors.Errorf("failed to marshal C: %v", err)
+			return nil, nil, xerrors.Errorf("failed to marshal C: %v", err)
 		}
 
 		ballot[i] = ptypes.EGPairJSON{
@@ -444,25 +456,7 @@ func marshallBallotManual(voteStr string, pubkey kyber.Point, chunks int) (ptype
 		}
 	}
 
-	return ballot, nil
-}
-
-func encryptManual(message []byte, pubkey kyber.Point) (K, C kyber.Point, remainder []byte, err error) {
-
-	// Embed the message (or as much of it as will fit) into a curve point.
-	M := suite.Point().Embed(message, random.New())
-	max := suite.Point().EmbedLen()
-	if max > len(message) {
-		max = len(message)
-	}
-	remainder = message[max:]
-	// ElGamal-encrypt the point to produce ciphertext (K,C).
-	k := suite.Scalar().Pick(random.New()) // ephemeral private key
-	K = suite.Point().Mul(k, nil)          // ephemeral DH public key
-	S := suite.Point().Mul(k, pubkey)      // ephemeral DH shared secret
-	C = S.Add(S, M)                        // message blinded with secret
-
-	return K, C, remainder, nil
+	return ballot, proof, nil
 }
 
 func chunksPerBallot(size int) int { return (size-1)/29 + 1 }
@@ -520,8 +514,22 @@ func initDKG(secret kyber.Scalar, proxyAddr, electionIDHex string, t *testing.T)
 }
 
 func updateElection(secret kyber.Scalar, proxyAddr, electionIDHex, action string, t *testing.T) (int, error) {
+	commands := map[string]evoting.Command{
+		"open":          evoting.CmdOpenElection,
+		"close":         evoting.CmdCloseElection,
+		"combineShares": evoting.CmdCombineShares,
+		"cancel":        evoting.CmdCancelElection,
+	}
+
+	election := getElectionInfo(proxyAddr, electionIDHex, t)
+
+	adminSig, err := schnorr.Sign(suite, secret,
+		types.AdminMessage(string(commands[action]), electionIDHex, nil, election.AdminNonce))
+	require.NoError(t, err)
+
 	msg := ptypes.UpdateElectionRequest{
-		Action: action,
+		Action:         action,
+		AdminSignature: adminSig,
 	}
 
 	signed, err := createSignedRequest(secret, msg)
@@ -540,11 +548,46 @@ func updateElection(secret kyber.Scalar, proxyAddr, electionIDHex, action string
 	if err != nil {
 		return 0, xerrors.Errorf("failed to read response body: %v", err)
 	}
-	require.Equal(t, resp.StatusCode, http.StatusOK, "unexpected status: %s", body)
+	require.Equal(t, resp.StatusCode, http.StatusAccepted, "unexpected status: %s", body)
+
+	var txResponse ptypes.TransactionResponse
+
+	err = json.Unmarshal(body, &txResponse)
+	require.NoError(t, err)
+
+	waitForTransaction(proxyAddr, txResponse.TransactionID, t)
 
 	return 0, nil
 }
 
+// waitForTransaction polls the status of the transaction until it is accepted,
+// and returns its status.
+func waitForTransaction(proxyAddr, txID string, t *testing.T) ptypes.GetTransactionResponse {
+	for i := 0; i < 50; i++ {
+		resp, err := http.Get(proxyAddr + "/evoting/transactions/" + txID)
+		require.NoError(t, err)
+
+		var status ptypes.GetTransactionResponse
+
+		err = json.NewDecoder(resp.Body).Decode(&status)
+		require.NoError(t, err)
+
+		resp.Body.Close()
+
+		require.NotEqual(t, ptypes.TransactionRejected, status.Status, status.Message)
+
+		if status.Status == ptypes.TransactionAccepted {
+			return status
+		}
+
+		time.Sleep(200 * time.Millisecond)
+	}
+
+	t.Fatalf("transaction %s is still pending", txID)
+
+	return ptypes.GetTransactionResponse{}
+}
+
 func updateDKG(secret kyber.Scalar, proxyAddr, electionIDHex, action string, t *testing.T) (int, error) {
 	msg := ptypes.UpdateDKG{
 		Action: action,
@@ -3,6 +3,7 @@ package fake
 import (
 	"github.com/dedis/d-voting/services/dkg"
 	"go.dedis.ch/dela/core/txn"
+	"go.dedis.ch/dela/mino"
 	"go.dedis.ch/kyber/v3"
 )
 
@@ -37,18 +38,35 @@ func (f Pedersen) GetActor(electionID []byte) (dkg.Actor, bool) {
 
 // - implements dkg.Actor
 type DKGActor struct {
-	Err    error
-	PubKey kyber.Point
+	Err          error
+	PubKey       kyber.Point
+	Participants []mino.Address
 }
 
 func (f DKGActor) Setup() (pubKey kyber.Point, err error) {
 	return f.PubKey, f.Err
 }
 
+func (f DKGActor) Retry(excludeUnresponsive bool) (kyber.Point, error) {
+	return f.PubKey, f.Err
+}
+
 func (f DKGActor) GetPublicKey() (kyber.Point, error) {
 	return f.PubKey, f.Err
 }
 
+func (f DKGActor) GetVerificationKeys() ([]kyber.Point, error) {
+	return []kyber.Point{f.PubKey}, f.Err
+}
+
+func (f DKGActor) GetParticipants() ([]mino.Address, error) {
+	return f.Participants, f.Err
+}
+
+func (f DKGActor) GetThreshold() (int, error) {
+	return 1, f.Err
+}
+
 func (f DKGActor) Encrypt(message []byte) (K, C kyber.Point, remainder []byte, err error) {
 	return nil, nil, nil, f.Err
 }
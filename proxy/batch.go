@@ -0,0 +1,113 @@
+package proxy
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dedis/d-voting/contracts/evoting/types"
+)
+
+// voteBatchWindow is how long the first vote of a batch waits for other votes
+// before the batch is submitted.
+const voteBatchWindow = 200 * time.Millisecond
+
+// voteBatcher buffers the votes received by the proxy so that they are
+// submitted together in a single CAST_VOTES transaction. Each vote gets its
+// own outcome.
+type voteBatcher struct {
+	sync.Mutex
+
+	window  time.Duration
+	maxSize int
+	submit  func([]types.CastVote) []voteOutcome
+
+	pending []pendingVote
+	timer   *time.Timer
+}
+
+// pendingVote is a vote waiting for its batch to be submitted. The outcome of
+// the vote is sent on done.
+type pendingVote struct {
+	vote types.CastVote
+	done chan voteOutcome
+}
+
+// voteOutcome is the outcome of the submission of a vote. err is nil if the
+// vote is submitted, in which case txID is the ID of the transaction of its
+// batch.
+type voteOutcome struct {
+	txID []byte
+	err  error
+}
+
+// newVoteBatcher returns a batcher that calls submit with at most maxSize votes
+// at a time. submit must return one outcome per vote.
+func newVoteBatcher(window time.Duration, maxSize int,
+	submit func([]types.CastVote) []voteOutcome) *voteBatcher {
+
+	return &voteBatcher{
+		window:  window,
+		maxSize: maxSize,
+		submit:  submit,
+	}
+}
+
+// Add queues the vote and returns the channel on which its outcome is sent.
+// The batch is submitted when the window of its first vote has elapsed or when
+// it is full, whichever comes first.
+func (b *voteBatcher) Add(vote types.CastVote) <-chan voteOutcome {
+	b.Lock()
+	defer b.Unlock()
+
+	done := make(chan voteOutcome, 1)
+
+	b.pending = append(b.pending, pendingVote{vote: vote, done: done})
+
+	switch {
+	case len(b.pending) >= b.maxSize:
+		batch := b.take()
+		go b.flush(batch)
+	case len(b.pending) == 1:
+		b.timer = time.AfterFunc(b.window, func() {
+			b.Lock()
+			batch := b.take()
+			b.Unlock()
+
+			b.flush(batch)
+		})
+	}
+
+	return done
+}
+
+// take removes the pending votes and stops the timer of the batch. The lock
+// must be held.
+func (b *voteBatcher) take() []pendingVote {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+
+	batch := b.pending
+	b.pending = nil
+
+	return batch
+}
+
+// flush submits the batch and sends each vote its outcome.
+func (b *voteBatcher) flush(batch []pendingVote) {
+	if len(batch) == 0 {
+		return
+	}
+
+	votes := make([]types.CastVote, len(batch))
+	for i, p := range batch {
+		votes[i] = p.vote
+	}
+
+	outcomes := b.submit(votes)
+
+	for i, p := range batch {
+		p.done <- outcomes[i]
+	}
+}
@@ -0,0 +1,55 @@
+package proxy
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVoteBatcher_Window(t *testing.T) {
+	batches := make(chan []types.CastVote, 10)
+
+	b := newVoteBatcher(20*time.Millisecond, 10, func(votes []types.CastVote) []voteOutcome {
+		batches <- votes
+
+		outcomes := make([]voteOutcome, len(votes))
+		outcomes[0].txID = []byte{0xaa}
+		outcomes[1].err = errors.New("rejected")
+
+		return outcomes
+	})
+
+	done1 := b.Add(types.CastVote{UserID: "user1"})
+	done2 := b.Add(types.CastVote{UserID: "user2"})
+
+	outcome := <-done1
+	require.NoError(t, outcome.err)
+	require.Equal(t, []byte{0xaa}, outcome.txID)
+	require.EqualError(t, (<-done2).err, "rejected")
+
+	batch := <-batches
+	require.Len(t, batch, 2)
+	require.Equal(t, "user1", batch[0].UserID)
+	require.Equal(t, "user2", batch[1].UserID)
+}
+
+func TestVoteBatcher_Full(t *testing.T) {
+	batches := make(chan []types.CastVote, 10)
+
+	b := newVoteBatcher(time.Hour, 2, func(votes []types.CastVote) []voteOutcome {
+		batches <- votes
+		return make([]voteOutcome, len(votes))
+	})
+
+	done1 := b.Add(types.CastVote{UserID: "user1"})
+	done2 := b.Add(types.CastVote{UserID: "user2"})
+
+	// the batch is submitted without waiting for the window
+	require.NoError(t, (<-done1).err)
+	require.NoError(t, (<-done2).err)
+	require.Len(t, <-batches, 2)
+	require.Len(t, batches, 0)
+}
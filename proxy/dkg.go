@@ -162,6 +162,24 @@ func (d dkg) EditDKGActor(w http.ResponseWriter, r *http.Request) {
 				dela.Logger.Err(err).Msg("failed to setup")
 			}
 		}()
+	case "reshare":
+		// Like the setup, the resharing runs asynchronously and one can fetch
+		// the status of the actor to know when it is over.
+		go func() {
+			err := a.Reshare()
+			if err != nil {
+				dela.Logger.Err(err).Msg("failed to reshare")
+			}
+		}()
+	case "retry":
+		// Like the setup, the retry runs asynchronously and one can fetch the
+		// status of the actor to know when it is over.
+		go func() {
+			_, err := a.Retry(req.ExcludeUnresponsive)
+			if err != nil {
+				dela.Logger.Err(err).Msg("failed to retry setup")
+			}
+		}()
 	case "computePubshares":
 		err = a.ComputePubshares()
 		if err != nil {
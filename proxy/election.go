@@ -4,11 +4,16 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
+	"encoding/csv"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/dedis/d-voting/contracts/evoting"
 	"github.com/dedis/d-voting/contracts/evoting/types"
@@ -18,6 +23,7 @@ import (
 	"go.dedis.ch/dela"
 	"go.dedis.ch/dela/core/execution/native"
 	"go.dedis.ch/dela/core/ordering"
+	ctypes "go.dedis.ch/dela/core/ordering/cosipbft/types"
 	"go.dedis.ch/dela/core/txn"
 	"go.dedis.ch/dela/core/txn/pool"
 	"go.dedis.ch/dela/serde"
@@ -26,6 +32,26 @@ import (
 	"golang.org/x/xerrors"
 )
 
+// adminSignatureHeader is the HTTP header containing the hex-encoded signature
+// of the admin when deleting an election.
+const adminSignatureHeader = "X-Admin-Signature"
+
+// defaultElectionsLimit and maxElectionsLimit bound the number of elections
+// returned in a single page when listing the elections.
+const (
+	defaultElectionsLimit = 100
+	maxElectionsLimit     = 1000
+)
+
+// maxCatalogReads is the maximum number of catalog entries read to build a
+// single page. It bounds the cost of a request whose filter matches few
+// elections: the client continues with the returned cursor.
+const maxCatalogReads = 5000
+
+// votersBatchSize is the maximum number of voters sent in a single transaction
+// when updating the electorate.
+const votersBatchSize = 1000
+
 func newSignedErr(err error) error {
 	return xerrors.Errorf("failed to created signed request: %v", err)
 }
@@ -40,7 +66,7 @@ func NewElection(srv ordering.Service, mngr txn.Manager, p pool.Pool,
 
 	logger := dela.Logger.With().Timestamp().Str("role", "evoting-proxy").Logger()
 
-	return &election{
+	h := &election{
 		logger:      logger,
 		orderingSvc: srv,
 		context:     ctx,
@@ -49,6 +75,19 @@ func NewElection(srv ordering.Service, mngr txn.Manager, p pool.Pool,
 		pool:        p,
 		pk:          pk,
 	}
+
+	h.votes = newVoteBatcher(voteBatchWindow, types.MaxBatchedVotes, h.castVotes)
+	h.txs = newTxTracker()
+	h.events = newEventHub(logger, ctx, h.loadElection)
+
+	watchCtx, cancel := context.WithCancel(context.Background())
+	h.ctx = watchCtx
+	h.stopWatch = cancel
+	h.watchDone = make(chan struct{})
+
+	go h.watch(watchCtx, srv.Watch(watchCtx))
+
+	return h
 }
 
 // election defines HTTP handlers to manipulate the evoting smart contract
@@ -64,6 +103,50 @@ type election struct {
 	mngr        txn.Manager
 	pool        pool.Pool
 	pk          kyber.Point
+
+	// votes buffers the votes so that they are cast in batches
+	votes *voteBatcher
+
+	// txs keeps the status of the transactions submitted by the proxy
+	txs *txTracker
+
+	// events pushes the changes of the elections to the subscribers
+	events *eventHub
+
+	// ctx is done once the proxy is stopped. stopWatch stops watching the
+	// blocks, and watchDone is closed once the last block is dispatched.
+	ctx       context.Context
+	stopWatch context.CancelFunc
+	watchDone chan struct{}
+}
+
+// watch dispatches the blocks of the ordering service until the context is
+// done or events is closed.
+func (h *election) watch(ctx context.Context, events <-chan ordering.Event) {
+	defer close(h.watchDone)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			h.txs.update(event)
+			h.events.update(event)
+		}
+	}
+}
+
+// Stop implements proxy.Election. It stops watching the blocks and
+// disconnects the subscribers of the events.
+func (h *election) Stop() {
+	h.stopWatch()
+	<-h.watchDone
+
+	h.events.close()
 }
 
 // NewElection implements proxy.Proxy
@@ -83,8 +166,13 @@ func (h *election) NewElection(w http.ResponseWriter, r *http.Request) {
 	}
 
 	createElection := types.CreateElection{
-		Configuration: req.Configuration,
-		AdminID:       req.AdminID,
+		Configuration:  req.Configuration,
+		AdminID:        req.AdminID,
+		Admins:         req.Admins,
+		AdminThreshold: req.AdminThreshold,
+
+		ShuffleThreshold:    req.ShuffleThreshold,
+		DecryptionThreshold: req.DecryptionThreshold,
 	}
 
 	data, err := createElection.Serialize(h.context)
@@ -94,7 +182,7 @@ func (h *election) NewElection(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	txID, err := h.submitAndWaitForTxn(r.Context(), evoting.CmdCreateElection, evoting.ElectionArg, data)
+	txID, err := h.submitTxn(evoting.CmdCreateElection, evoting.ElectionArg, data)
 	if err != nil {
 		http.Error(w, "failed to submit txn: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -105,10 +193,13 @@ func (h *election) NewElection(w http.ResponseWriter, r *http.Request) {
 	electionID := hash.Sum(nil)
 
 	response := ptypes.CreateElectionResponse{
-		ElectionID: hex.EncodeToString(electionID),
+		ElectionID:    hex.EncodeToString(electionID),
+		TransactionID: hex.EncodeToString(txID),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+
 	err = json.NewEncoder(w).Encode(response)
 	if err != nil {
 		http.Error(w, "failed to write in ResponseWriter: "+err.Error(),
@@ -142,14 +233,9 @@ func (h *election) NewElectionVote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	elecMD, err := h.getElectionsMetadata()
+	election, err := getElection(h.context, h.electionFac, electionID, h.orderingSvc)
 	if err != nil {
-		http.Error(w, "failed to get election metadata", http.StatusNotFound)
-		return
-	}
-
-	if elecMD.ElectionsIDs.Contains(electionID) < 0 {
-		http.Error(w, "the election does not exist", http.StatusNotFound)
+		http.Error(w, "failed to get election: "+err.Error(), http.StatusNotFound)
 		return
 	}
 
@@ -182,22 +268,116 @@ func (h *election) NewElectionVote(w http.ResponseWriter, r *http.Request) {
 		ElectionID: electionID,
 		UserID:     req.UserID,
 		Ballot:     ciphervote,
+		Proof:      req.Proof,
 	}
 
-	data, err := castVote.Serialize(h.context)
+	now, err := getChainTime(h.orderingSvc, election)
 	if err != nil {
-		http.Error(w, "failed to marshal CastVoteTransaction: "+err.Error(),
-			http.StatusInternalServerError)
+		InternalError(w, r, xerrors.Errorf("failed to get time: %v", err), nil)
 		return
 	}
 
-	_, err = h.submitAndWaitForTxn(r.Context(), evoting.CmdCastVote, evoting.ElectionArg, data)
+	// the votes that the contract would skip are rejected right away
+	err = election.CheckVote(castVote, now)
 	if err != nil {
-		http.Error(w, "failed to submit txn: "+err.Error(), http.StatusInternalServerError)
+		BadRequestError(w, r, xerrors.Errorf("invalid vote: %v", err), nil)
+		return
+	}
+
+	eligible, err := h.isEligible(election, req.UserID)
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to check voter: %v", err), nil)
+		return
+	}
+
+	if !eligible {
+		BadRequestError(w, r, xerrors.Errorf("invalid vote: user %q is not "+
+			"allowed to vote", req.UserID), nil)
+		return
+	}
+
+	receipt, err := ciphervote.Receipt()
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to get receipt: %v", err), nil)
+		return
+	}
+
+	// the vote only waits for its batch to be submitted, not for the
+	// transaction to be included
+	var outcome voteOutcome
+
+	select {
+	case outcome = <-h.votes.Add(castVote):
+	case <-r.Context().Done():
+		outcome.err = r.Context().Err()
+	}
+
+	if outcome.err != nil {
+		http.Error(w, "failed to cast vote: "+outcome.err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ptypes.CastVoteResponse{
+		Receipt:       hex.EncodeToString(receipt),
+		TransactionID: hex.EncodeToString(outcome.txID),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		http.Error(w, "failed to write response: "+err.Error(),
+			http.StatusInternalServerError)
 		return
 	}
 }
 
+// castVotes submits the votes in a single transaction, without waiting for it
+// to be included, and returns the outcome of each vote.
+func (h *election) castVotes(votes []types.CastVote) []voteOutcome {
+	outcomes := make([]voteOutcome, len(votes))
+
+	var txID []byte
+
+	data, err := types.CastVotes{Votes: votes}.Serialize(h.context)
+	if err == nil {
+		txID, err = h.submitTxn(evoting.CmdCastVotes, evoting.ElectionArg, data)
+	}
+
+	for i := range outcomes {
+		outcomes[i].txID = txID
+
+		if err != nil {
+			outcomes[i].err = xerrors.Errorf("failed to submit txn: %v", err)
+		}
+	}
+
+	return outcomes
+}
+
+// isEligible returns true if the user is registered in the electorate of the
+// election, or if the electorate is not restricted.
+func (h *election) isEligible(election types.Election, userID string) (bool, error) {
+	if !election.Electorate.Restricted {
+		return true, nil
+	}
+
+	electionID, err := hex.DecodeString(election.ElectionID)
+	if err != nil {
+		return false, xerrors.Errorf("failed to decode electionID: %v", err)
+	}
+
+	hash := types.HashUserID(election.ElectionID, userID)
+
+	proof, err := h.orderingSvc.GetProof(election.Electorate.VoterKey(electionID, hash))
+	if err != nil {
+		return false, xerrors.Errorf("failed to get proof: %v", err)
+	}
+
+	return len(proof.GetValue()) != 0, nil
+}
+
 // EditElection implements proxy.Proxy
 func (h *election) EditElection(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -209,13 +389,13 @@ func (h *election) EditElection(w http.ResponseWriter, r *http.Request) {
 
 	electionID := vars["electionID"]
 
-	elecMD, err := h.getElectionsMetadata()
+	exists, err := h.electionExists(electionID)
 	if err != nil {
-		http.Error(w, "failed to get election metadata", http.StatusNotFound)
+		http.Error(w, "failed to get election: "+err.Error(), http.StatusNotFound)
 		return
 	}
 
-	if elecMD.ElectionsIDs.Contains(electionID) < 0 {
+	if !exists {
 		http.Error(w, "the election does not exist", http.StatusNotFound)
 		return
 	}
@@ -236,13 +416,17 @@ func (h *election) EditElection(w http.ResponseWriter, r *http.Request) {
 
 	switch req.Action {
 	case "open":
-		h.openElection(electionID, w, r)
+		h.openElection(electionID, req.AdminSignature, w, r)
 	case "close":
-		h.closeElection(electionID, w, r)
+		h.closeElection(electionID, req.AdminSignature, w, r)
 	case "combineShares":
-		h.combineShares(electionID, w, r)
+		h.combineShares(electionID, req.AdminSignature, w, r)
 	case "cancel":
-		h.cancelElection(electionID, w, r)
+		h.cancelElection(electionID, req.AdminSignature, w, r)
+	case "updateConfiguration":
+		h.updateConfiguration(electionID, req.Configuration, req.AdminSignature, w, r)
+	case "updateRoster":
+		h.updateRoster(electionID, req.AdminSignature, w, r)
 	default:
 		BadRequestError(w, r, xerrors.Errorf("invalid action: %s", req.Action), nil)
 		return
@@ -251,9 +435,12 @@ func (h *election) EditElection(w http.ResponseWriter, r *http.Request) {
 
 // openElection allows opening an election, which sets the public key based on
 // the DKG actor.
-func (h *election) openElection(elecID string, w http.ResponseWriter, r *http.Request) {
+func (h *election) openElection(elecID string, adminSig []byte, w http.ResponseWriter,
+	r *http.Request) {
+
 	openElection := types.OpenElection{
-		ElectionID: elecID,
+		ElectionID:     elecID,
+		AdminSignature: adminSig,
 	}
 
 	data, err := openElection.Serialize(h.context)
@@ -263,18 +450,16 @@ func (h *election) openElection(elecID string, w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	_, err = h.submitAndWaitForTxn(r.Context(), evoting.CmdOpenElection, evoting.ElectionArg, data)
-	if err != nil {
-		http.Error(w, "failed to submit txn: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
+	h.submitAsync(w, r, evoting.CmdOpenElection, data)
 }
 
 // closeElection closes an election.
-func (h *election) closeElection(electionIDHex string, w http.ResponseWriter, r *http.Request) {
+func (h *election) closeElection(electionIDHex string, adminSig []byte, w http.ResponseWriter,
+	r *http.Request) {
 
 	closeElection := types.CloseElection{
-		ElectionID: electionIDHex,
+		ElectionID:     electionIDHex,
+		AdminSignature: adminSig,
 	}
 
 	data, err := closeElection.Serialize(h.context)
@@ -284,15 +469,12 @@ func (h *election) closeElection(electionIDHex string, w http.ResponseWriter, r
 		return
 	}
 
-	_, err = h.submitAndWaitForTxn(r.Context(), evoting.CmdCloseElection, evoting.ElectionArg, data)
-	if err != nil {
-		http.Error(w, "failed to submit txn: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
+	h.submitAsync(w, r, evoting.CmdCloseElection, data)
 }
 
 // combineShares decrypts the shuffled ballots in an election.
-func (h *election) combineShares(electionIDHex string, w http.ResponseWriter, r *http.Request) {
+func (h *election) combineShares(electionIDHex string, adminSig []byte, w http.ResponseWriter,
+	r *http.Request) {
 
 	election, err := getElection(h.context, h.electionFac, electionIDHex, h.orderingSvc)
 	if err != nil {
@@ -307,7 +489,8 @@ func (h *election) combineShares(electionIDHex string, w http.ResponseWriter, r
 	}
 
 	decryptBallots := types.CombineShares{
-		ElectionID: electionIDHex,
+		ElectionID:     electionIDHex,
+		AdminSignature: adminSig,
 	}
 
 	data, err := decryptBallots.Serialize(h.context)
@@ -317,18 +500,16 @@ func (h *election) combineShares(electionIDHex string, w http.ResponseWriter, r
 		return
 	}
 
-	_, err = h.submitAndWaitForTxn(r.Context(), evoting.CmdCombineShares, evoting.ElectionArg, data)
-	if err != nil {
-		http.Error(w, "failed to submit txn: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
+	h.submitAsync(w, r, evoting.CmdCombineShares, data)
 }
 
 // cancelElection cancels an election.
-func (h *election) cancelElection(electionIDHex string, w http.ResponseWriter, r *http.Request) {
+func (h *election) cancelElection(electionIDHex string, adminSig []byte, w http.ResponseWriter,
+	r *http.Request) {
 
 	cancelElection := types.CancelElection{
-		ElectionID: electionIDHex,
+		ElectionID:     electionIDHex,
+		AdminSignature: adminSig,
 	}
 
 	data, err := cancelElection.Serialize(h.context)
@@ -338,11 +519,48 @@ func (h *election) cancelElection(electionIDHex string, w http.ResponseWriter, r
 		return
 	}
 
-	_, err = h.submitAndWaitForTxn(r.Context(), evoting.CmdCancelElection, evoting.ElectionArg, data)
+	h.submitAsync(w, r, evoting.CmdCancelElection, data)
+}
+
+// updateConfiguration replaces the configuration of an election that is not
+// open yet.
+func (h *election) updateConfiguration(electionIDHex string, configuration []byte,
+	adminSig []byte, w http.ResponseWriter, r *http.Request) {
+
+	updateConfiguration := types.UpdateConfiguration{
+		ElectionID:     electionIDHex,
+		Configuration:  configuration,
+		AdminSignature: adminSig,
+	}
+
+	data, err := updateConfiguration.Serialize(h.context)
 	if err != nil {
-		http.Error(w, "failed to submit txn: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "failed to marshal UpdateConfiguration: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	h.submitAsync(w, r, evoting.CmdUpdateConfiguration, data)
+}
+
+// updateRoster replaces the roster of an election by the roster of the chain,
+// once the DKG has been reshared.
+func (h *election) updateRoster(electionIDHex string, adminSig []byte,
+	w http.ResponseWriter, r *http.Request) {
+
+	updateRoster := types.UpdateRoster{
+		ElectionID:     electionIDHex,
+		AdminSignature: adminSig,
+	}
+
+	data, err := updateRoster.Serialize(h.context)
+	if err != nil {
+		http.Error(w, "failed to marshal UpdateRoster: "+err.Error(),
+			http.StatusInternalServerError)
 		return
 	}
+
+	h.submitAsync(w, r, evoting.CmdUpdateRoster, data)
 }
 
 // Election implements proxy.Proxy. The request should not be signed because it
@@ -385,14 +603,23 @@ func (h *election) Election(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := ptypes.GetElectionResponse{
-		ElectionID:      string(election.ElectionID),
-		Configuration:   election.Configuration,
-		Status:          uint16(election.Status),
-		Pubkey:          hex.EncodeToString(pubkeyBuf),
-		Result:          election.DecryptedBallots,
-		Roster:          roster,
-		ChunksPerBallot: election.ChunksPerBallot(),
-		BallotSize:      election.BallotSize,
+		ElectionID:       string(election.ElectionID),
+		Configuration:    election.Configuration,
+		Status:           uint16(election.Status),
+		StatusReason:     election.StatusReason,
+		Pubkey:           hex.EncodeToString(pubkeyBuf),
+		Result:           election.DecryptedBallots,
+		Roster:           roster,
+		ChunksPerBallot:  election.ChunksPerBallot(),
+		BallotSize:       election.BallotSize,
+		EligibleVoters:   election.Electorate.Len(),
+		RestrictedVoters: election.Electorate.Restricted,
+		Admins:           election.Admins.Keys,
+		AdminThreshold:   election.Admins.Threshold,
+		AdminNonce:       election.AdminNonce,
+
+		ShuffleThreshold:    election.ShuffleThreshold,
+		DecryptionThreshold: election.DecryptionThreshold,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -405,48 +632,35 @@ func (h *election) Election(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// Elections implements proxy.Proxy. The request should not be signed because it
-// is fecthing public data.
-func (h *election) Elections(w http.ResponseWriter, r *http.Request) {
+// Results implements proxy.Proxy. The request should not be signed because it
+// is fetching public data.
+func (h *election) Results(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Access-Control-Allow-Headers", "*")
 
-	elecMD, err := h.getElectionsMetadata()
-	if err != nil {
-		InternalError(w, r, xerrors.Errorf("failed to get election metadata: %v", err), nil)
+	vars := mux.Vars(r)
+
+	if vars == nil || vars["electionID"] == "" {
+		http.Error(w, fmt.Sprintf("electionID not found: %v", vars), http.StatusInternalServerError)
 		return
 	}
 
-	allElectionsInfo := make([]ptypes.LightElection, len(elecMD.ElectionsIDs))
-
-	for i, id := range elecMD.ElectionsIDs {
-		election, err := getElection(h.context, h.electionFac, id, h.orderingSvc)
-		if err != nil {
-			InternalError(w, r, xerrors.Errorf("failed to get election: %v", err), nil)
-			return
-		}
-
-		var pubkeyBuf []byte
-
-		if election.Pubkey != nil {
-			pubkeyBuf, err = election.Pubkey.MarshalBinary()
-			if err != nil {
-				InternalError(w, r, xerrors.Errorf("failed to marshal pubkey: %v", err), nil)
-				return
-			}
-		}
-
-		info := ptypes.LightElection{
-			ElectionID: string(election.ElectionID),
-			Title:      election.Configuration.MainTitle,
-			Status:     uint16(election.Status),
-			Pubkey:     hex.EncodeToString(pubkeyBuf),
-		}
+	election, err := getElection(h.context, h.electionFac, vars["electionID"], h.orderingSvc)
+	if err != nil {
+		NotFoundErr(w, r, xerrors.Errorf("failed to get election: %v", err), nil)
+		return
+	}
 
-		allElectionsInfo[i] = info
+	if election.Status != types.ResultAvailable {
+		NotFoundErr(w, r, xerrors.Errorf("the results are not available, "+
+			"current status: %d", election.Status), nil)
+		return
 	}
 
-	response := ptypes.GetElectionsResponse{Elections: allElectionsInfo}
+	response := ptypes.GetResultsResponse{
+		ElectionID: election.ElectionID,
+		Tally:      election.Tally,
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 
@@ -457,173 +671,1050 @@ func (h *election) Elections(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// DeleteElection implements proxy.Proxy
-func (h *election) DeleteElection(w http.ResponseWriter, r *http.Request) {
+// Ballot implements proxy.Proxy. It looks up a ballot with the receipt
+// returned when casting the vote, and tells whether the ballot is in the
+// suffragia. The request should not be signed because it is fetching public
+// data.
+func (h *election) Ballot(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Headers", "*")
+
 	vars := mux.Vars(r)
 
-	if vars == nil || vars["electionID"] == "" {
-		http.Error(w, fmt.Sprintf("electionID not found: %v", vars), http.StatusInternalServerError)
+	if vars == nil || vars["electionID"] == "" || vars["receipt"] == "" {
+		http.Error(w, fmt.Sprintf("electionID or receipt not found: %v", vars),
+			http.StatusInternalServerError)
 		return
 	}
 
-	electionID := vars["electionID"]
-
-	elecMD, err := h.getElectionsMetadata()
+	receipt, err := hex.DecodeString(vars["receipt"])
 	if err != nil {
-		http.Error(w, "failed to get election metadata", http.StatusNotFound)
+		BadRequestError(w, r, xerrors.Errorf("failed to decode receipt: %v", err), nil)
 		return
 	}
 
-	if elecMD.ElectionsIDs.Contains(electionID) < 0 {
-		http.Error(w, "the election does not exist", http.StatusNotFound)
+	election, err := getElection(h.context, h.electionFac, vars["electionID"], h.orderingSvc)
+	if err != nil {
+		NotFoundErr(w, r, xerrors.Errorf("failed to get election: %v", err), nil)
 		return
 	}
 
-	// auth should contain the hex-encoded signature on the hex-encoded election
-	// ID
-	auth := r.Header.Get("Authorization")
-
-	sig, err := hex.DecodeString(auth)
+	ballot, index, proof, err := h.findBallot(election, vars["electionID"], receipt)
 	if err != nil {
-		BadRequestError(w, r, xerrors.Errorf("failed to decode auth: %v", err), nil)
+		NotFoundErr(w, r, xerrors.Errorf("failed to find ballot: %v", err), nil)
 		return
 	}
 
-	err = schnorr.Verify(suite, h.pk, []byte(electionID), sig)
+	proofJSON, err := h.newBallotProof(proof)
 	if err != nil {
-		ForbiddenError(w, r, xerrors.Errorf("signature verification failed: %v", err), nil)
+		InternalError(w, r, xerrors.Errorf("failed to get proof: %v", err), nil)
 		return
 	}
 
-	deleteElection := types.DeleteElection{
-		ElectionID: electionID,
+	ballotJSON := make(ptypes.CiphervoteJSON, len(ballot))
+
+	for i, egpair := range ballot {
+		k, err := egpair.K.MarshalBinary()
+		if err != nil {
+			InternalError(w, r, xerrors.Errorf("failed to marshal K: %v", err), nil)
+			return
+		}
+
+		c, err := egpair.C.MarshalBinary()
+		if err != nil {
+			InternalError(w, r, xerrors.Errorf("failed to marshal C: %v", err), nil)
+			return
+		}
+
+		ballotJSON[i] = ptypes.EGPairJSON{K: k, C: c}
 	}
 
-	data, err := deleteElection.Serialize(h.context)
-	if err != nil {
-		InternalError(w, r, xerrors.Errorf("failed to marshal DeleteElection: %v", err), nil)
-		return
+	response := ptypes.GetBallotResponse{
+		Receipt:        vars["receipt"],
+		ElectionStatus: uint16(election.Status),
+		Ballot:         ballotJSON,
+		Proof:          proofJSON,
+		InSuffragia:    index >= 0,
+		SuffragiaIndex: index,
 	}
 
-	_, err = h.submitAndWaitForTxn(r.Context(), evoting.CmdDeleteElection, evoting.ElectionArg, data)
+	w.Header().Set("Content-Type", "application/json")
+
+	err = json.NewEncoder(w).Encode(response)
 	if err != nil {
-		http.Error(w, "failed to submit txn: "+err.Error(), http.StatusInternalServerError)
+		InternalError(w, r, xerrors.Errorf("failed to write response: %v", err), nil)
 		return
 	}
 }
 
-// waitForTxnID blocks until `ID` is included or `events` is closed.
-func (h *election) waitForTxnID(events <-chan ordering.Event, ID []byte) error {
-	for event := range events {
-		for _, res := range event.Transactions {
-			if !bytes.Equal(res.GetTransaction().GetID(), ID) {
-				continue
-			}
+// Bulletin implements proxy.Proxy. It streams the bulletin board of the
+// election as newline-delimited JSON, see types.WriteBulletin. The request
+// should not be signed because it is fetching public data.
+func (h *election) Bulletin(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Headers", "*")
 
-			ok, msg := res.GetStatus()
-			if !ok {
-				return xerrors.Errorf("transaction %x denied : %s", ID, msg)
-			}
+	vars := mux.Vars(r)
 
-			return nil
-		}
+	if vars == nil || vars["electionID"] == "" {
+		http.Error(w, fmt.Sprintf("electionID not found: %v", vars), http.StatusInternalServerError)
+		return
 	}
 
-	return xerrors.New("transaction not found")
-}
+	election, err := getElection(h.context, h.electionFac, vars["electionID"], h.orderingSvc)
+	if err != nil {
+		NotFoundErr(w, r, xerrors.Errorf("failed to get election: %v", err), nil)
+		return
+	}
 
-func (h *election) getElectionsMetadata() (types.ElectionsMetadata, error) {
-	var md types.ElectionsMetadata
+	w.Header().Set("Content-Type", "application/x-ndjson")
 
-	proof, err := h.orderingSvc.GetProof([]byte(evoting.ElectionsMetadataKey))
+	// the status is already sent if writing fails, so the error can only be
+	// logged
+	err = types.WriteBulletin(w, election)
 	if err != nil {
-		// if the proof doesn't exist we assume there is no metadata, thus no
-		// elections has been created so far.
-		return md, nil
+		h.logger.Err(err).Msg("failed to write bulletin board")
 	}
+}
+
+// findBallot returns the ballot with the receipt and its position in the
+// suffragia, or -1 if the ballots have not been gathered yet, and the proof of
+// the key that stores it.
+func (h *election) findBallot(election types.Election, electionIDHex string,
+	receipt []byte) (types.Ciphervote, int, ordering.Proof, error) {
 
-	// if there is not election created yet the metadata will be empty
-	if len(proof.GetValue()) == 0 {
-		return types.ElectionsMetadata{}, nil
+	electionID, err := hex.DecodeString(electionIDHex)
+	if err != nil {
+		return nil, -1, nil, xerrors.Errorf("failed to decode electionIDHex: %v", err)
 	}
 
-	err = json.Unmarshal(proof.GetValue(), &md)
+	proof, err := h.orderingSvc.GetProof(types.ReceiptKey(electionID, receipt))
 	if err != nil {
-		return md, xerrors.Errorf("failed to unmarshal ElectionMetadata: %v", err)
+		return nil, -1, nil, xerrors.Errorf("failed to get proof: %v", err)
 	}
 
-	return md, nil
-}
+	userID := string(proof.GetValue())
+	if userID == "" {
+		return nil, -1, nil, xerrors.New("unknown receipt")
+	}
 
-// getElection gets the election from the snap. Returns the election ID NOT hex
-// encoded.
-func getElection(ctx serde.Context, electionFac serde.Factory, electionIDHex string,
-	srv ordering.Service) (types.Election, error) {
+	var ballot types.Ciphervote
 
-	var election types.Election
+	index := -1
 
-	electionID, err := hex.DecodeString(electionIDHex)
-	if err != nil {
-		return election, xerrors.Errorf("failed to decode electionIDHex: %v", err)
+	// the ballot is in the election once gathered
+	if len(election.Suffragia.UserIDs) != 0 {
+		proof, err = h.orderingSvc.GetProof(electionID)
+	} else {
+		proof, err = h.orderingSvc.GetProof(types.BallotKey(electionID, userID))
 	}
 
-	proof, err := srv.GetProof(electionID)
 	if err != nil {
-		return election, xerrors.Errorf("failed to get proof: %v", err)
+		return nil, -1, nil, xerrors.Errorf("failed to get proof: %v", err)
 	}
 
-	electionBuff := proof.GetValue()
-	if len(electionBuff) == 0 {
-		return election, xerrors.Errorf("election does not exist")
+	if len(election.Suffragia.UserIDs) == 0 {
+		message, err := types.CiphervoteFactory{}.Deserialize(h.context, proof.GetValue())
+		if err != nil {
+			return nil, -1, nil, xerrors.Errorf("failed to deserialize ballot: %v", err)
+		}
+
+		var ok bool
+
+		ballot, ok = message.(types.Ciphervote)
+		if !ok {
+			return nil, -1, nil, xerrors.Errorf("wrong message type: %T", message)
+		}
+	} else {
+		for i, u := range election.Suffragia.UserIDs {
+			if u == userID {
+				ballot = election.Suffragia.Ciphervotes[i]
+				index = i
+			}
+		}
 	}
 
-	message, err := electionFac.Deserialize(ctx, electionBuff)
+	if ballot == nil {
+		return nil, -1, nil, xerrors.Errorf("ballot of the receipt not found")
+	}
+
+	stored, err := ballot.Receipt()
 	if err != nil {
-		return election, xerrors.Errorf("failed to deserialize Election: %v", err)
+		return nil, -1, nil, xerrors.Errorf("failed to get receipt: %v", err)
 	}
 
-	election, ok := message.(types.Election)
-	if !ok {
-		return election, xerrors.Errorf("wrong message type: %T", message)
+	if !bytes.Equal(stored, receipt) {
+		return nil, -1, nil, xerrors.New("the ballot has been replaced")
 	}
 
-	return election, nil
+	return ballot, index, proof, nil
 }
 
-// submitAndWaitForTxn submits a transaction and waits for it to be included.
-// Returns the transaction ID.
-func (h *election) submitAndWaitForTxn(ctx context.Context, cmd evoting.Command,
-	cmdArg string, payload []byte) ([]byte, error) {
+// chainProof is implemented by the proofs of the cosipbft ordering service,
+// which include the chain of block links up to the block of the value.
+type chainProof interface {
+	GetChain() ctypes.Chain
+}
 
-	h.Lock()
-	defer h.Unlock()
+// newBallotProof returns the JSON representation of the proof, with the
+// serialized chain of block links when the ordering service provides it.
+func (h *election) newBallotProof(proof ordering.Proof) (ptypes.BallotProofJSON, error) {
+	res := ptypes.BallotProofJSON{
+		Key:   proof.GetKey(),
+		Value: proof.GetValue(),
+	}
 
-	err := h.mngr.Sync()
-	if err != nil {
-		return nil, xerrors.Errorf("failed to sync manager: %v", err)
+	withChain, ok := proof.(chainProof)
+	if !ok {
+		return res, nil
 	}
 
-	tx, err := createTransaction(h.mngr, cmd, cmdArg, payload)
+	chain := withChain.GetChain()
+
+	chainBuf, err := chain.Serialize(h.context)
 	if err != nil {
-		return nil, xerrors.Errorf("failed to create transaction: %v", err)
+		return res, xerrors.Errorf("failed to serialize chain: %v", err)
 	}
 
-	watchCtx, cancel := context.WithTimeout(ctx, inclusionTimeout)
-	defer cancel()
+	block := chain.GetBlock()
+	hash := block.GetHash()
 
-	events := h.orderingSvc.Watch(watchCtx)
+	res.Chain = chainBuf
+	res.BlockIndex = block.GetIndex()
+	res.BlockHash = hex.EncodeToString(hash[:])
 
-	err = h.pool.Add(tx)
-	if err != nil {
-		return nil, xerrors.Errorf("failed to add transaction to the pool: %v", err)
-	}
+	return res, nil
+}
 
-	err = h.waitForTxnID(events, tx.GetID())
-	if err != nil {
-		return nil, xerrors.Errorf("failed to wait for transaction: %v", err)
-	}
+// Elections implements proxy.Proxy. The request should not be signed because it
+// is fecthing public data. The elections are read from the catalog, page by
+// page, and can be filtered by status, admin and title.
+func (h *election) Elections(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Headers", "*")
 
-	return tx.GetID(), nil
+	query := r.URL.Query()
+
+	var cursor uint64
+	var err error
+
+	if query.Get("cursor") != "" {
+		cursor, err = strconv.ParseUint(query.Get("cursor"), 10, 64)
+		if err != nil {
+			BadRequestError(w, r, xerrors.Errorf("invalid cursor: %v", err), nil)
+			return
+		}
+	}
+
+	limit := defaultElectionsLimit
+
+	if query.Get("limit") != "" {
+		limit, err = strconv.Atoi(query.Get("limit"))
+		if err != nil || limit <= 0 || limit > maxElectionsLimit {
+			BadRequestError(w, r, xerrors.Errorf("limit must be between 1 and %d",
+				maxElectionsLimit), nil)
+			return
+		}
+	}
+
+	filter := types.CatalogFilter{
+		AdminID: query.Get("admin"),
+		Title:   query.Get("title"),
+	}
+
+	if query.Get("status") != "" {
+		status, err := strconv.ParseUint(query.Get("status"), 10, 16)
+		if err != nil {
+			BadRequestError(w, r, xerrors.Errorf("invalid status: %v", err), nil)
+			return
+		}
+
+		filter.Status = new(types.Status)
+		*filter.Status = types.Status(status)
+	}
+
+	entries, nextCursor, err := h.getCatalogEntries(cursor, limit, filter)
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to get catalog: %v", err), nil)
+		return
+	}
+
+	elections := make([]ptypes.LightElection, len(entries))
+
+	for i, entry := range entries {
+		elections[i] = ptypes.LightElection{
+			ElectionID: entry.ElectionID,
+			Title:      entry.Title,
+			Status:     uint16(entry.Status),
+			Pubkey:     entry.Pubkey,
+			AdminID:    entry.AdminID,
+		}
+	}
+
+	response := ptypes.GetElectionsResponse{
+		Elections:  elections,
+		NextCursor: nextCursor,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to write response: %v", err), nil)
+		return
+	}
+}
+
+// DeleteElection implements proxy.Proxy
+func (h *election) DeleteElection(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if vars == nil || vars["electionID"] == "" {
+		http.Error(w, fmt.Sprintf("electionID not found: %v", vars), http.StatusInternalServerError)
+		return
+	}
+
+	electionID := vars["electionID"]
+
+	exists, err := h.electionExists(electionID)
+	if err != nil {
+		http.Error(w, "failed to get election: "+err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if !exists {
+		http.Error(w, "the election does not exist", http.StatusNotFound)
+		return
+	}
+
+	// auth should contain the hex-encoded signature on the hex-encoded election
+	// ID
+	auth := r.Header.Get("Authorization")
+
+	sig, err := hex.DecodeString(auth)
+	if err != nil {
+		BadRequestError(w, r, xerrors.Errorf("failed to decode auth: %v", err), nil)
+		return
+	}
+
+	err = schnorr.Verify(suite, h.pk, []byte(electionID), sig)
+	if err != nil {
+		ForbiddenError(w, r, xerrors.Errorf("signature verification failed: %v", err), nil)
+		return
+	}
+
+	// the admin's signature authorizing the deletion is hex-encoded
+	adminSig, err := hex.DecodeString(r.Header.Get(adminSignatureHeader))
+	if err != nil {
+		BadRequestError(w, r, xerrors.Errorf("failed to decode admin signature: %v", err), nil)
+		return
+	}
+
+	deleteElection := types.DeleteElection{
+		ElectionID:     electionID,
+		AdminSignature: adminSig,
+	}
+
+	data, err := deleteElection.Serialize(h.context)
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to marshal DeleteElection: %v", err), nil)
+		return
+	}
+
+	h.submitAsync(w, r, evoting.CmdDeleteElection, data)
+}
+
+// EditVoters implements proxy.Proxy. POST adds the voters to the electorate,
+// DELETE removes them, and PUT replaces the whole electorate. Large lists are
+// split into several transactions, submitted in order as a single operation.
+func (h *election) EditVoters(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if vars == nil || vars["electionID"] == "" {
+		http.Error(w, fmt.Sprintf("electionID not found: %v", vars), http.StatusInternalServerError)
+		return
+	}
+
+	electionID := vars["electionID"]
+
+	exists, err := h.electionExists(electionID)
+	if err != nil {
+		http.Error(w, "failed to get election: "+err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if !exists {
+		http.Error(w, "the election does not exist", http.StatusNotFound)
+		return
+	}
+
+	var req ptypes.UpdateVotersRequest
+
+	signed, err := ptypes.NewSignedRequest(r.Body)
+	if err != nil {
+		InternalError(w, r, newSignedErr(err), nil)
+		return
+	}
+
+	err = signed.GetAndVerify(h.pk, &req)
+	if err != nil {
+		InternalError(w, r, getSignedErr(err), nil)
+		return
+	}
+
+	userIDs, err := parseVoters(req)
+	if err != nil {
+		BadRequestError(w, r, xerrors.Errorf("failed to parse voters: %v", err), nil)
+		return
+	}
+
+	hashes := make([]string, len(userIDs))
+	for i, userID := range userIDs {
+		hashes[i] = types.HashUserID(electionID, userID)
+	}
+
+	if r.Method != http.MethodPost && r.Method != http.MethodPut &&
+		r.Method != http.MethodDelete {
+
+		BadRequestError(w, r, xerrors.Errorf("invalid method: %s", r.Method), nil)
+		return
+	}
+
+	batches := batchVoters(hashes)
+
+	// a replacement is a single transaction, otherwise the electorate would be
+	// incomplete until the last batch is accepted
+	if r.Method == http.MethodPut && len(batches) > 1 {
+		BadRequestError(w, r, xerrors.Errorf("a replacement is limited to %d "+
+			"voters, the others must be added afterwards: %d", votersBatchSize,
+			len(hashes)), nil)
+		return
+	}
+
+	// each transaction is authorized by its own signature since the payload
+	// and the nonce differ
+	if len(req.AdminSignatures) != len(batches) {
+		BadRequestError(w, r, xerrors.Errorf("expected %d admin signatures, got %d",
+			len(batches), len(req.AdminSignatures)), nil)
+		return
+	}
+
+	var cmd evoting.Command
+
+	switch r.Method {
+	case http.MethodPost:
+		cmd = evoting.CmdRegisterVoters
+	case http.MethodDelete:
+		cmd = evoting.CmdRemoveVoters
+	default:
+		cmd = evoting.CmdReplaceVoters
+	}
+
+	txs := make([][]byte, len(batches))
+
+	for i, batch := range batches {
+		var msg serde.Message
+
+		switch cmd {
+		case evoting.CmdRegisterVoters:
+			msg = types.RegisterVoters{ElectionID: electionID, Voters: batch,
+				AdminSignature: req.AdminSignatures[i]}
+		case evoting.CmdRemoveVoters:
+			msg = types.RemoveVoters{ElectionID: electionID, Voters: batch,
+				AdminSignature: req.AdminSignatures[i]}
+		default:
+			msg = types.ReplaceVoters{ElectionID: electionID, Voters: batch,
+				AdminSignature: req.AdminSignatures[i]}
+		}
+
+		txs[i], err = msg.Serialize(h.context)
+		if err != nil {
+			InternalError(w, r, xerrors.Errorf("failed to marshal %s: %v", cmd, err), nil)
+			return
+		}
+	}
+
+	if len(txs) == 1 {
+		h.submitAsync(w, r, cmd, txs[0])
+		return
+	}
+
+	h.submitOperation(w, r, cmd, txs)
+}
+
+// Actions implements proxy.Proxy. The request should not be signed because it
+// is fetching public data.
+func (h *election) Actions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Headers", "*")
+
+	vars := mux.Vars(r)
+
+	if vars == nil || vars["electionID"] == "" {
+		http.Error(w, fmt.Sprintf("electionID not found: %v", vars), http.StatusInternalServerError)
+		return
+	}
+
+	election, err := getElection(h.context, h.electionFac, vars["electionID"], h.orderingSvc)
+	if err != nil {
+		NotFoundErr(w, r, xerrors.Errorf("failed to get election: %v", err), nil)
+		return
+	}
+
+	response := ptypes.GetActionsResponse{
+		Threshold: election.Admins.Threshold,
+		Actions:   election.PendingActions,
+	}
+
+	if response.Actions == nil {
+		response.Actions = []types.PendingAction{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to write response: %v", err), nil)
+		return
+	}
+}
+
+// NewAction implements proxy.Proxy. It proposes a sensitive action that must
+// be approved by other admins.
+func (h *election) NewAction(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if vars == nil || vars["electionID"] == "" {
+		http.Error(w, fmt.Sprintf("electionID not found: %v", vars), http.StatusInternalServerError)
+		return
+	}
+
+	electionID := vars["electionID"]
+
+	var req ptypes.ProposeActionRequest
+
+	signed, err := ptypes.NewSignedRequest(r.Body)
+	if err != nil {
+		InternalError(w, r, newSignedErr(err), nil)
+		return
+	}
+
+	err = signed.GetAndVerify(h.pk, &req)
+	if err != nil {
+		InternalError(w, r, getSignedErr(err), nil)
+		return
+	}
+
+	commands := map[string]evoting.Command{
+		"close":  evoting.CmdCloseElection,
+		"cancel": evoting.CmdCancelElection,
+		"delete": evoting.CmdDeleteElection,
+	}
+
+	cmd, found := commands[req.Action]
+	if !found {
+		BadRequestError(w, r, xerrors.Errorf("invalid action: %s", req.Action), nil)
+		return
+	}
+
+	proposeAction := types.ProposeAction{
+		ElectionID:     electionID,
+		Command:        string(cmd),
+		AdminID:        req.AdminID,
+		AdminSignature: req.AdminSignature,
+	}
+
+	data, err := proposeAction.Serialize(h.context)
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to marshal ProposeAction: %v", err), nil)
+		return
+	}
+
+	txID, err := h.submitTxn(evoting.CmdProposeAction, evoting.ElectionArg, data)
+	if err != nil {
+		http.Error(w, "failed to submit txn: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ptypes.ProposeActionResponse{
+		ActionID:      hex.EncodeToString(txID),
+		TransactionID: hex.EncodeToString(txID),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to write response: %v", err), nil)
+		return
+	}
+}
+
+// ApproveAction implements proxy.Proxy. The action is executed by the smart
+// contract once it has enough approvals.
+func (h *election) ApproveAction(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if vars == nil || vars["electionID"] == "" || vars["actionID"] == "" {
+		http.Error(w, fmt.Sprintf("electionID or actionID not found: %v", vars),
+			http.StatusInternalServerError)
+		return
+	}
+
+	var req ptypes.ApproveActionRequest
+
+	signed, err := ptypes.NewSignedRequest(r.Body)
+	if err != nil {
+		InternalError(w, r, newSignedErr(err), nil)
+		return
+	}
+
+	err = signed.GetAndVerify(h.pk, &req)
+	if err != nil {
+		InternalError(w, r, getSignedErr(err), nil)
+		return
+	}
+
+	approveAction := types.ApproveAction{
+		ElectionID:     vars["electionID"],
+		ActionID:       vars["actionID"],
+		AdminID:        req.AdminID,
+		AdminSignature: req.AdminSignature,
+	}
+
+	data, err := approveAction.Serialize(h.context)
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to marshal ApproveAction: %v", err), nil)
+		return
+	}
+
+	h.submitAsync(w, r, evoting.CmdApproveAction, data)
+}
+
+// batchVoters splits the voters in batches of at most votersBatchSize. It
+// always returns at least one batch so that an empty list can still be used
+// to clear the electorate.
+func batchVoters(voters []string) [][]string {
+	batches := [][]string{}
+
+	for start := 0; start < len(voters); start += votersBatchSize {
+		end := start + votersBatchSize
+		if end > len(voters) {
+			end = len(voters)
+		}
+
+		batches = append(batches, voters[start:end])
+	}
+
+	if len(batches) == 0 {
+		batches = append(batches, []string{})
+	}
+
+	return batches
+}
+
+// parseVoters returns the user IDs contained in the request, reading the
+// first column of each CSV record. Empty IDs are ignored.
+func parseVoters(req ptypes.UpdateVotersRequest) ([]string, error) {
+	userIDs := make([]string, 0, len(req.UserIDs))
+
+	for _, userID := range req.UserIDs {
+		userID = strings.TrimSpace(userID)
+		if userID != "" {
+			userIDs = append(userIDs, userID)
+		}
+	}
+
+	if req.CSV == "" {
+		return userIDs, nil
+	}
+
+	reader := csv.NewReader(strings.NewReader(req.CSV))
+	reader.FieldsPerRecord = -1
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, xerrors.Errorf("failed to read CSV: %v", err)
+		}
+
+		userID := strings.TrimSpace(record[0])
+		if userID != "" {
+			userIDs = append(userIDs, userID)
+		}
+	}
+
+	return userIDs, nil
+}
+
+// electionExists checks that the election is stored on the chain without
+// deserializing it. electionIDHex is hex-encoded.
+func (h *election) electionExists(electionIDHex string) (bool, error) {
+	electionID, err := hex.DecodeString(electionIDHex)
+	if err != nil {
+		return false, xerrors.Errorf("failed to decode electionIDHex: %v", err)
+	}
+
+	proof, err := h.orderingSvc.GetProof(electionID)
+	if err != nil {
+		return false, xerrors.Errorf("failed to get proof: %v", err)
+	}
+
+	return len(proof.GetValue()) != 0, nil
+}
+
+// getCatalogEntries returns the entries of the catalog that match the filter,
+// starting at the cursor. It stops after limit entries or after reading
+// maxCatalogReads entries, and returns the cursor of the next page, which is
+// empty if the end of the catalog has been reached.
+func (h *election) getCatalogEntries(cursor uint64, limit int,
+	filter types.CatalogFilter) ([]types.CatalogEntry, string, error) {
+
+	proof, err := h.orderingSvc.GetProof([]byte(types.CatalogCountKey))
+	if err != nil {
+		return nil, "", xerrors.Errorf("failed to get proof: %v", err)
+	}
+
+	count, err := types.DecodeCatalogCount(proof.GetValue())
+	if err != nil {
+		return nil, "", xerrors.Errorf("failed to decode catalog count: %v", err)
+	}
+
+	// the elections of a chain created before the catalog are listed at the
+	// legacy key until a transaction migrates them
+	var legacy []types.CatalogEntry
+
+	if count == 0 {
+		legacy, err = types.LegacyCatalogEntries(h.context, h.electionFac, h.getValue)
+		if err != nil {
+			return nil, "", xerrors.Errorf("failed to get legacy entries: %v", err)
+		}
+
+		count = uint64(len(legacy))
+	}
+
+	entries := []types.CatalogEntry{}
+
+	index := cursor
+	for reads := 0; index < count && len(entries) < limit && reads < maxCatalogReads; reads++ {
+		entry, err := h.getCatalogEntry(index, legacy)
+		if err != nil {
+			return nil, "", xerrors.Errorf("failed to get entry %d: %v", index, err)
+		}
+
+		if entry.Matches(filter) {
+			entries = append(entries, entry)
+		}
+
+		index++
+	}
+
+	if index >= count {
+		return entries, "", nil
+	}
+
+	return entries, strconv.FormatUint(index, 10), nil
+}
+
+// getCatalogEntry returns the index-th entry of the catalog, or of the legacy
+// entries if set.
+func (h *election) getCatalogEntry(index uint64,
+	legacy []types.CatalogEntry) (types.CatalogEntry, error) {
+
+	if legacy != nil {
+		return legacy[index], nil
+	}
+
+	proof, err := h.orderingSvc.GetProof(types.CatalogKey(index))
+	if err != nil {
+		return types.CatalogEntry{}, xerrors.Errorf("failed to get proof: %v", err)
+	}
+
+	entry, err := types.DecodeCatalogEntry(proof.GetValue())
+	if err != nil {
+		return types.CatalogEntry{}, xerrors.Errorf("failed to decode entry: %v", err)
+	}
+
+	return entry, nil
+}
+
+// getValue returns the value stored at the key.
+func (h *election) getValue(key []byte) ([]byte, error) {
+	proof, err := h.orderingSvc.GetProof(key)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get proof: %v", err)
+	}
+
+	return proof.GetValue(), nil
+}
+
+// getElection gets the election from the snap. Returns the election ID NOT hex
+// encoded.
+func getElection(ctx serde.Context, electionFac serde.Factory, electionIDHex string,
+	srv ordering.Service) (types.Election, error) {
+
+	var election types.Election
+
+	electionID, err := hex.DecodeString(electionIDHex)
+	if err != nil {
+		return election, xerrors.Errorf("failed to decode electionIDHex: %v", err)
+	}
+
+	proof, err := srv.GetProof(electionID)
+	if err != nil {
+		return election, xerrors.Errorf("failed to get proof: %v", err)
+	}
+
+	electionBuff := proof.GetValue()
+	if len(electionBuff) == 0 {
+		return election, xerrors.Errorf("election does not exist")
+	}
+
+	message, err := electionFac.Deserialize(ctx, electionBuff)
+	if err != nil {
+		return election, xerrors.Errorf("failed to deserialize Election: %v", err)
+	}
+
+	election, ok := message.(types.Election)
+	if !ok {
+		return election, xerrors.Errorf("wrong message type: %T", message)
+	}
+
+	return election, nil
+}
+
+// getChainTime returns the time of the chain for the roster of the election,
+// which is the time against which the smart contract checks its schedule.
+func getChainTime(srv ordering.Service, election types.Election) (time.Time, error) {
+	proof, err := srv.GetProof([]byte(types.ClockKey))
+	if err != nil {
+		return time.Time{}, xerrors.Errorf("failed to get proof: %v", err)
+	}
+
+	clock, err := types.DecodeClock(proof.GetValue())
+	if err != nil {
+		return time.Time{}, xerrors.Errorf("failed to decode clock: %v", err)
+	}
+
+	now, err := clock.Time(election.Roster)
+	if err != nil {
+		return time.Time{}, xerrors.Errorf("failed to get the time of the roster: %v", err)
+	}
+
+	return now, nil
+}
+
+// submitTxn adds a transaction to the pool without waiting for it to be
+// included, and tracks its status. Returns the transaction ID.
+func (h *election) submitTxn(cmd evoting.Command, cmdArg string,
+	payload []byte) ([]byte, error) {
+
+	h.Lock()
+	defer h.Unlock()
+
+	// the manager increments the nonce locally. It is only synchronized with
+	// the chain when no transaction is pending, otherwise the nonce of the
+	// pending transaction would be reused.
+	if !h.txs.hasPending(time.Now()) {
+		err := h.mngr.Sync()
+		if err != nil {
+			return nil, xerrors.Errorf("failed to sync manager: %v", err)
+		}
+	}
+
+	tx, err := createTransaction(h.mngr, cmd, cmdArg, payload)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create transaction: %v", err)
+	}
+
+	h.txs.track(tx.GetID())
+
+	err = h.pool.Add(tx)
+	if err != nil {
+		h.txs.finish(tx.GetID(), false, err.Error(), 0)
+		return nil, xerrors.Errorf("failed to add transaction to the pool: %v", err)
+	}
+
+	return tx.GetID(), nil
+}
+
+// submitAndWaitForTxn submits a transaction and waits for it to be included.
+// Returns the transaction ID.
+func (h *election) submitAndWaitForTxn(ctx context.Context, cmd evoting.Command,
+	cmdArg string, payload []byte) ([]byte, error) {
+
+	txID, err := h.submitTxn(cmd, cmdArg, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, inclusionTimeout)
+	defer cancel()
+
+	err = h.txs.wait(waitCtx, txID)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to wait for transaction: %v", err)
+	}
+
+	return txID, nil
+}
+
+// submitAsync submits a transaction and responds with 202 Accepted and the
+// transaction ID. The status of the transaction is available at
+// /transactions/{txID}.
+func (h *election) submitAsync(w http.ResponseWriter, r *http.Request,
+	cmd evoting.Command, data []byte) {
+
+	txID, err := h.submitTxn(cmd, evoting.ElectionArg, data)
+	if err != nil {
+		http.Error(w, "failed to submit txn: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := ptypes.TransactionResponse{
+		TransactionID: hex.EncodeToString(txID),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to write response: %v", err), nil)
+		return
+	}
+}
+
+// submitOperation submits the transactions one after the other, each once the
+// previous one is accepted, and responds with 202 Accepted and the ID of the
+// operation. Its status is available at /transactions/{ID} like the one of a
+// transaction: it is pending until the last transaction is accepted, or
+// rejected as soon as one of them is.
+func (h *election) submitOperation(w http.ResponseWriter, r *http.Request,
+	cmd evoting.Command, txs [][]byte) {
+
+	id, err := types.RandomID()
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to get operation ID: %v", err), nil)
+		return
+	}
+
+	opID, err := hex.DecodeString(id)
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to decode operation ID: %v", err), nil)
+		return
+	}
+
+	h.txs.track(opID)
+
+	go func() {
+		var lastID []byte
+
+		for i, data := range txs {
+			txID, err := h.submitAndWaitForTxn(h.ctx, cmd, evoting.ElectionArg, data)
+			if err != nil {
+				h.txs.finish(opID, false, fmt.Sprintf("transaction %d/%d failed: %v",
+					i+1, len(txs), err), 0)
+				return
+			}
+
+			lastID = txID
+		}
+
+		h.txs.finish(opID, true, "", h.txs.blockIndex(lastID))
+	}()
+
+	response := ptypes.TransactionResponse{
+		TransactionID: id,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to write response: %v", err), nil)
+		return
+	}
+}
+
+// Transaction implements proxy.Proxy. It returns the status of a transaction
+// submitted by this proxy, with the result of each vote when the transaction
+// casts votes. The results of the votes are read from the chain, so a
+// CAST_VOTES transaction submitted through another proxy is found as well. The
+// request should not be signed because it is fetching public data.
+func (h *election) Transaction(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Headers", "*")
+
+	vars := mux.Vars(r)
+
+	if vars == nil || vars["txID"] == "" {
+		http.Error(w, fmt.Sprintf("txID not found: %v", vars), http.StatusInternalServerError)
+		return
+	}
+
+	txIDHex := strings.ToLower(vars["txID"])
+
+	response, found := h.txs.get(txIDHex)
+	if found && response.Status == ptypes.TransactionPending {
+		writeTransaction(w, r, response)
+		return
+	}
+
+	txID, err := hex.DecodeString(txIDHex)
+	if err != nil {
+		BadRequestError(w, r, xerrors.Errorf("failed to decode txID: %v", err), nil)
+		return
+	}
+
+	buf, err := h.getValue(types.VoteResultsKey(txID))
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to get vote results: %v", err), nil)
+		return
+	}
+
+	votes, err := types.DecodeVoteResults(buf)
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to decode vote results: %v", err), nil)
+		return
+	}
+
+	// the results are only stored once the transaction is accepted
+	if !found && len(votes) > 0 {
+		response = ptypes.GetTransactionResponse{
+			TransactionID: txIDHex,
+			Status:        ptypes.TransactionAccepted,
+		}
+		found = true
+	}
+
+	if !found {
+		NotFoundErr(w, r, xerrors.Errorf("transaction %s not found", vars["txID"]), nil)
+		return
+	}
+
+	response.Votes = votes
+
+	writeTransaction(w, r, response)
+}
+
+// writeTransaction writes the status of a transaction.
+func writeTransaction(w http.ResponseWriter, r *http.Request,
+	response ptypes.GetTransactionResponse) {
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to write response: %v", err), nil)
+		return
+	}
 }
 
 func createTransaction(manager txn.Manager, commandType evoting.Command,
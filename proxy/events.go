@@ -0,0 +1,381 @@
+package proxy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dedis/d-voting/contracts/evoting"
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	ptypes "github.com/dedis/d-voting/proxy/types"
+	"github.com/rs/zerolog"
+	"go.dedis.ch/dela/core/execution/native"
+	"go.dedis.ch/dela/core/ordering"
+	"go.dedis.ch/dela/core/txn"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+// eventBufferSize is the number of events buffered for a subscriber. A
+// subscriber that falls further behind is disconnected.
+const eventBufferSize = 64
+
+// eventKeepAlive is the interval at which a comment is sent on an idle stream
+// so that intermediaries don't close the connection.
+const eventKeepAlive = 15 * time.Second
+
+// electionState is the part of an election that is reported by the events.
+type electionState struct {
+	status    types.Status
+	ballots   int
+	shuffles  int
+	pubshares int
+}
+
+func newElectionState(election types.Election) electionState {
+	return electionState{
+		status:    election.Status,
+		ballots:   election.Suffragia.Count,
+		shuffles:  len(election.ShuffleInstances),
+		pubshares: len(election.PubsharesUnits.Pubshares),
+	}
+}
+
+// subscriber receives the events of one election, or of all elections if
+// electionID is empty. events is closed when the subscriber is disconnected.
+type subscriber struct {
+	electionID string
+	events     chan ptypes.ElectionEvent
+}
+
+// eventHub turns the blocks of the ordering service into election events and
+// pushes them to the subscribers.
+type eventHub struct {
+	sync.Mutex
+
+	logger zerolog.Logger
+	ctx    serde.Context
+	txFac  serde.Factory
+
+	// load returns the election, or false if it doesn't exist
+	load func(electionIDHex string) (types.Election, bool, error)
+
+	states      map[string]electionState
+	subscribers map[*subscriber]struct{}
+
+	// closed is set once the hub stops, after which the subscribers are
+	// disconnected right away
+	closed bool
+}
+
+func newEventHub(logger zerolog.Logger, ctx serde.Context,
+	load func(string) (types.Election, bool, error)) *eventHub {
+
+	return &eventHub{
+		logger:      logger,
+		ctx:         ctx,
+		txFac:       types.NewTransactionFactory(types.CiphervoteFactory{}),
+		load:        load,
+		states:      make(map[string]electionState),
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// subscribe returns a new subscriber to the events of an election, or of all
+// elections if electionIDHex is empty.
+func (e *eventHub) subscribe(electionIDHex string) *subscriber {
+	e.Lock()
+	defer e.Unlock()
+
+	sub := &subscriber{
+		electionID: electionIDHex,
+		events:     make(chan ptypes.ElectionEvent, eventBufferSize),
+	}
+
+	if e.closed {
+		close(sub.events)
+		return sub
+	}
+
+	e.subscribers[sub] = struct{}{}
+
+	return sub
+}
+
+// unsubscribe removes the subscriber. It does nothing if the subscriber has
+// already been disconnected.
+func (e *eventHub) unsubscribe(sub *subscriber) {
+	e.Lock()
+	defer e.Unlock()
+
+	_, found := e.subscribers[sub]
+	if found {
+		delete(e.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// close disconnects all the subscribers and the next ones.
+func (e *eventHub) close() {
+	e.Lock()
+	defer e.Unlock()
+
+	for sub := range e.subscribers {
+		delete(e.subscribers, sub)
+		close(sub.events)
+	}
+
+	e.closed = true
+}
+
+// update reloads the elections targeted by the accepted transactions of the
+// block and pushes the resulting events.
+func (e *eventHub) update(event ordering.Event) {
+	var electionIDs []string
+
+	seen := make(map[string]bool)
+
+	for _, res := range event.Transactions {
+		accepted, _ := res.GetStatus()
+		if !accepted {
+			continue
+		}
+
+		ids, err := e.targetElections(res.GetTransaction())
+		if err != nil {
+			e.logger.Warn().Err(err).Msg("failed to read transaction")
+			continue
+		}
+
+		for _, id := range ids {
+			if !seen[id] {
+				seen[id] = true
+				electionIDs = append(electionIDs, id)
+			}
+		}
+	}
+
+	e.refresh(event.Index, electionIDs)
+}
+
+// targetElections returns the hex-encoded IDs of the elections whose state
+// reported by the events may be changed by the transaction.
+func (e *eventHub) targetElections(tx txn.Transaction) ([]string, error) {
+	if string(tx.GetArg(native.ContractArg)) != evoting.ContractName {
+		return nil, nil
+	}
+
+	if evoting.Command(tx.GetArg(evoting.CmdArg)) == evoting.CmdCreateElection {
+		h := sha256.New()
+		h.Write(tx.GetID())
+
+		return []string{hex.EncodeToString(h.Sum(nil))}, nil
+	}
+
+	msg, err := e.txFac.Deserialize(e.ctx, tx.GetArg(evoting.ElectionArg))
+	if err != nil {
+		return nil, xerrors.Errorf("failed to deserialize transaction: %v", err)
+	}
+
+	return electionsOf(msg), nil
+}
+
+// electionsOf returns the hex-encoded IDs of the elections targeted by a
+// transaction message. Messages that don't change the status, the ballots,
+// the shuffles, or the pubshares of an election return nothing.
+func electionsOf(msg serde.Message) []string {
+	switch tx := msg.(type) {
+	case types.OpenElection:
+		return []string{tx.ElectionID}
+	case types.CastVote:
+		return []string{tx.ElectionID}
+	case types.CastVotes:
+		ids := make([]string, len(tx.Votes))
+		for i, vote := range tx.Votes {
+			ids[i] = vote.ElectionID
+		}
+		return ids
+	case types.CloseElection:
+		return []string{tx.ElectionID}
+	case types.ShuffleBallots:
+		return []string{tx.ElectionID}
+	case types.RegisterPubShares:
+		return []string{tx.ElectionID}
+	case types.CombineShares:
+		return []string{tx.ElectionID}
+	case types.CancelElection:
+		return []string{tx.ElectionID}
+	case types.DeleteElection:
+		return []string{tx.ElectionID}
+	case types.ProposeAction:
+		return []string{tx.ElectionID}
+	case types.ApproveAction:
+		return []string{tx.ElectionID}
+	default:
+		return nil
+	}
+}
+
+// refresh compares the elections with their last known state and pushes an
+// event for each change.
+func (e *eventHub) refresh(index uint64, electionIDs []string) {
+	for _, id := range electionIDs {
+		election, found, err := e.load(id)
+		if err != nil {
+			e.logger.Warn().Err(err).Str("election", id).Msg("failed to load election")
+			continue
+		}
+
+		e.Lock()
+
+		if !found {
+			delete(e.states, id)
+			e.push(ptypes.ElectionEvent{
+				Type:       ptypes.EventDeleted,
+				ElectionID: id,
+				Index:      index,
+			})
+
+			e.Unlock()
+			continue
+		}
+
+		state := newElectionState(election)
+		prev, known := e.states[id]
+		e.states[id] = state
+
+		event := ptypes.ElectionEvent{
+			ElectionID:    id,
+			Index:         index,
+			Status:        uint16(state.status),
+			Ballots:       state.ballots,
+			ShuffleRounds: state.shuffles,
+			Pubshares:     state.pubshares,
+		}
+
+		for _, typ := range changes(prev, state, known) {
+			event.Type = typ
+			e.push(event)
+		}
+
+		e.Unlock()
+	}
+}
+
+// changes returns the types of the events between two states of an election.
+// An election that is not known yet only gets a status event.
+func changes(prev, state electionState, known bool) []ptypes.ElectionEventType {
+	if !known {
+		return []ptypes.ElectionEventType{ptypes.EventStatus}
+	}
+
+	var events []ptypes.ElectionEventType
+
+	if prev.status != state.status {
+		events = append(events, ptypes.EventStatus)
+	}
+	if prev.ballots != state.ballots {
+		events = append(events, ptypes.EventBallots)
+	}
+	if prev.shuffles != state.shuffles {
+		events = append(events, ptypes.EventShuffle)
+	}
+	if prev.pubshares != state.pubshares {
+		events = append(events, ptypes.EventPubshares)
+	}
+
+	return events
+}
+
+// push sends the event to the interested subscribers. A subscriber whose
+// buffer is full is disconnected so that a slow client can't block the hub.
+// The lock must be held.
+func (e *eventHub) push(event ptypes.ElectionEvent) {
+	for sub := range e.subscribers {
+		if sub.electionID != "" && sub.electionID != event.ElectionID {
+			continue
+		}
+
+		select {
+		case sub.events <- event:
+		default:
+			delete(e.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// Events implements proxy.Proxy. It streams the changes of the elections as
+// server-sent events. The optional "electionID" query parameter restricts the
+// stream to a single election.
+func (h *election) Events(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		InternalError(w, r, xerrors.Errorf("streaming is not supported"), nil)
+		return
+	}
+
+	electionID := r.URL.Query().Get("electionID")
+	if electionID != "" {
+		_, err := hex.DecodeString(electionID)
+		if err != nil {
+			BadRequestError(w, r, xerrors.Errorf("invalid electionID: %v", err), nil)
+			return
+		}
+	}
+
+	sub := h.events.subscribe(electionID)
+	defer h.events.unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(eventKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case event, ok := <-sub.events:
+			if !ok {
+				return
+			}
+
+			buf, err := json.Marshal(event)
+			if err != nil {
+				h.logger.Err(err).Msg("failed to marshal event")
+				return
+			}
+
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, buf)
+		}
+
+		flusher.Flush()
+	}
+}
+
+// loadElection returns the election, or false if it doesn't exist.
+func (h *election) loadElection(electionIDHex string) (types.Election, bool, error) {
+	exists, err := h.electionExists(electionIDHex)
+	if err != nil || !exists {
+		return types.Election{}, false, err
+	}
+
+	election, err := getElection(h.context, h.electionFac, electionIDHex, h.orderingSvc)
+	if err != nil {
+		return types.Election{}, false, err
+	}
+
+	return election, true, nil
+}
@@ -0,0 +1,133 @@
+package proxy
+
+import (
+	"testing"
+
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"github.com/dedis/d-voting/internal/testing/fake"
+	ptypes "github.com/dedis/d-voting/proxy/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventHub_Refresh(t *testing.T) {
+	elections := map[string]types.Election{
+		"aa": {Status: types.Open},
+		"bb": {Status: types.Open},
+	}
+
+	hub := newEventHub(zerolog.Nop(), nil, func(id string) (types.Election, bool, error) {
+		election, found := elections[id]
+		return election, found, nil
+	})
+
+	all := hub.subscribe("")
+	onlyBB := hub.subscribe("bb")
+
+	hub.refresh(1, []string{"aa"})
+
+	event := <-all.events
+	require.Equal(t, ptypes.EventStatus, event.Type)
+	require.Equal(t, "aa", event.ElectionID)
+	require.Equal(t, uint64(1), event.Index)
+	require.Len(t, onlyBB.events, 0)
+
+	election := elections["aa"]
+	election.Status = types.Closed
+	election.Suffragia.Count = 3
+	elections["aa"] = election
+
+	hub.refresh(2, []string{"aa"})
+
+	event = <-all.events
+	require.Equal(t, ptypes.EventStatus, event.Type)
+	require.Equal(t, uint16(types.Closed), event.Status)
+
+	event = <-all.events
+	require.Equal(t, ptypes.EventBallots, event.Type)
+	require.Equal(t, 3, event.Ballots)
+
+	// nothing changed
+	hub.refresh(3, []string{"aa"})
+	require.Len(t, all.events, 0)
+
+	delete(elections, "bb")
+	hub.refresh(4, []string{"bb"})
+
+	event = <-onlyBB.events
+	require.Equal(t, ptypes.EventDeleted, event.Type)
+	require.Equal(t, "bb", event.ElectionID)
+
+	hub.unsubscribe(all)
+	hub.unsubscribe(all)
+
+	_, ok := <-all.events
+	require.False(t, ok)
+}
+
+func TestEventHub_Close(t *testing.T) {
+	hub := newEventHub(zerolog.Nop(), nil, nil)
+
+	sub := hub.subscribe("")
+
+	hub.close()
+
+	_, ok := <-sub.events
+	require.False(t, ok)
+
+	// the subscribers of a closed hub are disconnected right away
+	sub = hub.subscribe("aa")
+
+	_, ok = <-sub.events
+	require.False(t, ok)
+
+	hub.unsubscribe(sub)
+}
+
+func TestElection_Stop(t *testing.T) {
+	service := fake.Service{}
+
+	h := NewElection(&service, nil, nil, nil, nil, nil).(*election)
+
+	sub := h.events.subscribe("")
+
+	h.Stop()
+
+	_, ok := <-sub.events
+	require.False(t, ok)
+
+	// the blocks are not dispatched anymore
+	select {
+	case <-h.watchDone:
+	default:
+		t.Fatal("the watch is still running")
+	}
+}
+
+func TestEventHub_SlowSubscriber(t *testing.T) {
+	status := types.Initial
+
+	hub := newEventHub(zerolog.Nop(), nil, func(id string) (types.Election, bool, error) {
+		status++
+		return types.Election{Status: status}, true, nil
+	})
+
+	sub := hub.subscribe("aa")
+
+	for i := 0; i <= eventBufferSize; i++ {
+		hub.refresh(uint64(i), []string{"aa"})
+	}
+
+	require.Len(t, sub.events, eventBufferSize)
+	require.Len(t, hub.subscribers, 0)
+}
+
+func TestElectionsOf(t *testing.T) {
+	require.Equal(t, []string{"aa"}, electionsOf(types.OpenElection{ElectionID: "aa"}))
+	require.Nil(t, electionsOf(types.RegisterVoters{ElectionID: "aa"}))
+
+	votes := types.CastVotes{
+		Votes: []types.CastVote{{ElectionID: "aa"}, {ElectionID: "bb"}},
+	}
+	require.Equal(t, []string{"aa", "bb"}, electionsOf(votes))
+}
@@ -34,6 +34,27 @@ type Election interface {
 	Election(http.ResponseWriter, *http.Request)
 	// DELETE /elections/{electionID}
 	DeleteElection(http.ResponseWriter, *http.Request)
+	// POST|PUT|DELETE /elections/{electionID}/voters
+	EditVoters(http.ResponseWriter, *http.Request)
+	// GET /elections/{electionID}/results
+	Results(http.ResponseWriter, *http.Request)
+	// GET /elections/{electionID}/ballots/{receipt}
+	Ballot(http.ResponseWriter, *http.Request)
+	// GET /elections/{electionID}/bulletin
+	Bulletin(http.ResponseWriter, *http.Request)
+	// GET /elections/{electionID}/actions
+	Actions(http.ResponseWriter, *http.Request)
+	// POST /elections/{electionID}/actions
+	NewAction(http.ResponseWriter, *http.Request)
+	// PUT /elections/{electionID}/actions/{actionID}
+	ApproveAction(http.ResponseWriter, *http.Request)
+	// GET /transactions/{txID}
+	Transaction(http.ResponseWriter, *http.Request)
+	// GET /events
+	Events(http.ResponseWriter, *http.Request)
+	// Stop stops watching the blocks and closes the event streams. It must be
+	// called once, when the proxy stops.
+	Stop()
 }
 
 // DKG defines the public HTTP API of the DKG service
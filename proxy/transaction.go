@@ -0,0 +1,175 @@
+package proxy
+
+import (
+	"context"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	ptypes "github.com/dedis/d-voting/proxy/types"
+	"go.dedis.ch/dela/core/ordering"
+	"golang.org/x/xerrors"
+)
+
+// txRetention is how long the status of a transaction is kept after its
+// submission.
+const txRetention = time.Hour
+
+// txSyncTimeout is how long a pending transaction prevents the nonce from
+// being synchronized with the chain. After that, the transaction is considered
+// lost.
+const txSyncTimeout = time.Minute
+
+// txTracker keeps the status of the transactions submitted by the proxy, and of
+// the operations made of several transactions. It is updated by the events of
+// the ordering service. The statuses are only kept in memory, for txRetention:
+// a transaction submitted through another proxy, before a restart, or more
+// than an hour ago is unknown, and its effect must be checked on the election
+// itself.
+type txTracker struct {
+	sync.Mutex
+
+	txs map[string]*trackedTx
+}
+
+// trackedTx is the status of a transaction. done is closed once the
+// transaction is accepted or rejected, and index is then the index of the block
+// that included it.
+type trackedTx struct {
+	status    ptypes.TransactionStatus
+	message   string
+	index     uint64
+	submitted time.Time
+	done      chan struct{}
+}
+
+func newTxTracker() *txTracker {
+	return &txTracker{
+		txs: make(map[string]*trackedTx),
+	}
+}
+
+// update sets the status of the tracked transactions included in the block.
+func (t *txTracker) update(event ordering.Event) {
+	for _, res := range event.Transactions {
+		accepted, msg := res.GetStatus()
+		t.finish(res.GetTransaction().GetID(), accepted, msg, event.Index)
+	}
+}
+
+// track starts tracking a transaction that is about to be submitted. The
+// transactions older than txRetention are forgotten.
+func (t *txTracker) track(id []byte) {
+	t.Lock()
+	defer t.Unlock()
+
+	now := time.Now()
+
+	for key, tx := range t.txs {
+		if now.Sub(tx.submitted) > txRetention {
+			delete(t.txs, key)
+		}
+	}
+
+	t.txs[hex.EncodeToString(id)] = &trackedTx{
+		status:    ptypes.TransactionPending,
+		submitted: now,
+		done:      make(chan struct{}),
+	}
+}
+
+// finish sets the final status of a transaction and the index of the block
+// that included it. It does nothing if the transaction is not tracked or
+// already finished.
+func (t *txTracker) finish(id []byte, accepted bool, message string, index uint64) {
+	t.Lock()
+	defer t.Unlock()
+
+	tx, found := t.txs[hex.EncodeToString(id)]
+	if !found || tx.status != ptypes.TransactionPending {
+		return
+	}
+
+	tx.index = index
+	tx.status = ptypes.TransactionAccepted
+	if !accepted {
+		tx.status = ptypes.TransactionRejected
+		tx.message = message
+	}
+
+	close(tx.done)
+}
+
+// get returns the status of a transaction. idHex is hex-encoded.
+func (t *txTracker) get(idHex string) (ptypes.GetTransactionResponse, bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	tx, found := t.txs[idHex]
+	if !found {
+		return ptypes.GetTransactionResponse{}, false
+	}
+
+	return ptypes.GetTransactionResponse{
+		TransactionID: idHex,
+		Status:        tx.status,
+		Message:       tx.message,
+		BlockIndex:    tx.index,
+	}, true
+}
+
+// blockIndex returns the index of the block that included the transaction, or
+// 0 if the transaction is unknown or still pending.
+func (t *txTracker) blockIndex(id []byte) uint64 {
+	t.Lock()
+	defer t.Unlock()
+
+	tx, found := t.txs[hex.EncodeToString(id)]
+	if !found {
+		return 0
+	}
+
+	return tx.index
+}
+
+// hasPending returns true if a transaction submitted less than txSyncTimeout
+// ago is still pending.
+func (t *txTracker) hasPending(now time.Time) bool {
+	t.Lock()
+	defer t.Unlock()
+
+	for _, tx := range t.txs {
+		if tx.status == ptypes.TransactionPending && now.Sub(tx.submitted) < txSyncTimeout {
+			return true
+		}
+	}
+
+	return false
+}
+
+// wait blocks until the transaction is accepted or rejected, or until the
+// context is done.
+func (t *txTracker) wait(ctx context.Context, id []byte) error {
+	t.Lock()
+	tx, found := t.txs[hex.EncodeToString(id)]
+	t.Unlock()
+
+	if !found {
+		return xerrors.Errorf("transaction %x not tracked", id)
+	}
+
+	select {
+	case <-tx.done:
+	case <-ctx.Done():
+		return xerrors.Errorf("transaction %x not included: %v", id, ctx.Err())
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	if tx.status == ptypes.TransactionRejected {
+		return xerrors.Errorf("transaction %x denied : %s", id, tx.message)
+	}
+
+	return nil
+}
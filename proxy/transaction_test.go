@@ -0,0 +1,66 @@
+package proxy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ptypes "github.com/dedis/d-voting/proxy/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxTracker_Status(t *testing.T) {
+	txs := newTxTracker()
+
+	_, found := txs.get("aa")
+	require.False(t, found)
+
+	txs.track([]byte{0xaa})
+	txs.track([]byte{0xbb})
+
+	status, found := txs.get("aa")
+	require.True(t, found)
+	require.Equal(t, ptypes.TransactionPending, status.Status)
+	require.True(t, txs.hasPending(time.Now()))
+	require.False(t, txs.hasPending(time.Now().Add(txSyncTimeout)))
+
+	txs.finish([]byte{0xaa}, true, "", 3)
+	txs.finish([]byte{0xbb}, false, "oops", 3)
+
+	// the final status doesn't change
+	txs.finish([]byte{0xbb}, true, "", 4)
+
+	status, _ = txs.get("aa")
+	require.Equal(t, ptypes.TransactionAccepted, status.Status)
+	require.Equal(t, uint64(3), status.BlockIndex)
+	require.Equal(t, uint64(3), txs.blockIndex([]byte{0xaa}))
+
+	status, _ = txs.get("bb")
+	require.Equal(t, ptypes.TransactionRejected, status.Status)
+	require.Equal(t, "oops", status.Message)
+	require.Equal(t, uint64(3), status.BlockIndex)
+
+	require.False(t, txs.hasPending(time.Now()))
+}
+
+func TestTxTracker_Wait(t *testing.T) {
+	txs := newTxTracker()
+
+	err := txs.wait(context.Background(), []byte{0xaa})
+	require.EqualError(t, err, "transaction aa not tracked")
+
+	txs.track([]byte{0xaa})
+
+	go txs.finish([]byte{0xaa}, false, "oops", 1)
+
+	err = txs.wait(context.Background(), []byte{0xaa})
+	require.EqualError(t, err, "transaction aa denied : oops")
+
+	txs.track([]byte{0xbb})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = txs.wait(ctx, []byte{0xbb})
+	require.EqualError(t, err, "transaction bb not included: context canceled")
+}
@@ -8,6 +8,8 @@ type NewDKGRequest struct {
 // UpdateDKG defines the input used to update dkg
 type UpdateDKG struct {
 	Action string
+	// ExcludeUnresponsive is only used by the "retry" action
+	ExcludeUnresponsive bool
 }
 
 // GetActorInfo defines the result of a get actor info
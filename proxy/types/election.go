@@ -6,13 +6,25 @@ import (
 
 // CreateElectionRequest defines the HTTP request for creating an election
 type CreateElectionRequest struct {
+	// AdminID is the hex-encoded public key of the admin
 	AdminID       string
 	Configuration etypes.Configuration
+	// Admins optionally contains the hex-encoded public keys of the
+	// co-administrators, and AdminThreshold the number of them required to
+	// approve closing, canceling, or deleting the election.
+	Admins         []string
+	AdminThreshold int
+	// ShuffleThreshold and DecryptionThreshold optionally set the number of
+	// shuffles and the number of pubshares required to decrypt the ballots.
+	// They default to the Byzantine threshold of the roster.
+	ShuffleThreshold    int
+	DecryptionThreshold int
 }
 
 // CreateElectionResponse defines the HTTP response when creating an election
 type CreateElectionResponse struct {
-	ElectionID string // hex-encoded
+	ElectionID    string // hex-encoded
+	TransactionID string // hex-encoded
 }
 
 // CastVoteRequest defines the HTTP request for casting a vote
@@ -20,6 +32,49 @@ type CastVoteRequest struct {
 	UserID string
 	// Marshalled representation of Ciphervote. It contains []{K:,C:}
 	Ballot CiphervoteJSON
+	// Proof is the proof of knowledge of the random scalars used to encrypt
+	// the ballot, bound to the election and UserID. See types.ProveBallot.
+	Proof []byte
+}
+
+// CastVoteResponse defines the HTTP response when casting a vote. The receipt
+// lets the voter check that the ballot was recorded as cast.
+type CastVoteResponse struct {
+	// Receipt is the hex-encoded hash of the ciphervote
+	Receipt string
+	// TransactionID is the hex-encoded ID of the transaction that casts the
+	// vote along with the other votes of its batch
+	TransactionID string
+}
+
+// GetBallotResponse defines the HTTP response when looking up a ballot with
+// its receipt
+type GetBallotResponse struct {
+	Receipt        string
+	ElectionStatus uint16
+	// Ballot is the recorded ciphervote
+	Ballot CiphervoteJSON
+	// Proof proves that the ballot is stored on the chain
+	Proof BallotProofJSON
+	// InSuffragia is true once the election is closed and the ballot is in the
+	// suffragia, at position SuffragiaIndex. The suffragia is stored in the
+	// election proved by Proof.
+	InSuffragia    bool
+	SuffragiaIndex int
+}
+
+// BallotProofJSON is the proof that a value is stored at a key of the chain
+type BallotProofJSON struct {
+	// Key is the key of the ballot while the election is open, and the key of
+	// the election once the ballots are gathered in its suffragia
+	Key   []byte
+	Value []byte
+	// Chain is the serialized chain of block links that leads to the block
+	// which includes the value. It can be verified with the genesis block.
+	Chain []byte
+	// BlockIndex and BlockHash identify the block at the end of the chain
+	BlockIndex uint64
+	BlockHash  string
 }
 
 // CiphervoteJSON is the JSON representation of a ciphervote
@@ -34,6 +89,53 @@ type EGPairJSON struct {
 // UpdateElectionRequest defines the HTTP request for updating an election
 type UpdateElectionRequest struct {
 	Action string
+	// Configuration is the JSON encoding of the new configuration, only used
+	// by the "updateConfiguration" action. The admin signs its hash, see
+	// AdminPayload, so it is forwarded to the chain as it is.
+	Configuration []byte
+	// AdminSignature is the admin's signature on the message returned by
+	// AdminMessage for the command corresponding to the action.
+	AdminSignature []byte
+}
+
+// UpdateVotersRequest defines the HTTP request for updating the voters allowed
+// to vote in an election. Voters can be given as a list of user IDs, as CSV
+// data where the first column of each record is the user ID, or both.
+type UpdateVotersRequest struct {
+	UserIDs []string
+	CSV     string
+	// AdminSignatures are the admin's signatures on the message returned by
+	// AdminMessage for the command corresponding to the method, one for each
+	// batch of voters. Each transaction uses the next nonce of the election.
+	AdminSignatures [][]byte
+}
+
+// ProposeActionRequest defines the HTTP request for proposing a sensitive
+// action. Action is one of "close", "cancel", or "delete".
+type ProposeActionRequest struct {
+	Action         string
+	AdminID        string
+	AdminSignature []byte
+}
+
+// ProposeActionResponse defines the HTTP response when proposing an action
+type ProposeActionResponse struct {
+	ActionID      string
+	TransactionID string
+}
+
+// ApproveActionRequest defines the HTTP request for approving a pending
+// action
+type ApproveActionRequest struct {
+	AdminID        string
+	AdminSignature []byte
+}
+
+// GetActionsResponse defines the HTTP response when getting the pending
+// actions of an election
+type GetActionsResponse struct {
+	Threshold int
+	Actions   []etypes.PendingAction
 }
 
 // GetElectionResponse defines the HTTP response when getting the election info
@@ -47,6 +149,32 @@ type GetElectionResponse struct {
 	Roster          []string
 	ChunksPerBallot int
 	BallotSize      int
+	// EligibleVoters is the number of registered voters. It is only
+	// meaningful if RestrictedVoters is set, otherwise anyone can vote.
+	EligibleVoters   int
+	RestrictedVoters bool
+	Admins           []string
+	AdminThreshold   int
+	// AdminNonce is the nonce that the admins must use to sign the next
+	// command, see AdminMessage.
+	AdminNonce uint64
+	// ShuffleThreshold is the number of shuffles required, and
+	// DecryptionThreshold the number of pubshares required to decrypt the
+	// ballots. DecryptionThreshold is 0 for the elections created before it
+	// was configurable.
+	ShuffleThreshold    int
+	DecryptionThreshold int
+
+	// StatusReason explains the status, for example why the quorum is not
+	// reached
+	StatusReason string
+}
+
+// GetResultsResponse defines the HTTP response when getting the aggregated
+// results of an election
+type GetResultsResponse struct {
+	ElectionID string
+	Tally      etypes.Tally
 }
 
 // LightElection represents a light version of the election
@@ -55,12 +183,14 @@ type LightElection struct {
 	Title      string
 	Status     uint16
 	Pubkey     string
+	AdminID    string
 }
 
-// GetElectionsResponse defines the HTTP response when getting all elections
-// infos.
+// GetElectionsResponse defines the HTTP response when listing the elections.
+// NextCursor is empty on the last page.
 type GetElectionsResponse struct {
-	Elections []LightElection
+	Elections  []LightElection
+	NextCursor string `json:",omitempty"`
 }
 
 // HTTPError defines the standard error format
@@ -0,0 +1,32 @@
+package types
+
+// ElectionEventType is the kind of change reported by an election event
+type ElectionEventType string
+
+const (
+	// EventStatus is when the status of the election changes. It is also the
+	// first event sent for an election the proxy did not know yet.
+	EventStatus ElectionEventType = "status"
+	// EventBallots is when the number of ballots cast changes
+	EventBallots ElectionEventType = "ballots"
+	// EventShuffle is when a shuffle round is submitted
+	EventShuffle ElectionEventType = "shuffle"
+	// EventPubshares is when a node submits its public shares
+	EventPubshares ElectionEventType = "pubshares"
+	// EventDeleted is when the election is deleted
+	EventDeleted ElectionEventType = "deleted"
+)
+
+// ElectionEvent defines the server-sent event pushed when an election changes.
+// It contains the state of the election after the change.
+type ElectionEvent struct {
+	Type ElectionEventType
+	// ElectionID is hex-encoded
+	ElectionID string
+	// Index is the index of the block that changed the election
+	Index         uint64
+	Status        uint16
+	Ballots       int
+	ShuffleRounds int
+	Pubshares     int
+}
@@ -0,0 +1,43 @@
+package types
+
+import (
+	etypes "github.com/dedis/d-voting/contracts/evoting/types"
+)
+
+// TransactionStatus is the status of a transaction submitted by the proxy
+type TransactionStatus string
+
+const (
+	// TransactionPending is when the transaction has not been included yet
+	TransactionPending TransactionStatus = "pending"
+	// TransactionAccepted is when the transaction has been included and
+	// executed successfully
+	TransactionAccepted TransactionStatus = "accepted"
+	// TransactionRejected is when the transaction has been included but its
+	// execution failed, or when it could not be added to the pool
+	TransactionRejected TransactionStatus = "rejected"
+)
+
+// TransactionResponse defines the HTTP response of the endpoints that submit a
+// transaction without waiting for it to be included
+type TransactionResponse struct {
+	// TransactionID is hex-encoded. When the request needs several
+	// transactions, it is the ID of the whole operation, whose status is
+	// available like the one of a transaction.
+	TransactionID string
+}
+
+// GetTransactionResponse defines the HTTP response when getting the status of
+// a transaction
+type GetTransactionResponse struct {
+	// TransactionID is hex-encoded
+	TransactionID string
+	Status        TransactionStatus
+	// Message explains why the transaction is rejected
+	Message string `json:",omitempty"`
+	// BlockIndex is the index of the block that included the transaction
+	BlockIndex uint64 `json:",omitempty"`
+	// Votes are the results of the votes of an accepted CAST_VOTES
+	// transaction, in order
+	Votes []etypes.VoteResult `json:",omitempty"`
+}
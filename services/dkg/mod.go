@@ -2,6 +2,7 @@ package dkg
 
 import (
 	"go.dedis.ch/dela/core/txn"
+	"go.dedis.ch/dela/mino"
 	"go.dedis.ch/kyber/v3"
 )
 
@@ -22,7 +23,8 @@ const (
 	Initialized StatusCode = 0
 	// Setup is when the actor was set up
 	Setup StatusCode = 1
-	// Failed is when the actor failed to set up
+	// Failed is when the actor failed to set up. The Args of the status list
+	// the "unresponsive" participants, if any.
 	Failed StatusCode = 2
 )
 
@@ -48,10 +50,41 @@ type Actor interface {
 	// Returns an error if Setup was already done.
 	Setup() (pubKey kyber.Point, err error)
 
+	// Retry must be called by the actor whose setup failed. It tears down the
+	// state left by the failed setup and runs it again. If
+	// excludeUnresponsive is true, the participants that didn't answer during
+	// the failed setup are left out of the DKG. Returns an error if the setup
+	// didn't fail or if the election already has a public key.
+	Retry(excludeUnresponsive bool) (pubKey kyber.Point, err error)
+
 	// GetPublicKey returns the collective public key. Returns an error if the
 	// setup has not been done.
 	GetPublicKey() (kyber.Point, error)
 
+	// GetVerificationKeys returns the public key of the private share of each
+	// participant, in the order of their index in the DKG. They allow anyone to
+	// verify the pubshares of a node. Returns an error if the setup has not
+	// been done.
+	GetVerificationKeys() ([]kyber.Point, error)
+
+	// GetParticipants returns the addresses of the participants of the DKG, in
+	// the order of their index. They are the roster of the election, except
+	// the nodes left out by a retry. Returns an error if the setup has not
+	// been done.
+	GetParticipants() ([]mino.Address, error)
+
+	// GetThreshold returns the t of the t-of-n DKG, i.e. the number of
+	// pubshares required to decrypt. Returns an error if the setup has not
+	// been done.
+	GetThreshold() (int, error)
+
+	// Reshare must be called by ONE of the participants of the DKG. It moves
+	// the private shares to the nodes of the roster of the chain while keeping
+	// the collective public key and the threshold. Each new node must first
+	// execute Listen(). The nodes that leave the DKG erase their share.
+	// Returns an error if the setup has not been done.
+	Reshare() error
+
 	Encrypt(message []byte) (K, C kyber.Point, remainder []byte, err error)
 
 	// ComputePubshares sends a decryption request to all nodes. Nodes will then
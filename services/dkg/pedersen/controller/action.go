@@ -157,6 +157,59 @@ func (a *setupAction) Execute(ctx node.Context) error {
 	return nil
 }
 
+// reshareAction is an action to move the private shares of the DKG to the
+// nodes of the roster of the chain
+//
+// - implements node.ActionTemplate
+type reshareAction struct {
+}
+
+// Execute implements node.ActionTemplate. It requests the resharing.
+func (a *reshareAction) Execute(ctx node.Context) error {
+
+	electionIDBuf, err := hex.DecodeString(ctx.Flags.String("electionID"))
+	if err != nil {
+		return xerrors.Errorf("failed to decode electionID: %v", err)
+	}
+
+	var dkg dkg.DKG
+	err = ctx.Injector.Resolve(&dkg)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve DKG: %v", err)
+	}
+
+	actor, exists := dkg.GetActor(electionIDBuf)
+	if !exists {
+		return xerrors.Errorf("failed to get actor for election %x", electionIDBuf)
+	}
+
+	err = actor.Reshare()
+	if err != nil {
+		return xerrors.Errorf("failed to reshare DKG: %v", err)
+	}
+
+	dela.Logger.Info().Msg("DKG has been reshared successfully")
+
+	err = updateDKGStore(ctx.Injector, func(tx kv.WritableTx) error {
+		bucket, err := tx.GetBucketOrCreate([]byte(BucketName))
+		if err != nil {
+			return err
+		}
+
+		actorBuf, err := actor.MarshalJSON()
+		if err != nil {
+			return err
+		}
+
+		return bucket.Set(electionIDBuf, actorBuf)
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to update DKG store: %v", err)
+	}
+
+	return nil
+}
+
 // exportInfoAction is an action to display a base64 string describing the node.
 // It can be used to transmit the identity of a node to another one.
 //
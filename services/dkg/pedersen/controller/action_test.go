@@ -146,6 +146,57 @@ func TestSetupAction_Execute(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestReshareAction_Execute(t *testing.T) {
+	action := reshareAction{}
+
+	flags := fakeFlags{strings: make(map[string]string)}
+	inj := node.NewInjector()
+
+	ctx := node.Context{
+		Injector: inj,
+		Out:      ioutil.Discard,
+	}
+
+	electionID := "deadbeef"
+
+	flags.strings["electionID"] = electionID
+	ctx.Flags = flags
+
+	// No DKG
+	err := action.Execute(ctx)
+	require.EqualError(t, err, "failed to resolve DKG: couldn't find dependency for 'dkg.DKG'")
+
+	// No actor
+	p := fake.Pedersen{Actors: make(map[string]dkg.Actor)}
+	inj.Inject(p)
+
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "failed to get actor for election deadbeef")
+
+	electionIDBuf, err := hex.DecodeString(electionID)
+	require.NoError(t, err)
+
+	p.Actors[string(electionIDBuf)] = fake.DKGActor{
+		PubKey: suite.Point(),
+		Err:    xerrors.Errorf("fake error"),
+	}
+
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "failed to reshare DKG: fake error")
+
+	_, err = p.Listen(electionIDBuf, fake.Manager{})
+	require.NoError(t, err)
+
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "failed to update DKG store: failed to resolve db: "+
+		"couldn't find dependency for 'kv.DB'")
+
+	ctx.Injector.Inject(fake.NewInMemoryDB())
+
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+}
+
 func TestExportInfoAction_Execute(t *testing.T) {
 
 	ctx := node.Context{
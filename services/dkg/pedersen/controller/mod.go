@@ -68,6 +68,12 @@ func (m controller) SetCommands(builder node.Builder) {
 	sub.SetFlags(electionIDFlag)
 	sub.SetAction(builder.MakeAction(&setupAction{}))
 
+	// memcoin --config /tmp/node1 dkg reshare --electionID electionID
+	sub = cmd.SetSubCommand("reshare")
+	sub.SetDescription("move the private shares to the nodes of the roster of the chain")
+	sub.SetFlags(electionIDFlag)
+	sub.SetAction(builder.MakeAction(&reshareAction{}))
+
 	sub = cmd.SetSubCommand("export")
 	sub.SetDescription("export the node address and public key")
 	sub.SetAction(builder.MakeAction(&exportInfoAction{}))
@@ -153,7 +159,7 @@ func (m controller) OnStart(ctx cli.Flags, inj node.Injector) error {
 
 	electionFac := etypes.NewElectionFactory(etypes.CiphervoteFactory{}, rosterFac)
 
-	dkg := pedersen.NewPedersen(no, srvc, p, electionFac, signer)
+	dkg := pedersen.NewPedersen(no, srvc, p, electionFac, rosterFac, signer)
 
 	// Use dkgMap to fill the actors map
 	err = db.View(func(tx kv.ReadableTx) error {
@@ -185,7 +191,7 @@ func (m controller) OnStart(ctx cli.Flags, inj node.Injector) error {
 	inj.Inject(dkg)
 
 	rosterKey := [32]byte{}
-	c := evoting.NewContract(evotingAccessKey[:], rosterKey[:], access, dkg, rosterFac)
+	c := evoting.NewContract(evotingAccessKey[:], rosterKey[:], access, rosterFac)
 	evoting.RegisterContract(exec, c)
 
 	return nil
@@ -33,8 +33,23 @@ import (
 	"golang.org/x/xerrors"
 )
 
-// recvResponseTimeout is the maximum time a node will wait for a response
-const recvResponseTimeout = time.Second * 10
+const (
+	// recvDealsTimeout is the maximum time a node will wait for the deals of
+	// the other participants
+	recvDealsTimeout = time.Second * 30
+
+	// recvResponseTimeout is the maximum time a node will wait for the
+	// responses of the other participants
+	recvResponseTimeout = time.Second * 30
+
+	// registerTimeout is the maximum time a node will try to register the
+	// result of the DKG on the chain
+	registerTimeout = time.Second * 30
+
+	// registerWatchTimeout is the maximum time a node will wait for one of its
+	// registrations to be included in a block
+	registerWatchTimeout = time.Second * 5
+)
 
 // Handler represents the RPC executed on each node
 //
@@ -44,6 +59,7 @@ type Handler struct {
 	sync.RWMutex
 
 	me              mino.Address
+	electionID      string
 	service         ordering.Service
 	dkg             *pedersen.DistKeyGenerator
 	pool            pool.Pool
@@ -60,8 +76,8 @@ type Handler struct {
 	electionFac serde.Factory
 }
 
-// NewHandler creates a new handler
-func NewHandler(me mino.Address, service ordering.Service, pool pool.Pool,
+// NewHandler creates a new handler for the DKG of the election
+func NewHandler(me mino.Address, electionID string, service ordering.Service, pool pool.Pool,
 	txnmngr txn.Manager, pubSharesSigner crypto.Signer, handlerData HandlerData,
 	context serde.Context, electionFac serde.Factory) *Handler {
 
@@ -72,6 +88,7 @@ func NewHandler(me mino.Address, service ordering.Service, pool pool.Pool,
 
 	return &Handler{
 		me:              me,
+		electionID:      electionID,
 		service:         service,
 		pool:            pool,
 		txmnger:         txnmngr,
@@ -116,6 +133,12 @@ mainSwitch:
 			return xerrors.Errorf("failed to start: %v", err)
 		}
 
+	case types.Reshare:
+		err := h.reshare(msg, deals, responses, from, out, in)
+		if err != nil {
+			return xerrors.Errorf("failed to reshare: %v", err)
+		}
+
 	case types.Deal:
 		// This is a special case where a DKG started, some nodes received the
 		// start signal and started sending their deals but we have not yet
@@ -183,8 +206,13 @@ func (h *Handler) start(start types.Start, receivedDeals []types.Deal,
 			"pubKey: %d := %d", len(start.GetAddresses()), len(start.GetPublicKeys()))
 	}
 
-	// create the DKG
-	thrshold := threshold.ByzantineThreshold(len(start.GetPublicKeys()))
+	// create the DKG. An initiator that doesn't set the threshold uses the
+	// Byzantine threshold.
+	thrshold := start.GetThreshold()
+	if thrshold == 0 {
+		thrshold = threshold.ByzantineThreshold(len(start.GetPublicKeys()))
+	}
+
 	d, err := pedersen.NewDistKeyGenerator(suite, h.privKey, start.GetPublicKeys(), thrshold)
 	if err != nil {
 		return xerrors.Errorf("failed to create new DKG: %v", err)
@@ -197,6 +225,179 @@ func (h *Handler) start(start types.Start, receivedDeals []types.Deal,
 		return xerrors.Errorf("failed to compute the deals: %v", err)
 	}
 
+	h.sendDeals(deals, start.GetAddresses(), out)
+
+	prog := newProgress(h.me, start.GetAddresses(), start.GetAddresses())
+
+	// If there are N nodes, then N nodes first send (N-1) Deals. Then each node
+	// send a response to every other nodes. So the number of responses a node
+	// get is (N-1) * (N-1), where (N-1) should equal len(deals).
+	receivedResps, err = h.receiveDeals(prog, len(deals), receivedDeals,
+		receivedResps, from, start.GetAddresses(), out, in)
+	if err != nil {
+		return xerrors.Errorf("failed to receive deals: %v", err)
+	}
+
+	h.startRes.SetParticipants(start.GetAddresses())
+	h.startRes.SetPublicKeys(start.GetPublicKeys())
+
+	err = h.certify(prog, receivedResps, out, in, from)
+	if err != nil {
+		return xerrors.Errorf("failed to certify: %v", err)
+	}
+
+	return nil
+}
+
+// reshare is called when the node has received its reshare message. The
+// dealers, a threshold of the old participants, deal their share to the new
+// participants, which then process the deals like at the start of the DKG. The
+// public key of the DKG doesn't change. A node that doesn't participate after
+// the resharing erases its share.
+func (h *Handler) reshare(reshare types.Reshare, receivedDeals []types.Deal,
+	receivedResps []*pedersen.Response, from mino.Address, out mino.Sender,
+	in mino.Receiver) error {
+
+	oldAddrs := reshare.GetOldAddresses()
+	oldPubkeys := reshare.GetOldPublicKeys()
+	dealers := reshare.GetDealers()
+	newAddrs := reshare.GetNewAddresses()
+	newPubkeys := reshare.GetNewPublicKeys()
+
+	if len(oldAddrs) != len(oldPubkeys) {
+		return xerrors.Errorf("there should be as many old players as "+
+			"pubKey: %d := %d", len(oldAddrs), len(oldPubkeys))
+	}
+
+	if len(newAddrs) != len(newPubkeys) {
+		return xerrors.Errorf("there should be as many new players as "+
+			"pubKey: %d := %d", len(newAddrs), len(newPubkeys))
+	}
+
+	// the deals of exactly a threshold of the old participants are used so
+	// that every new participant computes its share from the same ones
+	if len(dealers) != len(reshare.GetCommits()) {
+		return xerrors.Errorf("there should be as many dealers as the "+
+			"threshold: %d != %d", len(dealers), len(reshare.GetCommits()))
+	}
+
+	h.RLock()
+	privShare := h.privShare
+	h.RUnlock()
+
+	isOld := privShare != nil && containsKey(oldPubkeys, h.pubKey)
+	isDealer := isOld && indexOf(dealers, h.me) != -1
+	isNew := containsKey(newPubkeys, h.pubKey)
+
+	if !isOld && !isNew {
+		return xerrors.Errorf("%s is not a participant of the resharing", h.me)
+	}
+
+	if !isDealer && !isNew {
+		h.leave()
+		return nil
+	}
+
+	// An initiator that doesn't set the threshold uses the Byzantine
+	// threshold.
+	thrshold := reshare.GetThreshold()
+	if thrshold == 0 {
+		thrshold = threshold.ByzantineThreshold(len(newPubkeys))
+	}
+
+	config := &pedersen.Config{
+		Suite:        suite,
+		Longterm:     h.privKey,
+		OldNodes:     oldPubkeys,
+		NewNodes:     newPubkeys,
+		Threshold:    thrshold,
+		OldThreshold: len(reshare.GetCommits()),
+	}
+
+	// The new participants only know the public polynomial of the DKG, which
+	// they use to verify the deals.
+	if isDealer {
+		config.Share = &pedersen.DistKeyShare{
+			Commits: h.startRes.GetCommits(),
+			Share:   privShare,
+		}
+	} else {
+		config.PublicCoeffs = reshare.GetCommits()
+	}
+
+	d, err := pedersen.NewDistKeyHandler(config)
+	if err != nil {
+		return xerrors.Errorf("failed to create new DKG: %v", err)
+	}
+	h.dkg = d
+
+	// Every dealer sends a deal to every new participant
+	numDeals := len(dealers)
+
+	if isDealer {
+		deals, err := h.dkg.Deals()
+		if err != nil {
+			return xerrors.Errorf("failed to compute the deals: %v", err)
+		}
+
+		h.sendDeals(deals, newAddrs, out)
+
+		// a node that stays processes its own deal when computing the deals
+		numDeals--
+	}
+
+	if !isNew {
+		h.leave()
+		return nil
+	}
+
+	// only the dealers are expected to deal, at their index in the DKG
+	expected := make([]mino.Address, len(oldAddrs))
+	for i, addr := range oldAddrs {
+		if indexOf(dealers, addr) != -1 {
+			expected[i] = addr
+		}
+	}
+
+	prog := newProgress(h.me, expected, newAddrs)
+
+	receivedResps, err = h.receiveDeals(prog, numDeals, receivedDeals,
+		receivedResps, from, newAddrs, out, in)
+	if err != nil {
+		return xerrors.Errorf("failed to receive deals: %v", err)
+	}
+
+	h.startRes.SetParticipants(newAddrs)
+	h.startRes.SetPublicKeys(newPubkeys)
+
+	err = h.certify(prog, receivedResps, out, in, from)
+	if err != nil {
+		return xerrors.Errorf("failed to certify: %v", err)
+	}
+
+	return nil
+}
+
+// leave erases the share of the node, which doesn't participate in the DKG
+// after the resharing.
+func (h *Handler) leave() {
+	h.Lock()
+	h.privShare = nil
+	h.Unlock()
+
+	h.startRes.SetDistKey(nil)
+	h.startRes.SetCommits(nil)
+	h.startRes.SetParticipants(nil)
+	h.startRes.SetPublicKeys(nil)
+
+	dela.Logger.Info().Msgf("%s left the DKG", h.me)
+}
+
+// sendDeals sends each deal to the participant at the index of the deal and
+// waits until all of them are sent.
+func (h *Handler) sendDeals(deals map[int]*pedersen.Deal, addrs []mino.Address,
+	out mino.Sender) {
+
 	// use a waitgroup to send all the deals asynchronously and wait
 	var wg sync.WaitGroup
 	wg.Add(len(deals))
@@ -213,7 +414,7 @@ func (h *Handler) start(start types.Start, receivedDeals []types.Deal,
 			),
 		)
 
-		errs := out.Send(dealMsg, start.GetAddresses()[i])
+		errs := out.Send(dealMsg, addrs[i])
 		go func(errs <-chan error) {
 			err, more := <-errs
 			if more {
@@ -226,37 +427,53 @@ func (h *Handler) start(start types.Start, receivedDeals []types.Deal,
 	wg.Wait()
 
 	dela.Logger.Trace().Msgf("%s sent all its deals", h.me)
+}
+
+// receiveDeals processes the deals received before the start message, then
+// waits for the other ones until numDeals deals are processed. The responses
+// to the deals are sent to the addresses. It returns the responses received so
+// far. If the deals don't arrive in time, the initiator is told which dealers
+// are missing.
+func (h *Handler) receiveDeals(prog *progress, numDeals int,
+	receivedDeals []types.Deal, receivedResps []*pedersen.Response,
+	initiator mino.Address, addrs []mino.Address, out mino.Sender,
+	in mino.Receiver) ([]*pedersen.Response, error) {
 
 	numReceivedDeals := 0
 
 	// Process the deals we received before the start message
 	for _, deal := range receivedDeals {
-		err = h.handleDeal(deal, from, start.GetAddresses(), out)
+		prog.dealReceived(deal.GetIndex())
+
+		err := h.handleDeal(deal, initiator, addrs, out)
 		if err != nil {
 			dela.Logger.Warn().Msgf("%s failed to handle received deal "+
-				"from %s: %v", h.me, from, err)
+				"from %s: %v", h.me, initiator, err)
 		}
 		numReceivedDeals++
 	}
 
-	// If there are N nodes, then N nodes first send (N-1) Deals. Then each node
-	// send a response to every other nodes. So the number of responses a node
-	// get is (N-1) * (N-1), where (N-1) should equal len(deals).
-	for numReceivedDeals < len(deals) {
-		from, msg, err := in.Recv(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), recvDealsTimeout)
+	defer cancel()
+
+	for numReceivedDeals < numDeals {
+		from, msg, err := in.Recv(ctx)
 		if err != nil {
-			return xerrors.Errorf("failed to receive after sending deals: %v", err)
+			h.reportFailure(prog.missingDeals(), initiator, out)
+			return nil, xerrors.Errorf("failed to receive after sending deals: %v", err)
 		}
 
 		switch msg := msg.(type) {
 
 		case types.Deal:
+			prog.dealReceived(msg.GetIndex())
+
 			// Process the Deal and Send the response to all the other nodes
-			err = h.handleDeal(msg, from, start.GetAddresses(), out)
+			err = h.handleDeal(msg, from, addrs, out)
 			if err != nil {
 				dela.Logger.Warn().Msgf("%s failed to handle received deal "+
 					"from %s: %v", h.me, from, err)
-				return xerrors.Errorf("failed to handle deal from '%s': %v", from, err)
+				return nil, xerrors.Errorf("failed to handle deal from '%s': %v", from, err)
 			}
 			numReceivedDeals++
 
@@ -275,37 +492,35 @@ func (h *Handler) start(start types.Start, receivedDeals []types.Deal,
 			receivedResps = append(receivedResps, response)
 
 		default:
-			return xerrors.Errorf("unexpected message: %T", msg)
+			return nil, xerrors.Errorf("unexpected message: %T", msg)
 		}
 	}
 
-	h.startRes.SetParticipants(start.GetAddresses())
-
-	err = h.certify(receivedResps, out, in, from)
-	if err != nil {
-		return xerrors.Errorf("failed to certify: %v", err)
-	}
-
-	return nil
+	return receivedResps, nil
 }
 
-func (h *Handler) certify(resps []*pedersen.Response, out mino.Sender,
-	in mino.Receiver, from mino.Address) error {
+// certify processes the responses until the DKG is certified and sends the
+// public key to the initiator. If the responses don't arrive in time, the
+// initiator is told which participants are missing.
+func (h *Handler) certify(prog *progress, resps []*pedersen.Response,
+	out mino.Sender, in mino.Receiver, initiator mino.Address) error {
 
 	for _, response := range resps {
+		prog.responseReceived(response.Response.Index)
+
 		_, err := h.dkg.ProcessResponse(response)
 		if err != nil {
 			dela.Logger.Warn().Msgf("%s failed to process response: %v", h.me, err)
 		}
 	}
 
-	for !h.dkg.Certified() {
-		ctx, cancel := context.WithTimeout(context.Background(),
-			recvResponseTimeout)
-		defer cancel()
+	ctx, cancel := context.WithTimeout(context.Background(), recvResponseTimeout)
+	defer cancel()
 
+	for !h.dkg.Certified() {
 		from, msg, err := in.Recv(ctx)
 		if err != nil {
+			h.reportFailure(prog.missingResponses(), initiator, out)
 			return xerrors.Errorf("failed to receive after sending deals: %v", err)
 		}
 
@@ -324,6 +539,8 @@ func (h *Handler) certify(resps []*pedersen.Response, out mino.Sender,
 				},
 			}
 
+			prog.responseReceived(response.Response.Index)
+
 			_, err = h.dkg.ProcessResponse(response)
 			if err != nil {
 				dela.Logger.Warn().Msgf("%s, failed to process response "+
@@ -346,13 +563,22 @@ func (h *Handler) certify(resps []*pedersen.Response, out mino.Sender,
 	// Update the state before sending to acknowledgement to the
 	// orchestrator, so that it can process decrypt requests right away.
 	h.startRes.SetDistKey(distKey.Public())
+	h.startRes.SetCommits(distKey.Commits)
 
 	h.Lock()
 	h.privShare = distKey.PriShare()
 	h.Unlock()
 
+	// The result is on the chain once the initiator hears from every
+	// participant, so that the election can be opened right away.
+	err = h.registerResult(distKey.Commits)
+	if err != nil {
+		h.reportFailure(nil, initiator, out)
+		return xerrors.Errorf("failed to register the dkg: %v", err)
+	}
+
 	done := types.NewStartDone(distKey.Public())
-	err = <-out.Send(done, from)
+	err = <-out.Send(done, initiator)
 	if err != nil {
 		return xerrors.Errorf("got an error while sending pub key: %v", err)
 	}
@@ -360,6 +586,95 @@ func (h *Handler) certify(resps []*pedersen.Response, out mino.Sender,
 	return nil
 }
 
+// registerResult registers the result of the DKG on the election and waits for
+// it to be accepted. The election is opened, or its roster updated after a
+// resharing, with the result registered by its participants rather than with
+// the state of a single node.
+func (h *Handler) registerResult(commits []kyber.Point) error {
+	registerDKG := etypes.RegisterDKG{
+		ElectionID:   h.electionID,
+		Commits:      make([][]byte, len(commits)),
+		Participants: make([][]byte, len(h.startRes.GetParticipants())),
+	}
+
+	for i, commit := range commits {
+		buf, err := commit.MarshalBinary()
+		if err != nil {
+			return xerrors.Errorf("failed to marshal commit: %v", err)
+		}
+
+		registerDKG.Commits[i] = buf
+	}
+
+	for i, addr := range h.startRes.GetParticipants() {
+		buf, err := addr.MarshalText()
+		if err != nil {
+			return xerrors.Errorf("failed to marshal address: %v", err)
+		}
+
+		registerDKG.Participants[i] = buf
+	}
+
+	signature, pubkey, err := signFingerprint(registerDKG, h.pubSharesSigner)
+	if err != nil {
+		return xerrors.Errorf("failed to sign: %v", err)
+	}
+
+	registerDKG.Signature = signature
+	registerDKG.PublicKey = pubkey
+
+	ctx, cancel := context.WithTimeout(context.Background(), registerTimeout)
+	defer cancel()
+
+	for ctx.Err() == nil {
+		err = h.txmnger.Sync()
+		if err != nil {
+			return xerrors.Errorf("failed to sync manager: %v", err)
+		}
+
+		tx, err := makeContractTx(h.context, evoting.CmdRegisterDKG, registerDKG,
+			h.txmnger)
+		if err != nil {
+			return xerrors.Errorf("failed to make tx: %v", err)
+		}
+
+		watchCtx, cancelWatch := context.WithTimeout(ctx, registerWatchTimeout)
+		events := h.service.Watch(watchCtx)
+
+		err = h.pool.Add(tx)
+		if err != nil {
+			cancelWatch()
+			return xerrors.Errorf("failed to add transaction to the pool: %v", err)
+		}
+
+		accepted, msg := watchTx(events, tx.GetID())
+		cancelWatch()
+
+		if accepted {
+			dela.Logger.Info().Msgf("%s registered the dkg", h.me)
+			return nil
+		}
+
+		dela.Logger.Info().Msgf("registration of the dkg denied: %s", msg)
+	}
+
+	return xerrors.Errorf("the registration was not accepted in time")
+}
+
+// reportFailure tells the initiator that the node gave up on the DKG, along
+// with the participants it didn't hear from, so that it doesn't wait for this
+// node until its own timeout.
+func (h *Handler) reportFailure(missing []mino.Address, initiator mino.Address,
+	out mino.Sender) {
+
+	dela.Logger.Warn().Msgf("%s gave up on the DKG, missing: %v", h.me, missing)
+
+	err := <-out.Send(types.NewStartFailed(missing), initiator)
+	if err != nil {
+		dela.Logger.Warn().Msgf("%s failed to report the failure: %v", h.me, err)
+	}
+}
+
 // handleDeal process the Deal and send the responses to the other nodes.
 func (h *Handler) handleDeal(msg types.Deal, from mino.Address, addrs []mino.Address,
 	out mino.Sender) error {
@@ -422,21 +737,25 @@ func (h *Handler) handleDecryptRequest(electionID string) error {
 	numberOfShuffles := len(shuffleInstances)
 	numberOfBallots := len(shuffleInstances[numberOfShuffles-1].ShuffledBallots)
 	publicShares := make([][]etypes.Pubshare, numberOfBallots)
+	proofs := make(etypes.PubshareProofsUnit, numberOfBallots)
 
 	h.RLock()
 
 	for i, ballot := range shuffleInstances[numberOfShuffles-1].ShuffledBallots {
 		ballotShares := make([]etypes.Pubshare, len(ballot))
+		ballotProofs := make([]etypes.PubshareProof, len(ballot))
 
 		for j, ciphertext := range ballot {
-			S := suite.Point().Mul(h.privShare.V, ciphertext.K)
-
-			partialVal := suite.Point().Sub(ciphertext.C, S)
-
-			ballotShares[j] = partialVal
+			ballotShares[j], ballotProofs[j], err = etypes.ProvePubshare(h.privShare.V,
+				ciphertext)
+			if err != nil {
+				h.RUnlock()
+				return xerrors.Errorf("failed to compute pubshare: %v", err)
+			}
 		}
 
 		publicShares[i] = ballotShares
+		proofs[i] = ballotProofs
 	}
 
 	h.RUnlock()
@@ -454,17 +773,15 @@ func (h *Handler) handleDecryptRequest(electionID string) error {
 			return xerrors.Errorf("could not get the election: %v", err)
 		}
 
-		//TODO: Works with current "shuffleThreshold", but the shuffle threshold
-		// should be smaller in theory ? (1/3 + 1 vs 2/3 + 1 ? )
 		nbrSubmissions := len(election.PubsharesUnits.Pubshares)
 
-		if nbrSubmissions >= election.ShuffleThreshold {
+		if nbrSubmissions >= election.PubsharesThreshold() {
 			dela.Logger.Info().Msgf("decryption possible with shares from %d nodes",
 				nbrSubmissions)
 			return nil
 		}
 
-		tx, err := makeTx(h.context, &election, publicShares, h.privShare.I,
+		tx, err := makeTx(h.context, &election, publicShares, proofs, h.privShare.I,
 			h.txmnger, h.pubSharesSigner)
 
 		if err != nil {
@@ -472,7 +789,7 @@ func (h *Handler) handleDecryptRequest(electionID string) error {
 		}
 
 		//TODO: Define in term of size of election ? (same in shuffle)
-		watchTimeout := 4 + rand.Intn(election.ShuffleThreshold)
+		watchTimeout := 4 + rand.Intn(election.PubsharesThreshold())
 		watchCtx, cancel := context.WithTimeout(context.Background(), time.Duration(watchTimeout)*time.Second)
 		defer cancel()
 
@@ -667,7 +984,11 @@ func (hd *HandlerData) UnmarshalJSON(data []byte) error {
 type state struct {
 	sync.Mutex
 	distKey      kyber.Point
+	commits      []kyber.Point
 	participants []mino.Address
+	// pubkeys are the DKG public keys of the participants, which are needed to
+	// reshare even if some of them are unreachable
+	pubkeys []kyber.Point
 }
 
 func (s *state) Done() bool {
@@ -688,6 +1009,19 @@ func (s *state) SetDistKey(key kyber.Point) {
 	s.distKey = key
 }
 
+// GetCommits returns the commitments of the public polynomial of the DKG
+func (s *state) GetCommits() []kyber.Point {
+	s.Lock()
+	defer s.Unlock()
+	return s.commits
+}
+
+func (s *state) SetCommits(commits []kyber.Point) {
+	s.Lock()
+	defer s.Unlock()
+	s.commits = commits
+}
+
 func (s *state) GetParticipants() []mino.Address {
 	s.Lock()
 	defer s.Unlock()
@@ -700,12 +1034,28 @@ func (s *state) SetParticipants(addrs []mino.Address) {
 	s.participants = addrs
 }
 
+// GetPublicKeys returns the DKG public keys of the participants, in the order
+// of their index.
+func (s *state) GetPublicKeys() []kyber.Point {
+	s.Lock()
+	defer s.Unlock()
+	return s.pubkeys
+}
+
+func (s *state) SetPublicKeys(pubkeys []kyber.Point) {
+	s.Lock()
+	defer s.Unlock()
+	s.pubkeys = pubkeys
+}
+
 func (s *state) MarshalJSON() ([]byte, error) {
 	s.Lock()
 	defer s.Unlock()
 
 	var distKeyBuf []byte
+	var commitsBuf [][]byte
 	var participantsBuf [][]byte
+	var pubkeysBuf [][]byte
 	var err error
 
 	if s.distKey != nil {
@@ -714,6 +1064,14 @@ func (s *state) MarshalJSON() ([]byte, error) {
 			return nil, err
 		}
 
+		commitsBuf = make([][]byte, len(s.commits))
+		for i, c := range s.commits {
+			commitsBuf[i], err = c.MarshalBinary()
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		participantsBuf = make([][]byte, len(s.participants))
 		for i, p := range s.participants {
 			pBuf, err := p.MarshalText()
@@ -722,21 +1080,35 @@ func (s *state) MarshalJSON() ([]byte, error) {
 			}
 			participantsBuf[i] = pBuf
 		}
+
+		pubkeysBuf = make([][]byte, len(s.pubkeys))
+		for i, p := range s.pubkeys {
+			pubkeysBuf[i], err = p.MarshalBinary()
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	return json.Marshal(&struct {
 		DistKey      []byte   `json:",omitempty"`
+		Commits      [][]byte `json:",omitempty"`
 		Participants [][]byte `json:",omitempty"`
+		PublicKeys   [][]byte `json:",omitempty"`
 	}{
 		DistKey:      distKeyBuf,
+		Commits:      commitsBuf,
 		Participants: participantsBuf,
+		PublicKeys:   pubkeysBuf,
 	})
 }
 
 func (s *state) UnmarshalJSON(data []byte) error {
 	aux := &struct {
 		DistKey      []byte
+		Commits      [][]byte
 		Participants [][]byte
+		PublicKeys   [][]byte
 	}{}
 	err := json.Unmarshal(data, &aux)
 	if err != nil {
@@ -754,6 +1126,32 @@ func (s *state) UnmarshalJSON(data []byte) error {
 		s.SetDistKey(nil)
 	}
 
+	var commits []kyber.Point
+
+	for _, buf := range aux.Commits {
+		c := suite.Point()
+		err = c.UnmarshalBinary(buf)
+		if err != nil {
+			return err
+		}
+		commits = append(commits, c)
+	}
+
+	s.SetCommits(commits)
+
+	var pubkeys []kyber.Point
+
+	for _, buf := range aux.PublicKeys {
+		p := suite.Point()
+		err = p.UnmarshalBinary(buf)
+		if err != nil {
+			return err
+		}
+		pubkeys = append(pubkeys, p)
+	}
+
+	s.SetPublicKeys(pubkeys)
+
 	if aux.Participants != nil {
 		// TODO: Is using a fake implementation a problem?
 		f := fake.NewBadMino().GetAddressFactory()
@@ -769,6 +1167,77 @@ func (s *state) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// progress keeps track of the participants that delivered their deal and
+// their responses during a run of the DKG, to report the ones that didn't.
+type progress struct {
+	me         mino.Address
+	dealers    []mino.Address
+	responders []mino.Address
+	dealt      map[uint32]bool
+	responded  map[uint32]bool
+}
+
+// newProgress returns a new progress for the dealers and the responders,
+// ordered by their index in the DKG.
+func newProgress(me mino.Address, dealers, responders []mino.Address) *progress {
+	return &progress{
+		me:         me,
+		dealers:    dealers,
+		responders: responders,
+		dealt:      make(map[uint32]bool),
+		responded:  make(map[uint32]bool),
+	}
+}
+
+// dealReceived records the deal of the dealer at the index.
+func (p *progress) dealReceived(index uint32) {
+	p.dealt[index] = true
+}
+
+// responseReceived records a response of the responder at the index.
+func (p *progress) responseReceived(index uint32) {
+	p.responded[index] = true
+}
+
+// missingDeals returns the dealers whose deal was not received.
+func (p *progress) missingDeals() []mino.Address {
+	return p.missing(p.dealers, p.dealt)
+}
+
+// missingResponses returns the responders from which no response was
+// received.
+func (p *progress) missingResponses() []mino.Address {
+	return p.missing(p.responders, p.responded)
+}
+
+// missing returns the addresses whose index is not received, except the node
+// itself, which processes its own deal and responses locally. A nil address is
+// not expected to send anything.
+func (p *progress) missing(addrs []mino.Address, received map[uint32]bool) []mino.Address {
+	missing := make([]mino.Address, 0)
+
+	for i, addr := range addrs {
+		if addr == nil || received[uint32(i)] || addr.Equal(p.me) {
+			continue
+		}
+
+		missing = append(missing, addr)
+	}
+
+	return missing
+}
+
+// containsKey returns true if the key is in the list.
+func containsKey(keys []kyber.Point, key kyber.Point) bool {
+	for _, k := range keys {
+		if k.Equal(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // watchTx checks the transaction to find one that match txID. Returns if the
 // transaction has been accepted or not. Will also return false if/when the
 // events chan is closed, which is expected to happen.
@@ -794,6 +1263,7 @@ func watchTx(events <-chan ordering.Event, txID []byte) (bool, string) {
 }
 
 func makeTx(ctx serde.Context, election *etypes.Election, pubShares etypes.PubsharesUnit,
+	proofs etypes.PubshareProofsUnit,
 	index int,
 	manager txn.Manager,
 	pubSharesSigner crypto.Signer) (txn.Transaction, error) {
@@ -801,41 +1271,58 @@ func makeTx(ctx serde.Context, election *etypes.Election, pubShares etypes.Pubsh
 	pubShareTx := etypes.RegisterPubShares{
 		ElectionID: election.ElectionID,
 		Pubshares:  pubShares,
+		Proofs:     proofs,
 		Index:      index,
 	}
 
+	signature, pubKey, err := signFingerprint(pubShareTx, pubSharesSigner)
+	if err != nil {
+		return nil, xerrors.Errorf("could not sign the pubShares : %v", err)
+	}
+
+	// Complete transaction:
+	pubShareTx.Signature = signature
+	pubShareTx.PublicKey = pubKey
+
+	return makeContractTx(ctx, evoting.CmdRegisterPubShares, pubShareTx, manager)
+}
+
+// signFingerprint returns the encoded signature of the fingerprint of the
+// message and the public key of the signer.
+func signFingerprint(msg serde.Fingerprinter, signer crypto.Signer) ([]byte, []byte, error) {
 	h := sha256.New()
 
-	err := pubShareTx.Fingerprint(h)
+	err := msg.Fingerprint(h)
 	if err != nil {
-		return nil, xerrors.Errorf("failed to get fingerprint: %v", err)
+		return nil, nil, xerrors.Errorf("failed to get fingerprint: %v", err)
 	}
 
-	hash := h.Sum(nil)
-
-	// Sign the pubShares :
-	signature, err := pubSharesSigner.Sign(hash)
+	signature, err := signer.Sign(h.Sum(nil))
 	if err != nil {
-		return nil, xerrors.Errorf("could not sign the pubShares : %v", err)
+		return nil, nil, xerrors.Errorf("failed to sign: %v", err)
 	}
 
-	pubKey, err := pubSharesSigner.GetPublicKey().MarshalBinary()
+	pubKey, err := signer.GetPublicKey().MarshalBinary()
 	if err != nil {
-		return nil, xerrors.Errorf("could not marshal signer's public key: %v", err)
+		return nil, nil, xerrors.Errorf("could not marshal signer's public key: %v", err)
 	}
 
 	encodedSignature, err := signature.Serialize(jsondela.NewContext())
 	if err != nil {
-		return nil, xerrors.Errorf("Could not encode signature as []byte : %v ", err)
+		return nil, nil, xerrors.Errorf("Could not encode signature as []byte : %v ", err)
 	}
 
-	// Complete transaction:
-	pubShareTx.Signature = encodedSignature
-	pubShareTx.PublicKey = pubKey
+	return encodedSignature, pubKey, nil
+}
+
+// makeContractTx returns a transaction that runs the command of the evoting
+// contract with the message as argument.
+func makeContractTx(ctx serde.Context, cmd evoting.Command, msg serde.Message,
+	manager txn.Manager) (txn.Transaction, error) {
 
-	data, err := pubShareTx.Serialize(ctx)
+	data, err := msg.Serialize(ctx)
 	if err != nil {
-		return nil, xerrors.Errorf("failed to serialize register pubShares: %v", err)
+		return nil, xerrors.Errorf("failed to serialize: %v", err)
 	}
 
 	args := make([]txn.Arg, 3)
@@ -845,7 +1332,7 @@ func makeTx(ctx serde.Context, election *etypes.Election, pubShares etypes.Pubsh
 	}
 	args[1] = txn.Arg{
 		Key:   evoting.CmdArg,
-		Value: []byte(evoting.CmdRegisterPubShares),
+		Value: []byte(cmd),
 	}
 	args[2] = txn.Arg{
 		Key:   evoting.ElectionArg,
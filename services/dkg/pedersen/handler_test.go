@@ -108,6 +108,7 @@ func TestHandler_Start(t *testing.T) {
 		privKey:  privKey,
 	}
 	start := types.NewStart(
+		0,
 		[]mino.Address{fake.NewAddress(0)},
 		[]kyber.Point{},
 	)
@@ -115,28 +116,80 @@ func TestHandler_Start(t *testing.T) {
 	require.EqualError(t, err, "there should be as many players as pubKey: 1 := 0")
 
 	start = types.NewStart(
+		0,
 		[]mino.Address{fake.NewAddress(0), fake.NewAddress(1)},
 		[]kyber.Point{pubKey, suite.Point()},
 	)
 	receiver := fake.NewBadReceiver()
 	err = h.start(start, []types.Deal{}, []*pedersen.Response{}, nil, fake.Sender{}, receiver)
-	require.EqualError(t, err, fake.Err("failed to receive after sending deals"))
+	require.EqualError(t, err, fake.Err("failed to receive deals: failed to receive after sending deals"))
 
 	receiver = fake.NewReceiver(
 		fake.NewRecvMsg(fake.NewAddress(0), types.Deal{}),
 		fake.NewRecvMsg(fake.NewAddress(0), nil),
 	)
 	err = h.start(start, []types.Deal{}, []*pedersen.Response{}, nil, fake.Sender{}, receiver)
-	require.EqualError(t, err, "failed to handle deal from 'fake.Address[0]': failed to process deal from %!s(<nil>): schnorr: signature of invalid length 0 instead of 64")
+	require.EqualError(t, err, "failed to receive deals: failed to handle deal from 'fake.Address[0]': failed to process deal from %!s(<nil>): schnorr: signature of invalid length 0 instead of 64")
 
 	err = h.start(start, []types.Deal{}, []*pedersen.Response{}, nil, fake.Sender{}, &fake.Receiver{})
-	require.EqualError(t, err, "unexpected message: <nil>")
+	require.EqualError(t, err, "failed to receive deals: unexpected message: <nil>")
 
 	// We check when there is already something in the slice if Deals
 	err = h.start(start, []types.Deal{{}}, []*pedersen.Response{}, nil, fake.NewBadSender(), &fake.Receiver{})
 	require.EqualError(t, err, "failed to certify: expected a response, got: <nil>")
 }
 
+func TestHandler_Reshare(t *testing.T) {
+	privKey := suite.Scalar().Pick(suite.RandomStream())
+	pubKey := suite.Point().Mul(privKey, nil)
+
+	priPoly := share.NewPriPoly(suite, 2, nil, suite.RandomStream())
+	_, commits := priPoly.Commit(nil).Info()
+
+	h := Handler{
+		startRes: &state{
+			distKey:      commits[0],
+			commits:      commits,
+			participants: []mino.Address{fake.NewAddress(0), fake.NewAddress(1)},
+		},
+		me:        fake.NewAddress(0),
+		privShare: priPoly.Shares(2)[0],
+		privKey:   privKey,
+		pubKey:    pubKey,
+	}
+
+	oldAddrs := []mino.Address{fake.NewAddress(0), fake.NewAddress(1)}
+	oldPubkeys := []kyber.Point{pubKey, suite.Point().Pick(suite.RandomStream())}
+	newAddrs := []mino.Address{fake.NewAddress(1), fake.NewAddress(2)}
+	newPubkeys := []kyber.Point{oldPubkeys[1], suite.Point().Pick(suite.RandomStream())}
+
+	reshare := types.NewReshare(2, commits, oldAddrs, nil, oldAddrs, newAddrs, newPubkeys)
+	err := h.reshare(reshare, nil, nil, nil, nil, nil)
+	require.EqualError(t, err, "there should be as many old players as pubKey: 2 := 0")
+
+	reshare = types.NewReshare(2, commits, oldAddrs, oldPubkeys, oldAddrs, newAddrs, nil)
+	err = h.reshare(reshare, nil, nil, nil, nil, nil)
+	require.EqualError(t, err, "there should be as many new players as pubKey: 2 := 0")
+
+	reshare = types.NewReshare(2, commits, oldAddrs, oldPubkeys, oldAddrs[:1], newAddrs,
+		newPubkeys)
+	err = h.reshare(reshare, nil, nil, nil, nil, nil)
+	require.EqualError(t, err, "there should be as many dealers as the threshold: 1 != 2")
+
+	reshare = types.NewReshare(2, commits, newAddrs, newPubkeys, newAddrs, newAddrs, newPubkeys)
+	err = h.reshare(reshare, nil, nil, fake.NewAddress(0), nil, nil)
+	require.EqualError(t, err, "fake.Address[0] is not a participant of the resharing")
+
+	// a node that leaves the DKG deals its share and erases it
+	reshare = types.NewReshare(2, commits, oldAddrs, oldPubkeys, oldAddrs, newAddrs, newPubkeys)
+	err = h.reshare(reshare, nil, nil, fake.NewAddress(0), fake.Sender{}, nil)
+	require.NoError(t, err)
+	require.Nil(t, h.privShare)
+	require.False(t, h.startRes.Done())
+	require.Nil(t, h.startRes.GetCommits())
+	require.Nil(t, h.startRes.GetPublicKeys())
+}
+
 func TestHandler_Certify(t *testing.T) {
 	privKey := suite.Scalar().Pick(suite.RandomStream())
 	pubKey := suite.Point().Mul(privKey, nil)
@@ -151,15 +204,87 @@ func TestHandler_Certify(t *testing.T) {
 	receiver := fake.NewBadReceiver()
 	responses := []*pedersen.Response{{Response: &vss.Response{}}}
 
-	err = h.certify(responses, fake.Sender{}, receiver, nil)
+	err = h.certify(newProgress(nil, nil, nil), responses, fake.Sender{}, receiver, nil)
 	require.EqualError(t, err, fake.Err("failed to receive after sending deals"))
 
-	dkg = getCertified(t)
-	h.dkg = dkg
-	err = h.certify(responses, fake.NewBadSender(), &fake.Receiver{}, nil)
+	service := fake.Service{Context: json.NewContext()}
+	pool := fake.Pool{Service: &service}
+
+	h.electionID = hex.EncodeToString([]byte("election"))
+	h.service = &service
+	h.pool = &pool
+	h.context = json.NewContext()
+	h.pubSharesSigner = fake.NewSigner()
+	h.txmnger = fake.Manager{}
+	h.startRes.SetParticipants([]mino.Address{fake.NewAddress(0), fake.NewAddress(1)})
+
+	h.dkg = getCertified(t)
+	err = h.certify(newProgress(nil, nil, nil), responses, fake.Sender{}, &fake.Receiver{}, nil)
+	require.EqualError(t, err, fake.Err("failed to register the dkg: failed to make tx: "+
+		"failed to use manager"))
+
+	h.txmnger = signed.NewManager(fake.NewSigner(), fakeClient{})
+
+	h.dkg = getCertified(t)
+	err = h.certify(newProgress(nil, nil, nil), responses, fake.NewBadSender(), &fake.Receiver{}, nil)
 	require.EqualError(t, err, fake.Err("got an error while sending pub key"))
 }
 
+func TestHandler_RegisterResult(t *testing.T) {
+	service := fake.Service{Context: json.NewContext()}
+	pool := fake.Pool{Service: &service}
+
+	h := Handler{
+		me:              fake.NewAddress(0),
+		electionID:      hex.EncodeToString([]byte("election")),
+		startRes:        &state{},
+		service:         &service,
+		pool:            &pool,
+		context:         json.NewContext(),
+		pubSharesSigner: fake.NewSigner(),
+		txmnger:         signed.NewManager(fake.NewSigner(), fakeClient{}),
+	}
+
+	participants := []mino.Address{fake.NewAddress(0), fake.NewAddress(1)}
+	h.startRes.SetParticipants(participants)
+
+	priPoly := share.NewPriPoly(suite, 2, nil, suite.RandomStream())
+	_, commits := priPoly.Commit(nil).Info()
+
+	// the first registration is denied by the fake service, the node
+	// registers again
+	err := h.registerResult(commits)
+	require.NoError(t, err)
+	require.True(t, service.Status)
+
+	h.pubSharesSigner = fake.NewBadSigner()
+
+	err = h.registerResult(commits)
+	require.EqualError(t, err, fake.Err("failed to sign: failed to sign"))
+}
+
+func TestProgress_Missing(t *testing.T) {
+	dealers := []mino.Address{fake.NewAddress(0), fake.NewAddress(1), fake.NewAddress(2)}
+	responders := []mino.Address{fake.NewAddress(1), fake.NewAddress(2), fake.NewAddress(3)}
+
+	p := newProgress(fake.NewAddress(1), dealers, responders)
+
+	require.Equal(t, []mino.Address{fake.NewAddress(0), fake.NewAddress(2)}, p.missingDeals())
+	require.Equal(t, []mino.Address{fake.NewAddress(2), fake.NewAddress(3)}, p.missingResponses())
+
+	p.dealReceived(2)
+	p.responseReceived(2)
+
+	require.Equal(t, []mino.Address{fake.NewAddress(0)}, p.missingDeals())
+	require.Equal(t, []mino.Address{fake.NewAddress(2)}, p.missingResponses())
+
+	p.dealReceived(0)
+	p.responseReceived(1)
+
+	require.Empty(t, p.missingDeals())
+	require.Empty(t, p.missingResponses())
+}
+
 func TestHandler_HandleDeal(t *testing.T) {
 	privKey1 := suite.Scalar().Pick(suite.RandomStream())
 	pubKey1 := suite.Point().Mul(privKey1, nil)
@@ -233,7 +358,10 @@ func TestState_MarshalJSON(t *testing.T) {
 	participants := []mino.Address{fake.NewAddress(0), fake.NewAddress(1)}
 
 	s1.SetDistKey(distKey)
+	s1.SetCommits([]kyber.Point{distKey, suite.Point().Pick(suite.RandomStream())})
 	s1.SetParticipants(participants)
+	s1.SetPublicKeys([]kyber.Point{suite.Point().Pick(suite.RandomStream()),
+		suite.Point().Pick(suite.RandomStream())})
 
 	data, err = s1.MarshalJSON()
 	require.NoError(t, err)
@@ -414,6 +542,20 @@ func requireStatesEqual(t *testing.T, s1, s2 *state) {
 		require.True(t, DistKey2.Equal(DistKey1))
 	}
 	require.Equal(t, s2.GetParticipants(), s1.GetParticipants())
+
+	commits1 := s1.GetCommits()
+	commits2 := s2.GetCommits()
+	require.Len(t, commits2, len(commits1))
+	for i := range commits1 {
+		require.True(t, commits2[i].Equal(commits1[i]))
+	}
+
+	pubkeys1 := s1.GetPublicKeys()
+	pubkeys2 := s2.GetPublicKeys()
+	require.Len(t, pubkeys2, len(pubkeys1))
+	for i := range pubkeys1 {
+		require.True(t, pubkeys2[i].Equal(pubkeys1[i]))
+	}
 }
 
 type fakeClient struct{}
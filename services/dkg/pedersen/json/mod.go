@@ -27,6 +27,16 @@ type Start struct {
 	PublicKeys []PublicKey
 }
 
+type Reshare struct {
+	Threshold     int
+	Commits       []PublicKey
+	OldAddresses  []Address
+	OldPublicKeys []PublicKey
+	Dealers       []Address
+	NewAddresses  []Address
+	NewPublicKeys []PublicKey
+}
+
 type EncryptedDeal struct {
 	DHKey     []byte
 	Signature []byte
@@ -56,6 +66,10 @@ type StartDone struct {
 	PublicKey PublicKey
 }
 
+type StartFailed struct {
+	Missing []Address
+}
+
 type DecryptRequest struct {
 	ElectionId string
 }
@@ -68,9 +82,11 @@ type GetPeerPubKeyResp struct {
 
 type Message struct {
 	Start             *Start             `json:",omitempty"`
+	Reshare           *Reshare           `json:",omitempty"`
 	Deal              *Deal              `json:",omitempty"`
 	Response          *Response          `json:",omitempty"`
 	StartDone         *StartDone         `json:",omitempty"`
+	StartFailed       *StartFailed       `json:",omitempty"`
 	DecryptRequest    *DecryptRequest    `json:",omitempty"`
 	GetPeerPubKey     *GetPeerPubKey     `json:",omitempty"`
 	GetPeerPubKeyResp *GetPeerPubKeyResp `json:",omitempty"`
@@ -96,32 +112,65 @@ func (f msgFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, error)
 
 	switch in := msg.(type) {
 	case types.Start:
-		addrs := make([]Address, len(in.GetAddresses()))
-		for i, addr := range in.GetAddresses() {
-			data, err := addr.MarshalText()
-			if err != nil {
-				return nil, xerrors.Errorf("couldn't marshal address: %v", err)
-			}
-
-			addrs[i] = data
+		addrs, err := encodeAddresses(in.GetAddresses())
+		if err != nil {
+			return nil, err
 		}
 
-		pubkeys := make([]PublicKey, len(in.GetPublicKeys()))
-		for i, pubkey := range in.GetPublicKeys() {
-			data, err := pubkey.MarshalBinary()
-			if err != nil {
-				return nil, xerrors.Errorf("couldn't marshal public key: %v", err)
-			}
-
-			pubkeys[i] = data
+		pubkeys, err := encodePublicKeys(in.GetPublicKeys())
+		if err != nil {
+			return nil, err
 		}
 
 		start := Start{
+			Threshold:  in.GetThreshold(),
 			Addresses:  addrs,
 			PublicKeys: pubkeys,
 		}
 
 		m = Message{Start: &start}
+	case types.Reshare:
+		commits, err := encodePublicKeys(in.GetCommits())
+		if err != nil {
+			return nil, err
+		}
+
+		oldAddrs, err := encodeAddresses(in.GetOldAddresses())
+		if err != nil {
+			return nil, err
+		}
+
+		oldPubkeys, err := encodePublicKeys(in.GetOldPublicKeys())
+		if err != nil {
+			return nil, err
+		}
+
+		dealers, err := encodeAddresses(in.GetDealers())
+		if err != nil {
+			return nil, err
+		}
+
+		newAddrs, err := encodeAddresses(in.GetNewAddresses())
+		if err != nil {
+			return nil, err
+		}
+
+		newPubkeys, err := encodePublicKeys(in.GetNewPublicKeys())
+		if err != nil {
+			return nil, err
+		}
+
+		reshare := Reshare{
+			Threshold:     in.GetThreshold(),
+			Commits:       commits,
+			OldAddresses:  oldAddrs,
+			OldPublicKeys: oldPubkeys,
+			Dealers:       dealers,
+			NewAddresses:  newAddrs,
+			NewPublicKeys: newPubkeys,
+		}
+
+		m = Message{Reshare: &reshare}
 	case types.Deal:
 		d := Deal{
 			Index:     in.GetIndex(),
@@ -158,6 +207,17 @@ func (f msgFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, error)
 		}
 
 		m = Message{StartDone: &ack}
+	case types.StartFailed:
+		missing, err := encodeAddresses(in.GetMissing())
+		if err != nil {
+			return nil, err
+		}
+
+		failed := StartFailed{
+			Missing: missing,
+		}
+
+		m = Message{StartFailed: &failed}
 	case types.DecryptRequest:
 		req := DecryptRequest{
 			ElectionId: in.GetElectionId(),
@@ -202,6 +262,10 @@ func (f msgFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error)
 		return f.decodeStart(ctx, m.Start)
 	}
 
+	if m.Reshare != nil {
+		return f.decodeReshare(ctx, m.Reshare)
+	}
+
 	if m.Deal != nil {
 		deal := types.NewDeal(
 			m.Deal.Index,
@@ -243,6 +307,15 @@ func (f msgFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error)
 		return ack, nil
 	}
 
+	if m.StartFailed != nil {
+		missing, err := decodeAddresses(ctx, m.StartFailed.Missing)
+		if err != nil {
+			return nil, err
+		}
+
+		return types.NewStartFailed(missing), nil
+	}
+
 	if m.DecryptRequest != nil {
 		req := types.NewDecryptRequest(m.DecryptRequest.ElectionId)
 
@@ -267,6 +340,74 @@ func (f msgFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error)
 }
 
 func (f msgFormat) decodeStart(ctx serde.Context, start *Start) (serde.Message, error) {
+	addrs, err := decodeAddresses(ctx, start.Addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	pubkeys, err := f.decodePublicKeys(start.PublicKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	s := types.NewStart(start.Threshold, addrs, pubkeys)
+
+	return s, nil
+}
+
+func (f msgFormat) decodeReshare(ctx serde.Context, reshare *Reshare) (serde.Message, error) {
+	commits, err := f.decodePublicKeys(reshare.Commits)
+	if err != nil {
+		return nil, err
+	}
+
+	oldAddrs, err := decodeAddresses(ctx, reshare.OldAddresses)
+	if err != nil {
+		return nil, err
+	}
+
+	oldPubkeys, err := f.decodePublicKeys(reshare.OldPublicKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	dealers, err := decodeAddresses(ctx, reshare.Dealers)
+	if err != nil {
+		return nil, err
+	}
+
+	newAddrs, err := decodeAddresses(ctx, reshare.NewAddresses)
+	if err != nil {
+		return nil, err
+	}
+
+	newPubkeys, err := f.decodePublicKeys(reshare.NewPublicKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	r := types.NewReshare(reshare.Threshold, commits, oldAddrs, oldPubkeys, dealers,
+		newAddrs, newPubkeys)
+
+	return r, nil
+}
+
+func (f msgFormat) decodePublicKeys(buffers []PublicKey) ([]kyber.Point, error) {
+	pubkeys := make([]kyber.Point, len(buffers))
+	for i, pubkey := range buffers {
+		point := f.suite.Point()
+		err := point.UnmarshalBinary(pubkey)
+		if err != nil {
+			return nil, xerrors.Errorf("couldn't unmarshal public key: %v", err)
+		}
+
+		pubkeys[i] = point
+	}
+
+	return pubkeys, nil
+}
+
+func decodeAddresses(ctx serde.Context, buffers []Address) ([]mino.Address, error) {
 	factory := ctx.GetFactory(types.AddrKey{})
 
 	fac, ok := factory.(mino.AddressFactory)
@@ -274,23 +415,38 @@ func (f msgFormat) decodeStart(ctx serde.Context, start *Start) (serde.Message,
 		return nil, xerrors.Errorf("invalid factory of type '%T'", factory)
 	}
 
-	addrs := make([]mino.Address, len(start.Addresses))
-	for i, addr := range start.Addresses {
+	addrs := make([]mino.Address, len(buffers))
+	for i, addr := range buffers {
 		addrs[i] = fac.FromText(addr)
 	}
 
-	pubkeys := make([]kyber.Point, len(start.PublicKeys))
-	for i, pubkey := range start.PublicKeys {
-		point := f.suite.Point()
-		err := point.UnmarshalBinary(pubkey)
+	return addrs, nil
+}
+
+func encodePublicKeys(pubkeys []kyber.Point) ([]PublicKey, error) {
+	buffers := make([]PublicKey, len(pubkeys))
+	for i, pubkey := range pubkeys {
+		data, err := pubkey.MarshalBinary()
 		if err != nil {
-			return nil, xerrors.Errorf("couldn't unmarshal public key: %v", err)
+			return nil, xerrors.Errorf("couldn't marshal public key: %v", err)
 		}
 
-		pubkeys[i] = point
+		buffers[i] = data
 	}
 
-	s := types.NewStart(addrs, pubkeys)
+	return buffers, nil
+}
 
-	return s, nil
+func encodeAddresses(addrs []mino.Address) ([]Address, error) {
+	buffers := make([]Address, len(addrs))
+	for i, addr := range addrs {
+		data, err := addr.MarshalText()
+		if err != nil {
+			return nil, xerrors.Errorf("couldn't marshal address: %v", err)
+		}
+
+		buffers[i] = data
+	}
+
+	return buffers, nil
 }
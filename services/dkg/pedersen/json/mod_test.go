@@ -17,21 +17,21 @@ import (
 var suite = suites.MustFind("Ed25519")
 
 func TestMessageFormat_Start_Encode(t *testing.T) {
-	start := types.NewStart([]mino.Address{fake.NewAddress(0)}, []kyber.Point{suite.Point()})
+	start := types.NewStart(2, []mino.Address{fake.NewAddress(0)}, []kyber.Point{suite.Point()})
 
 	format := newMsgFormat()
 	ctx := serde.NewContext(fake.ContextEngine{})
 
 	data, err := format.Encode(ctx, start)
 	require.NoError(t, err)
-	regexp := `{"Start":{"Threshold":0,"Addresses":\["AAAAAA=="\],"PublicKeys":\["[^"]+"\]}}`
+	regexp := `{"Start":{"Threshold":2,"Addresses":\["AAAAAA=="\],"PublicKeys":\["[^"]+"\]}}`
 	require.Regexp(t, regexp, string(data))
 
-	start = types.NewStart([]mino.Address{fake.NewBadAddress()}, nil)
+	start = types.NewStart(0, []mino.Address{fake.NewBadAddress()}, nil)
 	_, err = format.Encode(ctx, start)
 	require.EqualError(t, err, fake.Err("couldn't marshal address"))
 
-	start = types.NewStart(nil, []kyber.Point{badPoint{}})
+	start = types.NewStart(0, nil, []kyber.Point{badPoint{}})
 	_, err = format.Encode(ctx, start)
 	require.EqualError(t, err, fake.Err("couldn't marshal public key"))
 
@@ -42,6 +42,30 @@ func TestMessageFormat_Start_Encode(t *testing.T) {
 	require.EqualError(t, err, "unsupported message of type 'fake.Message'")
 }
 
+func TestMessageFormat_Reshare_Encode(t *testing.T) {
+	reshare := types.NewReshare(2, []kyber.Point{suite.Point()},
+		[]mino.Address{fake.NewAddress(0)}, []kyber.Point{suite.Point()},
+		[]mino.Address{fake.NewAddress(0)}, []mino.Address{fake.NewAddress(1)},
+		[]kyber.Point{suite.Point()})
+
+	format := newMsgFormat()
+	ctx := serde.NewContext(fake.ContextEngine{})
+
+	data, err := format.Encode(ctx, reshare)
+	require.NoError(t, err)
+	regexp := `{"Reshare":{"Threshold":2,"Commits":\["[^"]+"\],"OldAddresses":\["AAAAAA=="\],` +
+		`"OldPublicKeys":\["[^"]+"\],"Dealers":\["AAAAAA=="\],"NewAddresses":\["AQAAAA=="\],"NewPublicKeys":\["[^"]+"\]}}`
+	require.Regexp(t, regexp, string(data))
+
+	reshare = types.NewReshare(0, []kyber.Point{badPoint{}}, nil, nil, nil, nil, nil)
+	_, err = format.Encode(ctx, reshare)
+	require.EqualError(t, err, fake.Err("couldn't marshal public key"))
+
+	reshare = types.NewReshare(0, nil, nil, nil, nil, []mino.Address{fake.NewBadAddress()}, nil)
+	_, err = format.Encode(ctx, reshare)
+	require.EqualError(t, err, fake.Err("couldn't marshal address"))
+}
+
 func TestMessageFormat_Deal_Encode(t *testing.T) {
 	deal := types.NewDeal(1, []byte{1}, types.EncryptedDeal{})
 
@@ -81,6 +105,21 @@ func TestMessageFormat_StartDone_Encode(t *testing.T) {
 	require.EqualError(t, err, fake.Err("couldn't marshal public key"))
 }
 
+func TestMessageFormat_StartFailed_Encode(t *testing.T) {
+	failed := types.NewStartFailed([]mino.Address{fake.NewAddress(1)})
+
+	format := newMsgFormat()
+	ctx := serde.NewContext(fake.ContextEngine{})
+
+	data, err := format.Encode(ctx, failed)
+	require.NoError(t, err)
+	require.Equal(t, `{"StartFailed":{"Missing":["AQAAAA=="]}}`, string(data))
+
+	failed = types.NewStartFailed([]mino.Address{fake.NewBadAddress()})
+	_, err = format.Encode(ctx, failed)
+	require.EqualError(t, err, fake.Err("couldn't marshal address"))
+}
+
 func TestMessageFormat_DecryptRequest_Encode(t *testing.T) {
 	req := types.NewDecryptRequest("electionId")
 
@@ -99,6 +138,7 @@ func TestMessageFormat_Decode(t *testing.T) {
 
 	// Decode start messages.
 	expected := types.NewStart(
+		2,
 		[]mino.Address{fake.NewAddress(0)},
 		[]kyber.Point{suite.Point()},
 	)
@@ -110,6 +150,7 @@ func TestMessageFormat_Decode(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, start.(types.Start).GetAddresses(), len(expected.GetAddresses()))
 	require.Len(t, start.(types.Start).GetPublicKeys(), len(expected.GetPublicKeys()))
+	require.Equal(t, 2, start.(types.Start).GetThreshold())
 
 	_, err = format.Decode(ctx, []byte(`{"Start":{"PublicKeys":[[]]}}`))
 	require.EqualError(t, err,
@@ -119,6 +160,37 @@ func TestMessageFormat_Decode(t *testing.T) {
 	_, err = format.Decode(badCtx, []byte(`{"Start":{}}`))
 	require.EqualError(t, err, "invalid factory of type '<nil>'")
 
+	// Decode reshare messages.
+	expectedReshare := types.NewReshare(
+		2,
+		[]kyber.Point{suite.Point(), suite.Point()},
+		[]mino.Address{fake.NewAddress(0)},
+		[]kyber.Point{suite.Point()},
+		[]mino.Address{fake.NewAddress(0)},
+		[]mino.Address{fake.NewAddress(1), fake.NewAddress(2)},
+		[]kyber.Point{suite.Point(), suite.Point()},
+	)
+
+	data, err = format.Encode(ctx, expectedReshare)
+	require.NoError(t, err)
+
+	reshare, err := format.Decode(ctx, data)
+	require.NoError(t, err)
+	require.Equal(t, 2, reshare.(types.Reshare).GetThreshold())
+	require.Len(t, reshare.(types.Reshare).GetCommits(), 2)
+	require.Len(t, reshare.(types.Reshare).GetOldAddresses(), 1)
+	require.Len(t, reshare.(types.Reshare).GetOldPublicKeys(), 1)
+	require.Len(t, reshare.(types.Reshare).GetDealers(), 1)
+	require.Len(t, reshare.(types.Reshare).GetNewAddresses(), 2)
+	require.Len(t, reshare.(types.Reshare).GetNewPublicKeys(), 2)
+
+	_, err = format.Decode(ctx, []byte(`{"Reshare":{"Commits":[[]]}}`))
+	require.EqualError(t, err,
+		"couldn't unmarshal public key: invalid Ed25519 curve point")
+
+	_, err = format.Decode(badCtx, []byte(`{"Reshare":{}}`))
+	require.EqualError(t, err, "invalid factory of type '<nil>'")
+
 	// Decode deal messages.
 	deal, err := format.Decode(ctx, []byte(`{"Deal":{}}`))
 	require.NoError(t, err)
@@ -140,6 +212,14 @@ func TestMessageFormat_Decode(t *testing.T) {
 	require.EqualError(t, err,
 		"couldn't unmarshal public key: invalid Ed25519 curve point")
 
+	// Decode start failed messages.
+	failed, err := format.Decode(ctx, []byte(`{"StartFailed":{"Missing":["AQAAAA=="]}}`))
+	require.NoError(t, err)
+	require.Equal(t, types.NewStartFailed([]mino.Address{fake.NewAddress(1)}), failed)
+
+	_, err = format.Decode(badCtx, []byte(`{"StartFailed":{}}`))
+	require.EqualError(t, err, "invalid factory of type '<nil>'")
+
 	// Decode decryption request messages.
 	data = []byte(`{"DecryptRequest":{}}`)
 	req, err := format.Decode(ctx, data)
@@ -11,6 +11,7 @@ import (
 
 	"go.dedis.ch/dela"
 	"go.dedis.ch/dela/core/ordering"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
 
 	"github.com/dedis/d-voting/contracts/evoting"
 	etypes "github.com/dedis/d-voting/contracts/evoting/types"
@@ -25,6 +26,7 @@ import (
 	"go.dedis.ch/dela/serde"
 	jsonserde "go.dedis.ch/dela/serde/json"
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
 	"go.dedis.ch/kyber/v3/suites"
 	"go.dedis.ch/kyber/v3/util/random"
 	"golang.org/x/net/context"
@@ -44,12 +46,27 @@ var (
 	// protocolNameDecrypt denotes the value of the protocol span tag
 	// associated with the `dkg-decrypt` protocol.
 	protocolNameDecrypt = "dkg-decrypt"
+	// protocolNameReshare denotes the value of the protocol span tag
+	// associated with the `dkg-reshare` protocol.
+	protocolNameReshare = "dkg-reshare"
 )
 
+// rosterKey is the key at which the roster of the chain is stored.
+var rosterKey = [32]byte{}
+
 const (
 	setupTimeout   = time.Second * 300
 	decryptTimeout = time.Second * 100
 
+	// peerPubKeyTimeout is the maximum time to wait for the DKG public keys
+	// of the participants
+	peerPubKeyTimeout = time.Second * 10
+	// startDoneTimeout is the maximum time to wait for the participants to
+	// finish the DKG. It leaves them the time to give up on the deals and on
+	// the responses, and to report it, or to register the result.
+	startDoneTimeout = recvDealsTimeout + recvResponseTimeout + registerTimeout +
+		time.Second*20
+
 	// RPC defines the RPC name used for mino
 	RPC = "dkgevoting"
 )
@@ -64,6 +81,7 @@ type Pedersen struct {
 	factory     serde.Factory
 	service     ordering.Service
 	electionFac serde.Factory
+	rosterFac   authority.Factory
 	pool        pool.Pool
 	signer      crypto.Signer
 	actors      map[string]dkg.Actor
@@ -71,7 +89,7 @@ type Pedersen struct {
 
 // NewPedersen returns a new DKG Pedersen factory
 func NewPedersen(m mino.Mino, service ordering.Service, pool pool.Pool,
-	electionFac serde.Factory, signer crypto.Signer) *Pedersen {
+	electionFac serde.Factory, rosterFac authority.Factory, signer crypto.Signer) *Pedersen {
 
 	factory := types.NewMessageFactory(m.GetAddressFactory())
 	actors := make(map[string]dkg.Actor)
@@ -84,6 +102,7 @@ func NewPedersen(m mino.Mino, service ordering.Service, pool pool.Pool,
 		actors:      actors,
 		signer:      signer,
 		electionFac: electionFac,
+		rosterFac:   rosterFac,
 	}
 }
 
@@ -118,7 +137,7 @@ func (s *Pedersen) NewActor(electionIDBuf []byte, pool pool.Pool, txmngr txn.Man
 	ctx := jsonserde.NewContext()
 
 	// link the actor to an RPC by the election ID
-	h := NewHandler(s.mino.GetAddress(), s.service, pool, txmngr, s.signer,
+	h := NewHandler(s.mino.GetAddress(), electionID, s.service, pool, txmngr, s.signer,
 		handlerData, ctx, s.electionFac)
 
 	no := s.mino.WithSegment(electionID)
@@ -130,6 +149,7 @@ func (s *Pedersen) NewActor(electionIDBuf []byte, pool pool.Pool, txmngr txn.Man
 		service:     s.service,
 		context:     ctx,
 		electionFac: s.electionFac,
+		rosterFac:   s.rosterFac,
 		handler:     h,
 		electionID:  electionID,
 		status:      dkg.Status{Status: dkg.Initialized},
@@ -156,17 +176,28 @@ func (s *Pedersen) GetActor(electionIDBuf []byte) (dkg.Actor, bool) {
 //
 // - implements dkg.Actor
 type Actor struct {
+	// protects the status and the unresponsive participants, which are
+	// updated by the setup while being read by the proxy
+	sync.Mutex
+
 	rpc         mino.RPC
 	factory     serde.Factory
 	service     ordering.Service
 	context     serde.Context
 	electionFac serde.Factory
+	rosterFac   authority.Factory
 	handler     *Handler
 	electionID  string
 	status      dkg.Status
+
+	// the participants that didn't answer during the last failed setup
+	unresponsive []mino.Address
 }
 
 func (a *Actor) setErr(err error, args map[string]interface{}) {
+	a.Lock()
+	defer a.Unlock()
+
 	a.status = dkg.Status{
 		Status: dkg.Failed,
 		Err:    err,
@@ -176,10 +207,118 @@ func (a *Actor) setErr(err error, args map[string]interface{}) {
 	evoting.PromElectionDkgStatus.WithLabelValues(a.electionID).Set(float64(dkg.Failed))
 }
 
+func (a *Actor) setStatus(status dkg.StatusCode) {
+	a.Lock()
+	defer a.Unlock()
+
+	a.status = dkg.Status{Status: status}
+
+	evoting.PromElectionDkgStatus.WithLabelValues(a.electionID).Set(float64(status))
+}
+
+// setUnresponsive sets the error status with the participants that didn't
+// deliver their messages in time. They are remembered so that the setup can be
+// retried without them.
+func (a *Actor) setUnresponsive(err error, unresponsive []mino.Address) {
+	names := make([]string, len(unresponsive))
+	for i, addr := range unresponsive {
+		names[i] = addr.String()
+	}
+
+	a.setErr(err, map[string]interface{}{"unresponsive": names})
+
+	a.Lock()
+	a.unresponsive = unresponsive
+	a.Unlock()
+}
+
 // Setup implements dkg.Actor. It initializes the DKG protocol across all
 // participating nodes. This function updates the actor's status in case of
 // error to allow asynchronous call of this function.
 func (a *Actor) Setup() (kyber.Point, error) {
+	return a.setup(nil)
+}
+
+// Retry implements dkg.Actor. It tears down the state left by a failed setup
+// and runs the setup again, optionally without the participants that were
+// found unresponsive. The nodes gave up on the failed setup before the
+// initiator, which waits longer than each phase of the DKG. The participants
+// of the DKG become the roster of the election when it is opened.
+func (a *Actor) Retry(excludeUnresponsive bool) (kyber.Point, error) {
+	a.Lock()
+	status := a.status.Status
+	unresponsive := a.unresponsive
+	a.Unlock()
+
+	if status != dkg.Failed {
+		return nil, xerrors.Errorf("only a failed setup can be retried, "+
+			"current status: %d", status)
+	}
+
+	election, err := a.getElection()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get election: %v", err)
+	}
+
+	if election.Pubkey != nil {
+		return nil, xerrors.Errorf("the election already has a public key: %s",
+			election.Pubkey)
+	}
+
+	var exclude []mino.Address
+	if excludeUnresponsive {
+		exclude = unresponsive
+	}
+
+	addrs := participants(election, exclude)
+
+	// the retry runs in the background, so the status tells why it is refused
+	if election.DecryptionThreshold > len(addrs) {
+		err := xerrors.Errorf("the threshold is higher than the number of "+
+			"participants left: %d > %d", election.DecryptionThreshold, len(addrs))
+		a.setUnresponsive(err, unresponsive)
+		return nil, err
+	}
+
+	// the participants become the roster of the election, which must allow
+	// its shuffle threshold
+	if election.ShuffleThreshold > etypes.MaxThreshold(len(addrs)) {
+		err := xerrors.Errorf("the shuffle threshold is too high for the "+
+			"participants left: %d > %d", election.ShuffleThreshold,
+			etypes.MaxThreshold(len(addrs)))
+		a.setUnresponsive(err, unresponsive)
+		return nil, err
+	}
+
+	// another retry might have started in the meantime
+	a.Lock()
+	if a.status.Status != dkg.Failed {
+		a.Unlock()
+		return nil, xerrors.Errorf("only a failed setup can be retried, "+
+			"current status: %d", a.status.Status)
+	}
+
+	a.unresponsive = nil
+	a.status = dkg.Status{Status: dkg.Initialized}
+	a.Unlock()
+
+	evoting.PromElectionDkgStatus.WithLabelValues(a.electionID).Set(float64(dkg.Initialized))
+
+	a.handler.Lock()
+	a.handler.privShare = nil
+	a.handler.Unlock()
+
+	a.handler.startRes.SetDistKey(nil)
+	a.handler.startRes.SetCommits(nil)
+	a.handler.startRes.SetParticipants(nil)
+	a.handler.startRes.SetPublicKeys(nil)
+
+	return a.setup(exclude)
+}
+
+// setup runs the DKG on the nodes of the roster of the election, except the
+// excluded ones.
+func (a *Actor) setup(exclude []mino.Address) (kyber.Point, error) {
 
 	if a.handler.startRes.Done() {
 		err := xerrors.New("setup() was already called, only one call is allowed")
@@ -194,23 +333,19 @@ func (a *Actor) Setup() (kyber.Point, error) {
 		return nil, err
 	}
 
+	addrs := participants(election, exclude)
+
 	ctx, cancel := context.WithTimeout(context.Background(), setupTimeout)
 	defer cancel()
 	ctx = context.WithValue(ctx, tracing.ProtocolKey, protocolNameSetup)
 
-	sender, receiver, err := a.rpc.Stream(ctx, election.Roster)
+	sender, receiver, err := a.rpc.Stream(ctx, mino.NewAddresses(addrs...))
 	if err != nil {
 		err := xerrors.Errorf("failed to stream: %v", err)
 		a.setErr(err, nil)
 		return nil, err
 	}
 
-	addrs := make([]mino.Address, 0, election.Roster.Len())
-	addrIter := election.Roster.AddressIterator()
-	for addrIter.HasNext() {
-		addrs = append(addrs, addrIter.GetNext())
-	}
-
 	// get the peer DKG pub keys
 	getPeerKey := types.NewGetPeerPubKey()
 	errs := sender.Send(getPeerKey, addrs...)
@@ -223,8 +358,6 @@ func (a *Actor) Setup() (kyber.Point, error) {
 	}
 
 	lenAddrs := len(addrs)
-	dkgPeerPubkeys := make([]kyber.Point, 0, lenAddrs)
-	associatedAddrs := make([]mino.Address, 0, lenAddrs)
 
 	if lenAddrs == 0 {
 		err := xerrors.Errorf("the list of addresses is empty")
@@ -232,79 +365,325 @@ func (a *Actor) Setup() (kyber.Point, error) {
 		return nil, err
 	}
 
-	for i := 0; i < lenAddrs; i++ {
-		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
-		defer cancel()
+	if election.DecryptionThreshold > lenAddrs {
+		err := xerrors.Errorf("the threshold is higher than the number of "+
+			"participants: %d > %d", election.DecryptionThreshold, lenAddrs)
+		a.setErr(err, nil)
+		return nil, err
+	}
 
-		from, msg, err := receiver.Recv(ctx)
-		if err != nil {
-			err := xerrors.Errorf("failed to receive peer pubkey: %v", err)
+	associatedAddrs, dkgPeerPubkeys, err := a.getPeerPubKeys(receiver, addrs)
+	if err != nil {
+		return nil, err
+	}
+
+	message := types.NewStart(election.DecryptionThreshold, associatedAddrs, dkgPeerPubkeys)
+
+	errs = sender.Send(message, addrs...)
+	err = <-errs
+	if err != nil {
+		err := xerrors.Errorf("failed to send start: %v", err)
+		a.setErr(err, nil)
+		return nil, err
+	}
+
+	dkgPubKeys, err := a.waitDone(receiver, addrs)
+	if err != nil {
+		return nil, err
+	}
+
+	// this is a simple check that every node sends back the same DKG pub key.
+	for i := 1; i < len(dkgPubKeys); i++ {
+		if !dkgPubKeys[0].Equal(dkgPubKeys[i]) {
+			err := xerrors.Errorf("the public keys do not match: %v", dkgPubKeys)
 			a.setErr(err, nil)
 			return nil, err
 		}
+	}
 
-		dela.Logger.Info().Msgf("received a response from %v", from)
+	a.setStatus(dkg.Setup)
 
-		resp, ok := msg.(types.GetPeerPubKeyResp)
-		if !ok {
-			err := xerrors.Errorf("received an unexpected message: %T - %s", resp, resp)
-			a.setErr(err, nil)
-			return nil, err
+	return dkgPubKeys[0], nil
+}
+
+// participants returns the addresses of the roster of the election, except
+// the excluded ones.
+func participants(election etypes.Election, exclude []mino.Address) []mino.Address {
+	addrs := make([]mino.Address, 0, election.Roster.Len())
+
+	addrIter := election.Roster.AddressIterator()
+	for addrIter.HasNext() {
+		addr := addrIter.GetNext()
+		if indexOf(exclude, addr) == -1 {
+			addrs = append(addrs, addr)
 		}
+	}
 
-		dkgPeerPubkeys = append(dkgPeerPubkeys, resp.GetPublicKey())
-		associatedAddrs = append(associatedAddrs, from)
+	return addrs
+}
 
-		dela.Logger.Info().Msgf("Public key: %s", resp.GetPublicKey().String())
+// Reshare implements dkg.Actor. It moves the shares of the participants of the
+// DKG to the nodes of the roster of the chain, with the same threshold. Only the
+// participants that are reachable are contacted, and a threshold of them deal
+// their share, so that the DKG survives the loss of some participants. This
+// function updates the actor's status in case of error to allow asynchronous
+// call of this function.
+func (a *Actor) Reshare() error {
+	if !a.handler.startRes.Done() {
+		err := xerrors.Errorf("setup() was not called")
+		a.setErr(err, nil)
+		return err
 	}
 
-	message := types.NewStart(associatedAddrs, dkgPeerPubkeys)
+	distKey := a.handler.startRes.GetDistKey()
+	commits := a.handler.startRes.GetCommits()
+	oldAddrs := a.handler.startRes.GetParticipants()
+	oldPubkeys := a.handler.startRes.GetPublicKeys()
+
+	if len(oldPubkeys) != len(oldAddrs) {
+		err := xerrors.Errorf("the public keys of the participants are unknown")
+		a.setErr(err, nil)
+		return err
+	}
+
+	roster, err := a.getRoster()
+	if err != nil {
+		err := xerrors.Errorf("failed to get roster: %v", err)
+		a.setErr(err, nil)
+		return err
+	}
+
+	newAddrs := make([]mino.Address, 0, roster.Len())
+	addrIter := roster.AddressIterator()
+	for addrIter.HasNext() {
+		newAddrs = append(newAddrs, addrIter.GetNext())
+	}
+
+	if len(newAddrs) < len(commits) {
+		err := xerrors.Errorf("the roster has less nodes than the threshold: "+
+			"%d < %d", len(newAddrs), len(commits))
+		a.setErr(err, nil)
+		return err
+	}
+
+	reachable := a.reachable(oldAddrs)
+	if len(reachable) < len(commits) {
+		err := xerrors.Errorf("not enough participants are reachable: %d < %d",
+			len(reachable), len(commits))
+		a.setUnresponsive(err, notIn(oldAddrs, reachable))
+		return err
+	}
+
+	// every new participant must use the deals of the same dealers
+	dealers := reachable[:len(commits)]
+
+	// the nodes that stay in the DKG take part in the protocol once
+	addrs := append([]mino.Address{}, reachable...)
+	for _, addr := range newAddrs {
+		if indexOf(addrs, addr) == -1 {
+			addrs = append(addrs, addr)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), setupTimeout)
+	defer cancel()
+	ctx = context.WithValue(ctx, tracing.ProtocolKey, protocolNameReshare)
+
+	sender, receiver, err := a.rpc.Stream(ctx, mino.NewAddresses(addrs...))
+	if err != nil {
+		err := xerrors.Errorf("failed to stream: %v", err)
+		a.setErr(err, nil)
+		return err
+	}
+
+	// get the peer DKG pub keys
+	errs := sender.Send(types.NewGetPeerPubKey(), addrs...)
+
+	err = <-errs
+	if err != nil {
+		err := xerrors.Errorf("failed to send getPeerKey message: %v", err)
+		a.setErr(err, nil)
+		return err
+	}
+
+	peerAddrs, peerPubkeys, err := a.getPeerPubKeys(receiver, addrs)
+	if err != nil {
+		return err
+	}
+
+	newPubkeys, err := pubkeysOf(newAddrs, peerAddrs, peerPubkeys)
+	if err != nil {
+		err := xerrors.Errorf("failed to get the keys of the roster: %v", err)
+		a.setErr(err, nil)
+		return err
+	}
+
+	// the old participants keep the order of their index in the DKG
+	message := types.NewReshare(len(commits), commits, oldAddrs, oldPubkeys, dealers,
+		newAddrs, newPubkeys)
 
 	errs = sender.Send(message, addrs...)
 	err = <-errs
 	if err != nil {
-		err := xerrors.Errorf("failed to send start: %v", err)
+		err := xerrors.Errorf("failed to send reshare: %v", err)
 		a.setErr(err, nil)
-		return nil, err
+		return err
 	}
 
-	dkgPubKeys := make([]kyber.Point, lenAddrs)
+	// only the new participants acknowledge the resharing
+	pubkeys, err := a.waitDone(receiver, newAddrs)
+	if err != nil {
+		return err
+	}
+
+	for _, pubkey := range pubkeys {
+		if !distKey.Equal(pubkey) {
+			err := xerrors.Errorf("the public key changed: %s != %s", pubkey, distKey)
+			a.setErr(err, nil)
+			return err
+		}
+	}
 
-	for i := 0; i < lenAddrs; i++ {
+	a.setStatus(dkg.Setup)
+
+	return nil
+}
 
-		ctx, cancel := context.WithTimeout(context.Background(), time.Second*20)
-		defer cancel()
+// reachable returns the addresses that answer a request for their DKG public
+// key in time, in the same order. Each address is contacted on its own so that
+// an unreachable node doesn't prevent the others from being reached.
+func (a *Actor) reachable(addrs []mino.Address) []mino.Address {
+	res := make([]mino.Address, 0, len(addrs))
 
-		addr, msg, err := receiver.Recv(ctx)
+	for _, addr := range addrs {
+		err := a.ping(addr)
 		if err != nil {
-			err := xerrors.Errorf("got an error from '%s' while receiving: %v", addr, err)
-			a.setErr(err, nil)
-			return nil, err
+			dela.Logger.Warn().Msgf("%s is unreachable: %v", addr, err)
+			continue
 		}
 
-		doneMsg, ok := msg.(types.StartDone)
+		res = append(res, addr)
+	}
+
+	return res
+}
+
+// ping requests the DKG public key of the address and waits for the answer.
+func (a *Actor) ping(addr mino.Address) error {
+	ctx, cancel := context.WithTimeout(context.Background(), peerPubKeyTimeout)
+	defer cancel()
+	ctx = context.WithValue(ctx, tracing.ProtocolKey, protocolNameReshare)
+
+	sender, receiver, err := a.rpc.Stream(ctx, mino.NewAddresses(addr))
+	if err != nil {
+		return xerrors.Errorf("failed to stream: %v", err)
+	}
+
+	err = <-sender.Send(types.NewGetPeerPubKey(), addr)
+	if err != nil {
+		return xerrors.Errorf("failed to send: %v", err)
+	}
+
+	_, msg, err := receiver.Recv(ctx)
+	if err != nil {
+		return xerrors.Errorf("failed to receive: %v", err)
+	}
+
+	_, ok := msg.(types.GetPeerPubKeyResp)
+	if !ok {
+		return xerrors.Errorf("unexpected message: %T", msg)
+	}
+
+	return nil
+}
+
+// getPeerPubKeys waits for the DKG public key of each address and returns
+// them along with the address that sent each of them. The addresses that
+// didn't answer in time are reported as unresponsive in the actor's status.
+func (a *Actor) getPeerPubKeys(receiver mino.Receiver,
+	addrs []mino.Address) ([]mino.Address, []kyber.Point, error) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), peerPubKeyTimeout)
+	defer cancel()
+
+	peerAddrs := make([]mino.Address, 0, len(addrs))
+	peerPubkeys := make([]kyber.Point, 0, len(addrs))
+
+	for len(peerAddrs) < len(addrs) {
+		from, msg, err := receiver.Recv(ctx)
+		if err != nil {
+			err := xerrors.Errorf("failed to receive peer pubkey: %v", err)
+			a.setUnresponsive(err, notIn(addrs, peerAddrs))
+			return nil, nil, err
+		}
+
+		dela.Logger.Info().Msgf("received a response from %v", from)
+
+		resp, ok := msg.(types.GetPeerPubKeyResp)
 		if !ok {
-			err := xerrors.Errorf("expected to receive a Done message, but "+
-				"go the following: %T", msg)
+			err := xerrors.Errorf("received an unexpected message: %T - %s", msg, msg)
 			a.setErr(err, nil)
+			return nil, nil, err
+		}
+
+		peerAddrs = append(peerAddrs, from)
+		peerPubkeys = append(peerPubkeys, resp.GetPublicKey())
+
+		dela.Logger.Info().Msgf("Public key: %s", resp.GetPublicKey().String())
+	}
+
+	return peerAddrs, peerPubkeys, nil
+}
+
+// waitDone waits for each address to send the DKG public key it computed, and
+// returns them. A node that gives up on the DKG reports the participants it
+// didn't hear from. They are reported as unresponsive in the actor's status,
+// along with the addresses that didn't answer in time.
+func (a *Actor) waitDone(receiver mino.Receiver, addrs []mino.Address) ([]kyber.Point, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), startDoneTimeout)
+	defer cancel()
+
+	pubkeys := make([]kyber.Point, 0, len(addrs))
+	answered := make([]mino.Address, 0, len(addrs))
+	unresponsive := make([]mino.Address, 0)
+	failed := 0
+
+	for len(answered) < len(addrs) {
+		from, msg, err := receiver.Recv(ctx)
+		if err != nil {
+			err := xerrors.Errorf("failed to receive the done messages: %v", err)
+			unresponsive = append(unresponsive, notIn(notIn(addrs, answered), unresponsive)...)
+			a.setUnresponsive(err, unresponsive)
 			return nil, err
 		}
 
-		dkgPubKeys[i] = doneMsg.GetPublicKey()
+		switch msg := msg.(type) {
+		case types.StartDone:
+			pubkeys = append(pubkeys, msg.GetPublicKey())
+		case types.StartFailed:
+			dela.Logger.Warn().Msgf("%s gave up on the DKG, missing: %v",
+				from, msg.GetMissing())
 
-		// this is a simple check that every node sends back the same DKG pub
-		// key.
-		if i != 0 && !dkgPubKeys[i-1].Equal(doneMsg.GetPublicKey()) {
-			err := xerrors.Errorf("the public keys do not match: %v", dkgPubKeys)
+			unresponsive = append(unresponsive, notIn(msg.GetMissing(), unresponsive)...)
+			failed++
+		default:
+			err := xerrors.Errorf("expected to receive a Done message, but "+
+				"go the following: %T", msg)
 			a.setErr(err, nil)
 			return nil, err
 		}
+
+		answered = append(answered, from)
 	}
 
-	a.status = dkg.Status{Status: dkg.Setup}
-	evoting.PromElectionDkgStatus.WithLabelValues(a.electionID).Set(float64(dkg.Setup))
+	if failed > 0 {
+		err := xerrors.Errorf("%d node(s) gave up on the DKG, unresponsive: %v",
+			failed, unresponsive)
+		a.setUnresponsive(err, unresponsive)
+		return nil, err
+	}
 
-	return dkgPubKeys[0], nil
+	return pubkeys, nil
 }
 
 // GetPublicKey implements dkg.Actor
@@ -316,6 +695,52 @@ func (a *Actor) GetPublicKey() (kyber.Point, error) {
 	return a.handler.startRes.GetDistKey(), nil
 }
 
+// GetVerificationKeys implements dkg.Actor. The verification key of the node
+// at index i is the public polynomial of the DKG evaluated at i.
+func (a *Actor) GetVerificationKeys() ([]kyber.Point, error) {
+	if !a.handler.startRes.Done() {
+		return nil, xerrors.Errorf("dkg has not been initialized")
+	}
+
+	commits := a.handler.startRes.GetCommits()
+	if len(commits) == 0 {
+		return nil, xerrors.Errorf("the commits of the dkg are not available")
+	}
+
+	pubPoly := share.NewPubPoly(suite, nil, commits)
+	keys := make([]kyber.Point, len(a.handler.startRes.GetParticipants()))
+
+	for i := range keys {
+		keys[i] = pubPoly.Eval(i).V
+	}
+
+	return keys, nil
+}
+
+// GetParticipants implements dkg.Actor
+func (a *Actor) GetParticipants() ([]mino.Address, error) {
+	if !a.handler.startRes.Done() {
+		return nil, xerrors.Errorf("dkg has not been initialized")
+	}
+
+	return a.handler.startRes.GetParticipants(), nil
+}
+
+// GetThreshold implements dkg.Actor. The public polynomial of the DKG has t
+// commits.
+func (a *Actor) GetThreshold() (int, error) {
+	if !a.handler.startRes.Done() {
+		return 0, xerrors.Errorf("dkg has not been initialized")
+	}
+
+	commits := a.handler.startRes.GetCommits()
+	if len(commits) == 0 {
+		return 0, xerrors.Errorf("the commits of the dkg are not available")
+	}
+
+	return len(commits), nil
+}
+
 // Encrypt implements dkg.Actor. It uses the DKG public key to encrypt a
 // message.
 func (a *Actor) Encrypt(message []byte) (K, C kyber.Point, remainder []byte,
@@ -385,9 +810,54 @@ func (a *Actor) MarshalJSON() ([]byte, error) {
 
 // Status implements dkg.Actor
 func (a *Actor) Status() dkg.Status {
+	a.Lock()
+	defer a.Unlock()
+
 	return a.status
 }
 
+// indexOf returns the index of the address in the list, or -1 if it is not in
+// the list.
+func indexOf(addrs []mino.Address, addr mino.Address) int {
+	for i, a := range addrs {
+		if a.Equal(addr) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// notIn returns the addresses that are not in the other list.
+func notIn(addrs, others []mino.Address) []mino.Address {
+	res := make([]mino.Address, 0)
+
+	for _, addr := range addrs {
+		if indexOf(others, addr) == -1 {
+			res = append(res, addr)
+		}
+	}
+
+	return res
+}
+
+// pubkeysOf returns the public key of each address, in the same order, from
+// the addresses of the peers and their corresponding public keys.
+func pubkeysOf(addrs, peerAddrs []mino.Address, peerPubkeys []kyber.Point) ([]kyber.Point, error) {
+	pubkeys := make([]kyber.Point, len(addrs))
+
+	for i, addr := range addrs {
+		index := indexOf(peerAddrs, addr)
+		if index == -1 {
+			return nil, xerrors.Errorf("no public key for '%s'", addr)
+		}
+
+		pubkeys[i] = peerPubkeys[index]
+	}
+
+	return pubkeys, nil
+}
+
 func electionExists(service ordering.Service, electionIDBuf []byte) (ordering.Proof, bool) {
 	proof, err := service.GetProof(electionIDBuf)
 	if err != nil {
@@ -403,7 +873,7 @@ func electionExists(service ordering.Service, electionIDBuf []byte) (ordering.Pr
 }
 
 // getElection gets the election from the service.
-func (a Actor) getElection() (etypes.Election, error) {
+func (a *Actor) getElection() (etypes.Election, error) {
 	var election etypes.Election
 
 	electionID, err := hex.DecodeString(a.electionID)
@@ -433,3 +903,18 @@ func (a Actor) getElection() (etypes.Election, error) {
 
 	return election, nil
 }
+
+// getRoster gets the roster of the chain from the service.
+func (a *Actor) getRoster() (authority.Authority, error) {
+	proof, err := a.service.GetProof(rosterKey[:])
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read roster: %v", err)
+	}
+
+	roster, err := a.rosterFac.AuthorityOf(a.context, proof.GetValue())
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode roster: %v", err)
+	}
+
+	return roster, nil
+}
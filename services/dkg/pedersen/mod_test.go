@@ -50,7 +50,7 @@ func init() {
 func TestActor_MarshalJSON(t *testing.T) {
 	initMetrics()
 
-	p := NewPedersen(fake.Mino{}, &fake.Service{}, &fake.Pool{}, fake.Factory{}, fake.Signer{})
+	p := NewPedersen(fake.Mino{}, &fake.Service{}, &fake.Pool{}, fake.Factory{}, fake.RosterFac{}, fake.Signer{})
 
 	// Create new actor
 	actor1, err := p.NewActor([]byte("deadbeef"), &fake.Pool{},
@@ -135,7 +135,7 @@ func TestPedersen_InitNonEmptyMap(t *testing.T) {
 	require.NoError(t, err)
 
 	// Initialize a Pedersen
-	p := NewPedersen(fake.Mino{}, &fake.Service{}, &fake.Pool{}, fake.Factory{}, fake.Signer{})
+	p := NewPedersen(fake.Mino{}, &fake.Service{}, &fake.Pool{}, fake.Factory{}, fake.RosterFac{}, fake.Signer{})
 
 	err = dkgMap.View(func(tx kv.ReadableTx) error {
 		bucket := tx.GetBucket([]byte("dkgmap"))
@@ -179,7 +179,7 @@ func TestPedersen_InitNonEmptyMap(t *testing.T) {
 		require.True(t, exists)
 
 		otherActor := Actor{
-			handler: NewHandler(fake.NewAddress(0), &fake.Service{}, &fake.Pool{},
+			handler: NewHandler(fake.NewAddress(0), electionID, &fake.Service{}, &fake.Pool{},
 				fake.Manager{}, fake.Signer{}, handlerData, serdecontext, electionFac),
 		}
 
@@ -197,7 +197,7 @@ func TestPedersen_SyncDB(t *testing.T) {
 	fake.NewElection(electionID2)
 
 	// Initialize a Pedersen
-	p := NewPedersen(fake.Mino{}, &fake.Service{}, &fake.Pool{}, fake.Factory{}, fake.Signer{})
+	p := NewPedersen(fake.Mino{}, &fake.Service{}, &fake.Pool{}, fake.Factory{}, fake.RosterFac{}, fake.Signer{})
 
 	// Create actors
 	a1, err := p.NewActor([]byte(electionID1), &fake.Pool{}, fake.Manager{}, NewHandlerData())
@@ -241,7 +241,7 @@ func TestPedersen_SyncDB(t *testing.T) {
 	require.NoError(t, err)
 
 	// Recover them from the map
-	q := NewPedersen(fake.Mino{}, &fake.Service{}, &fake.Pool{}, fake.Factory{}, fake.Signer{})
+	q := NewPedersen(fake.Mino{}, &fake.Service{}, &fake.Pool{}, fake.Factory{}, fake.RosterFac{}, fake.Signer{})
 
 	err = dkgMap.View(func(tx kv.ReadableTx) error {
 		bucket := tx.GetBucket([]byte("dkgmap"))
@@ -287,7 +287,7 @@ func TestPedersen_Listen(t *testing.T) {
 		etypes.Election{Roster: fake.Authority{}}, serdecontext)
 
 	p := NewPedersen(fake.Mino{}, &service, &fake.Pool{},
-		fake.Factory{}, fake.Signer{})
+		fake.Factory{}, fake.RosterFac{}, fake.Signer{})
 
 	actor, err := p.Listen(electionIDBuf, fake.Manager{})
 	require.NoError(t, err)
@@ -304,7 +304,7 @@ func TestPedersen_TwoListens(t *testing.T) {
 	service := fake.NewService(electionID,
 		etypes.Election{Roster: fake.Authority{}}, serdecontext)
 
-	p := NewPedersen(fake.Mino{}, &service, &fake.Pool{}, fake.Factory{}, fake.Signer{})
+	p := NewPedersen(fake.Mino{}, &service, &fake.Pool{}, fake.Factory{}, fake.RosterFac{}, fake.Signer{})
 
 	actor1, err := p.Listen(electionIDBuf, fake.Manager{})
 	require.NoError(t, err)
@@ -420,6 +420,117 @@ func TestPedersen_Setup(t *testing.T) {
 	require.Equal(t, float64(dkg.Setup), testutil.ToFloat64(evoting.PromElectionDkgStatus))
 }
 
+func TestPedersen_Retry(t *testing.T) {
+	initMetrics()
+
+	electionID := "d3adbeef"
+
+	rosterLen := 3
+	roster := authority.FromAuthority(fake.NewAuthority(rosterLen, fake.NewSigner))
+
+	addrs := make([]mino.Address, 0, rosterLen)
+	addrsIter := roster.AddressIterator()
+	for addrsIter.HasNext() {
+		addrs = append(addrs, addrsIter.GetNext())
+	}
+
+	service := fake.NewService(electionID, etypes.Election{
+		ElectionID: electionID,
+		Roster:     roster,
+	}, serdecontext)
+
+	actor := Actor{
+		service: &service,
+		handler: &Handler{
+			startRes: &state{},
+		},
+		context:     serdecontext,
+		electionFac: etypes.NewElectionFactory(etypes.CiphervoteFactory{}, fake.NewRosterFac(roster)),
+		electionID:  electionID,
+	}
+
+	_, err := actor.Retry(false)
+	require.EqualError(t, err, "only a failed setup can be retried, current status: 0")
+
+	pubKey := suite.Point().Pick(suite.RandomStream())
+
+	// The third node is silent, and the others give up waiting for its deal
+	actor.rpc = fake.NewStreamRPC(fake.NewReceiver(
+		fake.NewRecvMsg(addrs[0], types.NewGetPeerPubKeyResp(pubKey)),
+		fake.NewRecvMsg(addrs[1], types.NewGetPeerPubKeyResp(pubKey)),
+		fake.NewRecvMsg(addrs[2], types.NewGetPeerPubKeyResp(pubKey)),
+		fake.NewRecvMsg(addrs[0], types.NewStartFailed([]mino.Address{addrs[2]})),
+		fake.NewRecvMsg(addrs[1], types.NewStartFailed([]mino.Address{addrs[2]})),
+	), fake.Sender{})
+
+	_, err = actor.Setup()
+	require.EqualError(t, err, "failed to receive the done messages: EOF")
+	require.Equal(t, dkg.Failed, actor.Status().Status)
+	require.Equal(t, []string{addrs[2].String()}, actor.Status().Args["unresponsive"])
+	require.Equal(t, float64(dkg.Failed), testutil.ToFloat64(evoting.PromElectionDkgStatus))
+
+	// The first node gives up while the others finish
+	actor.rpc = fake.NewStreamRPC(fake.NewReceiver(
+		fake.NewRecvMsg(addrs[0], types.NewGetPeerPubKeyResp(pubKey)),
+		fake.NewRecvMsg(addrs[1], types.NewGetPeerPubKeyResp(pubKey)),
+		fake.NewRecvMsg(addrs[2], types.NewGetPeerPubKeyResp(pubKey)),
+		fake.NewRecvMsg(addrs[0], types.NewStartFailed([]mino.Address{addrs[2]})),
+		fake.NewRecvMsg(addrs[1], types.NewStartDone(pubKey)),
+		fake.NewRecvMsg(addrs[2], types.NewStartDone(pubKey)),
+	), fake.Sender{})
+
+	_, err = actor.Retry(false)
+	require.Regexp(t, "^1 node\\(s\\) gave up on the DKG, unresponsive:", err)
+	require.Equal(t, []string{addrs[2].String()}, actor.Status().Args["unresponsive"])
+
+	// Excluding the third node would leave fewer nodes than the threshold
+	service = fake.NewService(electionID, etypes.Election{
+		ElectionID:          electionID,
+		Roster:              roster,
+		DecryptionThreshold: 3,
+	}, serdecontext)
+
+	_, err = actor.Retry(true)
+	require.EqualError(t, err, "the threshold is higher than the number of "+
+		"participants left: 3 > 2")
+	require.Equal(t, dkg.Failed, actor.Status().Status)
+	require.Equal(t, err, actor.Status().Err)
+	require.Equal(t, []string{addrs[2].String()}, actor.Status().Args["unresponsive"])
+
+	service = fake.NewService(electionID, etypes.Election{
+		ElectionID: electionID,
+		Roster:     roster,
+	}, serdecontext)
+
+	// The setup is retried without the third node
+	rpc := fake.NewStreamRPC(fake.NewReceiver(
+		fake.NewRecvMsg(addrs[0], types.NewGetPeerPubKeyResp(pubKey)),
+		fake.NewRecvMsg(addrs[1], types.NewGetPeerPubKeyResp(pubKey)),
+		fake.NewRecvMsg(addrs[0], types.NewStartDone(pubKey)),
+		fake.NewRecvMsg(addrs[1], types.NewStartDone(pubKey)),
+	), fake.Sender{})
+	actor.rpc = rpc
+
+	res, err := actor.Retry(true)
+	require.NoError(t, err)
+	require.Equal(t, 2, rpc.Calls.Get(0, 1).(mino.Players).Len())
+	require.True(t, pubKey.Equal(res))
+	require.Equal(t, dkg.Setup, actor.Status().Status)
+	require.Nil(t, actor.unresponsive)
+
+	// The setup can't be retried once the election has a public key
+	actor.status = dkg.Status{Status: dkg.Failed}
+
+	service = fake.NewService(electionID, etypes.Election{
+		ElectionID: electionID,
+		Roster:     roster,
+		Pubkey:     pubKey,
+	}, serdecontext)
+
+	_, err = actor.Retry(false)
+	require.Regexp(t, "^the election already has a public key:", err)
+}
+
 func TestPedersen_GetPublicKey(t *testing.T) {
 
 	actor := Actor{handler: &Handler{startRes: &state{}}}
@@ -434,6 +545,66 @@ func TestPedersen_GetPublicKey(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestPedersen_GetVerificationKeys(t *testing.T) {
+	actor := Actor{handler: &Handler{startRes: &state{}}}
+
+	_, err := actor.GetVerificationKeys()
+	require.EqualError(t, err, "dkg has not been initialized")
+
+	actor.handler.startRes = &state{
+		participants: []mino.Address{fake.NewAddress(0), fake.NewAddress(1)},
+		distKey:      suite.Point(),
+	}
+
+	_, err = actor.GetVerificationKeys()
+	require.EqualError(t, err, "the commits of the dkg are not available")
+
+	_, err = actor.GetThreshold()
+	require.EqualError(t, err, "the commits of the dkg are not available")
+
+	secret := suite.Scalar().Pick(suite.RandomStream())
+	slope := suite.Scalar().Pick(suite.RandomStream())
+
+	actor.handler.startRes.commits = []kyber.Point{
+		suite.Point().Mul(secret, nil),
+		suite.Point().Mul(slope, nil),
+	}
+
+	keys, err := actor.GetVerificationKeys()
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+
+	threshold, err := actor.GetThreshold()
+	require.NoError(t, err)
+	require.Equal(t, 2, threshold)
+
+	// the private share of the node at index i is secret + slope*(i+1)
+	for i, key := range keys {
+		x := suite.Scalar().SetInt64(int64(i + 1))
+		privShare := suite.Scalar().Add(secret, suite.Scalar().Mul(slope, x))
+
+		require.True(t, key.Equal(suite.Point().Mul(privShare, nil)))
+	}
+}
+
+func TestPedersen_GetParticipants(t *testing.T) {
+	actor := Actor{handler: &Handler{startRes: &state{}}}
+
+	_, err := actor.GetParticipants()
+	require.EqualError(t, err, "dkg has not been initialized")
+
+	addrs := []mino.Address{fake.NewAddress(0), fake.NewAddress(1)}
+
+	actor.handler.startRes = &state{
+		participants: addrs,
+		distKey:      suite.Point(),
+	}
+
+	participants, err := actor.GetParticipants()
+	require.NoError(t, err)
+	require.Equal(t, addrs, participants)
+}
+
 func TestPedersen_Scenario(t *testing.T) {
 	n := 5
 
@@ -484,13 +655,19 @@ func TestPedersen_Scenario(t *testing.T) {
 
 	election := fake.NewElection(electionID)
 	election.Roster = roster
+	election.DecryptionThreshold = 3
 
 	service := fake.NewService(electionID, election, serdecontext)
 
 	for i, mino := range minos {
 		fac := etypes.NewElectionFactory(etypes.CiphervoteFactory{}, fake.NewRosterFac(roster))
 
-		dkg := NewPedersen(mino, &service, &fake.Pool{}, fac, fake.Signer{})
+		// every node watches its own registration of the DKG
+		nodeService := service
+		pool := &fake.Pool{Service: &nodeService}
+
+		dkg := NewPedersen(mino, &nodeService, pool, fac, fake.NewRosterFac(roster),
+			fake.Signer{})
 
 		actor, err := dkg.Listen(electionIDBuf, signed.NewManager(fake.Signer{}, &client{
 			srvc: &fake.Service{},
@@ -535,6 +712,29 @@ func TestPedersen_Scenario(t *testing.T) {
 	_, err = actors[0].Setup()
 	require.EqualError(t, err, "setup() was already called, only one call is allowed")
 
+	// the DKG runs with the threshold of the election
+	threshold, err := actors[0].GetThreshold()
+	require.NoError(t, err)
+	require.Equal(t, 3, threshold)
+
+	// the verification keys are the public keys of the private shares, any
+	// threshold of which recover the DKG public key
+	keys, err := actors[0].GetVerificationKeys()
+	require.NoError(t, err)
+	require.Len(t, keys, n)
+
+	pubShares := make([]*share.PubShare, n)
+	for i, key := range keys {
+		pubShares[i] = &share.PubShare{I: i, V: key}
+	}
+
+	recovered, err := share.RecoverCommit(suite, pubShares[n-threshold:], threshold, n)
+	require.NoError(t, err)
+	require.True(t, recovered.Equal(pubKey))
+
+	_, err = share.RecoverCommit(suite, pubShares[:threshold-1], threshold, n)
+	require.Error(t, err)
+
 	// every node should be able to request the public shares
 
 	//for _, actor := range actors {  TODO : Doesn't pass? :(
@@ -543,6 +743,14 @@ func TestPedersen_Scenario(t *testing.T) {
 	//}
 }
 
+func TestPedersen_Reshare_Scenario(t *testing.T) {
+	reshareScenario(t, false)
+}
+
+func TestPedersen_Reshare_OldNodeDown(t *testing.T) {
+	reshareScenario(t, true)
+}
+
 func TestPedersen_Encrypt_NotStarted(t *testing.T) {
 	a := Actor{
 		handler: &Handler{
@@ -645,6 +853,148 @@ func TestPedersen_ComputePubshares_OK(t *testing.T) {
 // -----------------------------------------------------------------------------
 // Utility functions
 
+// reshareScenario runs a DKG on the nodes 0 to 3, then reshares it to the
+// nodes 1 to 4: the node 0 leaves the DKG and the node 4 joins it. If down is
+// set, the node 0 is stopped before the resharing.
+func reshareScenario(t *testing.T, down bool) {
+	n := 5
+
+	minos := makeMinos(t, n)
+	defer func() {
+		for i, m := range minos {
+			if i > 0 || !down {
+				m.(*minogrpc.Minogrpc).GracefulStop()
+			}
+		}
+	}()
+
+	signers := fake.NewAuthorityFromMino(fake.NewSigner, minos...)
+	roster := authority.FromAuthority(
+		signers.Take(mino.RangeFilter(0, n-1)).(fake.CollectiveAuthority))
+	chainRoster := authority.FromAuthority(
+		signers.Take(mino.RangeFilter(1, n)).(fake.CollectiveAuthority))
+
+	electionID := "deadbeef"
+	electionIDBuf, err := hex.DecodeString(electionID)
+	require.NoError(t, err)
+
+	election := fake.NewElection(electionID)
+	election.Roster = roster
+	election.DecryptionThreshold = 3
+
+	service := fake.NewService(electionID, election, serdecontext)
+
+	actors := make([]dkg.Actor, n)
+
+	for i, m := range minos {
+		fac := etypes.NewElectionFactory(etypes.CiphervoteFactory{}, fake.NewRosterFac(roster))
+
+		// every node watches its own registration of the DKG
+		nodeService := service
+		pool := &fake.Pool{Service: &nodeService}
+
+		dkg := NewPedersen(m, &nodeService, pool, fac, fake.NewRosterFac(chainRoster),
+			fake.Signer{})
+
+		actor, err := dkg.Listen(electionIDBuf, signed.NewManager(fake.Signer{}, &client{
+			srvc: &fake.Service{},
+			vs:   fake.ValidationService{},
+		}))
+		require.NoError(t, err)
+
+		actors[i] = actor
+	}
+
+	err = actors[1].Reshare()
+	require.EqualError(t, err, "setup() was not called")
+
+	pubKey, err := actors[0].Setup()
+	require.NoError(t, err)
+
+	if down {
+		// the nodes 1 to 3 are a threshold of the participants
+		minos[0].(*minogrpc.Minogrpc).GracefulStop()
+	}
+
+	err = actors[1].Reshare()
+	require.NoError(t, err)
+
+	if !down {
+		// the node that left doesn't hold a share anymore
+		_, err = actors[0].GetPublicKey()
+		require.EqualError(t, err, "dkg has not been initialized")
+		require.Nil(t, actors[0].(*Actor).handler.privShare)
+	}
+
+	for _, actor := range actors[1:] {
+		key, err := actor.GetPublicKey()
+		require.NoError(t, err)
+		require.True(t, key.Equal(pubKey))
+
+		threshold, err := actor.GetThreshold()
+		require.NoError(t, err)
+		require.Equal(t, 3, threshold)
+	}
+
+	keys, err := actors[1].GetVerificationKeys()
+	require.NoError(t, err)
+	require.Len(t, keys, n-1)
+
+	// the new shares match the verification keys, any threshold of which
+	// recover the DKG public key
+	for _, actor := range actors[1:] {
+		privShare := actor.(*Actor).handler.privShare
+		require.True(t, keys[privShare.I].Equal(suite.Point().Mul(privShare.V, nil)))
+	}
+
+	pubShares := make([]*share.PubShare, len(keys))
+	for i, key := range keys {
+		pubShares[i] = &share.PubShare{I: i, V: key}
+	}
+
+	recovered, err := share.RecoverCommit(suite, pubShares[1:], 3, len(keys))
+	require.NoError(t, err)
+	require.True(t, recovered.Equal(pubKey))
+}
+
+// makeMinos creates n minogrpc instances that know each other.
+func makeMinos(t *testing.T, n int) []mino.Mino {
+	minos := make([]mino.Mino, n)
+
+	for i := range minos {
+		addr := minogrpc.ParseAddress("127.0.0.1", 0)
+
+		m, err := minogrpc.NewMinogrpc(addr, nil, tree.NewRouter(minogrpc.NewAddressFactory()))
+		require.NoError(t, err)
+
+		minos[i] = m
+	}
+
+	for _, m := range minos {
+		// share the certificates
+		joinable, ok := m.(minogrpc.Joinable)
+		require.True(t, ok)
+
+		addrURL, err := url.Parse("//" + m.GetAddress().String())
+		require.NoError(t, err, addrURL)
+
+		token := joinable.GenerateToken(time.Hour)
+
+		certHash, err := joinable.GetCertificateStore().Hash(joinable.GetCertificate())
+		require.NoError(t, err)
+
+		for _, other := range minos {
+			otherJoinable, ok := other.(minogrpc.Joinable)
+			require.True(t, ok)
+
+			err = otherJoinable.Join(addrURL, token, certHash)
+			require.NoError(t, err)
+		}
+	}
+
+	return minos
+}
+
 func initMetrics() {
 	evoting.PromElectionDkgStatus.Reset()
 }
@@ -20,6 +20,8 @@ func RegisterMessageFormat(c serde.Format, f serde.FormatEngine) {
 //
 // - implements serde.Message
 type Start struct {
+	// the number of shares required to decrypt, t in t-of-n
+	threshold int
 	// the full list of addresses that will participate in the DKG
 	addresses []mino.Address
 	// the corresponding kyber.Point pub keys of the addresses
@@ -27,13 +29,19 @@ type Start struct {
 }
 
 // NewStart creates a new start message.
-func NewStart(addrs []mino.Address, pubkeys []kyber.Point) Start {
+func NewStart(threshold int, addrs []mino.Address, pubkeys []kyber.Point) Start {
 	return Start{
+		threshold: threshold,
 		addresses: addrs,
 		pubkeys:   pubkeys,
 	}
 }
 
+// GetThreshold returns the threshold.
+func (s Start) GetThreshold() int {
+	return s.threshold
+}
+
 // GetAddresses returns the list of addresses.
 func (s Start) GetAddresses() []mino.Address {
 	return append([]mino.Address{}, s.addresses...)
@@ -57,6 +65,93 @@ func (s Start) Serialize(ctx serde.Context) ([]byte, error) {
 	return data, nil
 }
 
+// Reshare is the message the initiator of the resharing protocol should send to
+// the reachable old participants and to the new participants of the DKG. The
+// dealers, a threshold of the old participants, deal their share to the new
+// ones, which keeps the collective public key.
+//
+// - implements serde.Message
+type Reshare struct {
+	// the number of shares required to decrypt with the new participants
+	threshold int
+	// the public polynomial of the DKG, which the new participants use to
+	// verify the deals
+	commits []kyber.Point
+	// the participants of the DKG, in the order of their index
+	oldAddresses []mino.Address
+	oldPubkeys   []kyber.Point
+	// the old participants that deal their share
+	dealers []mino.Address
+	// the participants after the resharing, in the order of their new index
+	newAddresses []mino.Address
+	newPubkeys   []kyber.Point
+}
+
+// NewReshare creates a new reshare message.
+func NewReshare(threshold int, commits []kyber.Point, oldAddrs []mino.Address,
+	oldPubkeys []kyber.Point, dealers []mino.Address, newAddrs []mino.Address,
+	newPubkeys []kyber.Point) Reshare {
+
+	return Reshare{
+		threshold:    threshold,
+		commits:      commits,
+		oldAddresses: oldAddrs,
+		oldPubkeys:   oldPubkeys,
+		dealers:      dealers,
+		newAddresses: newAddrs,
+		newPubkeys:   newPubkeys,
+	}
+}
+
+// GetThreshold returns the threshold of the new participants.
+func (r Reshare) GetThreshold() int {
+	return r.threshold
+}
+
+// GetCommits returns the commits of the public polynomial of the DKG.
+func (r Reshare) GetCommits() []kyber.Point {
+	return append([]kyber.Point{}, r.commits...)
+}
+
+// GetOldAddresses returns the addresses of the current participants.
+func (r Reshare) GetOldAddresses() []mino.Address {
+	return append([]mino.Address{}, r.oldAddresses...)
+}
+
+// GetOldPublicKeys returns the public keys of the current participants.
+func (r Reshare) GetOldPublicKeys() []kyber.Point {
+	return append([]kyber.Point{}, r.oldPubkeys...)
+}
+
+// GetDealers returns the addresses of the old participants that deal their
+// share.
+func (r Reshare) GetDealers() []mino.Address {
+	return append([]mino.Address{}, r.dealers...)
+}
+
+// GetNewAddresses returns the addresses of the new participants.
+func (r Reshare) GetNewAddresses() []mino.Address {
+	return append([]mino.Address{}, r.newAddresses...)
+}
+
+// GetNewPublicKeys returns the public keys of the new participants.
+func (r Reshare) GetNewPublicKeys() []kyber.Point {
+	return append([]kyber.Point{}, r.newPubkeys...)
+}
+
+// Serialize implements serde.Message. It looks up the format and returns the
+// serialized data for the reshare message.
+func (r Reshare) Serialize(ctx serde.Context) ([]byte, error) {
+	format := msgFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, r)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't encode message: %v", err)
+	}
+
+	return data, nil
+}
+
 // EncryptedDeal contains the different parameters and data of an encrypted
 // deal.
 type EncryptedDeal struct {
@@ -253,6 +348,39 @@ func (s StartDone) Serialize(ctx serde.Context) ([]byte, error) {
 	return data, nil
 }
 
+// StartFailed is sent by a node to the initiator of the DKG when it gave up
+// waiting for the deals or the responses of the other participants.
+//
+// - implements serde.Message
+type StartFailed struct {
+	missing []mino.Address
+}
+
+// NewStartFailed creates a new start failed message.
+func NewStartFailed(missing []mino.Address) StartFailed {
+	return StartFailed{
+		missing: missing,
+	}
+}
+
+// GetMissing returns the participants that did not deliver their deal or
+// their responses.
+func (s StartFailed) GetMissing() []mino.Address {
+	return s.missing
+}
+
+// Serialize implements serde.Message.
+func (s StartFailed) Serialize(ctx serde.Context) ([]byte, error) {
+	format := msgFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, s)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't encode start failed: %v", err)
+	}
+
+	return data, nil
+}
+
 // DecryptRequest is a message sent to request a decryption.
 //
 // - implements serde.Message
@@ -20,17 +20,23 @@ func init() {
 }
 
 func TestStart_GetAddresses(t *testing.T) {
-	start := NewStart([]mino.Address{fake.NewAddress(0)}, nil)
+	start := NewStart(0, []mino.Address{fake.NewAddress(0)}, nil)
 
 	require.Len(t, start.GetAddresses(), 1)
 }
 
 func TestStart_GetPublicKeys(t *testing.T) {
-	start := NewStart(nil, []kyber.Point{nil, nil})
+	start := NewStart(0, nil, []kyber.Point{nil, nil})
 
 	require.Len(t, start.GetPublicKeys(), 2)
 }
 
+func TestStart_GetThreshold(t *testing.T) {
+	start := NewStart(2, nil, nil)
+
+	require.Equal(t, 2, start.GetThreshold())
+}
+
 func TestStart_Serialize(t *testing.T) {
 	start := Start{}
 
@@ -42,6 +48,33 @@ func TestStart_Serialize(t *testing.T) {
 	require.EqualError(t, err, fake.Err("couldn't encode message"))
 }
 
+func TestReshare_Getters(t *testing.T) {
+	reshare := NewReshare(3, []kyber.Point{nil, nil, nil},
+		[]mino.Address{fake.NewAddress(0)}, []kyber.Point{nil},
+		[]mino.Address{fake.NewAddress(0)},
+		[]mino.Address{fake.NewAddress(1), fake.NewAddress(2)}, []kyber.Point{nil, nil})
+
+	require.Equal(t, 3, reshare.GetThreshold())
+	require.Len(t, reshare.GetCommits(), 3)
+	require.Equal(t, []mino.Address{fake.NewAddress(0)}, reshare.GetOldAddresses())
+	require.Len(t, reshare.GetOldPublicKeys(), 1)
+	require.Equal(t, []mino.Address{fake.NewAddress(0)}, reshare.GetDealers())
+	require.Equal(t, []mino.Address{fake.NewAddress(1), fake.NewAddress(2)},
+		reshare.GetNewAddresses())
+	require.Len(t, reshare.GetNewPublicKeys(), 2)
+}
+
+func TestReshare_Serialize(t *testing.T) {
+	reshare := Reshare{}
+
+	data, err := reshare.Serialize(fake.NewContext())
+	require.NoError(t, err)
+	require.Equal(t, fake.GetFakeFormatValue(), data)
+
+	_, err = reshare.Serialize(fake.NewBadContext())
+	require.EqualError(t, err, fake.Err("couldn't encode message"))
+}
+
 func TestEncryptedDeal_Getters(t *testing.T) {
 	f := func(key, sig, nonce, cipher []byte) bool {
 		e := NewEncryptedDeal(key, sig, nonce, cipher)
@@ -158,6 +191,23 @@ func TestStartDone_Serialize(t *testing.T) {
 	require.EqualError(t, err, fake.Err("couldn't encode ack"))
 }
 
+func TestStartFailed_GetMissing(t *testing.T) {
+	failed := NewStartFailed([]mino.Address{fake.NewAddress(1)})
+
+	require.Equal(t, []mino.Address{fake.NewAddress(1)}, failed.GetMissing())
+}
+
+func TestStartFailed_Serialize(t *testing.T) {
+	failed := StartFailed{}
+
+	data, err := failed.Serialize(fake.NewContext())
+	require.NoError(t, err)
+	require.Equal(t, fake.GetFakeFormatValue(), data)
+
+	_, err = failed.Serialize(fake.NewBadContext())
+	require.EqualError(t, err, fake.Err("couldn't encode start failed"))
+}
+
 func TestDecryptRequest_GetElectionId(t *testing.T) {
 	req := NewDecryptRequest("electionId")
 
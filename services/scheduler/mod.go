@@ -0,0 +1,516 @@
+// Package scheduler defines a service that automatically opens and closes the
+// elections that define a schedule. While an election waits for its schedule,
+// the node reports its time on the chain, from which the smart contract derives
+// the time against which the schedule is checked.
+package scheduler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/dedis/d-voting/contracts/evoting"
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"go.dedis.ch/dela"
+	"go.dedis.ch/dela/core/execution/native"
+	"go.dedis.ch/dela/core/ordering"
+	"go.dedis.ch/dela/core/txn"
+	"go.dedis.ch/dela/core/txn/pool"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+// DefaultInterval is the default time between two checks of the elections.
+const DefaultInterval = 10 * time.Second
+
+// pendingTimeout is how long a submitted transaction prevents the scheduler
+// from submitting another one for the same election. After that, the
+// transaction is considered lost.
+const pendingTimeout = time.Minute
+
+// Scheduler periodically checks the elections and submits the OPEN_ELECTION
+// and CLOSE_ELECTION transactions when their schedule requires it, according to
+// the time of the chain. It submits the REPORT_TIME transactions of the node as
+// long as an election of which the node is a member waits for its schedule.
+//
+// Only one node submits a transaction when the time comes: the nodes wait for
+// a delay that depends on their position in the roster of the election, so
+// that the next node takes over only if the previous ones failed to update the
+// election.
+type Scheduler struct {
+	sync.Mutex
+
+	service     ordering.Service
+	pool        pool.Pool
+	mngr        txn.Manager
+	signer      crypto.Signer
+	context     serde.Context
+	electionFac serde.Factory
+	me          mino.Address
+
+	interval time.Duration
+	clock    func() time.Time
+
+	// reported is the last time reported by the node, as the smart contract
+	// only accepts a later one.
+	reported int64
+
+	// pending contains the transactions submitted by the scheduler that are
+	// not included yet, by hex-encoded transaction ID.
+	pending map[string]submission
+
+	// rejected contains the IDs of the elections whose closing has been
+	// rejected by the smart contract. It would be rejected again, so that the
+	// admin must close or cancel the election instead.
+	rejected map[string]bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// submission is a transaction submitted by the scheduler.
+type submission struct {
+	electionID string
+	cmd        evoting.Command
+	submitted  time.Time
+}
+
+// NewScheduler returns a new scheduler for the node at the given address. The
+// signer is the one of the node in the roster, with which it reports its time.
+// It must be started with Start.
+func NewScheduler(service ordering.Service, p pool.Pool, mngr txn.Manager,
+	signer crypto.Signer, me mino.Address, ctx serde.Context,
+	electionFac serde.Factory, interval time.Duration) *Scheduler {
+
+	return &Scheduler{
+		service:     service,
+		pool:        p,
+		mngr:        mngr,
+		signer:      signer,
+		context:     ctx,
+		electionFac: electionFac,
+		me:          me,
+		interval:    interval,
+		clock:       time.Now,
+		pending:     make(map[string]submission),
+		rejected:    make(map[string]bool),
+	}
+}
+
+// Start starts checking the elections in the background. It does nothing if
+// the scheduler is already running.
+func (s *Scheduler) Start() {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.stop != nil {
+		return
+	}
+
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+
+	go s.run(s.stop, s.done)
+}
+
+// Stop stops the scheduler and waits for the current check to finish.
+func (s *Scheduler) Stop() {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.stop == nil {
+		return
+	}
+
+	close(s.stop)
+	<-s.done
+
+	s.stop = nil
+	s.done = nil
+}
+
+func (s *Scheduler) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := s.service.Watch(ctx)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case event, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+
+			s.update(event)
+		case <-ticker.C:
+			err := s.check()
+			if err != nil {
+				dela.Logger.Warn().Err(err).Msg("failed to check the elections schedule")
+			}
+		}
+	}
+}
+
+// update removes the transactions included in the block from the pending ones,
+// and remembers the elections whose closing has been rejected.
+func (s *Scheduler) update(event ordering.Event) {
+	for _, res := range event.Transactions {
+		id := hex.EncodeToString(res.GetTransaction().GetID())
+
+		sub, found := s.pending[id]
+		if !found {
+			continue
+		}
+
+		delete(s.pending, id)
+
+		accepted, msg := res.GetStatus()
+		if !accepted && sub.cmd == evoting.CmdCloseElection {
+			s.rejected[sub.electionID] = true
+
+			dela.Logger.Warn().Msgf("scheduled closing of election %s rejected: %s",
+				sub.electionID, msg)
+		}
+	}
+}
+
+// check goes through all the elections and submits a transaction for each one
+// that should be opened or closed, and reports the time of the node if one of
+// them waits for its schedule.
+func (s *Scheduler) check() error {
+	electionIDs, err := s.getElectionIDs()
+	if err != nil {
+		return xerrors.Errorf("failed to get elections: %v", err)
+	}
+
+	clock, err := s.getClock()
+	if err != nil {
+		return xerrors.Errorf("failed to get clock: %v", err)
+	}
+
+	now := s.clock()
+	waiting := false
+
+	for _, electionID := range electionIDs {
+		if s.rejected[electionID] {
+			continue
+		}
+
+		election, err := s.getElection(electionID)
+		if err != nil {
+			dela.Logger.Warn().Err(err).Msgf("failed to get election %s", electionID)
+			continue
+		}
+
+		schedule := election.Configuration.Schedule
+		if !schedule.IsSet() {
+			continue
+		}
+
+		rank := s.rankOf(election)
+		if rank < 0 {
+			continue
+		}
+
+		if (election.Status == types.Initial && schedule.Start != 0) ||
+			(election.Status == types.Open && schedule.End != 0) {
+
+			waiting = true
+		}
+
+		if s.isPending(electionID, now) {
+			continue
+		}
+
+		chainTime, err := clock.Time(election.Roster)
+		if err != nil {
+			dela.Logger.Warn().Err(err).Msgf("failed to get time of election %s", electionID)
+			continue
+		}
+
+		// the node waits for the nodes before it in the roster
+		due := chainTime.Add(-time.Duration(rank) * s.interval)
+
+		var cmd evoting.Command
+		var msg serde.Message
+
+		switch {
+		case election.Status == types.Initial && schedule.Start != 0 &&
+			schedule.HasStarted(due):
+
+			cmd = evoting.CmdOpenElection
+			msg = types.OpenElection{
+				ElectionID: election.ElectionID,
+			}
+		case election.Status == types.Open && schedule.HasEnded(due):
+			cmd = evoting.CmdCloseElection
+			msg = types.CloseElection{
+				ElectionID: election.ElectionID,
+			}
+		default:
+			continue
+		}
+
+		txID, err := s.submit(cmd, msg)
+		if err != nil {
+			dela.Logger.Warn().Err(err).Msgf("failed to update election %s", electionID)
+			continue
+		}
+
+		s.pending[hex.EncodeToString(txID)] = submission{
+			electionID: electionID,
+			cmd:        cmd,
+			submitted:  now,
+		}
+	}
+
+	if waiting {
+		err = s.reportTime(now)
+		if err != nil {
+			return xerrors.Errorf("failed to report time: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// reportTime submits the time of the node, unless the previous report is not
+// included yet. The reports are not tied to an election, hence their empty
+// election ID.
+func (s *Scheduler) reportTime(now time.Time) error {
+	if s.isPending("", now) || now.Unix() <= s.reported {
+		return nil
+	}
+
+	publicKey, err := s.signer.GetPublicKey().MarshalBinary()
+	if err != nil {
+		return xerrors.Errorf("failed to marshal public key: %v", err)
+	}
+
+	report := types.ReportTime{
+		Timestamp: now.Unix(),
+		PublicKey: publicKey,
+	}
+
+	h := sha256.New()
+
+	err = report.Fingerprint(h)
+	if err != nil {
+		return xerrors.Errorf("failed to get fingerprint: %v", err)
+	}
+
+	signature, err := s.signer.Sign(h.Sum(nil))
+	if err != nil {
+		return xerrors.Errorf("failed to sign: %v", err)
+	}
+
+	report.Signature, err = signature.Serialize(s.context)
+	if err != nil {
+		return xerrors.Errorf("failed to serialize signature: %v", err)
+	}
+
+	txID, err := s.submit(evoting.CmdReportTime, report)
+	if err != nil {
+		return xerrors.Errorf("failed to submit: %v", err)
+	}
+
+	s.pending[hex.EncodeToString(txID)] = submission{
+		cmd:       evoting.CmdReportTime,
+		submitted: now,
+	}
+
+	s.reported = now.Unix()
+
+	return nil
+}
+
+// isPending returns true if a transaction submitted for the election is not
+// included yet. The transactions pending for longer than pendingTimeout are
+// forgotten.
+func (s *Scheduler) isPending(electionID string, now time.Time) bool {
+	pending := false
+
+	for id, sub := range s.pending {
+		if now.Sub(sub.submitted) > pendingTimeout {
+			delete(s.pending, id)
+			continue
+		}
+
+		if sub.electionID == electionID {
+			pending = true
+		}
+	}
+
+	return pending
+}
+
+// rankOf returns the position of the node in the roster of the election, or -1
+// if it is not part of it.
+func (s *Scheduler) rankOf(election types.Election) int {
+	if election.Roster == nil {
+		return -1
+	}
+
+	iter := election.Roster.AddressIterator()
+
+	for i := 0; iter.HasNext(); i++ {
+		if iter.GetNext().Equal(s.me) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// submit adds the transaction to the pool without waiting for its inclusion,
+// and returns its ID.
+func (s *Scheduler) submit(cmd evoting.Command, msg serde.Message) ([]byte, error) {
+	data, err := msg.Serialize(s.context)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to serialize transaction: %v", err)
+	}
+
+	err = s.mngr.Sync()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to sync manager: %v", err)
+	}
+
+	tx, err := s.mngr.Make(
+		txn.Arg{Key: native.ContractArg, Value: []byte(evoting.ContractName)},
+		txn.Arg{Key: evoting.CmdArg, Value: []byte(cmd)},
+		txn.Arg{Key: evoting.ElectionArg, Value: data},
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create transaction: %v", err)
+	}
+
+	err = s.pool.Add(tx)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to add transaction to the pool: %v", err)
+	}
+
+	dela.Logger.Info().Msgf("scheduler submitted %s", cmd)
+
+	return tx.GetID(), nil
+}
+
+// getElectionIDs returns the IDs of the elections that might have to be opened
+// or closed, based on the status stored in the catalog.
+func (s *Scheduler) getElectionIDs() ([]string, error) {
+	proof, err := s.service.GetProof([]byte(types.CatalogCountKey))
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get proof: %v", err)
+	}
+
+	count, err := types.DecodeCatalogCount(proof.GetValue())
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode catalog count: %v", err)
+	}
+
+	entries := []types.CatalogEntry{}
+
+	// the elections of a chain created before the catalog are listed at the
+	// legacy key until a transaction migrates them
+	if count == 0 {
+		entries, err = types.LegacyCatalogEntries(s.context, s.electionFac, s.getValue)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to get legacy entries: %v", err)
+		}
+	}
+
+	for i := uint64(0); i < count; i++ {
+		proof, err := s.service.GetProof(types.CatalogKey(i))
+		if err != nil {
+			return nil, xerrors.Errorf("failed to get proof: %v", err)
+		}
+
+		entry, err := types.DecodeCatalogEntry(proof.GetValue())
+		if err != nil {
+			return nil, xerrors.Errorf("failed to decode catalog entry: %v", err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	electionIDs := []string{}
+
+	for _, entry := range entries {
+		if entry.Deleted || (entry.Status != types.Initial && entry.Status != types.Open) {
+			continue
+		}
+
+		electionIDs = append(electionIDs, entry.ElectionID)
+	}
+
+	return electionIDs, nil
+}
+
+// getClock returns the time reported by the nodes.
+func (s *Scheduler) getClock() (types.Clock, error) {
+	value, err := s.getValue([]byte(types.ClockKey))
+	if err != nil {
+		return types.Clock{}, xerrors.Errorf("failed to get value: %v", err)
+	}
+
+	clock, err := types.DecodeClock(value)
+	if err != nil {
+		return types.Clock{}, xerrors.Errorf("failed to decode clock: %v", err)
+	}
+
+	return clock, nil
+}
+
+// getValue returns the value stored at the key.
+func (s *Scheduler) getValue(key []byte) ([]byte, error) {
+	proof, err := s.service.GetProof(key)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get proof: %v", err)
+	}
+
+	return proof.GetValue(), nil
+}
+
+// getElection gets the election from the service. electionIDHex is
+// hex-encoded.
+func (s *Scheduler) getElection(electionIDHex string) (types.Election, error) {
+	var election types.Election
+
+	electionID, err := hex.DecodeString(electionIDHex)
+	if err != nil {
+		return election, xerrors.Errorf("failed to decode electionIDHex: %v", err)
+	}
+
+	proof, err := s.service.GetProof(electionID)
+	if err != nil {
+		return election, xerrors.Errorf("failed to get proof: %v", err)
+	}
+
+	if len(proof.GetValue()) == 0 {
+		return election, xerrors.Errorf("election does not exist")
+	}
+
+	message, err := s.electionFac.Deserialize(s.context, proof.GetValue())
+	if err != nil {
+		return election, xerrors.Errorf("failed to deserialize Election: %v", err)
+	}
+
+	election, ok := message.(types.Election)
+	if !ok {
+		return election, xerrors.Errorf("wrong message type: %T", message)
+	}
+
+	return election, nil
+}
@@ -0,0 +1,395 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/dedis/d-voting/contracts/evoting"
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"github.com/dedis/d-voting/internal/testing/fake"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/ordering"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/store"
+	"go.dedis.ch/dela/core/txn"
+	"go.dedis.ch/dela/core/validation"
+	"go.dedis.ch/dela/serde"
+	sjson "go.dedis.ch/dela/serde/json"
+	"golang.org/x/xerrors"
+)
+
+func TestScheduler_StartStop(t *testing.T) {
+	service := fake.NewService("", types.Election{}, sjson.NewContext())
+
+	s := NewScheduler(&service, &fake.Pool{}, fake.Manager{}, fake.NewSigner(),
+		fake.NewAddress(0), sjson.NewContext(), nil, time.Millisecond)
+
+	s.Start()
+	s.Start()
+
+	time.Sleep(10 * time.Millisecond)
+
+	s.Stop()
+	s.Stop()
+}
+
+func TestScheduler_Check_NoElection(t *testing.T) {
+	service := fake.NewService("", types.Election{}, sjson.NewContext())
+
+	s := NewScheduler(&service, &fake.Pool{}, fake.Manager{}, fake.NewSigner(),
+		fake.NewAddress(0), sjson.NewContext(), nil, time.Millisecond)
+
+	err := s.check()
+	require.NoError(t, err)
+}
+
+func TestScheduler_Check_OpenClose(t *testing.T) {
+	election := makeElection(types.Initial)
+
+	service := newFakeService(election)
+	pool := &fakePool{}
+	mngr := &fakeManager{}
+
+	s := NewScheduler(service, pool, mngr, fake.NewSigner(), fake.NewAddress(0),
+		sjson.NewContext(), fakeElectionFac{election: &election}, time.Second)
+
+	// the clock of the node doesn't matter, only the time of the chain
+	s.clock = func() time.Time { return time.Unix(1000, 0) }
+
+	// not started yet, so that the node reports its time
+	service.setTime(99)
+
+	err := s.check()
+	require.NoError(t, err)
+	require.Equal(t, []evoting.Command{evoting.CmdReportTime}, mngr.commands())
+
+	// the time is not reported again while the report is pending
+	service.setTime(100)
+
+	err = s.check()
+	require.NoError(t, err)
+	require.Equal(t, []evoting.Command{evoting.CmdReportTime, evoting.CmdOpenElection},
+		mngr.commands())
+
+	// the transaction is not submitted again while it is pending
+	err = s.check()
+	require.NoError(t, err)
+	require.Len(t, pool.txs, 2)
+
+	s.update(makeEvent(pool.txs[1], true))
+	require.Len(t, s.pending, 1)
+
+	election.Status = types.Open
+	service.setStatus(types.Open)
+	service.setTime(200)
+
+	err = s.check()
+	require.NoError(t, err)
+	require.Len(t, pool.txs, 3)
+	require.Equal(t, evoting.CmdCloseElection, mngr.commandOf(2))
+
+	// once the contract rejected the closing, it is not retried
+	s.update(makeEvent(pool.txs[2], false))
+
+	err = s.check()
+	require.NoError(t, err)
+	require.Len(t, pool.txs, 3)
+	require.True(t, s.rejected[election.ElectionID])
+}
+
+func TestScheduler_Check_ReportTime(t *testing.T) {
+	election := makeElection(types.Initial)
+
+	service := newFakeService(election)
+	pool := &fakePool{}
+	mngr := &fakeManager{}
+
+	s := NewScheduler(service, pool, mngr, fake.NewSigner(), fake.NewAddress(0),
+		sjson.NewContext(), fakeElectionFac{election: &election}, time.Second)
+
+	s.clock = func() time.Time { return time.Unix(50, 0) }
+
+	err := s.check()
+	require.NoError(t, err)
+	require.Equal(t, []evoting.Command{evoting.CmdReportTime}, mngr.commands())
+	require.Equal(t, int64(50), s.reported)
+
+	s.update(makeEvent(pool.txs[0], true))
+
+	// the time of the node must move forward
+	err = s.check()
+	require.NoError(t, err)
+	require.Len(t, pool.txs, 1)
+
+	s.clock = func() time.Time { return time.Unix(51, 0) }
+
+	err = s.check()
+	require.NoError(t, err)
+	require.Len(t, pool.txs, 2)
+
+	s.update(makeEvent(pool.txs[1], true))
+
+	// the time is not reported once no election waits for its schedule
+	election.Status = types.Closed
+	s.clock = func() time.Time { return time.Unix(52, 0) }
+
+	err = s.check()
+	require.NoError(t, err)
+	require.Len(t, pool.txs, 2)
+}
+
+func TestScheduler_Check_Rank(t *testing.T) {
+	election := makeElection(types.Open)
+
+	service := newFakeService(election)
+	pool := &fakePool{}
+	mngr := &fakeManager{}
+
+	// the second node of the roster waits for one interval
+	s := NewScheduler(service, pool, mngr, fake.NewSigner(), fake.NewAddress(1),
+		sjson.NewContext(), fakeElectionFac{election: &election}, time.Second)
+
+	s.clock = func() time.Time { return time.Unix(1000, 0) }
+
+	service.setTime(200)
+
+	err := s.check()
+	require.NoError(t, err)
+	require.Equal(t, []evoting.Command{evoting.CmdReportTime}, mngr.commands())
+
+	service.setTime(201)
+
+	err = s.check()
+	require.NoError(t, err)
+	require.Equal(t, []evoting.Command{evoting.CmdReportTime, evoting.CmdCloseElection},
+		mngr.commands())
+
+	// a node outside of the roster never submits
+	s = NewScheduler(service, pool, &fakeManager{}, fake.NewSigner(), fake.NewAddress(5),
+		sjson.NewContext(), fakeElectionFac{election: &election}, time.Second)
+
+	service.setTime(300)
+
+	err = s.check()
+	require.NoError(t, err)
+	require.Len(t, pool.txs, 2)
+}
+
+func TestScheduler_IsPending_Timeout(t *testing.T) {
+	s := NewScheduler(nil, nil, nil, fake.NewSigner(), fake.NewAddress(0),
+		sjson.NewContext(), nil, time.Second)
+
+	s.pending["aa"] = submission{electionID: "bb", submitted: time.Unix(0, 0)}
+
+	require.True(t, s.isPending("bb", time.Unix(0, 0).Add(pendingTimeout)))
+	require.False(t, s.isPending("bb", time.Unix(1, 0).Add(pendingTimeout)))
+	require.Len(t, s.pending, 0)
+}
+
+func TestScheduler_Submit_BadManager(t *testing.T) {
+	service := fake.NewService("", types.Election{}, sjson.NewContext())
+
+	s := NewScheduler(&service, &fake.Pool{}, fake.Manager{}, fake.NewSigner(),
+		fake.NewAddress(0), sjson.NewContext(), nil, time.Millisecond)
+
+	_, err := s.submit(evoting.CmdOpenElection, types.OpenElection{ElectionID: "abcd"})
+	require.EqualError(t, err, fake.Err("failed to create transaction"))
+}
+
+func TestScheduler_GetElection_BadID(t *testing.T) {
+	service := fake.NewService("", types.Election{}, sjson.NewContext())
+
+	s := NewScheduler(&service, &fake.Pool{}, fake.Manager{}, fake.NewSigner(),
+		fake.NewAddress(0), sjson.NewContext(), nil, time.Millisecond)
+
+	_, err := s.getElection("X")
+	require.EqualError(t, err, "failed to decode electionIDHex: encoding/hex: "+
+		"invalid byte: U+0058 'X'")
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+const fakeElectionID = "aabb"
+
+func makeElection(status types.Status) types.Election {
+	roster := authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner))
+
+	return types.Election{
+		ElectionID: fakeElectionID,
+		Status:     status,
+		Roster:     roster,
+		Configuration: types.Configuration{
+			Schedule: types.Schedule{Start: 100, End: 200},
+		},
+	}
+}
+
+func makeEvent(tx txn.Transaction, accepted bool) ordering.Event {
+	return ordering.Event{
+		Transactions: []validation.TransactionResult{
+			fakeResult{tx: tx, accepted: accepted},
+		},
+	}
+}
+
+// fakeService is an ordering service that stores the catalog of a single
+// election, and the election as its ID.
+//
+// - implements ordering.Service
+type fakeService struct {
+	ordering.Service
+
+	values map[string][]byte
+}
+
+func newFakeService(election types.Election) *fakeService {
+	s := &fakeService{
+		values: map[string][]byte{
+			types.CatalogCountKey: types.EncodeCatalogCount(1),
+		},
+	}
+
+	electionID, _ := hex.DecodeString(election.ElectionID)
+	s.values[string(electionID)] = []byte(election.ElectionID)
+
+	s.setStatus(election.Status)
+
+	return s
+}
+
+func (s *fakeService) setStatus(status types.Status) {
+	entry, _ := types.EncodeCatalogEntry(types.CatalogEntry{
+		ElectionID: fakeElectionID,
+		Status:     status,
+	})
+
+	s.values[string(types.CatalogKey(0))] = entry
+}
+
+// setTime sets the time of the chain, as reported by the nodes of the roster,
+// which all have the same fake public key.
+func (s *fakeService) setTime(t int64) {
+	key, _ := fake.NewSigner().GetPublicKey().MarshalBinary()
+
+	clock, _ := types.EncodeClock(types.Clock{
+		Reports: []types.TimeReport{{PublicKey: key, Time: t}},
+	})
+
+	s.values[types.ClockKey] = clock
+}
+
+func (s *fakeService) GetProof(key []byte) (ordering.Proof, error) {
+	return fakeProof{key: key, value: s.values[string(key)]}, nil
+}
+
+func (s *fakeService) GetStore() store.Readable {
+	return nil
+}
+
+func (s *fakeService) Watch(ctx context.Context) <-chan ordering.Event {
+	return make(chan ordering.Event)
+}
+
+type fakeProof struct {
+	key   []byte
+	value []byte
+}
+
+func (p fakeProof) GetKey() []byte {
+	return p.key
+}
+
+func (p fakeProof) GetValue() []byte {
+	return p.value
+}
+
+// fakeElectionFac returns the election whatever the data.
+//
+// - implements serde.Factory
+type fakeElectionFac struct {
+	election *types.Election
+}
+
+func (f fakeElectionFac) Deserialize(ctx serde.Context, data []byte) (serde.Message, error) {
+	if string(data) != f.election.ElectionID {
+		return nil, xerrors.Errorf("unknown election: %s", data)
+	}
+
+	return *f.election, nil
+}
+
+// fakeManager creates transactions with an increasing ID and remembers their
+// arguments.
+//
+// - implements txn.Manager
+type fakeManager struct {
+	txn.Manager
+
+	args [][]txn.Arg
+}
+
+func (m *fakeManager) Sync() error {
+	return nil
+}
+
+func (m *fakeManager) Make(args ...txn.Arg) (txn.Transaction, error) {
+	m.args = append(m.args, args)
+
+	return fake.Transaction{Id: []byte{byte(len(m.args))}}, nil
+}
+
+func (m *fakeManager) commands() []evoting.Command {
+	commands := make([]evoting.Command, len(m.args))
+
+	for i := range m.args {
+		commands[i] = m.commandOf(i)
+	}
+
+	return commands
+}
+
+func (m *fakeManager) commandOf(index int) evoting.Command {
+	for _, arg := range m.args[index] {
+		if arg.Key == evoting.CmdArg {
+			return evoting.Command(arg.Value)
+		}
+	}
+
+	return ""
+}
+
+// fakePool remembers the transactions added to it.
+type fakePool struct {
+	fake.Pool
+
+	txs []txn.Transaction
+}
+
+func (p *fakePool) Add(tx txn.Transaction) error {
+	p.txs = append(p.txs, tx)
+
+	return nil
+}
+
+// fakeResult is the result of a transaction.
+//
+// - implements validation.TransactionResult
+type fakeResult struct {
+	tx       txn.Transaction
+	accepted bool
+}
+
+func (r fakeResult) Serialize(ctx serde.Context) ([]byte, error) {
+	return nil, nil
+}
+
+func (r fakeResult) GetTransaction() txn.Transaction {
+	return r.tx
+}
+
+func (r fakeResult) GetStatus() (bool, string) {
+	return r.accepted, "rejected"
+}
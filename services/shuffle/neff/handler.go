@@ -160,9 +160,10 @@ func makeTx(ctx serde.Context, election *etypes.Election, manager txn.Manager,
 	}
 
 	shuffleBallots := etypes.ShuffleBallots{
-		ElectionID:      election.ElectionID,
-		Round:           len(election.ShuffleInstances),
-		ShuffledBallots: shuffledBallots,
+		ElectionID:          election.ElectionID,
+		Round:               len(election.ShuffleInstances),
+		ShuffledBallots:     shuffledBallots,
+		RandomVectorVersion: election.RandomVectorVersion,
 	}
 
 	h := sha256.New()
@@ -174,17 +175,12 @@ func makeTx(ctx serde.Context, election *etypes.Election, manager txn.Manager,
 
 	hash := h.Sum(nil)
 
-	// Generate random vector and proof
-	semiRandomStream, err := evoting.NewSemiRandomStream(hash)
+	// Generate random vector and proof, the same way the smart contract
+	// verifies them
+	e, err := evoting.ShuffleRandomVector(election.RandomVectorVersion,
+		election.ElectionID, shuffleBallots.Round, hash, election.ChunksPerBallot())
 	if err != nil {
-		return nil, xerrors.Errorf("could not create semi-random stream: %v", err)
-	}
-
-	e := make([]kyber.Scalar, election.ChunksPerBallot())
-
-	for i := 0; i < election.ChunksPerBallot(); i++ {
-		v := suite.Scalar().Pick(semiRandomStream)
-		e[i] = v
+		return nil, xerrors.Errorf("failed to derive random vector: %v", err)
 	}
 
 	prover, err := getProver(e)